@@ -16,7 +16,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/inconshreveable/log15"
-	"github.com/jinzhu/gorm"
 	"github.com/kildevaeld/go-acl"
 	"github.com/olebedev/emitter"
 	"github.com/shopspring/decimal"
@@ -60,7 +59,6 @@ import (
 	paymentMethodProvider "github.com/Confialink/wallet-accounts/internal/modules/payment-method/payment-method-provider"
 	paymentPeriodProvider "github.com/Confialink/wallet-accounts/internal/modules/payment-period/payment-period-provider"
 	permissionProvider "github.com/Confialink/wallet-accounts/internal/modules/permission/permission-provider"
-	"github.com/Confialink/wallet-accounts/internal/modules/request"
 	requestProvider "github.com/Confialink/wallet-accounts/internal/modules/request/request-provider"
 	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
 	stp "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/scheduled-transaction-provider"
@@ -200,25 +198,13 @@ func serve(name string, wg *sync.WaitGroup, ctx context.Context, srv *http.Serve
 	log.Printf("%s server exited properly\n", name)
 }
 
-func runScheduledJobs(
-	db *gorm.DB,
-	scheduledTxRepo *scheduledTransaction.Repository,
-	scheduledTxService *scheduledTransaction.Service,
-	requestCreator *request.Creator,
-	logger log15.Logger,
-) {
-	scheduleTransactionsCron, err := scheduledTransaction.Schedule(
-		scheduledTxRepo,
-		requestCreator,
-		db,
-		scheduledTxService,
-		logger.New("module", "scheduled_transaction"),
-	)
+func runScheduledJobs(jobs *scheduledTransaction.Jobs) {
+	scheduleTransactionsCron, err := jobs.Schedule()
 	if err != nil {
 		log.Fatal(err)
 	}
 	log.Println("Starting scheduled transactions jobs")
-	scheduleTransactionsCron.Start()
+	go jobs.Start(scheduleTransactionsCron)
 }
 
 func subscribeModules(c *dig.Container) {
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"go.uber.org/dig"
+
+	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+const backfillDateLayout = "2006-01-02"
+
+var backfillScheduledJob command = command{
+	Name:  "backfill-scheduled-job",
+	Usage: "backfill-scheduled-job?job={name}&from={YYYY-MM-DD}&till={YYYY-MM-DD}",
+	Description: "Runs scheduled job e.g. notify_expiring_cards for its scheduled times within the given dates, " +
+		"already handled periods are skipped by the job. " +
+		"Jobs which depend on current balances e.g. collect_account_interest can not be backfilled.",
+	Handler: func(c *dig.Container, args url.Values) {
+		name := args.Get("job")
+		if name == "" {
+			log.Fatal("parameter \"job\" is required\n usage: backfill-scheduled-job?job={name}&from={YYYY-MM-DD}&till={YYYY-MM-DD}")
+		}
+		from, err := time.Parse(backfillDateLayout, args.Get("from"))
+		if err != nil {
+			log.Fatal("invalid argument \"from\" is provided, it must be date in format YYYY-MM-DD.")
+		}
+		till := from
+		if args.Get("till") != "" {
+			till, err = time.Parse(backfillDateLayout, args.Get("till"))
+			if err != nil {
+				log.Fatal("invalid argument \"till\" is provided, it must be date in format YYYY-MM-DD.")
+			}
+		}
+		if till.Before(from) {
+			log.Fatal("argument \"till\" must not be before \"from\".")
+		}
+
+		err = c.Invoke(func(jobs *scheduledTransaction.Jobs) {
+			count, err := jobs.Backfill(name, from, till)
+			if err != nil {
+				log.Fatal("unable to backfill scheduled job: ", err)
+			}
+			fmt.Printf("scheduled job %s has been run %d time(s)\n", name, count)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
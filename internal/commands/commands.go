@@ -25,6 +25,8 @@ var commands = map[string]command{
 		Handler:     func(c *dig.Container, args url.Values) {},
 	},
 	executeScheduledTransaction.Name: executeScheduledTransaction,
+	importExchangeRates.Name:         importExchangeRates,
+	backfillScheduledJob.Name:        backfillScheduledJob,
 }
 
 func Process(cmd string, c *dig.Container) {
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"log"
 	"net/url"
 	"strconv"
@@ -39,6 +40,7 @@ var executeScheduledTransaction command = command{
 			}
 
 			scheduledTransaction.ExecuteScheduledTransactions(
+				context.Background(),
 				[]*scheduledTransaction.ScheduledTransaction{tx},
 				scheduledTransactionsRepository,
 				requestCreator,
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"go.uber.org/dig"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/currency"
+)
+
+var importExchangeRates command = command{
+	Name:        "import-exchange-rates",
+	Usage:       "import-exchange-rates?file={path}",
+	Description: "Imports exchange rates from CSV (base,reference,rate) or ECB XML file into local rates table.",
+	Handler: func(c *dig.Container, args url.Values) {
+		path := args.Get("file")
+		if path == "" {
+			log.Fatal("parameter \"file\" is required\n usage: import-exchange-rates?file={path}")
+		}
+
+		err := c.Invoke(func(importer *currency.RatesImporter) {
+			count, err := importer.Import(path)
+			if err != nil {
+				log.Fatal("unable to import exchange rates: ", err)
+			}
+			fmt.Printf("%d exchange rates are imported from %s\n", count, path)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -65,6 +65,7 @@ func LoadDependencies(
 func Initialize(validator appValidator.Interface) {
 	_ = validator.RegisterValidation("decimal", decimalValid)
 	_ = validator.RegisterValidation("decimalGT", decimalGreaterThan)
+	_ = validator.RegisterValidation("decimalGTE", decimalGreaterThanOrEqual)
 	_ = validator.RegisterValidation(tagExistUserId, existingUserIDValidation)
 	_ = validator.RegisterValidation(tagUserIsActive, userIsActiveValidation)
 	_ = validator.RegisterValidation("activeCurrencyCode", activeCurrencyCodeValidation)
@@ -216,6 +217,18 @@ func decimalGreaterThan(fl validator.FieldLevel) bool {
 	return dec.GreaterThan(decParam)
 }
 
+// GreaterThanOrEqual checks if passed decimal value is greater than or equal to specified.
+// Validator usage: "YOUR_TAG=DECIMAL_NUMBER", e.g. "decimalGTE=0"
+func decimalGreaterThanOrEqual(fl validator.FieldLevel) bool {
+	fieldStr := fl.Field().Interface().(string)
+	dec, err := decimal.NewFromString(fieldStr)
+	if err != nil {
+		return false
+	}
+	decParam, _ := decimal.NewFromString(fl.Param())
+	return dec.GreaterThanOrEqual(decParam)
+}
+
 // IsNumeric is the validation function for validating if the current field's value is a valid numeric value.
 func isNumeric(fl validator.FieldLevel) bool {
 	switch fl.Field().Kind() {
@@ -7,6 +7,15 @@ const (
 	CodeTanInvalid                      = "TAN_INVALID"
 	CodeTanNotificationMethodNotAllowed = "TAN_NOTIFICATION_METHOD_NOT_ALLOWED"
 	CodeTanRequestNotAllowed            = "TAN_REQUEST_NOT_ALLOWED"
+	CodeTanMethodNotAllowed             = "TAN_METHOD_NOT_ALLOWED"
+	CodeTanUnknownMethod                = "TAN_UNKNOWN_METHOD"
+	CodeTanApprovalNotFound             = "TAN_APPROVAL_NOT_FOUND"
+	CodeTanApprovalSelf                 = "TAN_APPROVAL_SELF"
+	CodeTotpNotEnrolled                 = "TOTP_NOT_ENROLLED"
+	CodeTanLocked                       = "TAN_LOCKED"
+	CodeTanTooManyAttempts              = "TAN_TOO_MANY_ATTEMPTS"
+	CodeTransferSignatureRequired       = "TRANSFER_SIGNATURE_REQUIRED"
+	CodeTransferSignatureInvalid        = "TRANSFER_SIGNATURE_INVALID"
 	CodeAccountNotFound                 = "ACCOUNT_NOT_FOUND"
 	CodeUserNotFound                    = "USER_NOT_FOUND"
 	CodeDuplicateTransferTemplate       = "DUPLICATE_TRANSFER_TEMPLATE"
@@ -33,10 +42,43 @@ const (
 	CodeRatesDoNotMatch                 = "RATES_DO_NOT_MATCH"
 	CodeInvalidExchangeRate             = "INVALID_EXCHANGE_RATE"
 	CodeExchangeRateNotFound            = "EXCHANGE_RATE_NOT_FOUND"
+	CodeExchangeRateStale               = "EXCHANGE_RATE_STALE"
+	CodeRateQuoteNotFound               = "RATE_QUOTE_NOT_FOUND"
+	CodeRateQuoteExpired                = "RATE_QUOTE_EXPIRED"
+	CodeConvertSameCurrency             = "CONVERT_SAME_CURRENCY"
+	CodeExchangeMarginNotFound          = "EXCHANGE_MARGIN_NOT_FOUND"
+	CodeOurChargeNotConfigured          = "OUR_CHARGE_NOT_CONFIGURED"
+	CodeInvalidExchangeMargin           = "INVALID_EXCHANGE_MARGIN"
+	CodeInvalidDateRange                = "INVALID_DATE_RANGE"
+	CodeFeeExceedsAmount                = "FEE_EXCEEDS_AMOUNT"
+	CodeWireExportInvalid               = "WIRE_EXPORT_INVALID"
+	CodeWireOrderingBicMissing          = "WIRE_ORDERING_BIC_MISSING"
+	CodeIncomingPaymentNotFound         = "INCOMING_PAYMENT_NOT_FOUND"
+	CodeIncomingPaymentProcessed        = "INCOMING_PAYMENT_PROCESSED"
+	CodeIncomingPaymentNotCredited      = "INCOMING_PAYMENT_NOT_CREDITED"
+	CodeTermDepositNotMatured           = "TERM_DEPOSIT_NOT_MATURED"
+	CodeInterestRateNotFound            = "INTEREST_RATE_NOT_FOUND"
+	CodeInterestRateAlreadyEffective    = "INTEREST_RATE_ALREADY_EFFECTIVE"
+	CodeInterestRateDateInPast          = "INTEREST_RATE_DATE_IN_PAST"
+	CodeInvalidInterestRateTiers        = "INVALID_INTEREST_RATE_TIERS"
+	CodeScheduledTransactionNotPending  = "SCHEDULED_TRANSACTION_NOT_PENDING"
+	CodeScheduledTransactionNotFee      = "SCHEDULED_TRANSACTION_NOT_FEE"
+	CodeScheduledDateInPast             = "SCHEDULED_DATE_IN_PAST"
+	CodeInvalidScheduledAmount          = "INVALID_SCHEDULED_AMOUNT"
+	CodeInvalidSettingValue             = "INVALID_SETTING_VALUE"
+	CodeInvalidOverdraftLimit           = "INVALID_OVERDRAFT_LIMIT"
+	CodeAccountDormant                  = "ACCOUNT_DORMANT"
+	CodeAccountNotDormant               = "ACCOUNT_NOT_DORMANT"
+	CodeAccountClosed                   = "ACCOUNT_CLOSED"
+	CodeClosureSweepRequired            = "CLOSURE_SWEEP_REQUIRED"
+	CodeInvalidSweepAccount             = "INVALID_SWEEP_ACCOUNT"
+	CodeClosureNegativeBalance          = "CLOSURE_NEGATIVE_BALANCE"
 	CodeTemplateNotFound                = "TEMPLATE_NOT_FOUND"
 	CodeCardNotFound                    = "CARD_NOT_FOUND"
 	CodeDuplicateCardNumber             = "DUPLICATE_CARD_NUMBER"
 	CodeInvalidCardOwner                = "INVALID_CARD_OWNER"
+	CodeCardExpired                     = "CARD_EXPIRED"
+	CodeCardCurrencyNotAllowed          = "CARD_CURRENCY_NOT_ALLOWED"
 	CodeInvalidTemplate                 = "INVALID_TEMPLATE"
 	CodeCardTypeCategoryNotFound        = "CARD_TYPE_CATEGORY_NOT_FOUND"
 	CodeCardTypeFormatNotFound          = "CARD_TYPE_FORMAT_NOT_FOUND"
@@ -75,6 +117,15 @@ var statusCodes = map[string]int{
 	CodeTanInvalid:                      http.StatusForbidden,
 	CodeTanNotificationMethodNotAllowed: http.StatusForbidden,
 	CodeTanRequestNotAllowed:            http.StatusUnprocessableEntity,
+	CodeTanMethodNotAllowed:             http.StatusForbidden,
+	CodeTanUnknownMethod:                http.StatusBadRequest,
+	CodeTanApprovalNotFound:             http.StatusNotFound,
+	CodeTanApprovalSelf:                 http.StatusForbidden,
+	CodeTotpNotEnrolled:                 http.StatusUnprocessableEntity,
+	CodeTanLocked:                       http.StatusForbidden,
+	CodeTanTooManyAttempts:              http.StatusTooManyRequests,
+	CodeTransferSignatureRequired:       http.StatusBadRequest,
+	CodeTransferSignatureInvalid:        http.StatusForbidden,
 	CodeAccountNotFound:                 http.StatusNotFound,
 	CodeUserNotFound:                    http.StatusNotFound,
 	CodeDuplicateTransferTemplate:       http.StatusConflict,
@@ -101,9 +152,43 @@ var statusCodes = map[string]int{
 	CodeRatesDoNotMatch:                 http.StatusBadRequest,
 	CodeInvalidExchangeRate:             http.StatusBadRequest,
 	CodeExchangeRateNotFound:            http.StatusNotFound,
+	CodeExchangeRateStale:               http.StatusServiceUnavailable,
+	CodeRateQuoteNotFound:               http.StatusNotFound,
+	CodeRateQuoteExpired:                http.StatusUnprocessableEntity,
+	CodeConvertSameCurrency:             http.StatusBadRequest,
+	CodeExchangeMarginNotFound:          http.StatusNotFound,
+	CodeOurChargeNotConfigured:          http.StatusUnprocessableEntity,
+	CodeInvalidExchangeMargin:           http.StatusBadRequest,
+	CodeInvalidDateRange:                http.StatusBadRequest,
+	CodeFeeExceedsAmount:                http.StatusUnprocessableEntity,
+	CodeWireExportInvalid:               http.StatusUnprocessableEntity,
+	CodeWireOrderingBicMissing:          http.StatusUnprocessableEntity,
+	CodeIncomingPaymentNotFound:         http.StatusNotFound,
+	CodeIncomingPaymentProcessed:        http.StatusUnprocessableEntity,
+	CodeIncomingPaymentNotCredited:      http.StatusUnprocessableEntity,
+	CodeTermDepositNotMatured:           http.StatusUnprocessableEntity,
+	CodeInterestRateNotFound:            http.StatusNotFound,
+	CodeInterestRateAlreadyEffective:    http.StatusUnprocessableEntity,
+	CodeInterestRateDateInPast:          http.StatusUnprocessableEntity,
+	CodeInvalidInterestRateTiers:        http.StatusBadRequest,
+	CodeScheduledTransactionNotPending:  http.StatusUnprocessableEntity,
+	CodeScheduledTransactionNotFee:      http.StatusUnprocessableEntity,
+	CodeScheduledDateInPast:             http.StatusUnprocessableEntity,
+	CodeInvalidScheduledAmount:          http.StatusBadRequest,
+	CodeInvalidOverdraftLimit:           http.StatusBadRequest,
+	CodeAccountDormant:                  http.StatusUnprocessableEntity,
+	CodeAccountNotDormant:               http.StatusUnprocessableEntity,
+	CodeAccountClosed:                   http.StatusUnprocessableEntity,
+	CodeClosureSweepRequired:            http.StatusUnprocessableEntity,
+	CodeInvalidSweepAccount:             http.StatusUnprocessableEntity,
+	CodeClosureNegativeBalance:          http.StatusUnprocessableEntity,
+	CodeCurrencyMismatch:                http.StatusUnprocessableEntity,
+	CodeInvalidSettingValue:             http.StatusBadRequest,
 	CodeTemplateNotFound:                http.StatusNotFound,
 	CodeCardNotFound:                    http.StatusNotFound,
 	CodeInvalidCardOwner:                http.StatusBadRequest,
+	CodeCardExpired:                     http.StatusUnprocessableEntity,
+	CodeCardCurrencyNotAllowed:          http.StatusUnprocessableEntity,
 	CodeInvalidTemplate:                 http.StatusBadRequest,
 	CodeCardTypeCategoryNotFound:        http.StatusNotFound,
 	CodeCardTypeFormatNotFound:          http.StatusNotFound,
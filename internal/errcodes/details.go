@@ -5,6 +5,15 @@ var details = map[string]string{
 	CodeTanInvalid:                      "Provided TAN is incorrect or has already been used.",
 	CodeTanNotificationMethodNotAllowed: "Notification method is not allowed.",
 	CodeTanRequestNotAllowed:            "TAN request is not allowed.",
+	CodeTanMethodNotAllowed:             "Your confirmation method is not allowed for this operation.",
+	CodeTanUnknownMethod:                "Unknown confirmation method.",
+	CodeTanApprovalNotFound:             "Approval request was not found or has expired.",
+	CodeTanApprovalSelf:                 "Approval request must be approved by another user.",
+	CodeTotpNotEnrolled:                 "Authenticator application is not set up.",
+	CodeTanLocked:                       "Confirmation is locked because of too many failed attempts. Please contact administrator.",
+	CodeTanTooManyAttempts:              "Too many failed confirmation attempts. Please try again later.",
+	CodeTransferSignatureRequired:       "The request was missing required headers X-Signing-Challenge and X-Signing-Code.",
+	CodeTransferSignatureInvalid:        "Provided signing code is incorrect, expired or does not match the transfer.",
 	CodeAccountNotFound:                 "Account was not found.",
 	CodeUserNotFound:                    "User was not found",
 	CodeDuplicateTransferTemplate:       "Template with the same name and request subject is already exist.",
@@ -15,6 +24,40 @@ var details = map[string]string{
 	CodeDuplicateTransferFee:            "Transfer fee with the same name and request subject is already exist.",
 	CodeUnknownRequestSubject:           "Unknown request subject.",
 	CodeAccountInactive:                 "Account is not active.",
+	CodeCardExpired:                     "Card is expired.",
+	CodeCardCurrencyNotAllowed:          "The currency is not allowed by the card controls.",
 	CodeLimitExceeded:                   "The requested action could not be performed due to the limitations that will be exceeded as a result of this action.",
 	CodeExchangeRateNotFound:            "The requested action requires a currency exchange rate that is currently not available.",
+	CodeExchangeRateStale:               "The currency exchange rate is outdated. Please try again later.",
+	CodeRateQuoteNotFound:               "Exchange rate quote was not found or has been already used.",
+	CodeRateQuoteExpired:                "Exchange rate quote has expired. Please preview the transfer again.",
+	CodeConvertSameCurrency:             "Accounts must have different currencies in order to convert.",
+	CodeExchangeMarginNotFound:          "Exchange margin is not found.",
+	CodeOurChargeNotConfigured:          "Charges of other banks are not configured, transfers where the sender bears all charges are not available.",
+	CodeInvalidExchangeMargin:           "Exchange margin percent must be between 0 and 100.",
+	CodeInvalidDateRange:                "Date range is invalid. Dates must be in YYYY-MM-DD format and the start must not be after the end.",
+	CodeFeeExceedsAmount:                "Transfer fee deducted from the amount exceeds the amount. Increase the amount or choose another charge bearer.",
+	CodeWireExportInvalid:               "Some requests could not be exported. Only pending outgoing wire transfers with complete details which have not been exported yet could be exported.",
+	CodeWireOrderingBicMissing:          "BIC of the ordering bank is not configured.",
+	CodeIncomingPaymentNotFound:         "Incoming payment was not found.",
+	CodeIncomingPaymentProcessed:        "Incoming payment has been already processed.",
+	CodeIncomingPaymentNotCredited:      "Account could not be credited with the incoming payment.",
+	CodeTermDepositNotMatured:           "Withdrawals from the term deposit are not allowed before its maturity date.",
+	CodeInterestRateNotFound:            "Interest rate was not found.",
+	CodeInterestRateAlreadyEffective:    "Interest rate which has already become effective can not be removed.",
+	CodeInterestRateDateInPast:          "Interest rate change can become effective only from a future date.",
+	CodeInvalidInterestRateTiers:        "The first tier must start from zero balance and minimum balances of the next tiers must increase.",
+	CodeScheduledTransactionNotPending:  "Only pending scheduled transaction can be changed or executed.",
+	CodeScheduledTransactionNotFee:      "Only scheduled fees can be waived or have their amount adjusted.",
+	CodeScheduledDateInPast:             "Scheduled date can not be in the past.",
+	CodeInvalidScheduledAmount:          "Amount of the scheduled fee must be negative.",
+	CodeInvalidSettingValue:             "Value of the setting is invalid.",
+	CodeInvalidOverdraftLimit:           "Overdraft limit must not be negative.",
+	CodeAccountDormant:                  "Outgoing transfers from the dormant account are not allowed until it is reactivated.",
+	CodeAccountNotDormant:               "The account is not dormant.",
+	CodeAccountClosed:                   "The account is closed.",
+	CodeClosureSweepRequired:            "Remaining balance must be swept to another account or by outgoing wire transfer.",
+	CodeInvalidSweepAccount:             "Remaining balance can be swept only to another active account of the same owner in the same currency.",
+	CodeClosureNegativeBalance:          "The account with negative balance can not be closed.",
+	CodeCurrencyMismatch:                "Currencies do not match.",
 }
@@ -1,35 +1,139 @@
 package exchange
 
-import "github.com/pkg/errors"
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CachePolicy defines how long cached rates are used
+type CachePolicy interface {
+	// TTL returns how long rate of the given pair is taken from cache without asking the source, zero means forever
+	TTL(base, reference string) time.Duration
+	// MaxStaleness returns maximum age of a rate (since it was fetched from its origin) which could be used,
+	// zero means no limit
+	MaxStaleness() time.Duration
+}
+
+// CacheMetrics receives results of cache lookups
+type CacheMetrics interface {
+	Hit(base, reference string)
+	Miss(base, reference string)
+}
+
+type cacheEntry struct {
+	rate     Rate
+	cachedAt time.Time
+}
 
 // cacheSource is used in order to preserve rates found from the given source
 type cacheSource struct {
 	topSource RateSource
-	cache     RateSourceAndReceiver
+	policy    CachePolicy
+	metrics   CacheMetrics
+	now       func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
 }
 
-// NewCacheSource is cache source constructor, it wraps passed rate source with cache
+// NewCacheSource is cache source constructor, it wraps passed rate source with cache which never expires
 func NewCacheSource(topSource RateSource) RateSource {
+	return NewCacheSourceWithPolicy(topSource, nil, nil)
+}
+
+// NewCacheSourceWithPolicy wraps passed rate source with cache which expires according to the given policy.
+// Both policy and metrics are optional.
+func NewCacheSourceWithPolicy(topSource RateSource, policy CachePolicy, metrics CacheMetrics) RateSource {
 	return &cacheSource{
 		topSource: topSource,
-		cache:     NewDirectRateSource(),
+		policy:    policy,
+		metrics:   metrics,
+		now:       time.Now,
+		entries:   make(map[string]cacheEntry),
 	}
 }
 
-// FindRate tries to fetch source from cache first
+// FindRate tries to fetch source from cache first, the cached rate which is older than max staleness
+// is fetched again even if it has not expired yet.
+// If the source fails the expired cached rate is still used while it is not older than max staleness.
 func (c *cacheSource) FindRate(base, reference string) (Rate, error) {
-	rate, err := c.cache.FindRate(base, reference)
-	if err == nil {
-		return rate, nil
+	now := c.now()
+	key := pairKey(base, reference)
+
+	c.mu.RLock()
+	entry, cached := c.entries[key]
+	c.mu.RUnlock()
+
+	if cached && c.isFresh(entry, now) {
+		if rate, err := c.checkStaleness(entry.rate, now); err == nil {
+			c.hit(base, reference)
+			return rate, nil
+		}
 	}
+	c.miss(base, reference)
+
 	nilRate := Rate{}
-	rate, err = c.topSource.FindRate(base, reference)
-	if err == nil {
-		err = c.cache.Set(rate)
-		if err != nil {
-			return nilRate, errors.Wrap(err, "failed to set rate to cache")
+	rate, err := c.topSource.FindRate(base, reference)
+	if err != nil {
+		if cached {
+			if _, staleErr := c.checkStaleness(entry.rate, now); staleErr == nil {
+				return entry.rate, nil
+			}
 		}
+		return nilRate, errors.Wrap(err, "failed to retrieve rate from source")
+	}
+	if rate.fetchedAt.IsZero() {
+		rate.fetchedAt = now
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{rate: rate, cachedAt: now}
+	c.mu.Unlock()
+
+	return c.checkStaleness(rate, now)
+}
+
+func (c *cacheSource) isFresh(entry cacheEntry, now time.Time) bool {
+	if c.policy == nil {
+		return true
+	}
+	ttl := c.policy.TTL(entry.rate.base, entry.rate.reference)
+	return ttl <= 0 || now.Sub(entry.cachedAt) < ttl
+}
+
+func (c *cacheSource) checkStaleness(rate Rate, now time.Time) (Rate, error) {
+	if c.policy == nil || rate.fetchedAt.IsZero() {
 		return rate, nil
 	}
-	return nilRate, errors.Wrap(err, "failed to retrieve rate from source")
+	maxStaleness := c.policy.MaxStaleness()
+	if maxStaleness > 0 && now.Sub(rate.fetchedAt) > maxStaleness {
+		return Rate{}, errors.Wrapf(
+			ErrRateStale,
+			"rate %s -> %s fetched at %s from %q is older than %s",
+			rate.base,
+			rate.reference,
+			rate.fetchedAt.Format(time.RFC3339),
+			rate.source,
+			maxStaleness,
+		)
+	}
+	return rate, nil
+}
+
+func (c *cacheSource) hit(base, reference string) {
+	if c.metrics != nil {
+		c.metrics.Hit(base, reference)
+	}
+}
+
+func (c *cacheSource) miss(base, reference string) {
+	if c.metrics != nil {
+		c.metrics.Miss(base, reference)
+	}
+}
+
+func pairKey(base, reference string) string {
+	return base + "/" + reference
 }
@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StaticCachePolicy is CachePolicy with fixed values
+type StaticCachePolicy struct {
+	// DefaultTTL is used for pairs which are not listed in PairTTL
+	DefaultTTL time.Duration
+	// PairTTL contains TTL by pair in "BASE/REFERENCE" format
+	PairTTL map[string]time.Duration
+	// MaxAge is maximum staleness of a rate
+	MaxAge time.Duration
+}
+
+// TTL returns TTL of the given pair
+func (p *StaticCachePolicy) TTL(base, reference string) time.Duration {
+	if ttl, ok := p.PairTTL[pairKey(base, reference)]; ok {
+		return ttl
+	}
+	return p.DefaultTTL
+}
+
+// MaxStaleness returns maximum age of a rate
+func (p *StaticCachePolicy) MaxStaleness() time.Duration {
+	return p.MaxAge
+}
+
+// CacheStats contains numbers of cache lookups
+type CacheStats struct {
+	Hits         uint64            `json:"hits"`
+	Misses       uint64            `json:"misses"`
+	MissesByPair map[string]uint64 `json:"missesByPair"`
+}
+
+// CacheCounter is CacheMetrics which counts hits and misses in memory
+type CacheCounter struct {
+	hits   uint64
+	misses uint64
+
+	mu           sync.Mutex
+	missesByPair map[string]uint64
+}
+
+func NewCacheCounter() *CacheCounter {
+	return &CacheCounter{missesByPair: make(map[string]uint64)}
+}
+
+func (c *CacheCounter) Hit(base, reference string) {
+	atomic.AddUint64(&c.hits, 1)
+}
+
+func (c *CacheCounter) Miss(base, reference string) {
+	atomic.AddUint64(&c.misses, 1)
+	c.mu.Lock()
+	c.missesByPair[pairKey(base, reference)]++
+	c.mu.Unlock()
+}
+
+// Stats returns snapshot of the counters
+func (c *CacheCounter) Stats() CacheStats {
+	c.mu.Lock()
+	pairs := make(map[string]uint64, len(c.missesByPair))
+	for pair, count := range c.missesByPair {
+		pairs[pair] = count
+	}
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:         atomic.LoadUint64(&c.hits),
+		Misses:       atomic.LoadUint64(&c.misses),
+		MissesByPair: pairs,
+	}
+}
@@ -10,4 +10,5 @@ func (e Error) Error() string {
 
 const (
 	ErrRateNotFound = Error("rate not found")
+	ErrRateStale    = Error("rate is stale")
 )
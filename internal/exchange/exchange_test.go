@@ -10,6 +10,7 @@ import (
 	"github.com/onsi/gomega/types"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
+	"time"
 )
 
 var (
@@ -195,6 +196,183 @@ var _ = Describe("Rate", func() {
 			}
 
 		})
+
+		It("fallback source should find rate in the second source if the first one fails", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			primarySource := mock_exchange.NewMockRateSource(ctrl)
+			primarySource.
+				EXPECT().
+				FindRate("EUR", "USD").
+				Return(NewRate("EUR", "USD", str2Dec("1.11")), nil)
+			primarySource.
+				EXPECT().
+				FindRate("EUR", "JPY").
+				Return(Rate{}, errors.New("service unavailable")).
+				Times(2)
+
+			fallbackSource := NewDirectRateSource()
+			_ = fallbackSource.Set(NewRate("EUR", "USD", decimal2))
+			_ = fallbackSource.Set(NewRate("EUR", "JPY", decimal4))
+
+			source := NewFallbackSource(primarySource, fallbackSource)
+
+			rate, err := source.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.Rate()).To(decEqual(str2Dec("1.11")))
+
+			rate, err = source.FindRate("EUR", "JPY")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.BaseCurrencyCode()).To(Equal("EUR"))
+			Expect(rate.ReferenceCurrencyCode()).To(Equal("JPY"))
+			Expect(rate.Rate()).To(decEqual(str2Dec("4")))
+
+			_, err = NewFallbackSource(primarySource, NewDirectRateSource()).FindRate("EUR", "JPY")
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Cause(err)).To(Equal(ErrRateNotFound))
+		})
+	})
+
+	Context("Checking time-aware cache", func() {
+		start := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+
+		It("should refetch rates after TTL of the pair", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockSource := mock_exchange.NewMockRateSource(ctrl)
+			mockSource.
+				EXPECT().
+				FindRate("EUR", "USD").
+				Return(NewRate("EUR", "USD", str2Dec("1.11")), nil).
+				Times(2)
+			mockSource.
+				EXPECT().
+				FindRate("EUR", "JPY").
+				Return(NewRate("EUR", "JPY", str2Dec("121")), nil).
+				Times(1)
+
+			now := start
+			counter := NewCacheCounter()
+			policy := &StaticCachePolicy{
+				DefaultTTL: time.Hour,
+				PairTTL:    map[string]time.Duration{"EUR/USD": time.Minute},
+			}
+			cached := NewCacheSourceWithPolicy(mockSource, policy, counter)
+			SetCacheSourceClock(cached, func() time.Time { return now })
+
+			rate, err := cached.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.FetchedAt()).To(Equal(start))
+			_, _ = cached.FindRate("EUR", "JPY")
+
+			now = start.Add(30 * time.Second)
+			_, _ = cached.FindRate("EUR", "USD")
+			_, _ = cached.FindRate("EUR", "JPY")
+
+			now = start.Add(2 * time.Minute)
+			rate, err = cached.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.FetchedAt()).To(Equal(now))
+			_, _ = cached.FindRate("EUR", "JPY")
+
+			stats := counter.Stats()
+			Expect(stats.Hits).To(BeEquivalentTo(3))
+			Expect(stats.Misses).To(BeEquivalentTo(3))
+			Expect(stats.MissesByPair["EUR/USD"]).To(BeEquivalentTo(2))
+		})
+
+		It("should use expired rate while the source fails until it becomes stale", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockSource := mock_exchange.NewMockRateSource(ctrl)
+			mockSource.
+				EXPECT().
+				FindRate("EUR", "USD").
+				Return(Rate{}, errors.New("service unavailable")).
+				Times(2).
+				After(
+					mockSource.
+						EXPECT().
+						FindRate("EUR", "USD").
+						Return(NewRate("EUR", "USD", str2Dec("1.11")).WithOrigin("currencies", start), nil),
+				)
+
+			now := start
+			policy := &StaticCachePolicy{DefaultTTL: time.Minute, MaxAge: time.Hour}
+			cached := NewCacheSourceWithPolicy(mockSource, policy, nil)
+			SetCacheSourceClock(cached, func() time.Time { return now })
+
+			_, err := cached.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			now = start.Add(30 * time.Minute)
+			rate, err := cached.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.Source()).To(Equal("currencies"))
+			Expect(rate.Rate()).To(decEqual(str2Dec("1.11")))
+
+			now = start.Add(2 * time.Hour)
+			_, err = cached.FindRate("EUR", "USD")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should refetch not expired rate once it becomes stale", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockSource := mock_exchange.NewMockRateSource(ctrl)
+			mockSource.
+				EXPECT().
+				FindRate("EUR", "USD").
+				Return(NewRate("EUR", "USD", str2Dec("1.12")).WithOrigin("currencies", start.Add(2*time.Hour)), nil).
+				After(
+					mockSource.
+						EXPECT().
+						FindRate("EUR", "USD").
+						Return(NewRate("EUR", "USD", str2Dec("1.11")).WithOrigin("currencies", start), nil),
+				)
+
+			now := start
+			counter := NewCacheCounter()
+			policy := &StaticCachePolicy{DefaultTTL: 24 * time.Hour, MaxAge: time.Hour}
+			cached := NewCacheSourceWithPolicy(mockSource, policy, counter)
+			SetCacheSourceClock(cached, func() time.Time { return now })
+
+			_, err := cached.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			now = start.Add(2 * time.Hour)
+			rate, err := cached.FindRate("EUR", "USD")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.Rate()).To(decEqual(str2Dec("1.12")))
+			Expect(counter.Stats().Misses).To(BeEquivalentTo(2))
+		})
+
+		It("should refuse rates older than max staleness", func() {
+			directSource := NewDirectRateSource()
+			_ = directSource.Set(NewRate("EUR", "USD", decimal2).WithOrigin("local", start.Add(-2*time.Hour)))
+
+			cached := NewCacheSourceWithPolicy(directSource, &StaticCachePolicy{MaxAge: time.Hour}, nil)
+			SetCacheSourceClock(cached, func() time.Time { return start })
+
+			_, err := cached.FindRate("EUR", "USD")
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Cause(err)).To(Equal(ErrRateStale))
+		})
+
+		It("derived rates should be as old as the oldest rate", func() {
+			directSource := NewDirectRateSource()
+			_ = directSource.Set(NewRate("EUR", "USD", decimal2).WithOrigin("ecb", start))
+			_ = directSource.Set(NewRate("EUR", "JPY", decimal4).WithOrigin("ecb", start.Add(-time.Hour)))
+
+			rate, err := NewPivotRateSource("EUR", directSource).FindRate("USD", "JPY")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rate.FetchedAt()).To(Equal(start.Add(-time.Hour)))
+			Expect(rate.Source()).To(Equal("ecb"))
+		})
 	})
 })
 
@@ -0,0 +1,8 @@
+package exchange
+
+import "time"
+
+// SetCacheSourceClock replaces clock of the cache source created by NewCacheSourceWithPolicy
+func SetCacheSourceClock(source RateSource, now func() time.Time) {
+	source.(*cacheSource).now = now
+}
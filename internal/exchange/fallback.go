@@ -0,0 +1,37 @@
+package exchange
+
+import "github.com/pkg/errors"
+
+// fallbackSource is used in order to find rate in the secondary source if the primary one fails
+// e.g. when a remote service is unavailable
+type fallbackSource struct {
+	primary  RateSource
+	fallback RateSource
+}
+
+// NewFallbackSource is fallback source constructor
+func NewFallbackSource(primary, fallback RateSource) RateSource {
+	return &fallbackSource{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// FindRate tries to fetch rate from the primary source first
+func (f *fallbackSource) FindRate(base, reference string) (Rate, error) {
+	rate, err := f.primary.FindRate(base, reference)
+	if err == nil {
+		return rate, nil
+	}
+	rate, fallbackErr := f.fallback.FindRate(base, reference)
+	if fallbackErr == nil {
+		return rate, nil
+	}
+	return Rate{}, errors.Wrapf(
+		fallbackErr,
+		"failed to retrieve rate %s -> %s from fallback source (primary source error: %s)",
+		base,
+		reference,
+		err,
+	)
+}
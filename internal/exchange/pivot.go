@@ -40,9 +40,5 @@ func (p *pivotRateSource) indirectRate(base, reference string) (Rate, error) {
 		return nilRate, err
 	}
 	ratio := referenceRate.Rate().Div(baseRate.Rate())
-	return Rate{
-		base:      base,
-		reference: reference,
-		rate:      ratio,
-	}, nil
+	return derivedRate(base, reference, ratio, baseRate, referenceRate), nil
 }
@@ -1,12 +1,18 @@
 package exchange
 
-import "github.com/shopspring/decimal"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 // Rate defines exchange rate between base and reference codes
 type Rate struct {
 	base      string
 	reference string
 	rate      decimal.Decimal
+	fetchedAt time.Time
+	source    string
 }
 
 // NewRate is Rate constructor
@@ -32,3 +38,36 @@ func (r *Rate) ReferenceCurrencyCode() string {
 func (r *Rate) Rate() decimal.Decimal {
 	return r.rate
 }
+
+// FetchedAt returns time when the rate has been obtained from its origin, zero time means unknown
+func (r *Rate) FetchedAt() time.Time {
+	return r.fetchedAt
+}
+
+// Source returns name of the rate origin e.g. "currencies"
+func (r *Rate) Source() string {
+	return r.source
+}
+
+// WithOrigin returns copy of the rate with the given source and fetch time
+func (r Rate) WithOrigin(source string, fetchedAt time.Time) Rate {
+	r.source = source
+	r.fetchedAt = fetchedAt
+	return r
+}
+
+// derivedRate creates rate calculated from the given ones, it is as old as the oldest of them
+func derivedRate(base, reference string, rate decimal.Decimal, from ...Rate) Rate {
+	result := NewRate(base, reference, rate)
+	for _, r := range from {
+		if !r.fetchedAt.IsZero() && (result.fetchedAt.IsZero() || r.fetchedAt.Before(result.fetchedAt)) {
+			result.fetchedAt = r.fetchedAt
+		}
+		if result.source == "" {
+			result.source = r.source
+		} else if r.source != "" && r.source != result.source {
+			result.source += "+" + r.source
+		}
+	}
+	return result
+}
@@ -34,9 +34,5 @@ func (r *reverseRateSource) FindRate(base, reference string) (Rate, error) {
 	if err != nil {
 		return Rate{}, errors.Wrapf(err, "failed to find reverse rate %s -> %s", base, reference)
 	}
-	return Rate{
-		base:      base,
-		reference: reference,
-		rate:      decimal.NewFromInt(1).Div(reverseRate.Rate()),
-	}, nil
+	return derivedRate(base, reference, decimal.NewFromInt(1).Div(reverseRate.Rate()), reverseRate), nil
 }
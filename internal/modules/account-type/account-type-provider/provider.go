@@ -9,8 +9,11 @@ import (
 func Providers() []interface{} {
 	return []interface{}{
 		service.NewAccountTypeService,
+		service.NewInterestRateService,
 		repository.NewAccountTypeRepository,
+		repository.NewInterestRateRepository,
 
 		handler.NewAccountTypeHandler,
+		handler.NewInterestRateHandler,
 	}
 }
@@ -0,0 +1,49 @@
+package form
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+)
+
+const effectiveFromLayout = "2006-01-02"
+
+// InterestRate schedules a change of the interest rate of the account type starting from EffectiveFrom date
+type InterestRate struct {
+	Kind          *string             `json:"kind" binding:"required,oneof=deposit credit"`
+	EffectiveFrom *string             `json:"effectiveFrom" binding:"required,datetime=2006-01-02"`
+	Tiers         []*InterestRateTier `json:"tiers" binding:"required,min=1,dive"`
+}
+
+type InterestRateTier struct {
+	MinBalance *string `json:"minBalance" binding:"required,decimal"`
+	Rate       *string `json:"rate" binding:"required,decimal"`
+}
+
+func (f *InterestRate) ToModel(accountTypeId uint64) (*model.InterestRate, error) {
+	effectiveFrom, err := time.Parse(effectiveFromLayout, *f.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := &model.InterestRate{
+		AccountTypeId: &accountTypeId,
+		Kind:          f.Kind,
+		EffectiveFrom: &effectiveFrom,
+		Tiers:         make([]*model.InterestRateTier, len(f.Tiers)),
+	}
+	for i, tier := range f.Tiers {
+		minBalance, err := decimal.NewFromString(*tier.MinBalance)
+		if err != nil {
+			return nil, err
+		}
+		annualRate, err := decimal.NewFromString(*tier.Rate)
+		if err != nil {
+			return nil, err
+		}
+		rate.Tiers[i] = &model.InterestRateTier{MinBalance: &minBalance, Rate: &annualRate}
+	}
+	return rate, nil
+}
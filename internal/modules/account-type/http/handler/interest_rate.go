@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/form"
+	serviceAccountType "github.com/Confialink/wallet-accounts/internal/modules/account-type/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+)
+
+type InterestRateHandler struct {
+	service        *serviceAccountType.InterestRateService
+	contextService appHttpService.ContextInterface
+	logger         log15.Logger
+}
+
+func NewInterestRateHandler(
+	service *serviceAccountType.InterestRateService,
+	contextService appHttpService.ContextInterface,
+	logger log15.Logger,
+) *InterestRateHandler {
+	return &InterestRateHandler{
+		service:        service,
+		contextService: contextService,
+		logger:         logger.New("Handler", "account_type.InterestRateHandler"),
+	}
+}
+
+// ListHandler returns history of interest rates of the account type including scheduled changes,
+// it may be filtered by kind query parameter
+func (h *InterestRateHandler) ListHandler(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	rates, err := h.service.History(id, c.Query("kind"))
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(rates))
+}
+
+// CreateHandler schedules a change of the interest rate of the account type
+func (h *InterestRateHandler) CreateHandler(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	var rateForm form.InterestRate
+	if err := c.ShouldBindJSON(&rateForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	rate, err := h.service.Schedule(id, &rateForm, time.Now())
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.New().SetData(rate))
+}
+
+// DeleteHandler cancels the interest rate change which has not become effective yet
+func (h *InterestRateHandler) DeleteHandler(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	rateId, err := strconv.ParseUint(c.Param("rateId"), 10, 64)
+	if err != nil {
+		errors.AddErrors(c, errcodes.CreatePublicError(errcodes.CodeNumeric, "rateId param must be an integer"))
+		return
+	}
+
+	if err := h.service.Cancel(id, rateId, time.Now()); err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
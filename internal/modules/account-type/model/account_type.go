@@ -43,6 +43,10 @@ type AccountTypePublic struct {
 	AutoNumberGeneration      *bool                                  `json:"autoNumberGeneration"`
 	NumberPrefix              *string                                `json:"numberPrefix" binding:"omitempty"`
 	MonthlyMaintenanceFee     *decimal.Decimal                       `json:"monthlyMaintenanceFee"`
+	// UnarrangedOverdraftFee is charged for each day the balance goes below the arranged overdraft limit
+	UnarrangedOverdraftFee *decimal.Decimal `json:"unarrangedOverdraftFee"`
+	// DormancyFee is charged monthly while the account is dormant
+	DormancyFee *decimal.Decimal `json:"dormancyFee"`
 }
 
 type AccountTypePrivate struct {
@@ -72,6 +76,8 @@ type AccountTypeEditable struct {
 	NumberPrefix              *string          `json:"numberPrefix" binding:"omitempty"`
 	MonthlyMaintenanceFee     *decimal.Decimal `json:"monthlyMaintenanceFee"`
 	MinimumBalance            *decimal.Decimal `json:"minimumBalance"`
+	UnarrangedOverdraftFee    *decimal.Decimal `json:"unarrangedOverdraftFee" binding:"omitempty,numeric"`
+	DormancyFee               *decimal.Decimal `json:"dormancyFee" binding:"omitempty,numeric"`
 }
 
 type AccountTypeCurrency struct {
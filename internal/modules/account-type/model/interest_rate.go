@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// InterestRateKindDeposit is accrued on positive balance
+	InterestRateKindDeposit = "deposit"
+	// InterestRateKindCredit is charged on negative balance
+	InterestRateKindCredit = "credit"
+)
+
+// InterestRate is a table of balance tiers of the account type which is valid since EffectiveFrom date
+// until the next rate of the same kind becomes effective, past rates are kept as history
+type InterestRate struct {
+	Id            *uint64             `gorm:"primary_key" json:"id"`
+	AccountTypeId *uint64             `json:"accountTypeId"`
+	Kind          *string             `json:"kind"`
+	EffectiveFrom *time.Time          `json:"effectiveFrom"`
+	Tiers         []*InterestRateTier `gorm:"foreignkey:InterestRateId" json:"tiers"`
+	CreatedAt     *time.Time          `json:"createdAt"`
+}
+
+// InterestRateTier is an annual rate applied to the part of the balance above MinBalance
+type InterestRateTier struct {
+	Id             *uint64          `gorm:"primary_key" json:"-"`
+	InterestRateId *uint64          `json:"-"`
+	MinBalance     *decimal.Decimal `json:"minBalance"`
+	Rate           *decimal.Decimal `json:"rate"`
+}
+
+func (*InterestRate) TableName() string {
+	return "account_type_interest_rates"
+}
+
+func (*InterestRateTier) TableName() string {
+	return "account_type_interest_rate_tiers"
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+)
+
+type InterestRateRepository struct {
+	db *gorm.DB
+}
+
+func NewInterestRateRepository(db *gorm.DB) *InterestRateRepository {
+	return &InterestRateRepository{db: db}
+}
+
+// Create creates the interest rate along with its tiers
+func (r *InterestRateRepository) Create(rate *model.InterestRate) error {
+	tiers := rate.Tiers
+	rate.Tiers = nil
+	if err := r.db.Create(rate).Error; err != nil {
+		return err
+	}
+	for _, tier := range tiers {
+		tier.InterestRateId = rate.Id
+		if err := r.db.Create(tier).Error; err != nil {
+			return err
+		}
+	}
+	rate.Tiers = tiers
+	return nil
+}
+
+// FindByID finds the interest rate with its tiers, nil is returned if it is not found
+func (r *InterestRateRepository) FindByID(id uint64) (*model.InterestRate, error) {
+	rate := &model.InterestRate{}
+	err := r.preloadTiers(r.db).Where("id = ?", id).First(rate).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return rate, err
+}
+
+// FindByAccountTypeId returns current, past and scheduled rates of the account type starting from the latest one,
+// rates of all kinds are returned if kind is empty
+func (r *InterestRateRepository) FindByAccountTypeId(accountTypeId uint64, kind string) ([]*model.InterestRate, error) {
+	rates := make([]*model.InterestRate, 0)
+	query := r.preloadTiers(r.db).Where("account_type_id = ?", accountTypeId)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	err := query.Order("effective_from desc").Find(&rates).Error
+	return rates, err
+}
+
+// FindEffective returns the rate of the account type valid on the given date, nil is returned if there is no such rate
+func (r *InterestRateRepository) FindEffective(accountTypeId uint64, kind string, date time.Time) (*model.InterestRate, error) {
+	rate := &model.InterestRate{}
+	err := r.preloadTiers(r.db).
+		Where("account_type_id = ? AND kind = ? AND effective_from <= ?", accountTypeId, kind, date).
+		Order("effective_from desc").
+		First(rate).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return rate, err
+}
+
+// Delete deletes the interest rate along with its tiers
+func (r *InterestRateRepository) Delete(rate *model.InterestRate) error {
+	err := r.db.Delete(&model.InterestRateTier{}, "interest_rate_id = ?", *rate.Id).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Delete(rate).Error
+}
+
+func (r InterestRateRepository) WrapContext(db *gorm.DB) *InterestRateRepository {
+	r.db = db
+	return &r
+}
+
+func (r *InterestRateRepository) preloadTiers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_balance asc")
+	})
+}
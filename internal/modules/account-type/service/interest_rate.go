@@ -0,0 +1,175 @@
+package service
+
+import (
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/calculation"
+)
+
+var oneHundred = decimal.New(100, 0)
+
+// InterestRateService manages effective-dated tiered interest rates of account types.
+// Account types without rates use their single deposit and credit annual interest rates.
+type InterestRateService struct {
+	db              *gorm.DB
+	repo            *repository.InterestRateRepository
+	accountTypeRepo *repository.AccountTypeRepository
+	logger          log15.Logger
+}
+
+func NewInterestRateService(
+	db *gorm.DB,
+	repo *repository.InterestRateRepository,
+	accountTypeRepo *repository.AccountTypeRepository,
+	logger log15.Logger,
+) *InterestRateService {
+	return &InterestRateService{
+		db:              db,
+		repo:            repo,
+		accountTypeRepo: accountTypeRepo,
+		logger:          logger.New("Service", "InterestRateService"),
+	}
+}
+
+// History returns past, current and scheduled rates of the account type
+func (s *InterestRateService) History(accountTypeId uint64, kind string) ([]*model.InterestRate, error) {
+	if err := s.checkAccountType(accountTypeId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByAccountTypeId(accountTypeId, kind)
+}
+
+// Schedule creates the rate which becomes effective at the given future date,
+// the rate scheduled for the same date is replaced. Rates effective today or earlier
+// are already used for interest accrual and can not be created or replaced.
+func (s *InterestRateService) Schedule(accountTypeId uint64, rateForm *form.InterestRate, now time.Time) (*model.InterestRate, error) {
+	if err := s.checkAccountType(accountTypeId); err != nil {
+		return nil, err
+	}
+
+	rate, err := rateForm.ToModel(accountTypeId)
+	if err != nil {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidInterestRateTiers, err.Error())
+	}
+	if !rate.EffectiveFrom.After(today(now)) {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInterestRateDateInPast)
+	}
+	if !validTiers(rate.Tiers) {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidInterestRateTiers)
+	}
+
+	tx := s.db.Begin()
+	repo := s.repo.WrapContext(tx)
+	existing, err := repo.FindEffective(accountTypeId, *rate.Kind, *rate.EffectiveFrom)
+	if err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to find interest rate")
+	}
+	if existing != nil && existing.EffectiveFrom.Equal(*rate.EffectiveFrom) {
+		if err := repo.Delete(existing); err != nil {
+			tx.Rollback()
+			return nil, errors.Wrap(err, "failed to replace interest rate")
+		}
+	}
+
+	rate.CreatedAt = &now
+	if err := repo.Create(rate); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to create interest rate")
+	}
+	return rate, tx.Commit().Error
+}
+
+// Cancel removes the rate which has not become effective yet
+func (s *InterestRateService) Cancel(accountTypeId, rateId uint64, now time.Time) error {
+	rate, err := s.repo.FindByID(rateId)
+	if err != nil {
+		return errors.Wrap(err, "failed to find interest rate")
+	}
+	if rate == nil || rate.AccountTypeId == nil || *rate.AccountTypeId != accountTypeId {
+		return errcodes.CreatePublicError(errcodes.CodeInterestRateNotFound)
+	}
+	if !rate.EffectiveFrom.After(today(now)) {
+		return errcodes.CreatePublicError(errcodes.CodeInterestRateAlreadyEffective)
+	}
+
+	tx := s.db.Begin()
+	if err := s.repo.WrapContext(tx).Delete(rate); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to delete interest rate")
+	}
+	return tx.Commit().Error
+}
+
+// AnnualRate returns annual rate of the given kind valid on the date for the balance,
+// rate of the tiered balance is blended into a single rate
+func (s *InterestRateService) AnnualRate(
+	accountType *model.AccountType,
+	kind string,
+	balance decimal.Decimal,
+	date time.Time,
+) (decimal.Decimal, error) {
+	rate, err := s.repo.FindEffective(accountType.ID, kind, date)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to find effective interest rate")
+	}
+
+	if rate == nil {
+		fallback := accountType.DepositAnnualInterestRate
+		if kind == model.InterestRateKindCredit {
+			fallback = accountType.CreditAnnualInterestRate
+		}
+		if fallback == nil {
+			return decimal.Zero, nil
+		}
+		return *fallback, nil
+	}
+
+	tiers := make([]calculation.RateTier, len(rate.Tiers))
+	for i, tier := range rate.Tiers {
+		tiers[i] = calculation.RateTier{From: *tier.MinBalance, Rate: *tier.Rate}
+	}
+	return calculation.BlendedRate(balance.Abs(), tiers), nil
+}
+
+func (s *InterestRateService) checkAccountType(accountTypeId uint64) error {
+	if _, err := s.accountTypeRepo.FindByID(accountTypeId); err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return errcodes.CreatePublicError(errcodes.CodeAccountTypeNotFound)
+		}
+		return errors.Wrap(err, "failed to find account type")
+	}
+	return nil
+}
+
+// validTiers checks that the first tier starts from zero balance,
+// minimum balances increase and rates are between 0 and 100 percent
+func validTiers(tiers []*model.InterestRateTier) bool {
+	if len(tiers) == 0 || !tiers[0].MinBalance.IsZero() {
+		return false
+	}
+	for i, tier := range tiers {
+		if tier.Rate.IsNegative() || tier.Rate.GreaterThan(oneHundred) {
+			return false
+		}
+		if i > 0 && !tier.MinBalance.GreaterThan(*tiers[i-1].MinBalance) {
+			return false
+		}
+	}
+	return true
+}
+
+// today returns the date of now as effective dates are stored in UTC
+func today(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"github.com/olebedev/emitter"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/account-type/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/account-type/service"
+)
+
+var _ = Describe("InterestRateService", func() {
+	var (
+		mock    sqlmock.Sqlmock
+		service *InterestRateService
+	)
+	now := time.Date(2020, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		db, sqlMock, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		gdb, err := gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = sqlMock
+		service = NewInterestRateService(
+			gdb,
+			repository.NewInterestRateRepository(gdb),
+			repository.NewAccountTypeRepository(gdb, emitter.New(10)),
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	rateForm := func(effectiveFrom string) *form.InterestRate {
+		kind, minBalance, rate := "deposit", "0", "1.5"
+		return &form.InterestRate{
+			Kind:          &kind,
+			EffectiveFrom: &effectiveFrom,
+			Tiers:         []*form.InterestRateTier{{MinBalance: &minBalance, Rate: &rate}},
+		}
+	}
+
+	Context("Schedule", func() {
+		BeforeEach(func() {
+			mock.ExpectQuery("SELECT \\* FROM `account_types`").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		})
+
+		It("should not schedule a rate effective today", func() {
+			_, err := service.Schedule(1, rateForm("2020-03-10"), now)
+			Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+			Expect(err.(*errors.PublicError).Code).To(Equal(errcodes.CodeInterestRateDateInPast))
+		})
+
+		It("should not schedule a rate effective in the past", func() {
+			_, err := service.Schedule(1, rateForm("2020-03-09"), now)
+			Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+			Expect(err.(*errors.PublicError).Code).To(Equal(errcodes.CodeInterestRateDateInPast))
+		})
+
+		It("should schedule a rate effective tomorrow", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT \\* FROM `account_type_interest_rates`").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectExec("INSERT INTO `account_type_interest_rates`").WillReturnResult(sqlmock.NewResult(5, 1))
+			mock.ExpectExec("INSERT INTO `account_type_interest_rate_tiers`").WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+
+			rate, err := service.Schedule(1, rateForm("2020-03-11"), now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(*rate.EffectiveFrom).To(Equal(time.Date(2020, 3, 11, 0, 0, 0, 0, time.UTC)))
+		})
+	})
+})
@@ -10,6 +10,7 @@ import (
 
 const (
 	AccountBalanceChanged = "account:balance-changed"
+	AccountReactivated    = "account:reactivated"
 )
 
 type ContextAccountBalanceChanged struct {
@@ -21,6 +22,15 @@ type ContextAccountBalanceChanged struct {
 	RequestSubject constants.Subject
 }
 
+type ContextAccountReactivated struct {
+	// database transaction context
+	DbTransaction *gorm.DB
+	// reactivated account
+	Account *model.Account
+	// event handler must specify whether processing was finished successfully
+	Error error
+}
+
 func TriggerBalanceChanged(eventEmitter *emitter.Emitter, tx *gorm.DB, subject constants.Subject, details types.Details) {
 	processed := make(map[uint64]struct{})
 	for _, detail := range details {
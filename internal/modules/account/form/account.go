@@ -18,4 +18,8 @@ type Account struct {
 	MaturityDate      *time.Time      `json:"maturityDate"`
 	PayoutDay         *uint64         `json:"payoutDay" binding:"omitempty,gte=1,lte=31"`
 	InterestAccountId *uint64         `json:"interestAccountId" binding:"omitempty"`
+	// MaturityAction is either "payout" or "rollover"
+	MaturityAction                *string          `json:"maturityAction" binding:"omitempty,oneof=payout rollover"`
+	TermMonths                    *uint64          `json:"termMonths" binding:"omitempty,gte=1,lte=600"`
+	EarlyWithdrawalPenaltyPercent *decimal.Decimal `json:"earlyWithdrawalPenaltyPercent"`
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/Confialink/wallet-accounts/internal/errcodes"
 	"github.com/Confialink/wallet-accounts/internal/modules/permission"
@@ -224,6 +225,25 @@ func (h *AccountHandler) UpdateHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response.New().SetData(updatedAccount))
 }
 
+// ReactivateHandler lifts dormancy of the requested account
+func (h *AccountHandler) ReactivateHandler(c *gin.Context) {
+	account := h.contextService.GetRequestedAccount(c)
+	if account == nil {
+		errcodes.AddError(c, errcodes.CodeAccountNotFound)
+		return
+	}
+
+	currentUser := h.contextService.MustGetCurrentUser(c)
+
+	reactivated, typedErr := h.service.Reactivate(account, currentUser, time.Now())
+	if nil != typedErr {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(reactivated))
+}
+
 // GenerateNumberHandler returns free account number
 func (h *AccountHandler) GenerateNumberHandler(c *gin.Context) {
 	f := form.GenerateNumber{}
@@ -18,6 +18,7 @@ var showListOutputFields = []interface{}{
 	"ID", "Number", "TypeID", "UserId",
 	"Description", "IsActive", "Balance", "AllowWithdrawals", "AllowDeposits",
 	"MaturityDate", "PayoutDay", "InterestAccountId", "AvailableAmount",
+	"MaturityAction", "TermMonths", "EarlyWithdrawalPenaltyPercent", "MaturedAt", "OverdraftLimit", "DormantAt", "ReactivatedAt", "ClosedAt",
 	"CreatedAt",
 	map[string][]interface{}{
 		"Type": {"ID", "Name", "CurrencyCode", "BalanceFeeAmount",
@@ -25,7 +26,7 @@ var showListOutputFields = []interface{}{
 			"CreditAnnualInterestRate", "CreditPayoutMethodID", "CreditChargePeriodID",
 			"CreditChargeDay", "DepositAnnualInterestRate", "DepositPayoutMethodID",
 			"DepositPayoutPeriodID", "DepositPayoutDay", "AutoNumberGeneration",
-			"MonthlyMaintenanceFee",
+			"MonthlyMaintenanceFee", "UnarrangedOverdraftFee", "DormancyFee",
 			map[string][]interface{}{"Currency": {"Id", "Code"}},
 		},
 		"User": {"UID", "Username", "FirstName", "LastName", "RoleName", "Email"},
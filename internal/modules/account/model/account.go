@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"github.com/pkg/errors"
 	"time"
 
@@ -16,6 +17,13 @@ func (*Account) TableName() string {
 
 const AccountStatusIsActiveTrue = "1"
 
+const (
+	// MaturityActionPayout transfers principal of the matured term deposit to the interest account
+	MaturityActionPayout = "payout"
+	// MaturityActionRollover starts a new term of the matured term deposit
+	MaturityActionRollover = "rollover"
+)
+
 // Account account model
 type Account struct {
 	AccountPublic
@@ -38,6 +46,14 @@ type AccountPublic struct {
 	InterestAccountId *uint64                       `json:"interestAccountId" binding:"omitempty"`
 	InterestAccount   *Account                      `gorm:"foreignkey:InterestAccountId;association_foreignkey:ID;association_autoupdate:false;association_save_reference:false" json:"interestAccount" binding:"omitempty"`
 	User              *userModel.User               `json:"user"`
+	// MaturityAction defines what happens with the term deposit at maturity date, it is "payout" if not set
+	MaturityAction *string `json:"maturityAction" binding:"omitempty,oneof=payout rollover"`
+	// TermMonths is length of a new term the deposit is rolled over into
+	TermMonths *uint64 `json:"termMonths" binding:"omitempty,gte=1,lte=600"`
+	// EarlyWithdrawalPenaltyPercent allows withdrawals before maturity charging the given percent of withdrawn amount
+	EarlyWithdrawalPenaltyPercent *decimal.Decimal `json:"earlyWithdrawalPenaltyPercent"`
+	// OverdraftLimit is arranged overdraft limit of the account, the credit limit of the account type is used if not set
+	OverdraftLimit *decimal.Decimal `json:"overdraftLimit"`
 }
 
 // AccountPrivate contains fields assigned automatically
@@ -47,17 +63,68 @@ type AccountPrivate struct {
 	UpdatedAt       time.Time       `json:"updatedAt"`
 	AvailableAmount decimal.Decimal `json:"availableAmount"`
 	Balance         decimal.Decimal `json:"balance"`
+	// MaturedAt is set once the matured term deposit has been paid out
+	MaturedAt *time.Time `json:"maturedAt"`
+	// DormantAt is set once the account has had no customer initiated transactions for the dormancy period
+	DormantAt *time.Time `json:"dormantAt"`
+	// ReactivatedAt is set once the dormant account has been reactivated, the dormancy period is counted since then
+	ReactivatedAt *time.Time `json:"reactivatedAt"`
+	// ClosedAt is set once the account has been settled and closed, closed accounts are kept for history
+	ClosedAt *time.Time `json:"closedAt"`
 }
 
 // AccountEditable contains fields can be modified
 type AccountEditable struct {
-	Description       *string    `json:"description"`
-	IsActive          *bool      `json:"isActive"`
-	AllowWithdrawals  *bool      `json:"allowWithdrawals"`
-	AllowDeposits     *bool      `json:"allowDeposits"`
-	MaturityDate      *time.Time `json:"maturityDate"`
-	PayoutDay         *uint64    `json:"payoutDay" binding:"omitempty,gte=1,lte=31"`
-	InterestAccountId *uint64    `json:"interestAccountId" binding:"omitempty"`
+	Description                   *string          `json:"description"`
+	IsActive                      *bool            `json:"isActive"`
+	AllowWithdrawals              *bool            `json:"allowWithdrawals"`
+	AllowDeposits                 *bool            `json:"allowDeposits"`
+	MaturityDate                  *time.Time       `json:"maturityDate"`
+	PayoutDay                     *uint64          `json:"payoutDay" binding:"omitempty,gte=1,lte=31"`
+	InterestAccountId             *uint64          `json:"interestAccountId" binding:"omitempty"`
+	MaturityAction                *string          `json:"maturityAction" binding:"omitempty,oneof=payout rollover"`
+	TermMonths                    *uint64          `json:"termMonths" binding:"omitempty,gte=1,lte=600"`
+	EarlyWithdrawalPenaltyPercent *decimal.Decimal `json:"earlyWithdrawalPenaltyPercent"`
+	OverdraftLimit                *decimal.Decimal `json:"overdraftLimit"`
+	// sent contains json names of fields sent by the client, it is nil if the struct is not decoded from json
+	sent map[string]bool
+}
+
+// UnmarshalJSON decodes fields and remembers which of them were sent
+func (e *AccountEditable) UnmarshalJSON(data []byte) error {
+	type editable AccountEditable
+	if err := json.Unmarshal(data, (*editable)(e)); err != nil {
+		return err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	e.sent = make(map[string]bool, len(fields))
+	for name := range fields {
+		e.sent[name] = true
+	}
+	return nil
+}
+
+// MarshalJSON encodes only fields which are modified so the account the result is applied to keeps others,
+// these are fields sent by the client (null clears the field) or set fields if the struct is not decoded from json
+func (e AccountEditable) MarshalJSON() ([]byte, error) {
+	type editable AccountEditable
+	data, err := json.Marshal(editable(e))
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		if (e.sent == nil && string(value) == "null") || (e.sent != nil && !e.sent[name]) {
+			delete(fields, name)
+		}
+	}
+	return json.Marshal(fields)
 }
 
 func (a *Account) BeforeCreate() {
@@ -66,6 +133,55 @@ func (a *Account) BeforeCreate() {
 	}
 }
 
+// IsTermDeposit checks whether the account is a fixed-term deposit
+func (a *Account) IsTermDeposit() bool {
+	return a.MaturityDate != nil
+}
+
+// IsMatured checks whether withdrawals from the account are not restricted by maturity date
+func (a *Account) IsMatured(now time.Time) bool {
+	return a.MaturityDate == nil || !now.Before(*a.MaturityDate)
+}
+
+// GetMaturityAction returns action performed at maturity date
+func (a *Account) GetMaturityAction() string {
+	if a.MaturityAction != nil && *a.MaturityAction == MaturityActionRollover {
+		return MaturityActionRollover
+	}
+	return MaturityActionPayout
+}
+
+// IsClosed checks whether the account has been closed
+func (a *Account) IsClosed() bool {
+	return a.ClosedAt != nil
+}
+
+// IsDormant checks whether the account has been flagged as dormant and not reactivated yet
+func (a *Account) IsDormant() bool {
+	return a.DormantAt != nil
+}
+
+// ArrangedOverdraftLimit returns the amount the balance is allowed to go below zero by, account type must be loaded
+func (a *Account) ArrangedOverdraftLimit() decimal.Decimal {
+	return ArrangedOverdraftLimit(a.OverdraftLimit, a.Type)
+}
+
+// IsInUnarrangedOverdraft checks whether the balance is below the arranged overdraft limit
+func (a *Account) IsInUnarrangedOverdraft() bool {
+	return a.Balance.Add(a.ArrangedOverdraftLimit()).IsNegative()
+}
+
+// ArrangedOverdraftLimit returns the limit of the account if it is set, otherwise the credit limit of the account type
+func ArrangedOverdraftLimit(accountLimit *decimal.Decimal, accountType *accountTypeModel.AccountType) decimal.Decimal {
+	if accountLimit != nil {
+		return *accountLimit
+	}
+	if accountType != nil && accountType.CreditLimitAmount != nil {
+		return *accountType.CreditLimitAmount
+	}
+	return decimal.Zero
+}
+
 func (a *Account) CurrentBalance() (decimal.Decimal, error) {
 	return a.Balance, nil
 }
@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	accountTypeModel "github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+	. "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+)
+
+var _ = Describe("Account", func() {
+	// dec converts the string into the decimal, nil is returned for empty string
+	dec := func(value string) *decimal.Decimal {
+		if value == "" {
+			return nil
+		}
+		result := decimal.RequireFromString(value)
+		return &result
+	}
+	newAccount := func(balance, overdraftLimit, creditLimit string) *Account {
+		account := &Account{}
+		account.Balance = *dec(balance)
+		account.OverdraftLimit = dec(overdraftLimit)
+		account.Type = &accountTypeModel.AccountType{}
+		account.Type.CreditLimitAmount = dec(creditLimit)
+		return account
+	}
+
+	table.DescribeTable("arranged overdraft limit",
+		func(overdraftLimit, creditLimit, expected string) {
+			account := newAccount("0", overdraftLimit, creditLimit)
+			Expect(account.ArrangedOverdraftLimit().String()).To(Equal(expected))
+		},
+		table.Entry("is limit of the account", "500", "100", "500"),
+		table.Entry("is zero limit of the account", "0", "100", "0"),
+		table.Entry("is credit limit of the type if the account has no limit", "", "100", "100"),
+		table.Entry("is zero if neither is set", "", "", "0"),
+	)
+
+	It("should use zero limit if account type is not loaded", func() {
+		account := newAccount("0", "", "")
+		account.Type = nil
+		Expect(account.ArrangedOverdraftLimit().String()).To(Equal("0"))
+	})
+
+	table.DescribeTable("unarranged overdraft",
+		func(balance, overdraftLimit, creditLimit string, expected bool) {
+			Expect(newAccount(balance, overdraftLimit, creditLimit).IsInUnarrangedOverdraft()).To(Equal(expected))
+		},
+		table.Entry("positive balance", "10", "", "", false),
+		table.Entry("negative balance without limit", "-0.01", "", "", true),
+		table.Entry("balance within limit of the account", "-500", "500", "100", false),
+		table.Entry("balance below limit of the account", "-500.01", "500", "1000", true),
+		table.Entry("balance within credit limit of the type", "-100", "", "100", false),
+		table.Entry("balance below credit limit of the type", "-100.01", "", "100", true),
+		table.Entry("negative balance with zero limit of the account", "-1", "0", "100", true),
+	)
+})
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/url"
 	"strconv"
+	"time"
 
 	list_params "github.com/Confialink/wallet-pkg-list_params"
 	userpb "github.com/Confialink/wallet-users/rpc/proto/users"
@@ -99,6 +100,19 @@ func (a *AccountRepository) FindByID(id uint64, preloads ...string) (*model.Acco
 	return &account, nil
 }
 
+// FindByIDForUpdate retrieves the account with its type and locks it within the current db transaction
+func (a *AccountRepository) FindByIDForUpdate(id uint64) (*model.Account, error) {
+	var account model.Account
+	if err := a.db.
+		Preload("Type").
+		Raw("SELECT * FROM `accounts` WHERE `accounts`.`id` = ? FOR UPDATE", id).
+		Find(&account).
+		Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
 // FindByIDAndUserID find account by id and user id
 func (a *AccountRepository) FindByIDAndUserID(id uint64, userID string, preloads ...string) (*model.Account, error) {
 	var account model.Account
@@ -138,6 +152,35 @@ func (a *AccountRepository) FindByNumber(number string) (*model.Account, error)
 	return &account, nil
 }
 
+// FindByNumbers find accounts by numbers
+func (a *AccountRepository) FindByNumbers(numbers []string) ([]*model.Account, error) {
+	accounts := make([]*model.Account, 0)
+	if len(numbers) == 0 {
+		return accounts, nil
+	}
+	err := a.db.Preload("Type").Where("number IN (?)", numbers).Find(&accounts).Error
+	return accounts, err
+}
+
+// FindTermDepositsMaturingBetween returns not paid out term deposits which mature within the given period
+func (a *AccountRepository) FindTermDepositsMaturingBetween(from, till time.Time) ([]*model.Account, error) {
+	var accounts []*model.Account
+	err := a.db.
+		Where("maturity_date >= ? AND maturity_date < ? AND matured_at IS NULL", from, till).
+		Find(&accounts).Error
+	return accounts, err
+}
+
+// FindMaturedTermDeposits returns term deposits which have reached maturity date but have not been paid out yet
+func (a *AccountRepository) FindMaturedTermDeposits(now time.Time) ([]*model.Account, error) {
+	var accounts []*model.Account
+	err := a.db.
+		Preload("Type").
+		Where("maturity_date <= ? AND matured_at IS NULL", now).
+		Find(&accounts).Error
+	return accounts, err
+}
+
 // CountByAccountTypeId count accounts by account type id
 func (a *AccountRepository) CountByAccountTypeId(id uint64) (uint64, error) {
 	var account []*model.Account
@@ -214,10 +257,20 @@ func (a *AccountRepository) Delete(account *model.Account) error {
 	return nil
 }
 
-// UpdateCreditAvailableAmount updates available amount value using diff value
+// Reactivate clears dormancy of the account
+func (a *AccountRepository) Reactivate(id uint64, reactivatedAt time.Time) error {
+	return a.db.
+		Model(&model.Account{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"dormant_at": nil, "reactivated_at": reactivatedAt}).
+		Error
+}
+
+// UpdateCreditAvailableAmount updates available amount value using diff value,
+// accounts with own arranged overdraft limit are not affected by credit limit of the type
 func (a *AccountRepository) UpdateAvailableAmountByAccountTypeId(diff decimal.Decimal, accountTypeId uint64) error {
 	table := (&model.Account{}).TableName()
-	return a.db.Exec("UPDATE "+table+" SET available_amount = available_amount + ? WHERE type_id = ?", diff, accountTypeId).Error
+	return a.db.Exec("UPDATE "+table+" SET available_amount = available_amount + ? WHERE type_id = ? AND overdraft_limit IS NULL", diff, accountTypeId).Error
 }
 
 //TODO: Do not use DB for this
@@ -3,15 +3,19 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/Confialink/wallet-pkg-errors"
 	"github.com/Confialink/wallet-pkg-list_params"
 	"github.com/Confialink/wallet-pkg-utils/pointer"
 	userpb "github.com/Confialink/wallet-users/rpc/proto/users"
 	"github.com/jinzhu/gorm"
+	"github.com/olebedev/emitter"
 
 	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountTypeModel "github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
 	accountTypeRepo "github.com/Confialink/wallet-accounts/internal/modules/account-type/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/event"
 	"github.com/Confialink/wallet-accounts/internal/modules/account/form"
 	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
 	accountRepo "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
@@ -23,6 +27,7 @@ type AccountService struct {
 	accountRepo       *accountRepo.AccountRepository
 	accountTypeRepo   *accountTypeRepo.AccountTypeRepository
 	systemLogsService *system_logs.SystemLogsService
+	eventEmitter      *emitter.Emitter
 }
 
 func NewAccountService(
@@ -30,12 +35,14 @@ func NewAccountService(
 	accountRepo *accountRepo.AccountRepository,
 	accountTypeRepo *accountTypeRepo.AccountTypeRepository,
 	systemLogsService *system_logs.SystemLogsService,
+	eventEmitter *emitter.Emitter,
 ) *AccountService {
 	return &AccountService{
 		db,
 		accountRepo,
 		accountTypeRepo,
 		systemLogsService,
+		eventEmitter,
 	}
 }
 
@@ -65,17 +72,18 @@ func (a *AccountService) Create(
 		return nil, errcodes.CreatePublicError(errcodes.CodeAccountTypeNotFound, fmt.Sprintf("account type #%d not found", account.TypeID))
 	}
 
-	if nil != account.InterestAccountId {
-		interestAccountRecord, _ := accRepo.FindByID(*account.InterestAccountId)
-		if nil == interestAccountRecord {
-			return nil, errcodes.CreatePublicError(errcodes.CodeAccountNotFound, fmt.Sprintf("account #%d is not exist", account.InterestAccountId))
-		}
+	if typedErr := checkInterestAccount(accRepo, account.InterestAccountId, typeRecord); typedErr != nil {
+		return nil, typedErr
+	}
+
+	if account.OverdraftLimit != nil && account.OverdraftLimit.IsNegative() {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidOverdraftLimit)
 	}
 
-	// available amount must be the same value as credit limit
+	// available amount must include arranged overdraft limit
 	// in order to allow negative balance transactions (overdraft)
-	if typeRecord.CreditLimitAmount != nil {
-		account.AvailableAmount = typeRecord.CreditLimitAmount.Add(account.Balance)
+	if limit := model.ArrangedOverdraftLimit(account.OverdraftLimit, typeRecord); !limit.IsZero() {
+		account.AvailableAmount = limit.Add(account.Balance)
 	}
 
 	res, err := accRepo.Create(account)
@@ -91,8 +99,24 @@ func (a *AccountService) Create(
 func (a *AccountService) Update(
 	account *model.Account, editable *model.AccountEditable, user *userpb.User,
 ) (*model.Account, errors.TypedError) {
+	if account.IsClosed() {
+		return nil, errcodes.CreatePublicError(errcodes.CodeAccountClosed)
+	}
+
 	old, _ := a.accountRepo.FindByID(account.ID) // @TODO: implement clone instead
 
+	if editable.OverdraftLimit != nil && editable.OverdraftLimit.IsNegative() {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidOverdraftLimit)
+	}
+	typeRecord, _ := a.accountTypeRepo.WrapContext(a.db).FindByID(account.TypeID)
+	oldLimit := model.ArrangedOverdraftLimit(account.OverdraftLimit, typeRecord)
+
+	typedErr := checkInterestAccount(a.accountRepo.WrapContext(a.db), editable.InterestAccountId, typeRecord)
+	if typedErr != nil {
+		return nil, typedErr
+	}
+
+	// only modified fields are encoded so others are kept
 	editableJSON, err := json.Marshal(editable)
 	if nil != err {
 		pvtErr := errors.PrivateError{Message: "can't marshal json"}
@@ -107,6 +131,10 @@ func (a *AccountService) Update(
 		return nil, &pvtErr
 	}
 
+	// available amount follows changes of arranged overdraft limit
+	newLimit := model.ArrangedOverdraftLimit(account.OverdraftLimit, typeRecord)
+	account.AvailableAmount = account.AvailableAmount.Add(newLimit.Sub(oldLimit))
+
 	updatedAccount, err := a.accountRepo.Update(account)
 
 	if nil != err {
@@ -123,6 +151,58 @@ func (a *AccountService) Update(
 	return updatedAccount, nil
 }
 
+// Reactivate lifts dormancy of the account so its owner is allowed to make outgoing transfers again,
+// the dormancy period is counted since reactivation. Subscribers of AccountReactivated event
+// are processed within the same db transaction (e.g. pending dormancy fee is waived).
+func (a *AccountService) Reactivate(
+	account *model.Account, user *userpb.User, currentTime time.Time,
+) (*model.Account, errors.TypedError) {
+	if account.IsClosed() {
+		return nil, errcodes.CreatePublicError(errcodes.CodeAccountClosed)
+	}
+	if !account.IsDormant() {
+		return nil, errcodes.CreatePublicError(errcodes.CodeAccountNotDormant)
+	}
+
+	old := *account
+	tx := a.db.Begin()
+	if err := a.accountRepo.WrapContext(tx).Reactivate(account.ID, currentTime); err != nil {
+		tx.Rollback()
+		pvtErr := &errors.PrivateError{Message: "can't reactivate account"}
+		pvtErr.AddLogPair("error", err)
+		pvtErr.AddLogPair("account id", account.ID)
+		return nil, pvtErr
+	}
+
+	evtContext := &event.ContextAccountReactivated{
+		DbTransaction: tx,
+		Account:       account,
+	}
+	// wait while event is processing
+	<-a.eventEmitter.Emit(event.AccountReactivated, evtContext)
+
+	if evtContext.Error != nil {
+		tx.Rollback()
+		pvtErr := &errors.PrivateError{Message: "can't reactivate account"}
+		pvtErr.AddLogPair("error", evtContext.Error)
+		pvtErr.AddLogPair("account id", account.ID)
+		return nil, pvtErr
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, &errors.PrivateError{Message: err.Error()}
+	}
+
+	reactivated, err := a.accountRepo.FindByID(account.ID)
+	if err != nil {
+		return nil, &errors.PrivateError{Message: err.Error()}
+	}
+
+	a.systemLogsService.LogReactivateAccountAsync(&old, reactivated, user.UID)
+
+	return reactivated, nil
+}
+
 // BulkCreate creates list of accounts
 func (a *AccountService) BulkCreate(accounts []*model.Account) ([]*model.Account, error) {
 	accRepo := a.accountRepo.WrapContext(a.db)
@@ -138,17 +218,18 @@ func (a *AccountService) BulkCreate(accounts []*model.Account) ([]*model.Account
 			return nil, errcodes.CreatePublicError(errcodes.CodeAccountTypeNotFound, fmt.Sprintf("account type #%d not found", account.TypeID))
 		}
 
-		if nil != account.InterestAccountId {
-			interestAccountRecord, _ := accRepo.FindByID(*account.InterestAccountId)
-			if nil == interestAccountRecord {
-				return nil, errcodes.CreatePublicError(errcodes.CodeAccountNotFound, fmt.Sprintf("account #%d is not exist", account.InterestAccountId))
-			}
+		if typedErr := checkInterestAccount(accRepo, account.InterestAccountId, typeRecord); typedErr != nil {
+			return nil, typedErr
+		}
+
+		if account.OverdraftLimit != nil && account.OverdraftLimit.IsNegative() {
+			return nil, errcodes.CreatePublicError(errcodes.CodeInvalidOverdraftLimit)
 		}
 
-		// available amount must be the same value as credit limit
+		// available amount must include arranged overdraft limit
 		// in order to allow negative balance transactions (overdraft)
-		if typeRecord.CreditLimitAmount != nil {
-			account.AvailableAmount = typeRecord.CreditLimitAmount.Add(account.Balance)
+		if limit := model.ArrangedOverdraftLimit(account.OverdraftLimit, typeRecord); !limit.IsZero() {
+			account.AvailableAmount = limit.Add(account.Balance)
 		}
 	}
 
@@ -160,6 +241,30 @@ func (a *AccountService) BulkCreate(accounts []*model.Account) ([]*model.Account
 	return res, nil
 }
 
+// checkInterestAccount checks the interest account exists and it is in the currency of the account,
+// interest and principal of term deposits are paid out to the interest account without conversion
+func checkInterestAccount(
+	accRepo *accountRepo.AccountRepository,
+	interestAccountId *uint64,
+	typeRecord *accountTypeModel.AccountType,
+) errors.TypedError {
+	if nil == interestAccountId {
+		return nil
+	}
+	interestAccountRecord, _ := accRepo.FindByID(*interestAccountId)
+	if nil == interestAccountRecord {
+		return errcodes.CreatePublicError(errcodes.CodeAccountNotFound, fmt.Sprintf("account #%d is not exist", *interestAccountId))
+	}
+	if nil == typeRecord || nil == interestAccountRecord.Type ||
+		interestAccountRecord.Type.CurrencyCode != typeRecord.CurrencyCode {
+		return errcodes.CreatePublicError(
+			errcodes.CodeCurrencyMismatch,
+			fmt.Sprintf("interest account #%d must be in the currency of the account", *interestAccountId),
+		)
+	}
+	return nil
+}
+
 func (a *AccountService) CreateAccountAsWallet(
 	currency string,
 	uid string,
@@ -0,0 +1,270 @@
+package service_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	userpb "github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"github.com/olebedev/emitter"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountTypeRepository "github.com/Confialink/wallet-accounts/internal/modules/account-type/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/event"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/account/service"
+	system_logs "github.com/Confialink/wallet-accounts/internal/modules/system-logs"
+)
+
+func newMockDB() (*gorm.DB, sqlmock.Sqlmock) {
+	var db *sql.DB
+	db, mock, err := sqlmock.New()
+	Expect(err).ShouldNot(HaveOccurred())
+	gdb, err := gorm.Open("mysql", db)
+	Expect(err).ShouldNot(HaveOccurred())
+	return gdb, mock
+}
+
+// dec converts the string into the decimal, nil is returned for empty string
+func dec(value string) *decimal.Decimal {
+	if value == "" {
+		return nil
+	}
+	result := decimal.RequireFromString(value)
+	return &result
+}
+
+// editableFromJSON decodes editable fields of the account sent by the client
+func editableFromJSON(data string) *model.AccountEditable {
+	editable := &model.AccountEditable{}
+	Expect(json.Unmarshal([]byte(data), editable)).To(Succeed())
+	return editable
+}
+
+// overdraftLimitJSON returns json sending the arranged overdraft limit, it is cleared for empty string
+func overdraftLimitJSON(limit string) string {
+	if limit == "" {
+		return `{"overdraftLimit": null}`
+	}
+	return fmt.Sprintf(`{"overdraftLimit": %q}`, limit)
+}
+
+var _ = Describe("AccountService", func() {
+	var (
+		mock         sqlmock.Sqlmock
+		service      *AccountService
+		eventEmitter *emitter.Emitter
+	)
+	user := &userpb.User{UID: "admin"}
+
+	BeforeEach(func() {
+		gdb, sqlMock := newMockDB()
+		mock = sqlMock
+		eventEmitter = emitter.New(10)
+		logsService := system_logs.NewSystemLogsService(
+			nil, nil, system_logs.NewAccountLogCreator(nil, log15.New()), nil, nil, nil, nil,
+		)
+		service = NewAccountService(
+			gdb,
+			repository.NewAccountRepository(gdb, nil),
+			accountTypeRepository.NewAccountTypeRepository(gdb, eventEmitter),
+			logsService,
+			eventEmitter,
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectAccount := func() {
+		mock.ExpectQuery("SELECT \\* FROM `accounts`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id"}).AddRow(1, 3))
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	}
+	expectAccountType := func(creditLimit string) {
+		rows := sqlmock.NewRows([]string{"id", "credit_limit_amount"})
+		if creditLimit == "" {
+			rows.AddRow(3, nil)
+		} else {
+			rows.AddRow(3, creditLimit)
+		}
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").WillReturnRows(rows)
+	}
+
+	table.DescribeTable("available amount follows arranged overdraft limit",
+		func(oldLimit, newLimit, creditLimit, expected string) {
+			account := &model.Account{}
+			account.ID = 1
+			account.TypeID = 3
+			account.Balance = decimal.RequireFromString("-50")
+			account.OverdraftLimit = dec(oldLimit)
+			account.AvailableAmount = *dec(availableAmount(oldLimit, creditLimit))
+
+			// old state of the account for system logs
+			expectAccount()
+			expectAccountType(creditLimit)
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE `accounts`").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			// updated account is returned
+			expectAccount()
+
+			_, err := service.Update(account, editableFromJSON(overdraftLimitJSON(newLimit)), user)
+			Expect(err).To(BeNil())
+			Expect(account.AvailableAmount.String()).To(Equal(expected))
+		},
+		table.Entry("limit of the account is set over credit limit of the type", "", "500", "100", "450"),
+		table.Entry("limit of the account is set without credit limit of the type", "", "500", "", "450"),
+		table.Entry("limit of the account is increased", "200", "500", "100", "450"),
+		table.Entry("limit of the account is decreased", "500", "200", "100", "150"),
+		table.Entry("limit of the account is set to zero", "500", "0", "100", "-50"),
+		table.Entry("limit of the account is cleared in favour of credit limit of the type", "500", "", "100", "50"),
+		table.Entry("limit of the account is cleared without credit limit of the type", "500", "", "", "-50"),
+		table.Entry("limit of the account is not changed", "500", "500", "100", "450"),
+	)
+
+	It("should keep fields which are not sent", func() {
+		description := "savings"
+		allowDeposits := false
+		account := &model.Account{}
+		account.ID = 1
+		account.TypeID = 3
+		account.Description = &description
+		account.AllowDeposits = &allowDeposits
+		account.OverdraftLimit = dec("200")
+		account.AvailableAmount = *dec("200")
+
+		expectAccount()
+		expectAccountType("")
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `accounts`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		expectAccount()
+
+		_, err := service.Update(account, editableFromJSON(`{"allowWithdrawals": false}`), user)
+		Expect(err).To(BeNil())
+		Expect(*account.Description).To(Equal("savings"))
+		Expect(*account.AllowDeposits).To(BeFalse())
+		Expect(*account.AllowWithdrawals).To(BeFalse())
+		Expect(account.OverdraftLimit.String()).To(Equal("200"))
+		Expect(account.AvailableAmount.String()).To(Equal("200"))
+	})
+
+	It("should not accept negative arranged overdraft limit", func() {
+		account := &model.Account{}
+		account.ID = 1
+
+		expectAccount()
+		_, err := service.Update(account, &model.AccountEditable{OverdraftLimit: dec("-1")}, user)
+		Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+		Expect(err.(*errors.PublicError).Code).To(Equal(errcodes.CodeInvalidOverdraftLimit))
+	})
+
+	It("should not accept interest account in another currency", func() {
+		account := &model.Account{}
+		account.ID = 1
+		account.TypeID = 3
+
+		expectAccount()
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(3, "EUR"))
+		mock.ExpectQuery("SELECT \\* FROM `accounts`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id"}).AddRow(2, 4))
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(4, "USD"))
+
+		interestAccountId := uint64(2)
+		_, err := service.Update(account, &model.AccountEditable{InterestAccountId: &interestAccountId}, user)
+		Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+		Expect(err.(*errors.PublicError).Code).To(Equal(errcodes.CodeCurrencyMismatch))
+	})
+
+	Context("reactivation", func() {
+		var reactivated chan *event.ContextAccountReactivated
+		var handlerError error
+		currentTime := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			reactivated = make(chan *event.ContextAccountReactivated, 1)
+			handlerError = nil
+			go func() {
+				for range eventEmitter.On(event.AccountReactivated, func(e *emitter.Event) {
+					context := e.Args[0].(*event.ContextAccountReactivated)
+					context.Error = handlerError
+					reactivated <- context
+				}) { /* empty */
+				}
+			}()
+			// wait for the subscription
+			Eventually(func() int { return len(eventEmitter.Listeners(event.AccountReactivated)) }).Should(Equal(1))
+		})
+
+		dormantAccount := func() *model.Account {
+			account := &model.Account{}
+			account.ID = 1
+			dormantAt := currentTime.AddDate(0, -1, 0)
+			account.DormantAt = &dormantAt
+			return account
+		}
+
+		It("should notify subscribers within the reactivation transaction", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE `accounts` SET `dormant_at` = \\?, `reactivated_at` = \\?").
+				WithArgs(nil, currentTime, sqlmock.AnyArg(), 1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			expectAccount()
+
+			_, err := service.Reactivate(dormantAccount(), user, currentTime)
+			Expect(err).To(BeNil())
+
+			var context *event.ContextAccountReactivated
+			Expect(reactivated).To(Receive(&context))
+			Expect(context.Account.ID).To(Equal(uint64(1)))
+			Expect(context.DbTransaction).NotTo(BeNil())
+		})
+
+		It("should roll back reactivation if subscriber fails", func() {
+			handlerError = fmt.Errorf("failed to waive dormancy fee")
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE `accounts` SET `dormant_at` = \\?, `reactivated_at` = \\?").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectRollback()
+
+			_, err := service.Reactivate(dormantAccount(), user, currentTime)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should not reactivate not dormant account", func() {
+			account := dormantAccount()
+			account.DormantAt = nil
+
+			_, err := service.Reactivate(account, user, currentTime)
+			Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+			Expect(err.(*errors.PublicError).Code).To(Equal(errcodes.CodeAccountNotDormant))
+		})
+	})
+})
+
+// availableAmount returns available amount of the account with balance -50 and the given limits
+func availableAmount(overdraftLimit, creditLimit string) string {
+	limit := decimal.Zero
+	if overdraftLimit != "" {
+		limit = *dec(overdraftLimit)
+	} else if creditLimit != "" {
+		limit = *dec(creditLimit)
+	}
+	return limit.Add(decimal.RequireFromString("-50")).String()
+}
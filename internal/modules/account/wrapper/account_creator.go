@@ -67,6 +67,10 @@ func (c *AccountCreator) CreateAccountWithRequest(form *form.Account, user *user
 			PayoutDay:         form.PayoutDay,
 			InterestAccountId: form.InterestAccountId,
 			InitialBalance:    &form.InitialBalance,
+
+			MaturityAction:                form.MaturityAction,
+			TermMonths:                    form.TermMonths,
+			EarlyWithdrawalPenaltyPercent: form.EarlyWithdrawalPenaltyPercent,
 		},
 	}
 
@@ -43,6 +43,8 @@ func Providers() []interface{} {
 			)
 			adminViewCard := permissionPolicy.ProvideCheckSpecificPermission(permission.ViewCards)
 			adminViewSettings := permissionPolicy.ProvideCheckSpecificPermission(permission.ViewSettings)
+			// scheduled fees and payouts are waived, rescheduled or executed on behalf of account owners
+			adminModifyScheduledTransaction := permissionPolicy.ProvideCheckSpecificPermission(permission.ModifyAccounts)
 			policies := &service.RequiredPolicies{
 				CreateModifyIwtBankAccount: createModifyIwtBankAccount,
 				ViewTransferRequest:        clientViewTransferRequest,
@@ -52,6 +54,7 @@ func Providers() []interface{} {
 				ViewAccount:                adminViewAccount,
 				ViewCard:                   adminViewCard,
 				ViewSettings:               adminViewSettings,
+				ModifyScheduledTransaction: adminModifyScheduledTransaction,
 			}
 
 			return service.NewService(acl, policies)
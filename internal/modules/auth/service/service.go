@@ -59,6 +59,7 @@ type RequiredPolicies struct {
 	ViewAccount                policy.Policy
 	ViewCard                   policy.Policy
 	ViewSettings               policy.Policy
+	ModifyScheduledTransaction policy.Policy
 }
 
 type PermissionMap map[string]map[string]map[string]policy.Policy
@@ -164,6 +165,9 @@ func NewService(acl *goAcl.ACL, policies *RequiredPolicies) AuthServiceInterface
 			ResourceSetting: {
 				ActionRead: policies.ViewSettings,
 			},
+			ResourceScheduledTransactions: {
+				ActionUpdate: policies.ModifyScheduledTransaction,
+			},
 		},
 	}
 
@@ -66,6 +66,20 @@ func (a *AggregationService) TotalDebitedByUserPerPeriod(
 	return a.reduce(aggregator, outCurrencyCode)
 }
 
+// TotalByCardPerPeriod provides sum of absolute amounts of all card transactions by specific time period
+func (a *AggregationService) TotalByCardPerPeriod(
+	cardId uint32,
+	from,
+	till time.Time,
+	outCurrencyCode string,
+) (AggregationItem, error) {
+	aggregator, err := a.factory.TotalByCardIdPerPeriod(cardId, from, till)
+	if err != nil {
+		return AggregationItem{}, errors.Wrap(err, "failed to obtain aggregator")
+	}
+	return a.reduce(aggregator, outCurrencyCode)
+}
+
 // WrapContext makes a copy of the service with new DB context
 func (a AggregationService) WrapContext(db *gorm.DB) *AggregationService {
 	a.factory = a.factory.WrapContext(db)
@@ -31,6 +31,16 @@ const (
 				AND tx.created_at BETWEEN ? AND ?
 				AND tx.amount < 0
 		GROUP BY t.currency_code `
+
+	sqlTotalByCardPerPeriod = `
+		SELECT SUM(ABS(tx.amount)) as amount, t.currency_code FROM transactions tx
+				INNER JOIN cards c ON tx.card_id = c.id
+				INNER JOIN card_types t on t.id = c.card_type_id
+				WHERE 
+				c.id = ? 
+				AND tx.status IN ('pending', 'executed')
+				AND tx.created_at BETWEEN ? AND ?
+		GROUP BY t.currency_code `
 )
 
 type dbGeneralTotalAggregator struct {
@@ -65,3 +75,23 @@ func (d *dbTotalDebitedPerPeriod) Aggregate() (AggregationResult, error) {
 		).Scan(&result).Error
 	return result, err
 }
+
+type dbTotalByCardPerPeriod struct {
+	db       *gorm.DB
+	cardId   uint32
+	dateFrom time.Time
+	dateTo   time.Time
+}
+
+// Aggregate aggregates all card transactions (both incoming and outgoing) for a certain period of time
+func (d *dbTotalByCardPerPeriod) Aggregate() (AggregationResult, error) {
+	result := AggregationResult{}
+	err := d.db.
+		Raw(
+			sqlTotalByCardPerPeriod,
+			d.cardId,
+			d.dateFrom.Format(dateLayout),
+			d.dateTo.Format(dateLayout),
+		).Scan(&result).Error
+	return result, err
+}
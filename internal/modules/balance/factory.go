@@ -13,6 +13,9 @@ type AggregationFactory interface {
 	// TotalDebitedByUserIdPerPeriod is an aggregator which summarize all user outgoing transaction
 	// for particular period
 	TotalDebitedByUserIdPerPeriod(userId string, from, till time.Time) (Aggregator, error)
+	// TotalByCardIdPerPeriod is an aggregator which summarize absolute amounts of all card transactions
+	// for particular period
+	TotalByCardIdPerPeriod(cardId uint32, from, till time.Time) (Aggregator, error)
 	// WrapContext creates a copy of the factory with provided db context
 	WrapContext(db *gorm.DB) AggregationFactory
 }
@@ -45,6 +48,17 @@ func (d *dbAggregationFactory) TotalDebitedByUserIdPerPeriod(userId string, from
 	}, nil
 }
 
+// TotalByCardIdPerPeriod is an aggregator which summarize absolute amounts of all card transactions
+// for particular period
+func (d *dbAggregationFactory) TotalByCardIdPerPeriod(cardId uint32, from, till time.Time) (Aggregator, error) {
+	return &dbTotalByCardPerPeriod{
+		db:       d.db,
+		cardId:   cardId,
+		dateFrom: from,
+		dateTo:   till,
+	}, nil
+}
+
 // WrapContext creates a copy of the factory
 func (d dbAggregationFactory) WrapContext(db *gorm.DB) AggregationFactory {
 	d.db = db
@@ -65,6 +65,21 @@ func (mr *MockAggregationFactoryMockRecorder) TotalDebitedByUserIdPerPeriod(user
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalDebitedByUserIdPerPeriod", reflect.TypeOf((*MockAggregationFactory)(nil).TotalDebitedByUserIdPerPeriod), userId, from, till)
 }
 
+// TotalByCardIdPerPeriod mocks base method
+func (m *MockAggregationFactory) TotalByCardIdPerPeriod(cardId uint32, from, till time.Time) (balance.Aggregator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TotalByCardIdPerPeriod", cardId, from, till)
+	ret0, _ := ret[0].(balance.Aggregator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TotalByCardIdPerPeriod indicates an expected call of TotalByCardIdPerPeriod
+func (mr *MockAggregationFactoryMockRecorder) TotalByCardIdPerPeriod(cardId, from, till interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalByCardIdPerPeriod", reflect.TypeOf((*MockAggregationFactory)(nil).TotalByCardIdPerPeriod), cardId, from, till)
+}
+
 // WrapContext mocks base method
 func (m *MockAggregationFactory) WrapContext(db *gorm.DB) balance.AggregationFactory {
 	m.ctrl.T.Helper()
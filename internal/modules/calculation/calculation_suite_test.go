@@ -0,0 +1,13 @@
+package calculation_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCalculation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Calculation Suite")
+}
@@ -0,0 +1,59 @@
+package calculation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DayCountConvention defines number of days in a year used for calculation of daily interest
+type DayCountConvention string
+
+const (
+	// DayCountActualActual uses actual number of days of the year (365 or 366)
+	DayCountActualActual = DayCountConvention("ACT/ACT")
+	// DayCountActual365 always uses 365 days
+	DayCountActual365 = DayCountConvention("ACT/365")
+	// DayCountActual360 always uses 360 days
+	DayCountActual360 = DayCountConvention("ACT/360")
+)
+
+// DefaultDayCountConvention is used if the convention is not configured
+const DefaultDayCountConvention = DayCountActualActual
+
+var knownDayCountConventions = map[string]DayCountConvention{
+	string(DayCountActualActual): DayCountActualActual,
+	string(DayCountActual365):    DayCountActual365,
+	string(DayCountActual360):    DayCountActual360,
+}
+
+func DayCountConventionFromString(convention string) (DayCountConvention, error) {
+	if result, ok := knownDayCountConventions[convention]; ok {
+		return result, nil
+	}
+	return DayCountConvention(""), errors.New("unknown day count convention " + convention)
+}
+
+// DaysInYear returns number of days in the year of the given date
+func (c DayCountConvention) DaysInYear(date time.Time) int64 {
+	switch c {
+	case DayCountActual365:
+		return 365
+	case DayCountActual360:
+		return 360
+	}
+	beginningOfYear := time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return int64(beginningOfYear.AddDate(1, 0, 0).Sub(beginningOfYear).Hours() / 24)
+}
+
+// DailyInterest calculates interest accrued on the balance for the given day
+func (c DayCountConvention) DailyInterest(balance decimal.Decimal, annualPercent decimal.Decimal, date time.Time) decimal.Decimal {
+	oneHundred := decimal.New(100, 0)
+	// balance * (percent / 100) / daysInYear
+	return balance.Mul(annualPercent).Div(oneHundred).Div(decimal.New(c.DaysInYear(date), 0))
+}
+
+func (c DayCountConvention) String() string {
+	return string(c)
+}
@@ -0,0 +1,41 @@
+package calculation_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/calculation"
+)
+
+var _ = Describe("DayCountConvention", func() {
+	leapDay := time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)
+	commonDay := time.Date(2021, time.February, 28, 0, 0, 0, 0, time.UTC)
+
+	It("should count days in year", func() {
+		Expect(DayCountActualActual.DaysInYear(leapDay)).To(Equal(int64(366)))
+		Expect(DayCountActualActual.DaysInYear(commonDay)).To(Equal(int64(365)))
+		Expect(DayCountActual365.DaysInYear(leapDay)).To(Equal(int64(365)))
+		Expect(DayCountActual360.DaysInYear(commonDay)).To(Equal(int64(360)))
+	})
+
+	It("should calculate daily interest", func() {
+		balance := decimal.NewFromInt(36500)
+		rate := decimal.NewFromInt(10)
+
+		Expect(DayCountActual365.DailyInterest(balance, rate, leapDay).String()).To(Equal("10"))
+		Expect(DayCountActual360.DailyInterest(decimal.NewFromInt(36000), rate, leapDay).String()).To(Equal("10"))
+		Expect(DayCountActualActual.DailyInterest(decimal.NewFromInt(36600), rate, leapDay).String()).To(Equal("10"))
+	})
+
+	It("should fail on unknown convention", func() {
+		_, err := DayCountConventionFromString("30/360")
+		Expect(err).To(HaveOccurred())
+
+		convention, err := DayCountConventionFromString("ACT/360")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(convention).To(Equal(DayCountActual360))
+	})
+})
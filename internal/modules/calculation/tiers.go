@@ -0,0 +1,34 @@
+package calculation
+
+import "github.com/shopspring/decimal"
+
+// RateTier is an annual interest rate applied to the part of the balance above the From amount
+type RateTier struct {
+	From decimal.Decimal
+	Rate decimal.Decimal
+}
+
+// BlendedRate returns annual rate of the whole balance where rate of each tier is applied to the part of the balance
+// between the tier and the next one, e.g. 0.5% on the first 10000 and 1% on the rest.
+// Tiers must be sorted by From in ascending order.
+func BlendedRate(balance decimal.Decimal, tiers []RateTier) decimal.Decimal {
+	if len(tiers) == 0 {
+		return decimal.Zero
+	}
+	if !balance.IsPositive() {
+		return tiers[0].Rate
+	}
+
+	interest := decimal.Zero
+	for i, tier := range tiers {
+		if !balance.GreaterThan(tier.From) {
+			break
+		}
+		upper := balance
+		if i+1 < len(tiers) && tiers[i+1].From.LessThan(balance) {
+			upper = tiers[i+1].From
+		}
+		interest = interest.Add(upper.Sub(tier.From).Mul(tier.Rate))
+	}
+	return interest.Div(balance)
+}
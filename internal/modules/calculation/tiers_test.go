@@ -0,0 +1,30 @@
+package calculation_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/calculation"
+)
+
+var _ = Describe("BlendedRate", func() {
+	tiers := []RateTier{
+		{From: decimal.Zero, Rate: decimal.RequireFromString("0.5")},
+		{From: decimal.NewFromInt(10000), Rate: decimal.NewFromInt(1)},
+	}
+
+	It("should use rate of the first tier for balance within it", func() {
+		Expect(BlendedRate(decimal.NewFromInt(5000), tiers).String()).To(Equal("0.5"))
+		Expect(BlendedRate(decimal.NewFromInt(10000), tiers).String()).To(Equal("0.5"))
+	})
+
+	It("should apply rate of each tier to its part of the balance", func() {
+		// 10000 * 0.5% + 10000 * 1% = 150 that is 0.75% of 20000
+		Expect(BlendedRate(decimal.NewFromInt(20000), tiers).String()).To(Equal("0.75"))
+	})
+
+	It("should return zero if there are no tiers", func() {
+		Expect(BlendedRate(decimal.NewFromInt(100), nil).IsZero()).To(BeTrue())
+	})
+})
@@ -12,11 +12,14 @@ func Providers() []interface{} {
 		repository.NewCardRepository,
 		service.NewCardService,
 		service.NewCsv,
+		service.NewExpiryService,
+		service.NewControlsService,
 		serializer.NewCardSerializer,
 
 		handlers.NewHandlerParams,
 		handlers.NewCardHandler,
 		handlers.NewCsvHandler,
 		handlers.NewCardListHandler,
+		handlers.NewControlsHandler,
 	}
 }
@@ -0,0 +1,35 @@
+package form
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+)
+
+type Controls struct {
+	MaxPerTransaction *string  `json:"maxPerTransaction" binding:"omitempty,decimal,decimalGT=0"`
+	MaxPerDay         *string  `json:"maxPerDay" binding:"omitempty,decimal,decimalGT=0"`
+	AllowedCurrencies []string `json:"allowedCurrencies" binding:"omitempty,dive,len=3"`
+}
+
+func (c *Controls) ToModel() (*model.Controls, error) {
+	controls := &model.Controls{AllowedCurrencies: c.AllowedCurrencies}
+
+	if c.MaxPerTransaction != nil {
+		value, err := decimal.NewFromString(*c.MaxPerTransaction)
+		if err != nil {
+			return nil, err
+		}
+		controls.MaxPerTransaction = &value
+	}
+
+	if c.MaxPerDay != nil {
+		value, err := decimal.NewFromString(*c.MaxPerDay)
+		if err != nil {
+			return nil, err
+		}
+		controls.MaxPerDay = &value
+	}
+
+	return controls, nil
+}
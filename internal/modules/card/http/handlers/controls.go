@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+	cardService "github.com/Confialink/wallet-accounts/internal/modules/card/service"
+)
+
+type ControlsHandler struct {
+	contextService appHttpService.ContextInterface
+	service        *cardService.ControlsService
+	logger         log15.Logger
+}
+
+func NewControlsHandler(
+	contextService appHttpService.ContextInterface,
+	service *cardService.ControlsService,
+	logger log15.Logger,
+) *ControlsHandler {
+	return &ControlsHandler{
+		contextService: contextService,
+		service:        service,
+		logger:         logger.New("Handler", "ControlsHandler"),
+	}
+}
+
+// ShowOwnHandler returns controls of the requested card of the current user
+func (h *ControlsHandler) ShowOwnHandler(c *gin.Context) {
+	card := h.ownCard(c)
+	if card == nil {
+		return
+	}
+
+	controls, err := h.service.Get(*card.Id)
+	if err != nil {
+		privateError := errors.PrivateError{Message: "can't retrieve card controls"}
+		privateError.AddLogPair("error", err)
+		privateError.AddLogPair("card id", *card.Id)
+		errors.AddErrors(c, &privateError)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(controls))
+}
+
+// UpdateOwnHandler replaces controls of the requested card of the current user
+func (h *ControlsHandler) UpdateOwnHandler(c *gin.Context) {
+	card := h.ownCard(c)
+	if card == nil {
+		return
+	}
+
+	var f form.Controls
+	if err := c.ShouldBindJSON(&f); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	controls, err := f.ToModel()
+	if err != nil {
+		errors.AddErrors(c, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	updated, err := h.service.Update(*card.Id, controls)
+	if err != nil {
+		privateError := errors.PrivateError{Message: "can't update card controls"}
+		privateError.AddLogPair("error", err)
+		privateError.AddLogPair("card id", *card.Id)
+		errors.AddErrors(c, &privateError)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(updated))
+}
+
+// ownCard retrieves requested card and makes sure it belongs to the current user
+func (h *ControlsHandler) ownCard(c *gin.Context) *model.Card {
+	card := h.contextService.GetRequestedCard(c)
+	if card == nil {
+		errcodes.AddError(c, errcodes.CodeCardNotFound)
+		return nil
+	}
+	currentUser := h.contextService.MustGetCurrentUser(c)
+	if card.UserId == nil || *card.UserId != currentUser.UID {
+		errcodes.AddError(c, errcodes.CodeInvalidCardOwner)
+		return nil
+	}
+	return card
+}
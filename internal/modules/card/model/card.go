@@ -9,6 +9,11 @@ import (
 	cardTypeModel "github.com/Confialink/wallet-accounts/internal/modules/card-type/model"
 )
 
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+)
+
 type Card struct {
 	Id              *uint32                 `json:"id"`
 	Number          *string                 `json:"number" binding:"required,validCardFormat,cardNumberUnique"`
@@ -78,3 +83,20 @@ func (c *Card) GetId() *uint64 {
 func (c *Card) GetUserId() *string {
 	return c.UserId
 }
+
+// ExpiresAt returns the moment when the card becomes expired.
+// A card is valid until the last day of its expiration month inclusive.
+func (c *Card) ExpiresAt(location *time.Location) time.Time {
+	return time.Date(int(*c.ExpirationYear), time.Month(*c.ExpirationMonth)+1, 1, 0, 0, 0, 0, location)
+}
+
+// IsExpired checks whether the card is expired at the given time
+func (c *Card) IsExpired(now time.Time) bool {
+	if c.Status != nil && *c.Status == StatusExpired {
+		return true
+	}
+	if c.ExpirationYear == nil || c.ExpirationMonth == nil {
+		return false
+	}
+	return !now.Before(c.ExpiresAt(now.Location()))
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Card controls are stored as limits (see internal/limit) associated with the card entity
+const (
+	// ControlsLimitEntity is the limit entity name used for card controls
+	ControlsLimitEntity = "card"
+	// ControlMaxPerTransaction limits amount of a single card operation
+	ControlMaxPerTransaction = "card_max_per_transaction"
+	// ControlMaxPerDay limits total amount of card operations per day
+	ControlMaxPerDay = "card_max_per_day"
+	// ControlAllowedCurrency defines a currency allowed for card operations,
+	// there is one limit record per each allowed currency named by AllowedCurrencyControlName, its amount is not used
+	ControlAllowedCurrency = "card_allowed_currency"
+)
+
+// AllowedCurrencyControlName returns limit name of the allowed currency control,
+// the name contains currency code since limit names must be unique per card
+func AllowedCurrencyControlName(currencyCode string) string {
+	return ControlAllowedCurrency + ":" + currencyCode
+}
+
+// IsAllowedCurrencyControl checks whether the limit name belongs to an allowed currency control
+func IsAllowedCurrencyControl(name string) bool {
+	return strings.HasPrefix(name, ControlAllowedCurrency)
+}
+
+// Controls are user defined rules applied to card operations.
+// Nil values mean that the rule is not set.
+type Controls struct {
+	MaxPerTransaction *decimal.Decimal `json:"maxPerTransaction"`
+	MaxPerDay         *decimal.Decimal `json:"maxPerDay"`
+	// AllowedCurrencies is a list of currency codes, empty list means that any currency is allowed
+	AllowedCurrencies []string `json:"allowedCurrencies"`
+}
@@ -20,6 +20,9 @@ type CardRepositoryInterface interface {
 	GetListCount(*list_params.ListParams) (uint64, error)
 	FillUsers(cards []*model.Card) error
 	BulkCreate(cards []*model.Card) ([]*model.Card, error)
+	GetListExpiredBefore(year, month int) ([]*model.Card, error)
+	GetListExpiringIn(year, month int) ([]*model.Card, error)
+	GetForUpdate(id uint32) (*model.Card, error)
 	WrapContext(db *gorm.DB) CardRepositoryInterface
 }
 
@@ -219,6 +222,40 @@ func (c *cardRepository) GetListByCardTypeId(id uint32) []*model.Card {
 	return cards
 }
 
+// GetListExpiredBefore returns not yet marked cards which expiration date is before the given year and month
+func (c *cardRepository) GetListExpiredBefore(year, month int) ([]*model.Card, error) {
+	var cards []*model.Card
+	err := c.db.
+		Preload("CardType").
+		Preload("CardType.Format").
+		Where("status <> ?", model.StatusExpired).
+		Where("expiration_year < ? OR (expiration_year = ? AND expiration_month < ?)", year, year, month).
+		Find(&cards).Error
+	return cards, err
+}
+
+// GetListExpiringIn returns not expired cards which expire in the given year and month
+func (c *cardRepository) GetListExpiringIn(year, month int) ([]*model.Card, error) {
+	var cards []*model.Card
+	err := c.db.
+		Preload("CardType").
+		Where("status <> ?", model.StatusExpired).
+		Where("expiration_year = ? AND expiration_month = ?", year, month).
+		Find(&cards).Error
+	return cards, err
+}
+
+// GetForUpdate locks the card and returns it with its card type
+func (c *cardRepository) GetForUpdate(id uint32) (*model.Card, error) {
+	card := &model.Card{}
+	err := c.db.
+		Preload("CardType").
+		Raw("SELECT * FROM `cards` WHERE `cards`.`id` = ? FOR UPDATE", id).
+		Find(card).
+		Error
+	return card, err
+}
+
 func (c cardRepository) WrapContext(db *gorm.DB) CardRepositoryInterface {
 	c.db = db
 	return &c
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Confialink/wallet-pkg-list_params"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/limit"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/repository"
+)
+
+// ControlsService manages user defined card controls.
+// Controls are persisted using limit storage.
+type ControlsService struct {
+	db      *gorm.DB
+	repo    repository.CardRepositoryInterface
+	storage limit.Storage
+	factory limit.Factory
+}
+
+func NewControlsService(
+	db *gorm.DB,
+	repo repository.CardRepositoryInterface,
+	storage limit.Storage,
+	factory limit.Factory,
+) *ControlsService {
+	return &ControlsService{
+		db:      db,
+		repo:    repo,
+		storage: storage,
+		factory: factory,
+	}
+}
+
+// Get retrieves controls of the given card
+func (s *ControlsService) Get(cardId uint32) (*model.Controls, error) {
+	limits, err := limit.NewService(s.storage, s.factory).Find(controlsIdentifier("", cardId))
+	if err != nil && errors.Cause(err) != limit.ErrNotFound {
+		return nil, errors.Wrapf(err, "failed to retrieve controls of card #%d", cardId)
+	}
+
+	controls := &model.Controls{AllowedCurrencies: []string{}}
+	for _, lim := range limits {
+		available := lim.Available()
+		if available.NoLimit() {
+			continue
+		}
+		amount := available.CurrencyAmount()
+		switch name := lim.Identifier().Name; {
+		case name == model.ControlMaxPerTransaction:
+			value := amount.Amount()
+			controls.MaxPerTransaction = &value
+		case name == model.ControlMaxPerDay:
+			value := amount.Amount()
+			controls.MaxPerDay = &value
+		case model.IsAllowedCurrencyControl(name):
+			controls.AllowedCurrencies = append(controls.AllowedCurrencies, amount.CurrencyCode())
+		}
+	}
+	return controls, nil
+}
+
+// Update replaces controls of the given card.
+// Amounts are stored in the card currency.
+func (s *ControlsService) Update(cardId uint32, controls *model.Controls) (*model.Controls, error) {
+	includes := list_params.Includes{}
+	includes.AddIncludes("CardType")
+
+	card, err := s.repo.Get(cardId, &includes)
+	if err != nil {
+		return nil, err
+	}
+	currencyCode, err := card.GetCurrencyCode()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+	storage := s.storage
+	if transactional, ok := storage.(limit.TransactionalStorage); ok {
+		storage = transactional.WrapContext(tx)
+	}
+
+	if err := s.save(storage, cardId, currencyCode, controls); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.Get(cardId)
+}
+
+func (s *ControlsService) save(storage limit.Storage, cardId uint32, currencyCode string, controls *model.Controls) error {
+	if err := storage.Delete(controlsIdentifier("", cardId)); err != nil {
+		return errors.Wrapf(err, "failed to delete controls of card #%d", cardId)
+	}
+
+	amounts := map[string]*decimal.Decimal{
+		model.ControlMaxPerTransaction: controls.MaxPerTransaction,
+		model.ControlMaxPerDay:         controls.MaxPerDay,
+	}
+	for name, amount := range amounts {
+		if amount == nil {
+			continue
+		}
+		if err := storage.Save(limit.Val(*amount, currencyCode), controlsIdentifier(name, cardId)); err != nil {
+			return errors.Wrapf(err, "failed to save control %s of card #%d", name, cardId)
+		}
+	}
+
+	saved := make(map[string]bool)
+	for _, code := range controls.AllowedCurrencies {
+		code = strings.ToUpper(code)
+		if saved[code] {
+			continue
+		}
+		// only currency code matters for this control
+		value := limit.Val(decimal.Zero, code)
+		if err := storage.Save(value, controlsIdentifier(model.AllowedCurrencyControlName(code), cardId)); err != nil {
+			return errors.Wrapf(err, "failed to save allowed currency %s of card #%d", code, cardId)
+		}
+		saved[code] = true
+	}
+	return nil
+}
+
+func controlsIdentifier(name string, cardId uint32) limit.Identifier {
+	return limit.Identifier{
+		Name:     name,
+		Entity:   model.ControlsLimitEntity,
+		EntityId: fmt.Sprint(cardId),
+	}
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"github.com/Confialink/wallet-pkg-list_params"
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/limit"
+	cardTypeModel "github.com/Confialink/wallet-accounts/internal/modules/card-type/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/card/service"
+)
+
+// cardRepository returns the same card for any id
+type cardRepository struct {
+	repository.CardRepositoryInterface
+	card *model.Card
+}
+
+func (r *cardRepository) Get(uint32, *list_params.Includes) (*model.Card, error) {
+	return r.card, nil
+}
+
+var _ = Describe("ControlsService", func() {
+	It("should save each allowed currency under its own limit name", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		gdb, err := gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		card := &model.Card{
+			Id:       pointer.ToUint32(234),
+			CardType: &cardTypeModel.CardType{CurrencyCode: pointer.ToString("USD")},
+		}
+		service := NewControlsService(
+			gdb,
+			&cardRepository{card: card},
+			limit.NewStorageGORM(gdb),
+			limit.NewFactory(),
+		)
+
+		insert := "INSERT INTO `limits`"
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM `limits`").WithArgs("card", "234").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insert).
+			WithArgs("USD", sqlmock.AnyArg(), "card_allowed_currency:USD", "card", "234").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insert).
+			WithArgs("EUR", sqlmock.AnyArg(), "card_allowed_currency:EUR", "card", "234").
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(insert).
+			WithArgs("GBP", sqlmock.AnyArg(), "card_allowed_currency:GBP", "card", "234").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT \\* FROM `limits`").
+			WithArgs("card", "234").
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "amount", "currency_code", "name", "entity", "entity_id"}).
+				AddRow(1, "0", "USD", "card_allowed_currency:USD", "card", "234").
+				AddRow(2, "0", "EUR", "card_allowed_currency:EUR", "card", "234").
+				AddRow(3, "0", "GBP", "card_allowed_currency:GBP", "card", "234"))
+
+		controls, err := service.Update(234, &model.Controls{AllowedCurrencies: []string{"USD", "eur", "GBP", "EUR"}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(controls.AllowedCurrencies).To(Equal([]string{"USD", "EUR", "GBP"}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+})
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	cardTypeFormatModel "github.com/Confialink/wallet-accounts/internal/modules/card-type-format/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/notifications"
+	requestConstants "github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	requestRepository "github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	system_logs "github.com/Confialink/wallet-accounts/internal/modules/system-logs"
+	txConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	txModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
+	txRepository "github.com/Confialink/wallet-accounts/internal/modules/transaction/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/user"
+)
+
+const (
+	expiryMaxErrors         = 3
+	generateNumberMaxTries  = 10
+	alphanumericNumberChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	alphanumericNumberLen   = 16
+	sixteenNumericNumberLen = 16
+)
+
+// ExpiryService marks expired cards, notifies owners about upcoming expiration
+// and optionally renews expired cards
+type ExpiryService struct {
+	db                    *gorm.DB
+	repo                  repository.CardRepositoryInterface
+	requestRepository     requestRepository.RequestRepositoryInterface
+	transactionRepository *txRepository.TransactionRepository
+	settingsService       *settings.Service
+	notificationsService  *notifications.Service
+	systemLogsService     *system_logs.SystemLogsService
+	logger                log15.Logger
+}
+
+func NewExpiryService(
+	db *gorm.DB,
+	repo repository.CardRepositoryInterface,
+	requestRepository requestRepository.RequestRepositoryInterface,
+	transactionRepository *txRepository.TransactionRepository,
+	settingsService *settings.Service,
+	notificationsService *notifications.Service,
+	systemLogsService *system_logs.SystemLogsService,
+	logger log15.Logger,
+) *ExpiryService {
+	return &ExpiryService{
+		db:                    db,
+		repo:                  repo,
+		requestRepository:     requestRepository,
+		transactionRepository: transactionRepository,
+		settingsService:       settingsService,
+		notificationsService:  notificationsService,
+		systemLogsService:     systemLogsService,
+		logger:                logger.New("service", "CardExpiry"),
+	}
+}
+
+// NotifyExpiring notifies owners of cards which expire in configured amount of days.
+// It is supposed to be called once a day.
+func (s *ExpiryService) NotifyExpiring(now time.Time) {
+	logger := s.logger.New("method", "NotifyExpiring")
+
+	noticeDays, err := s.settingsService.Int64(SettingCardExpiryNoticeDaysInt64)
+	if err != nil {
+		noticeDays = defaultCardExpiryNoticeDays
+	}
+	if noticeDays <= 0 {
+		return
+	}
+
+	// cards expire at the beginning of the month that follows expiration month,
+	// so owners are notified on the day that is exactly "noticeDays" before it
+	expiresAt := now.AddDate(0, 0, int(noticeDays))
+	if expiresAt.Day() != 1 {
+		return
+	}
+	expirationMonth := expiresAt.AddDate(0, -1, 0)
+
+	cards, err := s.repo.GetListExpiringIn(expirationMonth.Year(), int(expirationMonth.Month()))
+	if err != nil {
+		logger.Error("failed to retrieve expiring cards", "error", err)
+		return
+	}
+
+	for _, card := range cards {
+		if err := s.notificationsService.TriggerCardExpiring(*card.UserId, *card.Id); err != nil {
+			logger.Error("failed to notify card owner", "error", err, "cardId", *card.Id)
+		}
+	}
+}
+
+// ExpireCards marks all expired cards and renews them if auto renewal is enabled
+func (s *ExpiryService) ExpireCards(now time.Time) {
+	logger := s.logger.New("method", "ExpireCards")
+
+	cards, err := s.repo.GetListExpiredBefore(now.Year(), int(now.Month()))
+	if err != nil {
+		logger.Error("failed to retrieve expired cards", "error", err)
+		return
+	}
+
+	autoRenewal, err := s.settingsService.Bool(SettingCardAutoRenewalBool)
+	if err != nil {
+		autoRenewal = false
+	}
+
+	errorsCount := 0
+	expiredCount := 0
+	for _, card := range cards {
+		if autoRenewal {
+			_, err = s.Renew(card, now)
+		} else {
+			err = s.expire(card)
+		}
+		if err != nil {
+			errorsCount++
+			logger.Error("failed to expire card", "error", err, "cardId", *card.Id)
+			if errorsCount >= expiryMaxErrors {
+				break
+			}
+			continue
+		}
+		expiredCount++
+	}
+
+	if expiredCount > 0 {
+		logger.Info("successfully expired cards", "count", expiredCount, "autoRenewal", autoRenewal)
+	}
+}
+
+// Renew issues a new card of the same card type for the owner of the given card,
+// moves the balance to the new card and marks the given card as expired
+func (s *ExpiryService) Renew(card *model.Card, now time.Time) (*model.Card, error) {
+	validityYears, err := s.settingsService.Int64(SettingCardRenewalValidityYearsInt64)
+	if err != nil || validityYears <= 0 {
+		validityYears = defaultCardRenewalValidityYears
+	}
+
+	number, err := s.generateNumber(card)
+	if err != nil {
+		return nil, err
+	}
+
+	expirationYear := int32(now.Year()) + int32(validityYears)
+	expirationMonth := int32(now.Month())
+	renewed := &model.Card{
+		Number:          &number,
+		Status:          pointer.ToString(model.StatusActive),
+		CardTypeId:      card.CardTypeId,
+		UserId:          card.UserId,
+		ExpirationYear:  &expirationYear,
+		ExpirationMonth: &expirationMonth,
+	}
+
+	tx := s.db.Begin()
+	expired, err := s.replace(tx, *card.Id, renewed, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	systemUser := user.GetSystemUser()
+	s.systemLogsService.LogCreateCardAsync(renewed, systemUser.UID)
+	s.logModify(expired, model.StatusExpired, decimal.Zero)
+
+	if err := s.notificationsService.TriggerCardRenewed(*renewed.UserId, *renewed.Id); err != nil {
+		s.logger.Error("failed to notify card owner", "error", err, "cardId", *renewed.Id)
+	}
+
+	return renewed, nil
+}
+
+// replace locks the expiring card, creates its renewal and moves the balance to it,
+// the locked card is returned as it has been before the expiration
+func (s *ExpiryService) replace(tx *gorm.DB, cardId uint32, renewed *model.Card, now time.Time) (*model.Card, error) {
+	repo := s.repo.WrapContext(tx)
+	card, err := repo.GetForUpdate(cardId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to lock card #%d", cardId)
+	}
+	if card.Status != nil && *card.Status == model.StatusExpired {
+		return nil, errors.Errorf("card #%d has been already expired", cardId)
+	}
+
+	balance := decimal.Zero
+	if card.Balance != nil {
+		balance = *card.Balance
+	}
+	renewed.Balance = &balance
+	if _, err := repo.Create(renewed); err != nil {
+		return nil, errors.Wrapf(err, "failed to create renewal of card #%d", cardId)
+	}
+	_, err = repo.UpdateFields(cardId, map[string]interface{}{
+		"Status":  model.StatusExpired,
+		"Balance": decimal.Zero,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to expire card #%d", cardId)
+	}
+
+	if !balance.IsZero() {
+		if err := s.postBalanceTransfer(tx, card, renewed, balance, now); err != nil {
+			return nil, errors.Wrapf(err, "failed to move balance of card #%d", cardId)
+		}
+	}
+	return card, nil
+}
+
+// postBalanceTransfer records moving of the balance to the renewed card as executed system request
+// with transactions of both cards, so that the balance change could be seen in the history of cards
+func (s *ExpiryService) postBalanceTransfer(
+	tx *gorm.DB,
+	card, renewed *model.Card,
+	balance decimal.Decimal,
+	now time.Time,
+) error {
+	if card.CardType == nil || card.CardType.CurrencyCode == nil {
+		return errors.Errorf("card type of card #%d is not found", *card.Id)
+	}
+	currencyCode := *card.CardType.CurrencyCode
+	subject := requestConstants.SubjectCardRenewal
+	description := fmt.Sprintf("Balance transfer from expired card %s", *card.Number)
+	rate := decimal.NewFromInt(1)
+	request := &requestModel.Request{
+		Subject:               &subject,
+		Description:           &description,
+		Status:                pointer.ToString(requestConstants.StatusExecuted),
+		UserId:                card.UserId,
+		IsInitiatedByAdmin:    pointer.ToBool(false),
+		IsInitiatedBySystem:   pointer.ToBool(true),
+		BaseCurrencyCode:      &currencyCode,
+		ReferenceCurrencyCode: &currencyCode,
+		Amount:                &balance,
+		RateDesignation:       requestModel.RateDesignationBaseReference,
+		Rate:                  &rate,
+		StatusChangedAt:       &now,
+		IsVisible:             pointer.ToBool(true),
+	}
+	requestInput := request.GetInput()
+	requestInput.Set("sourceCardId", *card.Id)
+	requestInput.Set("destinationCardId", *renewed.Id)
+	if err := s.requestRepository.WrapContext(tx).Create(request); err != nil {
+		return err
+	}
+
+	transactions := []*txModel.Transaction{
+		{
+			RequestId:                request.Id,
+			CardId:                   card.Id,
+			Status:                   pointer.ToString(txModel.StatusExecuted),
+			Description:              &description,
+			Amount:                   pointer.ToDecimal(balance.Neg()),
+			IsVisible:                pointer.ToBool(true),
+			AvailableBalanceSnapshot: pointer.ToDecimal(decimal.Zero),
+			CurrentBalanceSnapshot:   pointer.ToDecimal(decimal.Zero),
+			Type:                     pointer.ToString(txModel.TypeCard),
+			Purpose:                  pointer.ToString(txConstants.PurposeCardRenewalOutgoing.String()),
+		},
+		{
+			RequestId:                request.Id,
+			CardId:                   renewed.Id,
+			Status:                   pointer.ToString(txModel.StatusExecuted),
+			Description:              &description,
+			Amount:                   pointer.ToDecimal(balance),
+			IsVisible:                pointer.ToBool(true),
+			AvailableBalanceSnapshot: pointer.ToDecimal(balance),
+			CurrentBalanceSnapshot:   pointer.ToDecimal(balance),
+			Type:                     pointer.ToString(txModel.TypeCard),
+			Purpose:                  pointer.ToString(txConstants.PurposeCardRenewalIncoming.String()),
+		},
+	}
+	txRepo := s.transactionRepository.WrapContext(tx)
+	for _, transaction := range transactions {
+		if err := txRepo.Create(transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ExpiryService) expire(card *model.Card) error {
+	_, err := s.repo.UpdateFields(*card.Id, map[string]interface{}{"Status": model.StatusExpired})
+	if err != nil {
+		return err
+	}
+	balance := decimal.Zero
+	if card.Balance != nil {
+		balance = *card.Balance
+	}
+	s.logModify(card, model.StatusExpired, balance)
+	return nil
+}
+
+func (s *ExpiryService) logModify(old *model.Card, status string, balance decimal.Decimal) {
+	updated := *old
+	updated.Status = &status
+	updated.Balance = &balance
+	systemUser := user.GetSystemUser()
+	s.systemLogsService.LogModifyCardAsync(old, &updated, systemUser.UID)
+}
+
+// generateNumber generates unique card number which satisfies format of the given card type
+func (s *ExpiryService) generateNumber(card *model.Card) (string, error) {
+	chars, length := alphanumericNumberChars, alphanumericNumberLen
+	if card.CardType != nil && card.CardType.Format != nil && card.CardType.Format.Code != nil &&
+		*card.CardType.Format.Code == cardTypeFormatModel.CodeSixteenNumeric {
+		chars, length = "0123456789", sixteenNumericNumberLen
+	}
+
+	for i := 0; i < generateNumberMaxTries; i++ {
+		number, err := randomString(chars, length)
+		if err != nil {
+			return "", err
+		}
+		if existing, _ := s.repo.GetByNumber(number, nil); existing == nil {
+			return number, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate unique card number after %d tries", generateNumberMaxTries)
+}
+
+func randomString(chars string, length int) (string, error) {
+	result := make([]byte, length)
+	max := big.NewInt(int64(len(chars)))
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = chars[n.Int64()]
+	}
+	return string(result), nil
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/card/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/card/service"
+	requestRepository "github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	txConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	txRepository "github.com/Confialink/wallet-accounts/internal/modules/transaction/repository"
+)
+
+var _ = Describe("ExpiryService", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *ExpiryService
+	)
+	any := sqlmock.AnyArg()
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err = gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		service = NewExpiryService(
+			gdb,
+			repository.NewCardRepository(gdb, nil, log15.New()),
+			requestRepository.NewRequestRepository(gdb, nil, nil, nil),
+			txRepository.NewTransactionRepository(gdb),
+			nil,
+			nil,
+			nil,
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectLockedCard := func(balance, status string) {
+		mock.ExpectQuery("SELECT \\* FROM `cards` WHERE `cards`.`id` = \\? FOR UPDATE").
+			WithArgs(234).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "number", "balance", "status", "card_type_id", "user_id"}).
+				AddRow(234, "OLD1", balance, status, 5, "user-1"))
+		mock.ExpectQuery("SELECT \\* FROM `card_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(5, "EUR"))
+	}
+	renewal := func() *model.Card {
+		return &model.Card{
+			Number:     pointer.ToString("NEW1"),
+			Status:     pointer.ToString(model.StatusActive),
+			CardTypeId: pointer.ToUint32(5),
+			UserId:     pointer.ToString("user-1"),
+		}
+	}
+
+	It("should move the locked balance to the renewal with transactions of both cards", func() {
+		mock.ExpectBegin()
+		expectLockedCard("25.50", model.StatusActive)
+		mock.ExpectExec("INSERT INTO `cards`").
+			WithArgs("NEW1", "25.5", model.StatusActive, 5, "user-1", any, any, any, any).
+			WillReturnResult(sqlmock.NewResult(235, 1))
+		mock.ExpectExec("UPDATE `cards` SET `balance` = \\?, `status` = \\?").
+			WithArgs("0", model.StatusExpired, any, 234).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `requests`").WillReturnResult(sqlmock.NewResult(77, 1))
+		mock.ExpectExec("INSERT INTO `transactions`").
+			WithArgs(77, nil, 234, nil, "executed", any, "-25.5", nil, "0", nil, true, "0", nil, "card",
+				txConstants.PurposeCardRenewalOutgoing.String(), any, any).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO `transactions`").
+			WithArgs(77, nil, 235, nil, "executed", any, "25.5", nil, "25.5", nil, true, "25.5", nil, "card",
+				txConstants.PurposeCardRenewalIncoming.String(), any, any).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		tx := gdb.Begin()
+		renewed := renewal()
+		expired, err := ReplaceExpiredCard(service, tx, 234, renewed, now)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tx.Commit().Error).ShouldNot(HaveOccurred())
+
+		Expect(renewed.Balance.String()).To(Equal("25.5"))
+		Expect(expired.Balance.String()).To(Equal("25.5"))
+	})
+
+	It("should not post transactions for zero balance", func() {
+		mock.ExpectBegin()
+		expectLockedCard("0", model.StatusActive)
+		mock.ExpectExec("INSERT INTO `cards`").WillReturnResult(sqlmock.NewResult(235, 1))
+		mock.ExpectExec("UPDATE `cards`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx := gdb.Begin()
+		_, err := ReplaceExpiredCard(service, tx, 234, renewal(), now)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tx.Commit().Error).ShouldNot(HaveOccurred())
+	})
+
+	It("should not renew the card which has been expired concurrently", func() {
+		mock.ExpectBegin()
+		expectLockedCard("25.50", model.StatusExpired)
+		mock.ExpectRollback()
+
+		tx := gdb.Begin()
+		_, err := ReplaceExpiredCard(service, tx, 234, renewal(), now)
+		Expect(err).To(HaveOccurred())
+		tx.Rollback()
+	})
+})
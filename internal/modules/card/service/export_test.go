@@ -0,0 +1,20 @@
+package service
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/card/model"
+)
+
+// ReplaceExpiredCard exposes creating of the renewal of the card within the transaction
+func ReplaceExpiredCard(
+	s *ExpiryService,
+	tx *gorm.DB,
+	cardId uint32,
+	renewed *model.Card,
+	now time.Time,
+) (*model.Card, error) {
+	return s.replace(tx, cardId, renewed, now)
+}
@@ -0,0 +1,17 @@
+package service
+
+import "github.com/Confialink/wallet-accounts/internal/modules/settings"
+
+const (
+	// SettingCardExpiryNoticeDaysInt64 defines how many days before expiration owners are notified
+	SettingCardExpiryNoticeDaysInt64 = settings.Name("card_expiry_notice_days")
+	// SettingCardAutoRenewalBool enables issuing of a new card when the previous one expires
+	SettingCardAutoRenewalBool = settings.Name("card_auto_renewal")
+	// SettingCardRenewalValidityYearsInt64 defines validity period of a renewed card
+	SettingCardRenewalValidityYearsInt64 = settings.Name("card_renewal_validity_years")
+)
+
+const (
+	defaultCardExpiryNoticeDays     = 30
+	defaultCardRenewalValidityYears = 3
+)
@@ -0,0 +1,68 @@
+package currency
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Confialink/wallet-accounts/internal/exchange"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+const defaultRateCacheTtlSeconds = 60
+
+// settingsCachePolicy is exchange.CachePolicy configured by settings
+type settingsCachePolicy struct {
+	settings *settings.Service
+}
+
+func NewSettingsCachePolicy(settings *settings.Service) exchange.CachePolicy {
+	return &settingsCachePolicy{settings: settings}
+}
+
+// TTL returns TTL of the pair if it is listed in pair TTL setting or default TTL otherwise
+func (p *settingsCachePolicy) TTL(base, reference string) time.Duration {
+	pairs, err := p.settings.String(SettingRateCachePairTtlString)
+	if err == nil {
+		if ttl, ok := parsePairTtl(pairs)[base+"/"+reference]; ok {
+			return ttl
+		}
+	}
+	seconds, err := p.settings.Int64(SettingRateCacheTtlSecondsInt64)
+	if err != nil || seconds < 0 {
+		seconds = defaultRateCacheTtlSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (p *settingsCachePolicy) MaxStaleness() time.Duration {
+	seconds, err := p.settings.Int64(SettingRateMaxStalenessSecondsInt64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// parsePairTtl parses "EUR/USD=30,USD/JPY=120" into TTL by pair, invalid items are skipped
+func parsePairTtl(value string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			log.Printf("currency: invalid exchange rate cache TTL %q", item)
+			continue
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || seconds < 0 {
+			log.Printf("currency: invalid exchange rate cache TTL %q", item)
+			continue
+		}
+		result[strings.ToUpper(strings.TrimSpace(parts[0]))] = time.Duration(seconds) * time.Second
+	}
+	return result
+}
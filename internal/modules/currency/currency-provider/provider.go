@@ -1,8 +1,11 @@
 package currency_provider
 
 import (
+	"github.com/Confialink/wallet-accounts/internal/exchange"
 	"github.com/Confialink/wallet-accounts/internal/modules/currency"
 	"github.com/Confialink/wallet-accounts/internal/modules/currency/connection"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/http/handler"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/repository"
 	"github.com/Confialink/wallet-accounts/internal/modules/currency/serializer"
 	"github.com/Confialink/wallet-accounts/internal/modules/currency/service"
 )
@@ -13,6 +16,10 @@ func Providers() []interface{} {
 		serializer.NewCurrencySerializer,
 		service.NewCurrenciesService,
 		currency.NewProvider,
-		currency.NewRateSource,
+		currency.NewExchangeRateSource,
+		exchange.NewCacheCounter,
+		handler.NewRatesHandler,
+		currency.NewRatesImporter,
+		repository.NewLocalRate,
 	}
 }
@@ -11,6 +11,7 @@ import (
 	mock_service "github.com/Confialink/wallet-accounts/internal/modules/currency/service/mock"
 	"github.com/Confialink/wallet-accounts/internal/transfer"
 	"errors"
+	"strings"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -93,6 +94,48 @@ var _ = Describe("Currency", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+	Context("Rates file", func() {
+		It("should parse CSV rates with optional header", func() {
+			rates, err := currency.ParseCsvRates(strings.NewReader("base,reference,rate\nEUR,USD,1.11\n# comment\nusd, jpy, 108.5\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rates).To(HaveLen(2))
+			Expect(rates[0].BaseCurrencyCode()).To(Equal("EUR"))
+			Expect(rates[0].ReferenceCurrencyCode()).To(Equal("USD"))
+			Expect(rates[0].Rate()).To(decEqual(str2Dec("1.11")))
+			Expect(rates[1].BaseCurrencyCode()).To(Equal("USD"))
+			Expect(rates[1].ReferenceCurrencyCode()).To(Equal("JPY"))
+			Expect(rates[1].Rate()).To(decEqual(str2Dec("108.5")))
+		})
+
+		It("should reject CSV rates which are not positive", func() {
+			_, err := currency.ParseCsvRates(strings.NewReader("EUR,USD,1.11\nEUR,JPY,0\n"))
+			Expect(err).To(HaveOccurred())
+			_, err = currency.ParseCsvRates(strings.NewReader("EUR,USD,1.11\nEUR,JPY,abc\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should parse the latest day of ECB rates", func() {
+			xml := `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2020-01-02">
+			<Cube currency="USD" rate="1.1193"/>
+			<Cube currency="JPY" rate="121.75"/>
+		</Cube>
+		<Cube time="2020-01-03">
+			<Cube currency="USD" rate="1.1147"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+			rates, err := currency.ParseEcbRates(strings.NewReader(xml))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rates).To(HaveLen(1))
+			Expect(rates[0].BaseCurrencyCode()).To(Equal("EUR"))
+			Expect(rates[0].ReferenceCurrencyCode()).To(Equal("USD"))
+			Expect(rates[0].Rate()).To(decEqual(str2Dec("1.1147")))
+		})
+	})
 })
 
 func str2Dec(v string) decimal.Decimal {
@@ -11,4 +11,5 @@ func (e Error) Error() string {
 
 const (
 	ErrExchangeRateNotFound = Error(errcodes.CodeExchangeRateNotFound)
+	ErrExchangeRateStale    = Error(errcodes.CodeExchangeRateStale)
 )
@@ -5,8 +5,12 @@ import (
 	currenciesService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
 	"github.com/pkg/errors"
 	"strings"
+	"time"
 )
 
+// remoteRateSourceName is source of rates obtained from currencies service
+const remoteRateSourceName = "currencies"
+
 type rateSource struct {
 	currenciesService currenciesService.CurrenciesServiceInterface
 }
@@ -28,5 +32,5 @@ func (r *rateSource) FindRate(base, reference string) (exchange.Rate, error) {
 			reference,
 		)
 	}
-	return exchange.NewRate(base, reference, rate.Rate), nil
+	return exchange.NewRate(base, reference, rate.Rate).WithOrigin(remoteRateSourceName, time.Now()), nil
 }
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Confialink/wallet-accounts/internal/exchange"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+)
+
+// RatesHandler exposes state of exchange rates
+type RatesHandler struct {
+	cacheMetrics *exchange.CacheCounter
+}
+
+func NewRatesHandler(cacheMetrics *exchange.CacheCounter) *RatesHandler {
+	return &RatesHandler{cacheMetrics: cacheMetrics}
+}
+
+// CacheStats returns numbers of exchange rate cache hits and misses since start
+func (h *RatesHandler) CacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, response.New().SetData(h.cacheMetrics.Stats()))
+}
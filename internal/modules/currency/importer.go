@@ -0,0 +1,79 @@
+package currency
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+// RatesImporter imports rates files into local rates table
+type RatesImporter struct {
+	repository *repository.LocalRate
+	settings   *settings.Service
+	logger     log15.Logger
+	now        func() time.Time
+}
+
+func NewRatesImporter(
+	repository *repository.LocalRate,
+	settings *settings.Service,
+	logger log15.Logger,
+) *RatesImporter {
+	return &RatesImporter{
+		repository: repository,
+		settings:   settings,
+		logger:     logger.New("service", "RatesImporter"),
+		now:        time.Now,
+	}
+}
+
+// Import reads rates from the given CSV or ECB XML file and saves them, it returns number of imported rates
+func (i *RatesImporter) Import(path string) (int, error) {
+	rates, err := ParseRatesFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	now := i.now()
+	localRates := make([]*model.LocalRate, 0, len(rates))
+	for _, rate := range rates {
+		localRates = append(localRates, &model.LocalRate{
+			BaseCurrencyCode:      rate.BaseCurrencyCode(),
+			ReferenceCurrencyCode: rate.ReferenceCurrencyCode(),
+			Rate:                  rate.Rate(),
+			Source:                filepath.Base(path),
+			ImportedAt:            now,
+		})
+	}
+	if err := i.repository.Replace(localRates); err != nil {
+		return 0, errors.Wrap(err, "failed to save imported rates")
+	}
+	return len(localRates), nil
+}
+
+// ImportConfigured imports rates file configured in settings, nothing is done if the file is not configured
+func (i *RatesImporter) ImportConfigured() {
+	logger := i.logger.New("method", "ImportConfigured")
+	path, err := i.settings.String(SettingLocalRatesFileString)
+	if err != nil {
+		logger.Error("failed to retrieve rates file setting", "error", err)
+		return
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return
+	}
+	count, err := i.Import(path)
+	if err != nil {
+		logger.Error("failed to import rates file", "error", err, "path", path)
+		return
+	}
+	logger.Info("rates file is imported", "path", path, "count", count)
+}
@@ -0,0 +1,88 @@
+package currency
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/exchange"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/repository"
+	currenciesService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+// localPivotCurrencyCode is used in order to calculate cross rates from imported ones,
+// ECB rates files are EUR based
+const localPivotCurrencyCode = "EUR"
+
+// localRateSourceName is prefix of imported rates source, it is followed by the rates file name
+const localRateSourceName = "local:"
+
+type localRateSource struct {
+	repository *repository.LocalRate
+}
+
+// NewLocalRateSource creates rate source backed by rates imported from a rates file
+func NewLocalRateSource(repository *repository.LocalRate) exchange.RateSource {
+	return &localRateSource{repository: repository}
+}
+
+func (l *localRateSource) FindRate(base, reference string) (exchange.Rate, error) {
+	if base == reference {
+		return exchange.NewRate(base, reference, decimal.NewFromInt(1)), nil
+	}
+	rate, err := l.repository.Find(base, reference)
+	if err != nil {
+		return exchange.Rate{}, errors.Wrapf(err, "failed to retrieve local rate %s/%s", base, reference)
+	}
+	if rate == nil {
+		return exchange.Rate{}, errors.Wrapf(exchange.ErrRateNotFound, "local rate not found %s/%s", base, reference)
+	}
+	return exchange.NewRate(base, reference, rate.Rate).WithOrigin(localRateSourceName+rate.Source, rate.ImportedAt), nil
+}
+
+// selectingRateSource uses currencies service and falls back to imported rates if the service fails,
+// only imported rates are used if it is configured in settings
+type selectingRateSource struct {
+	settings *settings.Service
+	remote   exchange.RateSource
+	local    exchange.RateSource
+}
+
+func (s *selectingRateSource) FindRate(base, reference string) (exchange.Rate, error) {
+	if localOnly, _ := s.settings.Bool(SettingLocalRatesOnlyBool); localOnly {
+		return s.local.FindRate(base, reference)
+	}
+	return exchange.NewFallbackSource(s.remote, s.local).FindRate(base, reference)
+}
+
+// exchangeRateSource caches rates according to settings and refuses stale rates
+type exchangeRateSource struct {
+	cache exchange.RateSource
+}
+
+// NewExchangeRateSource creates rate source which is used by the application
+func NewExchangeRateSource(
+	currenciesService currenciesService.CurrenciesServiceInterface,
+	localRates *repository.LocalRate,
+	settings *settings.Service,
+	metrics *exchange.CacheCounter,
+) exchange.RateSource {
+	local := NewLocalRateSource(localRates)
+	reverse := exchange.NewReverseRateSource(local)
+	selecting := &selectingRateSource{
+		settings: settings,
+		remote:   NewRateSource(currenciesService),
+		local:    exchange.NewFallbackSource(reverse, exchange.NewPivotRateSource(localPivotCurrencyCode, reverse)),
+	}
+	return &exchangeRateSource{
+		cache: exchange.NewCacheSourceWithPolicy(selecting, NewSettingsCachePolicy(settings), metrics),
+	}
+}
+
+func (e *exchangeRateSource) FindRate(base, reference string) (exchange.Rate, error) {
+	rate, err := e.cache.FindRate(base, reference)
+	if err != nil && errors.Cause(err) == exchange.ErrRateStale {
+		return rate, errors.Wrap(ErrExchangeRateStale, err.Error())
+	}
+	return rate, err
+}
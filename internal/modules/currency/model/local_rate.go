@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LocalRate is exchange rate imported from a rates file, it is used when rates are not taken from currencies service
+type LocalRate struct {
+	Id                    uint64          `json:"id"`
+	BaseCurrencyCode      string          `json:"baseCurrencyCode"`
+	ReferenceCurrencyCode string          `json:"referenceCurrencyCode"`
+	Rate                  decimal.Decimal `json:"rate"`
+	// Source is name of the file which the rate has been imported from
+	Source     string    `json:"source"`
+	ImportedAt time.Time `json:"importedAt"`
+}
+
+func (*LocalRate) TableName() string {
+	return "currency_local_rates"
+}
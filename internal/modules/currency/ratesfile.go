@@ -0,0 +1,123 @@
+package currency
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/exchange"
+)
+
+// ecbBaseCurrencyCode is base currency of rates published by the European Central Bank
+const ecbBaseCurrencyCode = "EUR"
+
+// ecbEnvelope is a subset of ECB reference rates XML (e.g. eurofxref-daily.xml)
+// <Cube><Cube time="2020-01-02"><Cube currency="USD" rate="1.1193"/>...</Cube></Cube>
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseRatesFile reads rates from the given file, files with ".xml" extension are parsed as ECB XML
+// and any other files as CSV
+func ParseRatesFile(path string) ([]exchange.Rate, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open rates file %s", path)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		return ParseEcbRates(file)
+	}
+	return ParseCsvRates(file)
+}
+
+// ParseCsvRates reads rates from CSV with "base,reference,rate" columns, the header row is optional
+func ParseCsvRates(r io.Reader) ([]exchange.Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	rates := make([]exchange.Rate, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CSV rates")
+		}
+		value, err := decimal.NewFromString(strings.TrimSpace(record[2]))
+		if err != nil {
+			if line == 1 {
+				// header
+				continue
+			}
+			return nil, errors.Wrapf(err, "invalid rate on line %d", line)
+		}
+		rate, err := newImportedRate(record[0], record[1], value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rate on line %d", line)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// ParseEcbRates reads EUR based rates from ECB reference rates XML, only the latest day is taken
+func ParseEcbRates(r io.Reader) ([]exchange.Rate, error) {
+	envelope := &ecbEnvelope{}
+	if err := xml.NewDecoder(r).Decode(envelope); err != nil {
+		return nil, errors.Wrap(err, "failed to read ECB rates")
+	}
+	if len(envelope.Days) == 0 {
+		return nil, errors.New("ECB rates file does not contain any rates")
+	}
+
+	latest := envelope.Days[0]
+	for _, day := range envelope.Days[1:] {
+		// dates are in ISO format so they could be compared as strings
+		if day.Time > latest.Time {
+			latest = day
+		}
+	}
+
+	rates := make([]exchange.Rate, 0, len(latest.Rates))
+	for _, item := range latest.Rates {
+		value, err := decimal.NewFromString(item.Rate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rate of %s", item.Currency)
+		}
+		rate, err := newImportedRate(ecbBaseCurrencyCode, item.Currency, value)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func newImportedRate(base, reference string, value decimal.Decimal) (exchange.Rate, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	reference = strings.ToUpper(strings.TrimSpace(reference))
+	if base == "" || reference == "" {
+		return exchange.Rate{}, errors.New("currency code is empty")
+	}
+	if value.LessThanOrEqual(decimal.Zero) {
+		return exchange.Rate{}, errors.Errorf("rate %s -> %s must be positive", base, reference)
+	}
+	return exchange.NewRate(base, reference, value), nil
+}
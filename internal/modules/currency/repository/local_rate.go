@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/model"
+)
+
+type LocalRate struct {
+	db *gorm.DB
+}
+
+func NewLocalRate(db *gorm.DB) *LocalRate {
+	return &LocalRate{db: db}
+}
+
+// Find retrieves rate for the given currencies, nil is returned if the rate is not imported
+func (r *LocalRate) Find(base, reference string) (*model.LocalRate, error) {
+	rate := &model.LocalRate{}
+	err := r.db.
+		Where("base_currency_code = ? AND reference_currency_code = ?", base, reference).
+		First(rate).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return rate, err
+}
+
+// Replace creates the given rates, existing rates for the same currencies are replaced
+func (r *LocalRate) Replace(rates []*model.LocalRate) error {
+	tx := r.db.Begin()
+	for _, rate := range rates {
+		err := tx.
+			Where("base_currency_code = ? AND reference_currency_code = ?", rate.BaseCurrencyCode, rate.ReferenceCurrencyCode).
+			Delete(&model.LocalRate{}).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Create(rate).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,16 @@
+package currency
+
+import "github.com/Confialink/wallet-accounts/internal/modules/settings"
+
+const (
+	// SettingLocalRatesOnlyBool makes exchange use only imported rates (frozen rates)
+	SettingLocalRatesOnlyBool = settings.Name("local_rates_only")
+	// SettingLocalRatesFileString is path of the rates file which is imported by schedule, import is disabled if empty
+	SettingLocalRatesFileString = settings.Name("local_rates_file")
+	// SettingRateCacheTtlSecondsInt64 is how long exchange rates are cached, zero means forever
+	SettingRateCacheTtlSecondsInt64 = settings.Name("exchange_rate_cache_ttl_seconds")
+	// SettingRateCachePairTtlString overrides cache TTL for currency pairs e.g. "EUR/USD=30,USD/JPY=120"
+	SettingRateCachePairTtlString = settings.Name("exchange_rate_cache_pair_ttl")
+	// SettingRateMaxStalenessSecondsInt64 is maximum age of an exchange rate which could be used, zero means no limit
+	SettingRateMaxStalenessSecondsInt64 = settings.Name("exchange_rate_max_staleness_seconds")
+)
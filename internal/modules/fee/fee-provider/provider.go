@@ -10,8 +10,12 @@ func Providers() []interface{} {
 	return []interface{}{
 		repository.NewTransferFee,
 		repository.NewTransferFeeParameters,
+		repository.NewExchangeMargin,
+		repository.NewRequestUsage,
 		fee.NewServiceTransferFee,
+		fee.NewServiceExchangeMargin,
 
 		handler.NewTransferFee,
+		handler.NewExchangeMargin,
 	}
 }
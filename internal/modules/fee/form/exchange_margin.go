@@ -0,0 +1,62 @@
+package form
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+)
+
+var maxExchangeMarginPercent = decimal.NewFromInt(100)
+
+type ExchangeMargin struct {
+	Name                  *string `json:"name" binding:"required"`
+	BaseCurrencyCode      *string `json:"baseCurrencyCode" binding:"omitempty,len=3"`
+	ReferenceCurrencyCode *string `json:"referenceCurrencyCode" binding:"omitempty,len=3"`
+	UserGroupId           *uint64 `json:"userGroupId"`
+	MinAmount             *string `json:"minAmount" binding:"omitempty,decimal,decimalGT=0"`
+	Percent               *string `json:"percent" binding:"required,decimal"`
+}
+
+// ExchangeMarginPreview is a query of the exchange which margin is previewed for
+type ExchangeMarginPreview struct {
+	BaseCurrencyCode      string  `form:"baseCurrencyCode" binding:"required,len=3"`
+	ReferenceCurrencyCode string  `form:"referenceCurrencyCode" binding:"required,len=3"`
+	UserGroupId           *uint64 `form:"userGroupId"`
+	Amount                string  `form:"amount" binding:"required,decimal"`
+}
+
+// ToModel fills the given model with form values, percent must be in [0, 100) range
+func (e *ExchangeMargin) ToModel(marginModel *model.ExchangeMargin) error {
+	percent, err := decimal.NewFromString(*e.Percent)
+	if err != nil {
+		return err
+	}
+	if percent.IsNegative() || percent.GreaterThanOrEqual(maxExchangeMarginPercent) {
+		return errcodes.CreatePublicError(errcodes.CodeInvalidExchangeMargin)
+	}
+
+	var minAmount *decimal.Decimal
+	if e.MinAmount != nil {
+		value, err := decimal.NewFromString(*e.MinAmount)
+		if err != nil {
+			return err
+		}
+		minAmount = &value
+	}
+
+	marginModel.Name = e.Name
+	marginModel.BaseCurrencyCode = emptyToNil(e.BaseCurrencyCode)
+	marginModel.ReferenceCurrencyCode = emptyToNil(e.ReferenceCurrencyCode)
+	marginModel.UserGroupId = e.UserGroupId
+	marginModel.MinAmount = minAmount
+	marginModel.Percent = &percent
+	return nil
+}
+
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
@@ -21,3 +21,12 @@ type UserGroupRelation struct {
 	//i.e. should the user group be attached or detached
 	Attached *bool `json:"attached" binding:"exists"`
 }
+
+// TransferFeeSimulation is a draft fee configuration which is replayed against executed requests
+// created within the date range (dates are inclusive, in YYYY-MM-DD format)
+type TransferFeeSimulation struct {
+	RequestSubject *constants.Subject        `json:"requestSubject" binding:"required"`
+	DateFrom       *string                   `json:"dateFrom" binding:"required"`
+	DateTo         *string                   `json:"dateTo" binding:"required"`
+	Parameters     TransferFeeParametersList `json:"parameters" binding:"dive"`
+}
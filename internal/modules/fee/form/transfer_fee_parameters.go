@@ -12,6 +12,20 @@ type TransferFeeParameters struct {
 	Percent      *string `json:"percent" binding:"omitempty,decimal,decimalGT=0"`
 	Max          *string `json:"max" binding:"omitempty,decimal,decimalGT=0"`
 	Delete       *bool   `json:"delete"`
+	FreePerMonth *uint64 `json:"freePerMonth"`
+	// Tiers and VolumeDiscounts replace the existing ones if they are passed, empty list removes them
+	Tiers           []*TransferFeeTier           `json:"tiers" binding:"omitempty,dive"`
+	VolumeDiscounts []*TransferFeeVolumeDiscount `json:"volumeDiscounts" binding:"omitempty,dive"`
+}
+
+type TransferFeeTier struct {
+	MinAmount *string `json:"minAmount" binding:"required,decimal,decimalGT=0"`
+	Percent   *string `json:"percent" binding:"required,decimal,decimalGTE=0"`
+}
+
+type TransferFeeVolumeDiscount struct {
+	MinVolume       *string `json:"minVolume" binding:"required,decimal,decimalGT=0"`
+	DiscountPercent *string `json:"discountPercent" binding:"required,decimal,decimalGT=0"`
 }
 
 type TransferFeeParametersList []*TransferFeeParameters
@@ -44,6 +58,40 @@ func (t *TransferFeeParameters) ToModel() (*model.TransferFeeParameters, error)
 		return nil, err
 	}
 	parametersModel.Max = max
+	parametersModel.FreePerMonth = t.FreePerMonth
+
+	if t.Tiers != nil {
+		parametersModel.Tiers = make([]*model.TransferFeeTier, len(t.Tiers))
+		for i, tier := range t.Tiers {
+			minAmount, err := decimal.NewFromString(*tier.MinAmount)
+			if err != nil {
+				return nil, err
+			}
+			percent, err := decimal.NewFromString(*tier.Percent)
+			if err != nil {
+				return nil, err
+			}
+			parametersModel.Tiers[i] = &model.TransferFeeTier{MinAmount: &minAmount, Percent: &percent}
+		}
+	}
+
+	if t.VolumeDiscounts != nil {
+		parametersModel.VolumeDiscounts = make([]*model.TransferFeeVolumeDiscount, len(t.VolumeDiscounts))
+		for i, discount := range t.VolumeDiscounts {
+			minVolume, err := decimal.NewFromString(*discount.MinVolume)
+			if err != nil {
+				return nil, err
+			}
+			discountPercent, err := decimal.NewFromString(*discount.DiscountPercent)
+			if err != nil {
+				return nil, err
+			}
+			parametersModel.VolumeDiscounts[i] = &model.TransferFeeVolumeDiscount{
+				MinVolume:       &minVolume,
+				DiscountPercent: &discountPercent,
+			}
+		}
+	}
 
 	return parametersModel, nil
 }
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/Confialink/wallet-pkg-list_params"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	httpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	currencyService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+)
+
+type ExchangeMargin struct {
+	serviceExchangeMargin    *fee.ServiceExchangeMargin
+	exchangeMarginRepository *repository.ExchangeMargin
+	currencyService          currencyService.CurrenciesServiceInterface
+	contextService           httpService.ContextInterface
+	logger                   log15.Logger
+}
+
+func NewExchangeMargin(
+	serviceExchangeMargin *fee.ServiceExchangeMargin,
+	exchangeMarginRepository *repository.ExchangeMargin,
+	currencyService currencyService.CurrenciesServiceInterface,
+	contextService httpService.ContextInterface,
+	logger log15.Logger,
+) *ExchangeMargin {
+	return &ExchangeMargin{
+		serviceExchangeMargin:    serviceExchangeMargin,
+		exchangeMarginRepository: exchangeMarginRepository,
+		currencyService:          currencyService,
+		contextService:           contextService,
+		logger:                   logger.New("Handler", "ExchangeMargin"),
+	}
+}
+
+func (e *ExchangeMargin) List(c *gin.Context) {
+	params := list_params.NewListParamsFromQuery(c.Request.URL.RawQuery, model.ExchangeMargin{})
+	params.AllowPagination()
+	params.AllowFilters([]string{"baseCurrencyCode", "referenceCurrencyCode", "userGroupId"})
+	params.AllowSortings([]string{"id", "name", "minAmount", "percent"})
+
+	if ok, errorsList := params.Validate(); !ok {
+		errcodes.AddErrorMeta(c, errcodes.CodeInvalidQueryParameters, errorsList)
+		return
+	}
+
+	margins, err := e.exchangeMarginRepository.GetList(params)
+	if err != nil {
+		privateError := errors.PrivateError{Message: "failed to get exchange margins"}
+		privateError.AddLogPair("error", err.Error())
+		errors.AddErrors(c, &privateError)
+		return
+	}
+	c.JSON(http.StatusOK, response.NewWithList(margins))
+}
+
+func (e *ExchangeMargin) Get(c *gin.Context) {
+	id, typedErr := e.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	margin, err := e.exchangeMarginRepository.FindById(id)
+	if err != nil {
+		errcodes.AddError(c, errcodes.CodeExchangeMarginNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, response.New().SetData(margin))
+}
+
+func (e *ExchangeMargin) Create(c *gin.Context) {
+	marginForm := &form.ExchangeMargin{}
+	if err := c.ShouldBind(marginForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	margin, err := e.serviceExchangeMargin.Create(marginForm)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+	c.JSON(http.StatusCreated, response.New().SetData(margin))
+}
+
+func (e *ExchangeMargin) Update(c *gin.Context) {
+	id, typedErr := e.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	marginForm := &form.ExchangeMargin{}
+	if err := c.ShouldBind(marginForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	margin, err := e.serviceExchangeMargin.Update(id, marginForm)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+	c.JSON(http.StatusOK, response.New().SetData(margin))
+}
+
+func (e *ExchangeMargin) Delete(c *gin.Context) {
+	id, typedErr := e.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	if err := e.serviceExchangeMargin.Delete(id); err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// Preview shows the rate and exchange margin which would be applied to the given exchange
+func (e *ExchangeMargin) Preview(c *gin.Context) {
+	previewForm := &form.ExchangeMarginPreview{}
+	if err := c.ShouldBindQuery(previewForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+	amount, err := decimal.NewFromString(previewForm.Amount)
+	if err != nil {
+		errcodes.AddError(c, errcodes.CodeNumeric)
+		return
+	}
+
+	rate, err := e.currencyService.GetCurrenciesRateByCodes(previewForm.BaseCurrencyCode, previewForm.ReferenceCurrencyCode)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	rate, rule, err := e.serviceExchangeMargin.Apply(rate, &fee.MarginQuery{
+		BaseCurrencyCode:      previewForm.BaseCurrencyCode,
+		ReferenceCurrencyCode: previewForm.ReferenceCurrencyCode,
+		UserGroupId:           previewForm.UserGroupId,
+		Amount:                amount,
+	}, nil)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(map[string]interface{}{
+		"rate":                  rate.Rate,
+		"exchangeMarginPercent": rate.ExchangeMargin,
+		"exchangeMargin":        rule,
+	}))
+}
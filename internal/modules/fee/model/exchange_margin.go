@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeMargin is a rule which defines exchange margin percent charged on currency exchange.
+// Empty currency codes and user group match any value, MinAmount is the lower bound (inclusive)
+// of the amount tier in the base currency.
+type ExchangeMargin struct {
+	Id                    *uint64          `gorm:"primary_key" json:"id"`
+	Name                  *string          `json:"name"`
+	BaseCurrencyCode      *string          `json:"baseCurrencyCode"`
+	ReferenceCurrencyCode *string          `json:"referenceCurrencyCode"`
+	UserGroupId           *uint64          `json:"userGroupId"`
+	MinAmount             *decimal.Decimal `json:"minAmount"`
+	Percent               *decimal.Decimal `json:"percent"`
+	CreatedAt             time.Time        `json:"createdAt"`
+	UpdatedAt             time.Time        `json:"updatedAt"`
+}
+
+func (*ExchangeMargin) TableName() string {
+	return "exchange_margins"
+}
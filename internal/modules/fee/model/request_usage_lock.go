@@ -0,0 +1,13 @@
+package model
+
+// RequestUsageLock is a row locked while the user's requests are counted by free transfers quota
+// and the counted request is created, so concurrent requests of the user are counted one by one
+type RequestUsageLock struct {
+	UserId         string `gorm:"primary_key"`
+	RequestSubject string `gorm:"primary_key"`
+	CurrencyCode   string `gorm:"primary_key"`
+}
+
+func (*RequestUsageLock) TableName() string {
+	return "transfer_fees_usage_locks"
+}
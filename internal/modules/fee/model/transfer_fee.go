@@ -26,6 +26,27 @@ type TransferFeeParameters struct {
 	Min           *decimal.Decimal `json:"min"`
 	Percent       *decimal.Decimal `json:"percent"`
 	Max           *decimal.Decimal `json:"max"`
+	// FreePerMonth is number of transfers of the user per calendar month which are not charged
+	FreePerMonth    *uint64                      `json:"freePerMonth"`
+	Tiers           []*TransferFeeTier           `gorm:"foreignkey:TransferFeeParametersId" json:"tiers"`
+	VolumeDiscounts []*TransferFeeVolumeDiscount `gorm:"foreignkey:TransferFeeParametersId" json:"volumeDiscounts"`
+}
+
+// TransferFeeTier overrides percent of fee parameters for amounts starting from MinAmount
+type TransferFeeTier struct {
+	Id                      *uint64          `gorm:"primary_key" json:"-"`
+	TransferFeeParametersId *uint64          `json:"-"`
+	MinAmount               *decimal.Decimal `json:"minAmount"`
+	Percent                 *decimal.Decimal `json:"percent"`
+}
+
+// TransferFeeVolumeDiscount reduces fee of users whose volume of executed transfers within
+// the current calendar month reached MinVolume
+type TransferFeeVolumeDiscount struct {
+	Id                      *uint64          `gorm:"primary_key" json:"-"`
+	TransferFeeParametersId *uint64          `json:"-"`
+	MinVolume               *decimal.Decimal `json:"minVolume"`
+	DiscountPercent         *decimal.Decimal `json:"discountPercent"`
 }
 
 type TransferFeeUserGroup struct {
@@ -41,6 +62,14 @@ func (*TransferFeeParameters) TableName() string {
 	return "transfer_fees_parameters"
 }
 
+func (*TransferFeeTier) TableName() string {
+	return "transfer_fees_tiers"
+}
+
+func (*TransferFeeVolumeDiscount) TableName() string {
+	return "transfer_fees_volume_discounts"
+}
+
 func (t *TransferFee) MarshalJSON() ([]byte, error) {
 
 	userGroups := make([]uint64, len(t.Relations))
@@ -59,13 +88,16 @@ func (t *TransferFee) MarshalJSON() ([]byte, error) {
 
 func (t *TransferFeeParameters) MarshalJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
-		"id":            t.Id,
-		"transferFeeId": t.TransferFeeId,
-		"currencyCode":  t.CurrencyCode,
-		"base":          t.Base,
-		"min":           t.Min,
-		"percent":       t.Percent,
-		"max":           t.Max,
+		"id":              t.Id,
+		"transferFeeId":   t.TransferFeeId,
+		"currencyCode":    t.CurrencyCode,
+		"base":            t.Base,
+		"min":             t.Min,
+		"percent":         t.Percent,
+		"max":             t.Max,
+		"freePerMonth":    t.FreePerMonth,
+		"tiers":           t.Tiers,
+		"volumeDiscounts": t.VolumeDiscounts,
 	})
 }
 
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"github.com/Confialink/wallet-pkg-list_params"
+	"github.com/Confialink/wallet-pkg-list_params/adapters"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+)
+
+type ExchangeMargin struct {
+	db *gorm.DB
+}
+
+func NewExchangeMargin(db *gorm.DB) *ExchangeMargin {
+	return &ExchangeMargin{db: db}
+}
+
+func (e *ExchangeMargin) Create(marginModel *model.ExchangeMargin) error {
+	return e.db.Create(marginModel).Error
+}
+
+func (e *ExchangeMargin) Save(marginModel *model.ExchangeMargin) error {
+	return e.db.Save(marginModel).Error
+}
+
+func (e *ExchangeMargin) Delete(marginModel *model.ExchangeMargin) error {
+	return e.db.Delete(marginModel).Error
+}
+
+func (e *ExchangeMargin) FindById(id uint64) (*model.ExchangeMargin, error) {
+	resultModel := &model.ExchangeMargin{}
+	return resultModel, e.db.Find(resultModel, "id = ?", id).Error
+}
+
+func (e *ExchangeMargin) GetList(params *list_params.ListParams) (margins []*model.ExchangeMargin, err error) {
+	adapter := adapters.NewGorm(e.db)
+	return margins, adapter.LoadList(&margins, params, (&model.ExchangeMargin{}).TableName())
+}
+
+// FindMostSpecific returns rule which matches the given exchange, nil is returned if there is no such rule.
+// Rules of the user group take precedence over the currency pair ones and the highest tier is taken.
+func (e *ExchangeMargin) FindMostSpecific(
+	baseCurrencyCode, referenceCurrencyCode string,
+	userGroupId *uint64,
+	amount decimal.Decimal,
+) (*model.ExchangeMargin, error) {
+	query := e.db.
+		Where("base_currency_code IS NULL OR base_currency_code = ?", baseCurrencyCode).
+		Where("reference_currency_code IS NULL OR reference_currency_code = ?", referenceCurrencyCode).
+		Where("min_amount IS NULL OR min_amount <= ?", amount)
+	if userGroupId != nil {
+		query = query.Where("user_group_id IS NULL OR user_group_id = ?", *userGroupId)
+	} else {
+		query = query.Where("user_group_id IS NULL")
+	}
+
+	resultModel := &model.ExchangeMargin{}
+	err := query.
+		Order("user_group_id IS NULL").
+		Order("base_currency_code IS NULL").
+		Order("reference_currency_code IS NULL").
+		Order("min_amount DESC").
+		First(resultModel).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resultModel, nil
+}
+
+func (copy ExchangeMargin) WrapContext(db *gorm.DB) *ExchangeMargin {
+	copy.db = db
+	return &copy
+}
@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"database/sql/driver"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+)
+
+var _ = Describe("ExchangeMargin", func() {
+	var (
+		mock       sqlmock.Sqlmock
+		repository *ExchangeMargin
+	)
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err := gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		repository = NewExchangeMargin(gdb)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	// rules of the user group go first, then rules of the exact currencies and the highest amount tier
+	const precedence = "ORDER BY user_group_id IS NULL,base_currency_code IS NULL,reference_currency_code IS NULL,min_amount DESC"
+
+	table.DescribeTable("FindMostSpecific",
+		func(userGroupId *uint64, groupCondition string, args []driver.Value) {
+			mock.ExpectQuery("SELECT \\* FROM `exchange_margins` " +
+				"WHERE \\(base_currency_code IS NULL OR base_currency_code = \\?\\) " +
+				"AND \\(reference_currency_code IS NULL OR reference_currency_code = \\?\\) " +
+				"AND \\(min_amount IS NULL OR min_amount <= \\?\\) " +
+				"AND \\(" + groupCondition + "\\) " + precedence).
+				WithArgs(args...).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "percent"}).AddRow(7, "1.5"))
+
+			rule, err := repository.FindMostSpecific("EUR", "USD", userGroupId, decimal.RequireFromString("1000"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(*rule.Id).To(Equal(uint64(7)))
+			Expect(rule.Percent.String()).To(Equal("1.5"))
+		},
+		table.Entry("matches rules of the user group and global ones",
+			pointer.ToUint64(3), "user_group_id IS NULL OR user_group_id = \\?", []driver.Value{"EUR", "USD", "1000", 3}),
+		table.Entry("matches only global rules without user group",
+			nil, "user_group_id IS NULL", []driver.Value{"EUR", "USD", "1000"}),
+	)
+
+	It("should return nil if there is no matching rule", func() {
+		mock.ExpectQuery("SELECT \\* FROM `exchange_margins`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		rule, err := repository.FindMostSpecific("EUR", "USD", nil, decimal.RequireFromString("10"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rule).To(BeNil())
+	})
+})
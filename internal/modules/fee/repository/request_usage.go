@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+)
+
+// RequestUsage calculates how many transfers the user has made, it is used for fee quotas and discounts
+type RequestUsage struct {
+	db *gorm.DB
+}
+
+func NewRequestUsage(db *gorm.DB) *RequestUsage {
+	return &RequestUsage{db: db}
+}
+
+// Lock locks usage of the user with the given subject and base currency until the end of the transaction
+// the repository is wrapped into, it must be called before the usage is counted
+func (r *RequestUsage) Lock(userId string, subject constants.Subject, currencyCode string) error {
+	err := r.db.Exec(
+		"INSERT IGNORE INTO `transfer_fees_usage_locks` (`user_id`, `request_subject`, `currency_code`) VALUES (?, ?, ?)",
+		userId,
+		subject,
+		currencyCode,
+	).Error
+	if err != nil {
+		return err
+	}
+	return r.db.
+		Set("gorm:query_option", "FOR UPDATE").
+		Where("user_id = ? AND request_subject = ? AND currency_code = ?", userId, subject, currencyCode).
+		First(&model.RequestUsageLock{}).
+		Error
+}
+
+// CountSince returns number of not cancelled requests of the user with the given subject
+// and base currency created since the given time
+func (r *RequestUsage) CountSince(
+	userId string,
+	subject constants.Subject,
+	currencyCode string,
+	since time.Time,
+) (count uint64, err error) {
+	err = r.db.
+		Table("requests").
+		Where(
+			"user_id = ? AND subject = ? AND base_currency_code = ? AND status <> ? AND created_at >= ?",
+			userId, subject, currencyCode, constants.StatusCancelled, since,
+		).
+		Count(&count).
+		Error
+	return
+}
+
+// ExecutedVolumeSince returns sum of amounts of executed requests of the user with the given subject
+// and base currency created since the given time
+func (r *RequestUsage) ExecutedVolumeSince(
+	userId string,
+	subject constants.Subject,
+	currencyCode string,
+	since time.Time,
+) (volume decimal.Decimal, err error) {
+	err = r.db.
+		Table("requests").
+		Select("COALESCE(SUM(amount), 0)").
+		Where(
+			"user_id = ? AND subject = ? AND base_currency_code = ? AND status = ? AND created_at >= ?",
+			userId, subject, currencyCode, constants.StatusExecuted, since,
+		).
+		Row().
+		Scan(&volume)
+	return
+}
+
+func (copy RequestUsage) WrapContext(db *gorm.DB) *RequestUsage {
+	copy.db = db
+	return &copy
+}
@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+)
+
+var _ = Describe("RequestUsage", func() {
+	It("should count only requests in the currency of the fee parameters", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		gdb, err := gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		since := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `requests` "+
+			"WHERE \\(user_id = \\? AND subject = \\? AND base_currency_code = \\? AND status <> \\? AND created_at >= \\?\\)").
+			WithArgs("user-1", constants.SubjectTransferOutgoingWireTransfer, "EUR", constants.StatusCancelled, since).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+		count, err := NewRequestUsage(gdb).CountSince("user-1", constants.SubjectTransferOutgoingWireTransfer, "EUR", since)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(count).To(Equal(uint64(4)))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+})
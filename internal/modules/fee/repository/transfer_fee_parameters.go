@@ -3,7 +3,6 @@ package repository
 import (
 	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
 	"github.com/jinzhu/gorm"
-	"github.com/shopspring/decimal"
 )
 
 type TransferFeeParameters struct {
@@ -17,32 +16,105 @@ func NewTransferFeeParameters(db *gorm.DB) *TransferFeeParameters {
 func (t *TransferFeeParameters) CreateUpdate(parameters *model.TransferFeeParameters) error {
 	where := model.TransferFeeParameters{TransferFeeId: parameters.TransferFeeId, CurrencyCode: parameters.CurrencyCode}
 	// map b/c fields might be updated with null values
-	assign := map[string]*decimal.Decimal{
-		"base":    parameters.Base,
-		"min":     parameters.Min,
-		"percent": parameters.Percent,
-		"max":     parameters.Max,
+	assign := map[string]interface{}{
+		"base":           parameters.Base,
+		"min":            parameters.Min,
+		"percent":        parameters.Percent,
+		"max":            parameters.Max,
+		"free_per_month": parameters.FreePerMonth,
 	}
 
-	return t.db.Where(where).Assign(assign).FirstOrCreate(&parameters).Error
+	// associations are replaced explicitly only if they are passed
+	tiers, discounts := parameters.Tiers, parameters.VolumeDiscounts
+	parameters.Tiers, parameters.VolumeDiscounts = nil, nil
+	if err := t.db.Where(where).Assign(assign).FirstOrCreate(&parameters).Error; err != nil {
+		return err
+	}
+
+	if tiers != nil {
+		if err := t.replaceTiers(*parameters.Id, tiers); err != nil {
+			return err
+		}
+		parameters.Tiers = tiers
+	}
+	if discounts != nil {
+		if err := t.replaceVolumeDiscounts(*parameters.Id, discounts); err != nil {
+			return err
+		}
+		parameters.VolumeDiscounts = discounts
+	}
+	return nil
 }
 
+func (t *TransferFeeParameters) replaceTiers(parametersId uint64, tiers []*model.TransferFeeTier) error {
+	err := t.db.Delete(&model.TransferFeeTier{}, "transfer_fee_parameters_id = ?", parametersId).Error
+	if err != nil {
+		return err
+	}
+	for _, tier := range tiers {
+		tier.TransferFeeParametersId = &parametersId
+		if err := t.db.Create(tier).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TransferFeeParameters) replaceVolumeDiscounts(parametersId uint64, discounts []*model.TransferFeeVolumeDiscount) error {
+	err := t.db.Delete(&model.TransferFeeVolumeDiscount{}, "transfer_fee_parameters_id = ?", parametersId).Error
+	if err != nil {
+		return err
+	}
+	for _, discount := range discounts {
+		discount.TransferFeeParametersId = &parametersId
+		if err := t.db.Create(discount).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the parameters together with their tiers and volume discounts,
+// it should be called within a transaction so that no orphaned rows are left on failure
 func (t *TransferFeeParameters) Delete(parameters *model.TransferFeeParameters) error {
 	where := model.TransferFeeParameters{Id: parameters.Id}
 	if where.Id == nil {
 		where = model.TransferFeeParameters{TransferFeeId: parameters.TransferFeeId, CurrencyCode: parameters.CurrencyCode}
 	}
-	return t.db.Delete(parameters, where).Error
+
+	var ids []uint64
+	if err := t.db.Model(&model.TransferFeeParameters{}).Where(where).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := t.db.Delete(&model.TransferFeeTier{}, "transfer_fee_parameters_id IN (?)", ids).Error; err != nil {
+		return err
+	}
+	if err := t.db.Delete(&model.TransferFeeVolumeDiscount{}, "transfer_fee_parameters_id IN (?)", ids).Error; err != nil {
+		return err
+	}
+	return t.db.Delete(&model.TransferFeeParameters{}, "id IN (?)", ids).Error
 }
 
 func (t *TransferFeeParameters) GetAllByTransferFeeId(transferFeeId uint64) (params []*model.TransferFeeParameters, err error) {
-	err = t.db.Model(&model.TransferFeeParameters{}).Find(&params, "transfer_fee_id = ?", transferFeeId).Error
+	err = t.db.
+		Model(&model.TransferFeeParameters{}).
+		Preload("Tiers").
+		Preload("VolumeDiscounts").
+		Find(&params, "transfer_fee_id = ?", transferFeeId).
+		Error
 	return
 }
 
 func (t *TransferFeeParameters) FindByTransferFeeIdAndCurrencyCode(transferFeeId uint64, currencyCode string) (*model.TransferFeeParameters, error) {
 	resultModel := &model.TransferFeeParameters{}
-	err := t.db.FirstOrInit(resultModel, "transfer_fee_id = ? AND currency_code = ?", transferFeeId, currencyCode).Error
+	err := t.db.
+		Preload("Tiers").
+		Preload("VolumeDiscounts").
+		FirstOrInit(resultModel, "transfer_fee_id = ? AND currency_code = ?", transferFeeId, currencyCode).
+		Error
 	return resultModel, err
 }
 
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	. "github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+)
+
+var _ = Describe("TransferFeeParameters", func() {
+	var (
+		gdb  *gorm.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err = gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	It("should delete parameters with their tiers and volume discounts in the same transaction", func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM `transfer_fees_parameters` WHERE \\(`transfer_fees_parameters`.`transfer_fee_id` = \\?\\) AND \\(`transfer_fees_parameters`.`currency_code` = \\?\\)").
+			WithArgs(5, "EUR").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectExec("DELETE FROM `transfer_fees_tiers` WHERE \\(transfer_fee_parameters_id IN \\(\\?\\)\\)").
+			WithArgs(9).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("DELETE FROM `transfer_fees_volume_discounts` WHERE \\(transfer_fee_parameters_id IN \\(\\?\\)\\)").
+			WithArgs(9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM `transfer_fees_parameters` WHERE \\(id IN \\(\\?\\)\\)").
+			WithArgs(9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx := gdb.Begin()
+		err := NewTransferFeeParameters(gdb).WrapContext(tx).Delete(&model.TransferFeeParameters{
+			TransferFeeId: pointer.ToUint64(5),
+			CurrencyCode:  pointer.ToString("EUR"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tx.Commit().Error).ShouldNot(HaveOccurred())
+	})
+
+	It("should not delete anything if the parameters do not exist", func() {
+		mock.ExpectQuery("SELECT id FROM `transfer_fees_parameters`").
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		err := NewTransferFeeParameters(gdb).Delete(&model.TransferFeeParameters{Id: pointer.ToUint64(3)})
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
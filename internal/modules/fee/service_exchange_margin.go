@@ -0,0 +1,116 @@
+package fee
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	currencyService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	feeRepository "github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+)
+
+// ServiceExchangeMargin manages exchange margin rules configured per currency pair, user group and amount tier
+type ServiceExchangeMargin struct {
+	exchangeMarginRepository *feeRepository.ExchangeMargin
+	logger                   log15.Logger
+}
+
+// MarginQuery describes an exchange which margin is looked for
+type MarginQuery struct {
+	BaseCurrencyCode      string
+	ReferenceCurrencyCode string
+	// UserGroupId is a group of the owner of the account which is debited, nil if the owner has no group
+	UserGroupId *uint64
+	// Amount is the exchanged amount in the base currency
+	Amount decimal.Decimal
+}
+
+func NewServiceExchangeMargin(
+	exchangeMarginRepository *feeRepository.ExchangeMargin,
+	logger log15.Logger,
+) *ServiceExchangeMargin {
+	return &ServiceExchangeMargin{
+		exchangeMarginRepository: exchangeMarginRepository,
+		logger:                   logger.New("module", "fee", "service", "exchangeMargin"),
+	}
+}
+
+func (s *ServiceExchangeMargin) Create(createForm *form.ExchangeMargin) (*model.ExchangeMargin, error) {
+	marginModel := &model.ExchangeMargin{}
+	if err := createForm.ToModel(marginModel); err != nil {
+		return nil, err
+	}
+	if err := s.exchangeMarginRepository.Create(marginModel); err != nil {
+		s.logger.Error("failed to create exchange margin", "error", err, "name", *createForm.Name)
+		return nil, err
+	}
+	return marginModel, nil
+}
+
+func (s *ServiceExchangeMargin) Update(id uint64, updateForm *form.ExchangeMargin) (*model.ExchangeMargin, error) {
+	marginModel, err := s.findById(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateForm.ToModel(marginModel); err != nil {
+		return nil, err
+	}
+	if err := s.exchangeMarginRepository.Save(marginModel); err != nil {
+		s.logger.Error("failed to update exchange margin", "error", err, "id", id)
+		return nil, err
+	}
+	return marginModel, nil
+}
+
+func (s *ServiceExchangeMargin) Delete(id uint64) error {
+	marginModel, err := s.findById(id)
+	if err != nil {
+		return err
+	}
+	return s.exchangeMarginRepository.Delete(marginModel)
+}
+
+// Resolve returns the most specific rule matching the query or nil if there is no such rule.
+// A rule of the user group wins over a rule of the currency pair, within them the highest amount tier wins.
+func (s *ServiceExchangeMargin) Resolve(query *MarginQuery, tx *gorm.DB) (*model.ExchangeMargin, error) {
+	repo := s.exchangeMarginRepository
+	if tx != nil {
+		repo = repo.WrapContext(tx)
+	}
+	rule, err := repo.FindMostSpecific(query.BaseCurrencyCode, query.ReferenceCurrencyCode, query.UserGroupId, query.Amount)
+	if err != nil {
+		s.logger.Error(
+			"failed to find exchange margin",
+			"error", err,
+			"base", query.BaseCurrencyCode,
+			"reference", query.ReferenceCurrencyCode,
+		)
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Apply replaces exchange margin of the given rate by the margin of the rule matching the query,
+// the rate is returned as is (with the margin of the currency service) if there is no such rule
+func (s *ServiceExchangeMargin) Apply(
+	rate *currencyService.Rate,
+	query *MarginQuery,
+	tx *gorm.DB,
+) (*currencyService.Rate, *model.ExchangeMargin, error) {
+	rule, err := s.Resolve(query, tx)
+	if err != nil || rule == nil {
+		return rate, nil, err
+	}
+	return &currencyService.Rate{Rate: rate.Rate, ExchangeMargin: *rule.Percent}, rule, nil
+}
+
+func (s *ServiceExchangeMargin) findById(id uint64) (*model.ExchangeMargin, error) {
+	marginModel, err := s.exchangeMarginRepository.FindById(id)
+	if err == gorm.ErrRecordNotFound {
+		return nil, errcodes.CreatePublicError(errcodes.CodeExchangeMarginNotFound)
+	}
+	return marginModel, err
+}
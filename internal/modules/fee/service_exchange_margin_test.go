@@ -0,0 +1,94 @@
+package fee_test
+
+import (
+	"database/sql/driver"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	currencyService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	. "github.com/Confialink/wallet-accounts/internal/modules/fee"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+)
+
+var _ = Describe("ServiceExchangeMargin", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *ServiceExchangeMargin
+	)
+	rate := &currencyService.Rate{Rate: decimal.RequireFromString("1.1"), ExchangeMargin: decimal.RequireFromString("2")}
+	columns := []string{"id", "base_currency_code", "reference_currency_code", "user_group_id", "min_amount", "percent"}
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err = gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		service = NewServiceExchangeMargin(repository.NewExchangeMargin(gdb), log15.New())
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	query := func(userGroupId *uint64, amount string) *MarginQuery {
+		return &MarginQuery{
+			BaseCurrencyCode:      "EUR",
+			ReferenceCurrencyCode: "USD",
+			UserGroupId:           userGroupId,
+			Amount:                decimal.RequireFromString(amount),
+		}
+	}
+
+	// the most specific rule is the first one selected by the database, see repository tests for the order
+	table.DescribeTable("Apply",
+		func(marginQuery *MarginQuery, rule []driver.Value, expectedMargin string) {
+			rows := sqlmock.NewRows(columns)
+			if rule != nil {
+				rows.AddRow(rule...)
+			}
+			mock.ExpectQuery("SELECT \\* FROM `exchange_margins`").WillReturnRows(rows)
+
+			applied, matched, err := service.Apply(rate, marginQuery, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(applied.Rate.String()).To(Equal("1.1"))
+			Expect(applied.ExchangeMargin.String()).To(Equal(expectedMargin))
+			if rule == nil {
+				Expect(matched).To(BeNil())
+			} else {
+				Expect(*matched.Id).To(Equal(uint64(rule[0].(int))))
+			}
+		},
+		table.Entry("rule of the user group wins over global rule",
+			query(pointer.ToUint64(3), "100"), []driver.Value{1, nil, nil, 3, nil, "0.5"}, "0.5"),
+		table.Entry("global rule is applied to users without group",
+			query(nil, "100"), []driver.Value{2, nil, nil, nil, nil, "1"}, "1"),
+		table.Entry("rule of the currency pair is applied",
+			query(nil, "100"), []driver.Value{3, "EUR", "USD", nil, nil, "1.25"}, "1.25"),
+		table.Entry("the highest reached amount tier is applied",
+			query(nil, "50000"), []driver.Value{4, "EUR", "USD", nil, "10000", "0.75"}, "0.75"),
+		table.Entry("margin of the currency service is kept without rules",
+			query(nil, "100"), nil, "2"),
+	)
+
+	It("should resolve the rule within the given transaction", func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT \\* FROM `exchange_margins`").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, nil, nil, 3, nil, "0.5"))
+		mock.ExpectCommit()
+
+		tx := gdb.Begin()
+		rule, err := service.Resolve(query(pointer.ToUint64(3), "100"), tx)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(*rule.UserGroupId).To(Equal(uint64(3)))
+		Expect(tx.Commit().Error).ShouldNot(HaveOccurred())
+	})
+})
@@ -1,6 +1,8 @@
 package fee
 
 import (
+	"time"
+
 	"github.com/Confialink/wallet-accounts/internal/errcodes"
 	"github.com/Confialink/wallet-accounts/internal/modules/fee/form"
 	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
@@ -8,12 +10,14 @@ import (
 	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	"github.com/inconshreveable/log15"
 	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
 )
 
 type ServiceTransferFee struct {
 	db                              *gorm.DB
 	transferFeeRepository           *feeRepository.TransferFee
 	transferFeeParametersRepository *feeRepository.TransferFeeParameters
+	requestUsageRepository          *feeRepository.RequestUsage
 	logger                          log15.Logger
 }
 
@@ -24,16 +28,36 @@ type ParamsQuery struct {
 	FeeId          *uint64
 }
 
+// UsageQuery describes transfers of the user which are taken into account by free transfers quota
+// and volume discounts
+type UsageQuery struct {
+	UserId         string
+	RequestSubject constants.Subject
+	CurrencyCode   string
+	Since          time.Time
+	// Lock locks usage of the user until the end of the transaction creating the request,
+	// so concurrent requests can not be covered by the same free transfer
+	Lock bool
+}
+
+// Discount is a reduction of the fee calculated from the user's usage
+type Discount struct {
+	Free            bool
+	DiscountPercent decimal.Decimal
+}
+
 func NewServiceTransferFee(
 	db *gorm.DB,
 	transferFeeRepository *feeRepository.TransferFee,
 	transferFeeParametersRepository *feeRepository.TransferFeeParameters,
+	requestUsageRepository *feeRepository.RequestUsage,
 	logger log15.Logger,
 ) *ServiceTransferFee {
 	return &ServiceTransferFee{
 		db:                              db,
 		transferFeeRepository:           transferFeeRepository,
 		transferFeeParametersRepository: transferFeeParametersRepository,
+		requestUsageRepository:          requestUsageRepository,
 		logger:                          logger.New("module", "fee", "service", "transferFee"),
 	}
 }
@@ -242,3 +266,64 @@ func (s *ServiceTransferFee) FindParams(paramsQuery *ParamsQuery, tx *gorm.DB) (
 
 	return feeParamsModel, nil
 }
+
+// FindDiscount checks whether the transfer is covered by free transfers quota of the given parameters
+// and finds the volume discount the user has reached
+func (s *ServiceTransferFee) FindDiscount(
+	params *model.TransferFeeParameters,
+	query *UsageQuery,
+	tx *gorm.DB,
+) (*Discount, error) {
+	if tx == nil {
+		tx = s.db
+	}
+	logger := s.logger.New("func", "FindDiscount")
+	usageRepo := s.requestUsageRepository.WrapContext(tx)
+	discount := &Discount{}
+
+	if params.FreePerMonth != nil && *params.FreePerMonth > 0 {
+		if query.Lock {
+			if err := usageRepo.Lock(query.UserId, query.RequestSubject, query.CurrencyCode); err != nil {
+				logger.Error("failed to lock user requests", "error", err, "userId", query.UserId)
+				return nil, err
+			}
+		}
+		count, err := usageRepo.CountSince(query.UserId, query.RequestSubject, query.CurrencyCode, query.Since)
+		if err != nil {
+			logger.Error("failed to count user requests", "error", err, "userId", query.UserId)
+			return nil, err
+		}
+		if count < *params.FreePerMonth {
+			discount.Free = true
+			return discount, nil
+		}
+	}
+
+	if len(params.VolumeDiscounts) == 0 {
+		return discount, nil
+	}
+	volume, err := usageRepo.ExecutedVolumeSince(query.UserId, query.RequestSubject, query.CurrencyCode, query.Since)
+	if err != nil {
+		logger.Error("failed to calculate user volume", "error", err, "userId", query.UserId)
+		return nil, err
+	}
+	discount.DiscountPercent = VolumeDiscountPercent(params.VolumeDiscounts, volume)
+	return discount, nil
+}
+
+// VolumeDiscountPercent returns discount of the highest volume threshold reached by the given volume
+func VolumeDiscountPercent(discounts []*model.TransferFeeVolumeDiscount, volume decimal.Decimal) decimal.Decimal {
+	var reached *model.TransferFeeVolumeDiscount
+	for _, discount := range discounts {
+		if discount.MinVolume.GreaterThan(volume) {
+			continue
+		}
+		if reached == nil || discount.MinVolume.GreaterThan(*reached.MinVolume) {
+			reached = discount
+		}
+	}
+	if reached == nil {
+		return decimal.Zero
+	}
+	return *reached.DiscountPercent
+}
@@ -0,0 +1,81 @@
+package fee_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/fee"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+)
+
+var _ = Describe("ServiceTransferFee", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *ServiceTransferFee
+	)
+	since := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	params := &model.TransferFeeParameters{FreePerMonth: pointer.ToUint64(3)}
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err = gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		service = NewServiceTransferFee(gdb, nil, nil, repository.NewRequestUsage(gdb), log15.New())
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	query := func(lock bool) *UsageQuery {
+		return &UsageQuery{
+			UserId:         "user-1",
+			RequestSubject: constants.SubjectTransferOutgoingWireTransfer,
+			CurrencyCode:   "EUR",
+			Since:          since,
+			Lock:           lock,
+		}
+	}
+	expectCount := func(count int) {
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `requests`").
+			WithArgs("user-1", constants.SubjectTransferOutgoingWireTransfer, "EUR", constants.StatusCancelled, since).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	It("should lock usage of the user before counting free transfers within the transaction", func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT IGNORE INTO `transfer_fees_usage_locks`").
+			WithArgs("user-1", constants.SubjectTransferOutgoingWireTransfer, "EUR").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT \\* FROM `transfer_fees_usage_locks` .* FOR UPDATE").
+			WithArgs("user-1", constants.SubjectTransferOutgoingWireTransfer, "EUR").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "request_subject", "currency_code"}).
+				AddRow("user-1", "OWT", "EUR"))
+		expectCount(2)
+		mock.ExpectCommit()
+
+		tx := gdb.Begin()
+		discount, err := service.FindDiscount(params, query(true), tx)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(discount.Free).To(BeTrue())
+		Expect(tx.Commit().Error).ShouldNot(HaveOccurred())
+	})
+
+	It("should not lock usage of the user for previews", func() {
+		expectCount(3)
+
+		discount, err := service.FindDiscount(params, query(false), gdb)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(discount.Free).To(BeFalse())
+	})
+})
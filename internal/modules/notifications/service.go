@@ -209,6 +209,152 @@ func (s *Service) TriggerNewMoneyRequest(eventContext *moneyRequestEvent.Context
 	return err
 }
 
+// TriggerCardExpiring notifies the card owner that the card expires soon
+func (s *Service) TriggerCardExpiring(userID string, cardID uint32) error {
+	logger := s.logger.New("method", "TriggerCardExpiring")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName: "CardExpiring",
+		To:        userID,
+		TemplateData: &notificationspb.TemplateData{
+			EntityID: uint64(cardID),
+		},
+	})
+
+	return err
+}
+
+// TriggerTermDepositMaturing notifies the account owner that the term deposit matures soon
+func (s *Service) TriggerTermDepositMaturing(userID string, accountID uint64, accountNumber string) error {
+	logger := s.logger.New("method", "TriggerTermDepositMaturing")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName: "TermDepositMaturing",
+		To:        userID,
+		TemplateData: &notificationspb.TemplateData{
+			EntityID:      accountID,
+			AccountNumber: accountNumber,
+		},
+	})
+
+	return err
+}
+
+// TriggerTermDepositMatured notifies the account owner that the term deposit has been paid out or rolled over
+func (s *Service) TriggerTermDepositMatured(userID string, accountID uint64, accountNumber string) error {
+	logger := s.logger.New("method", "TriggerTermDepositMatured")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName: "TermDepositMatured",
+		To:        userID,
+		TemplateData: &notificationspb.TemplateData{
+			EntityID:      accountID,
+			AccountNumber: accountNumber,
+		},
+	})
+
+	return err
+}
+
+// TriggerCardRenewed notifies the card owner that the expired card has been replaced by a new one
+func (s *Service) TriggerCardRenewed(userID string, cardID uint32) error {
+	logger := s.logger.New("method", "TriggerCardRenewed")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName: "CardRenewed",
+		To:        userID,
+		TemplateData: &notificationspb.TemplateData{
+			EntityID: uint64(cardID),
+		},
+	})
+
+	return err
+}
+
+// TriggerTanApprovalRequested informs the user about the approval challenge which awaits approval of another user
+func (s *Service) TriggerTanApprovalRequested(userID string, challengeID int64) error {
+	logger := s.logger.New("method", "TriggerTanApprovalRequested")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName: "TanApprovalRequested",
+		To:        userID,
+		TemplateData: &notificationspb.TemplateData{
+			EntityID: uint64(challengeID),
+		},
+	})
+
+	return err
+}
+
+// TriggerTransferSigningChallenge sends the code which confirms the transfer to the given destination
+func (s *Service) TriggerTransferSigningChallenge(userID string, challengeID int64, code, destination string) error {
+	logger := s.logger.New("method", "TriggerTransferSigningChallenge")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName: "TransferSigningChallenge",
+		To:        userID,
+		TemplateData: &notificationspb.TemplateData{
+			EntityID:      uint64(challengeID),
+			Tan:           code,
+			AccountNumber: destination,
+		},
+	})
+
+	return err
+}
+
+// TriggerTanFailedAttempt alerts the user of failed confirmation attempt, locked is true if the user has been locked out
+func (s *Service) TriggerTanFailedAttempt(userID string, locked bool) error {
+	logger := s.logger.New("method", "TriggerTanFailedAttempt")
+	client, err := s.getClient()
+	if err != nil {
+		logger.Error("failed to get pb client", "error", err)
+		return err
+	}
+
+	eventName := "TanFailedAttempt"
+	if locked {
+		eventName = "TanLocked"
+	}
+	_, err = client.Dispatch(context.Background(), &notificationspb.Request{
+		EventName:    eventName,
+		To:           userID,
+		TemplateData: &notificationspb.TemplateData{},
+	})
+
+	return err
+}
+
 func (s *Service) getClient() (notificationspb.NotificationHandler, error) {
 	notificationsUrl, err := srvdiscovery.ResolveRPC(srvdiscovery.ServiceNameNotifications)
 	if nil != err {
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/olebedev/emitter"
+
+	tanEvent "github.com/Confialink/wallet-accounts/internal/modules/tan/event"
+)
+
+func TanOnFailedAttempt(eventEmitter *emitter.Emitter) {
+	// empty loop is aimed to free chanel once event is emitted
+	for range eventEmitter.On(tanEvent.FailedAttempt, notifyTanFailedAttempt) { /* empty */
+	}
+}
+
+func notifyTanFailedAttempt(event *emitter.Event) {
+	context := event.Args[0].(*tanEvent.ContextFailedAttempt)
+
+	go func() {
+		if err := notificationService.TriggerTanFailedAttempt(context.UserID, context.Locked); err != nil {
+			logger.Error("failed to notify", "error", err, "userID", context.UserID)
+		}
+	}()
+}
@@ -11,5 +11,6 @@ func Subscribe(eventEmitter *emitter.Emitter) {
 	go handler.RequestOnPendingApproval(eventEmitter)
 	go handler.RequestOnRequestExecuted(eventEmitter)
 	go handler.RequestOnRequestCancelled(eventEmitter)
+	go handler.TanOnFailedAttempt(eventEmitter)
 	log.Println("module notifications subscribed on application events")
 }
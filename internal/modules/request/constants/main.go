@@ -30,6 +30,9 @@ const (
 	SubjectDebitAccount                 = Subject("DA")
 	SubjectTransferIncomingWireTransfer = Subject("IWT")
 	SubjectDebitRevenueAccount          = Subject("DRA")
+	SubjectConvert                      = Subject("CONVERT")
+	// SubjectCardRenewal is a system request which moves the balance of an expired card to its renewal
+	SubjectCardRenewal = Subject("CARD_RENEWAL")
 )
 
 var knownSubjects = map[string]Subject{
@@ -41,6 +44,7 @@ var knownSubjects = map[string]Subject{
 	string(SubjectDebitAccount):                 SubjectDebitAccount,
 	string(SubjectTransferIncomingWireTransfer): SubjectTransferIncomingWireTransfer,
 	string(SubjectDebitRevenueAccount):          SubjectDebitRevenueAccount,
+	string(SubjectConvert):                      SubjectConvert,
 }
 
 func (s Subject) String() string {
@@ -53,3 +57,22 @@ func SubjectFromString(subject string) (Subject, *errors.PublicError) {
 	}
 	return "", errcodes.CreatePublicError(errcodes.CodeUnknownRequestSubject)
 }
+
+// ChargeBearer defines who pays fees of outgoing wire transfers
+type ChargeBearer string
+
+const (
+	// ChargeBearerOur means the sender pays all fees
+	ChargeBearerOur = ChargeBearer("OUR")
+	// ChargeBearerShared means the sender pays our fees and the beneficiary pays fees of other banks
+	ChargeBearerShared = ChargeBearer("SHA")
+	// ChargeBearerBeneficiary means fees are deducted from the sent amount
+	ChargeBearerBeneficiary = ChargeBearer("BEN")
+)
+
+// DefaultChargeBearer is used by requests created before the charge bearer was introduced
+const DefaultChargeBearer = ChargeBearerShared
+
+func (b ChargeBearer) String() string {
+	return string(b)
+}
@@ -0,0 +1,215 @@
+package request
+
+import (
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/jinzhu/gorm"
+	errorsPkg "github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountEvent "github.com/Confialink/wallet-accounts/internal/modules/account/event"
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/event"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/transfers"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
+)
+
+// CreateConvertRequest creates request which exchanges currency between two accounts of the same user
+func (c *Creator) CreateConvertRequest(form *form.Convert, user *users.User, db *gorm.DB) (request *model.Request, err error) {
+	logger := c.logger.New("action", "CreateConvertRequest")
+	accountFrom, err := getAccountWithTypeForUpdateById(db, *form.AccountIdFrom)
+	if err != nil {
+		return
+	}
+
+	accountTo, err := getAccountWithTypeForUpdateById(db, *form.AccountIdTo)
+	if err != nil {
+		return
+	}
+
+	if err = validateConvertAccounts(accountFrom, accountTo); err != nil {
+		return
+	}
+
+	revenueAccount, err := c.revenueAccountService.FindOrCreateDefaultByCurrencyCode(accountFrom.Type.CurrencyCode, db)
+	if err != nil {
+		logger.Error("failed to find or create revenue account", "error", err)
+		return
+	}
+
+	revenueAccount, err = getRevenueAccountForUpdateById(db, revenueAccount.ID)
+	if err != nil {
+		return
+	}
+
+	subject := constants.SubjectConvert
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	if err != nil {
+		return
+	}
+
+	rate, err := c.getLockedRate(db, &RateQuery{
+		Subject:          subject,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   accountTo.Type.CurrencyCode,
+		Amount:           amount,
+	}, form.QuoteId)
+	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
+		return
+	}
+
+	params, err := c.getLockedFeeParams(db, accountFrom.UserId, accountFrom.Type.CurrencyCode, subject.String(), nil)
+	if err != nil && errorsPkg.Cause(err) != errFeeNotFound {
+		return
+	}
+
+	isAdmin, isSystem := c.GetIsAdminIsSystem(user)
+	status := constants.StatusNew
+	request = &model.Request{
+		Subject:               &subject,
+		Description:           form.Note,
+		Status:                &status,
+		UserId:                &user.UID,
+		IsInitiatedByAdmin:    &isAdmin,
+		IsInitiatedBySystem:   &isSystem,
+		BaseCurrencyCode:      &accountFrom.Type.CurrencyCode,
+		ReferenceCurrencyCode: &accountTo.Type.CurrencyCode,
+		Amount:                &amount,
+		RateDesignation:       model.RateDesignationBaseReference,
+		Rate:                  &rate.Rate,
+	}
+
+	shouldExecute, err := c.shouldExecute(request)
+	if err != nil {
+		return
+	}
+	request.IsVisible = pointer.ToBool(!shouldExecute)
+
+	requestInput := request.GetInput()
+	requestInput.Set("transferFeeParams", params)
+	requestInput.Set("sourceAccountId", int64(*form.AccountIdFrom))
+	requestInput.Set("destinationAccountId", int64(*form.AccountIdTo))
+	requestInput.Set("sourceAccountNumber", accountFrom.Number)
+	requestInput.Set("destinationAccountNumber", accountTo.Number)
+	requestInput.Set("revenueAccountId", int64(revenueAccount.ID))
+	requestInput.Set("exchangeMarginPercent", rate.ExchangeMargin)
+
+	if err = c.requestRepository.WrapContext(db).Create(request); err != nil {
+		return
+	}
+
+	input := transfers.NewBetweenAccountsInput(
+		accountFrom,
+		accountTo,
+		revenueAccount,
+		rate.ExchangeMargin,
+		params,
+	)
+	convert := transfers.NewBetweenAccounts(subject.String(), c.currencyProvider, input, db, c.pf)
+	if shouldExecute {
+		details, err := convert.Execute(request)
+		if err == nil {
+			<-c.emitter.Emit(
+				event.RequestExecuted,
+				&event.ContextRequestExecuted{
+					Tx:      db,
+					Request: request,
+					Details: details,
+				},
+			)
+			accountEvent.TriggerBalanceChanged(c.emitter, db, *request.Subject, details)
+		}
+		return request, err
+	}
+
+	details, err := convert.Pending(request)
+	if err == nil {
+		<-c.emitter.Emit(event.RequestPendingApproval, &event.ContextRequestPending{
+			Tx:      db,
+			Request: request,
+			Details: details,
+		})
+	}
+
+	return
+}
+
+// EvaluateConvertRequest calculates details of currency conversion between two accounts of the same user
+func (c *Creator) EvaluateConvertRequest(form *form.ConvertPreview, user *users.User) (details types.Details, err error) {
+	logger := c.logger.New("action", "EvaluateConvertRequest")
+	accountFrom, err := c.accountsRepository.FindByID(*form.AccountIdFrom)
+	if err != nil {
+		return
+	}
+
+	accountTo, err := c.accountsRepository.FindByID(*form.AccountIdTo)
+	if err != nil {
+		return
+	}
+
+	if err = validateConvertAccounts(accountFrom, accountTo); err != nil {
+		return
+	}
+
+	subject := constants.SubjectConvert
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	if err != nil {
+		return
+	}
+
+	rate, err := c.getQuotedRate(&RateQuery{
+		Subject:          subject,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   accountTo.Type.CurrencyCode,
+		Amount:           amount,
+	}, &form.RateQuote)
+	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
+		return
+	}
+
+	request := &model.Request{
+		Amount:                &amount,
+		Subject:               &subject,
+		RateDesignation:       model.RateDesignationBaseReference,
+		Rate:                  &rate.Rate,
+		BaseCurrencyCode:      &accountFrom.Type.CurrencyCode,
+		ReferenceCurrencyCode: &accountTo.Type.CurrencyCode,
+		IsInitiatedBySystem:   pointer.ToBool(false),
+	}
+
+	params, err := c.getFeeParams(c.db, accountFrom.UserId, accountFrom.Type.CurrencyCode, subject.String(), nil)
+	if err != nil && errorsPkg.Cause(err) != errFeeNotFound {
+		return
+	}
+
+	input := transfers.NewBetweenAccountsInput(
+		accountFrom,
+		accountTo,
+		stubRevenueAccount(accountFrom.Type.CurrencyCode),
+		rate.ExchangeMargin,
+		params,
+	)
+
+	return transfers.NewBetweenAccounts(subject.String(), c.currencyProvider, input, c.db, c.pf).Evaluate(request)
+}
+
+// validateConvertAccounts checks that accounts belong to the same user and have different currencies
+func validateConvertAccounts(accountFrom, accountTo *accountModel.Account) error {
+	if accountFrom.UserId != accountTo.UserId {
+		return errcodes.CreatePublicError(errcodes.CodeInvalidAccountOwner)
+	}
+	if accountFrom.Type.CurrencyCode == accountTo.Type.CurrencyCode {
+		return errcodes.CreatePublicError(errcodes.CodeConvertSameCurrency)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package request_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountTypeModel "github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	. "github.com/Confialink/wallet-accounts/internal/modules/request"
+)
+
+func convertAccount(userId, currencyCode string) *accountModel.Account {
+	return &accountModel.Account{
+		AccountPublic: accountModel.AccountPublic{
+			UserId: userId,
+			Type: &accountTypeModel.AccountType{
+				AccountTypePublic: accountTypeModel.AccountTypePublic{
+					CurrencyCode: currencyCode,
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Convert", func() {
+	It("should convert between accounts of the same user in different currencies", func() {
+		err := ValidateConvertAccounts(convertAccount("user-1", "EUR"), convertAccount("user-1", "USD"))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should not convert to account of another user", func() {
+		err := ValidateConvertAccounts(convertAccount("user-1", "EUR"), convertAccount("user-2", "USD"))
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeInvalidAccountOwner))
+	})
+
+	It("should not convert between accounts in the same currency", func() {
+		err := ValidateConvertAccounts(convertAccount("user-1", "EUR"), convertAccount("user-1", "EUR"))
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeConvertSameCurrency))
+	})
+})
@@ -2,6 +2,8 @@ package request
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Confialink/wallet-pkg-list_params"
 	"github.com/Confialink/wallet-pkg-utils/pointer"
@@ -14,6 +16,7 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/exchange"
 	accountEvent "github.com/Confialink/wallet-accounts/internal/modules/account/event"
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
 	accountRepository "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
@@ -54,6 +57,7 @@ type Creator struct {
 	userService              *userService.UserService
 	currencyService          service.CurrenciesServiceInterface
 	currencyProvider         transfer.CurrencyProvider
+	rateSource               exchange.RateSource
 	transferFeeService       *fee.ServiceTransferFee
 	requestRepository        repository.RequestRepositoryInterface
 	accountsRepository       *accountRepository.AccountRepository
@@ -65,6 +69,8 @@ type Creator struct {
 	emitter                  *emitter.Emitter
 	settings                 *settings.Service
 	pf                       transfers.PermissionFactory
+	quoteService             *QuoteService
+	exchangeMarginService    *fee.ServiceExchangeMargin
 	logger                   log15.Logger
 }
 
@@ -73,6 +79,7 @@ func NewCreator(
 	uService *userService.UserService,
 	currencyService service.CurrenciesServiceInterface,
 	currencyProvider transfer.CurrencyProvider,
+	rateSource exchange.RateSource,
 	transferFeeService *fee.ServiceTransferFee,
 	requestRepository repository.RequestRepositoryInterface,
 	accountsRepository *accountRepository.AccountRepository,
@@ -84,6 +91,8 @@ func NewCreator(
 	emitter *emitter.Emitter,
 	settings *settings.Service,
 	pf transfers.PermissionFactory,
+	quoteService *QuoteService,
+	exchangeMarginService *fee.ServiceExchangeMargin,
 	logger log15.Logger,
 ) *Creator {
 	return &Creator{
@@ -91,6 +100,7 @@ func NewCreator(
 		userService:              uService,
 		currencyService:          currencyService,
 		currencyProvider:         currencyProvider,
+		rateSource:               rateSource,
 		transferFeeService:       transferFeeService,
 		requestRepository:        requestRepository,
 		accountsRepository:       accountsRepository,
@@ -102,6 +112,8 @@ func NewCreator(
 		emitter:                  emitter,
 		settings:                 settings,
 		pf:                       pf,
+		quoteService:             quoteService,
+		exchangeMarginService:    exchangeMarginService,
 		logger:                   logger,
 	}
 }
@@ -130,19 +142,25 @@ func (c *Creator) CreateTBARequest(form *form.TBA, user *users.User, db *gorm.DB
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(accountFrom.Type.CurrencyCode, accountTo.Type.CurrencyCode)
-
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getLockedRate(db, &RateQuery{
+		Subject:          constants.SubjectTransferBetweenAccounts,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   accountTo.Type.CurrencyCode,
+		Amount:           amount,
+	}, form.QuoteId)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
-	params, err := c.getFeeParams(c.db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "TBA", nil)
+	params, err := c.getLockedFeeParams(db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "TBA", nil)
 	if err != nil && errorsPkg.Cause(err) != errFeeNotFound {
 		return
 	}
@@ -237,15 +255,21 @@ func (c *Creator) EvaluateTBARequest(form *form.TBAPreview, user *users.User) (d
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(accountFrom.Type.CurrencyCode, accountTo.Type.CurrencyCode)
-
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getQuotedRate(&RateQuery{
+		Subject:          constants.SubjectTransferBetweenAccounts,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   accountTo.Type.CurrencyCode,
+		Amount:           amount,
+	}, &form.RateQuote)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
@@ -313,19 +337,25 @@ func (c *Creator) CreateTBURequest(form *form.TBU, user *users.User, db *gorm.DB
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(accountFrom.Type.CurrencyCode, accountTo.Type.CurrencyCode)
-
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getLockedRate(db, &RateQuery{
+		Subject:          constants.SubjectTransferBetweenUsers,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   accountTo.Type.CurrencyCode,
+		Amount:           amount,
+	}, form.QuoteId)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
-	params, err := c.getFeeParams(c.db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "TBU", nil)
+	params, err := c.getLockedFeeParams(db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "TBU", nil)
 	if err != nil && errorsPkg.Cause(err) != errFeeNotFound {
 		return
 	}
@@ -427,15 +457,21 @@ func (c *Creator) EvaluateTBURequest(form *form.TBUPreview, user *users.User) (d
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(accountFrom.Type.CurrencyCode, accountTo.Type.CurrencyCode)
-
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getQuotedRate(&RateQuery{
+		Subject:          constants.SubjectTransferBetweenUsers,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   accountTo.Type.CurrencyCode,
+		Amount:           amount,
+	}, &form.RateQuote)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", accountTo.Type.CurrencyCode)
 		return
 	}
 
@@ -485,18 +521,25 @@ func (c *Creator) CreateOWTRequest(form *form.OWT, user *users.User, db *gorm.DB
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(*form.ReferenceCurrencyCode, accountFrom.Type.CurrencyCode)
-
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", form.ReferenceCurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getLockedRate(db, &RateQuery{
+		Subject:          constants.SubjectTransferOutgoingWireTransfer,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: *form.ReferenceCurrencyCode,
+		CurrencyCodeTo:   accountFrom.Type.CurrencyCode,
+		Amount:           amount,
+	}, form.QuoteId)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", form.ReferenceCurrencyCode)
 		return
 	}
-	params, err := c.getFeeParams(c.db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "OWT", form.FeeId)
+
+	params, err := c.getLockedFeeParams(db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "OWT", form.FeeId)
 	if form.FeeId != nil && errorsPkg.Cause(err) == errFeeNotFound {
 		return nil, errcodes.CreatePublicError(errcodes.CodeFeeParamsNotFound)
 	}
@@ -504,6 +547,11 @@ func (c *Creator) CreateOWTRequest(form *form.OWT, user *users.User, db *gorm.DB
 		return
 	}
 
+	ourChargePercent, err := c.getOurChargePercent(form.GetChargeBearer())
+	if err != nil {
+		return
+	}
+
 	isAdmin, isSystem := c.GetIsAdminIsSystem(user)
 	subject := constants.SubjectTransferOutgoingWireTransfer
 	status := constants.StatusNew
@@ -529,6 +577,8 @@ func (c *Creator) CreateOWTRequest(form *form.OWT, user *users.User, db *gorm.DB
 	requestInput.Set("exchangeMarginPercent", rate.ExchangeMargin)
 	requestInput.Set("refMessage", *form.RefMessage)
 	requestInput.Set("beneficiaryCustomerAccountName", *form.CustomerName)
+	requestInput.Set("chargeBearer", form.GetChargeBearer().String())
+	requestInput.Set("ourChargePercent", ourChargePercent)
 
 	reqRepoTx := c.requestRepository.WrapContext(db)
 
@@ -559,6 +609,8 @@ func (c *Creator) CreateOWTRequest(form *form.OWT, user *users.User, db *gorm.DB
 		params,
 		*form.CustomerName,
 		*form.RefMessage,
+		form.GetChargeBearer(),
+		ourChargePercent,
 	)
 
 	owt := transfers.NewOutgoingWireTransfer(input, c.currencyProvider, db, c.pf)
@@ -583,15 +635,21 @@ func (c *Creator) EvaluateOWTRequest(form *form.OWTPreview, user *users.User) (d
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(*form.ReferenceCurrencyCode, accountFrom.Type.CurrencyCode)
-
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", *form.ReferenceCurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getQuotedRate(&RateQuery{
+		Subject:          constants.SubjectTransferOutgoingWireTransfer,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: *form.ReferenceCurrencyCode,
+		CurrencyCodeTo:   accountFrom.Type.CurrencyCode,
+		Amount:           amount,
+	}, &form.RateQuote)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyIdFrom", accountFrom.Type.CurrencyCode, "currencyIdTo", *form.ReferenceCurrencyCode)
 		return
 	}
 
@@ -613,6 +671,11 @@ func (c *Creator) EvaluateOWTRequest(form *form.OWTPreview, user *users.User) (d
 		return
 	}
 
+	ourChargePercent, err := c.getOurChargePercent(form.GetChargeBearer())
+	if err != nil {
+		return
+	}
+
 	input := transfers.NewOwtInput(
 		accountFrom,
 		stubRevenueAccount(accountFrom.Type.CurrencyCode),
@@ -620,12 +683,31 @@ func (c *Creator) EvaluateOWTRequest(form *form.OWTPreview, user *users.User) (d
 		params,
 		"",
 		"",
+		form.GetChargeBearer(),
+		ourChargePercent,
 	)
 
 	owt := transfers.NewOutgoingWireTransfer(input, c.currencyProvider, c.db, c.pf)
 	return owt.Evaluate(request)
 }
 
+// getOurChargePercent returns charge of correspondent and beneficiary banks which is collected from the sender
+// if they bear all charges, such transfers are refused if the setting is not set
+func (c *Creator) getOurChargePercent(chargeBearer constants.ChargeBearer) (decimal.Decimal, error) {
+	if chargeBearer != constants.ChargeBearerOur {
+		return decimal.Zero, nil
+	}
+	value, err := c.settings.String(SettingOwtOurChargePercentString)
+	if err != nil || strings.TrimSpace(value) == "" {
+		return decimal.Zero, errcodes.CreatePublicError(errcodes.CodeOurChargeNotConfigured)
+	}
+	percent, err := decimal.NewFromString(strings.TrimSpace(value))
+	if err != nil || percent.IsNegative() {
+		return decimal.Zero, errorsPkg.Errorf("invalid setting %s: %q", SettingOwtOurChargePercentString, value)
+	}
+	return percent, nil
+}
+
 func (c *Creator) CreateCFTRequest(form *form.CFT, user *users.User, db *gorm.DB) (request *model.Request, err error) {
 	logger := c.logger.New("action", "CreateCFTRequest")
 
@@ -640,8 +722,19 @@ func (c *Creator) CreateCFTRequest(form *form.CFT, user *users.User, db *gorm.DB
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(accountFrom.Type.CurrencyCode, *card.CardType.CurrencyCode)
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	if err != nil {
+		return
+	}
 
+	rate, err := c.getLockedRate(db, &RateQuery{
+		Subject:          constants.SubjectCardFundingTransfer,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   *card.CardType.CurrencyCode,
+		Amount:           amount,
+	}, form.QuoteId)
 	if err != nil {
 		logger.Error("failed to obtain rate", "error", err, "currencyCodeFrom", accountFrom.Type.CurrencyCode, "currencyCodeTo", *card.CardType.CurrencyCode)
 		return
@@ -658,12 +751,7 @@ func (c *Creator) CreateCFTRequest(form *form.CFT, user *users.User, db *gorm.DB
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
-	if err != nil {
-		return
-	}
-
-	params, err := c.getFeeParams(c.db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "CFT", nil)
+	params, err := c.getLockedFeeParams(db, accountFrom.UserId, accountFrom.Type.CurrencyCode, "CFT", nil)
 	if err != nil && errorsPkg.Cause(err) != errFeeNotFound {
 		return
 	}
@@ -742,14 +830,21 @@ func (c *Creator) EvaluateCFTRequest(form *form.CFTPreview, user *users.User) (d
 		return
 	}
 
-	rate, err := c.getRateForCurrencies(accountFrom.Type.CurrencyCode, *card.CardType.CurrencyCode)
+	amount, err := decimal.NewFromString(*form.OutgoingAmount)
 	if err != nil {
-		logger.Error("failed to obtain rate", "error", err, "currencyCodeFrom", accountFrom.Type.CurrencyCode, "currencyCodeTo", card.CardType.CurrencyCode)
 		return
 	}
 
-	amount, err := decimal.NewFromString(*form.OutgoingAmount)
+	rate, err := c.getQuotedRate(&RateQuery{
+		Subject:          constants.SubjectCardFundingTransfer,
+		UserId:           user.UID,
+		OwnerId:          accountFrom.UserId,
+		CurrencyCodeFrom: accountFrom.Type.CurrencyCode,
+		CurrencyCodeTo:   *card.CardType.CurrencyCode,
+		Amount:           amount,
+	}, &form.RateQuote)
 	if err != nil {
+		logger.Error("failed to obtain rate", "error", err, "currencyCodeFrom", accountFrom.Type.CurrencyCode, "currencyCodeTo", card.CardType.CurrencyCode)
 		return
 	}
 
@@ -903,7 +998,7 @@ func (c *Creator) CreateCARequest(
 
 	var feeParams *transferFee.TransferFeeParams
 	if *form.ApplyIwtFee {
-		feeParams, err = c.getFeeParams(db, accountTo.UserId, *request.BaseCurrencyCode, "IWT", nil)
+		feeParams, err = c.getLockedFeeParams(db, accountTo.UserId, *request.BaseCurrencyCode, "IWT", nil)
 		if err != nil {
 			if errorsPkg.Cause(err) == errFeeNotFound {
 				return nil, errcodes.CreatePublicError(
@@ -1166,14 +1261,16 @@ func (c *Creator) saveTemplateIfRequired(db *gorm.DB, user *users.User, subject
 	return nil
 }
 
+// getRateForCurrencies returns the rate of the configured rate source, so that transfers use the same rate
+// as the one shown to users. Exchange margin of the currencies service is used by default, it is zero
+// if the service is not available and the rate is taken from imported rates.
 func (c *Creator) getRateForCurrencies(currencyCodeFrom, currencyCodeTo string) (*service.Rate, error) {
 	rate := &service.Rate{
 		Rate:           decimal.NewFromInt(1),
 		ExchangeMargin: decimal.NewFromInt(0),
 	}
 	if currencyCodeFrom != currencyCodeTo {
-		var err error
-		rate, err = c.currencyService.GetCurrenciesRateByCodes(currencyCodeFrom, currencyCodeTo)
+		found, err := c.rateSource.FindRate(currencyCodeFrom, currencyCodeTo)
 		if err != nil {
 			return rate, errorsPkg.Wrapf(
 				err,
@@ -1182,14 +1279,39 @@ func (c *Creator) getRateForCurrencies(currencyCodeFrom, currencyCodeTo string)
 				currencyCodeTo,
 			)
 		}
-		if rate.Rate.LessThanOrEqual(decimal.Zero) {
+		if found.Rate().LessThanOrEqual(decimal.Zero) {
 			return rate, errcodes.CreatePublicError(errcodes.CodeInvalidExchangeRate)
 		}
+		rate.Rate = found.Rate()
+
+		remote, err := c.currencyService.GetCurrenciesRateByCodes(currencyCodeFrom, currencyCodeTo)
+		if err != nil {
+			c.logger.Warn(
+				"exchange margin of currencies service is not available",
+				"error", err,
+				"currencyCodeFrom", currencyCodeFrom,
+				"currencyCodeTo", currencyCodeTo,
+			)
+			return rate, nil
+		}
+		rate.ExchangeMargin = remote.ExchangeMargin
 	}
 	return rate, nil
 }
 
 func (c *Creator) getFeeParams(db *gorm.DB, userUID, currencyCode, subject string, feeId *uint64) (*transferFee.TransferFeeParams, error) {
+	return c.findFeeParams(db, userUID, currencyCode, subject, feeId, false)
+}
+
+// getLockedFeeParams finds fee parameters within the transaction creating the request, usage of the user counted
+// by free transfers quota is locked until the transaction ends so concurrent requests are counted one by one
+func (c *Creator) getLockedFeeParams(tx *gorm.DB, userUID, currencyCode, subject string, feeId *uint64) (*transferFee.TransferFeeParams, error) {
+	return c.findFeeParams(tx, userUID, currencyCode, subject, feeId, true)
+}
+
+func (c *Creator) findFeeParams(
+	db *gorm.DB, userUID, currencyCode, subject string, feeId *uint64, lock bool,
+) (*transferFee.TransferFeeParams, error) {
 	logger := c.logger.New("method", "getFeeParams")
 	accountOwner, err := c.userService.GetByUID(userUID)
 	if err != nil {
@@ -1210,7 +1332,22 @@ func (c *Creator) getFeeParams(db *gorm.DB, userUID, currencyCode, subject strin
 	if err != nil {
 		return nil, errFeeNotFound
 	}
-	return transferFeeModelToParams(params), nil
+	result := transferFeeModelToParams(params)
+
+	now := time.Now()
+	discount, err := c.transferFeeService.FindDiscount(params, &fee.UsageQuery{
+		UserId:         userUID,
+		RequestSubject: constants.Subject(subject),
+		CurrencyCode:   currencyCode,
+		Since:          time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+		Lock:           lock,
+	}, db)
+	if err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to find transfer fee discount")
+	}
+	result.Free = discount.Free
+	result.DiscountPercent = discount.DiscountPercent
+	return result, nil
 }
 
 func (c *Creator) adminApprovalRequired(subject string) (bool, error) {
@@ -1240,6 +1377,12 @@ func transferFeeModelToParams(feeModelParams *feeModel.TransferFeeParameters) *t
 	if feeModelParams.Max != nil {
 		result.Max = *feeModelParams.Max
 	}
+	for _, tier := range feeModelParams.Tiers {
+		result.Tiers = append(result.Tiers, transferFee.TransferFeeTier{
+			From:    *tier.MinAmount,
+			Percent: *tier.Percent,
+		})
+	}
 	return result
 }
 
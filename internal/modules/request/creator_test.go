@@ -0,0 +1,121 @@
+package request_test
+
+import (
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/exchange"
+	mockExchange "github.com/Confialink/wallet-accounts/internal/exchange/mock"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	mockService "github.com/Confialink/wallet-accounts/internal/modules/currency/service/mock"
+	. "github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+)
+
+var _ = Describe("Creator", func() {
+	var (
+		ctrl            *gomock.Controller
+		rateSource      *mockExchange.MockRateSource
+		currencyService *mockService.MockCurrenciesServiceInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		rateSource = mockExchange.NewMockRateSource(ctrl)
+		currencyService = mockService.NewMockCurrenciesServiceInterface(ctrl)
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should take the rate from the configured rate source", func() {
+		rateSource.EXPECT().FindRate("EUR", "USD").
+			Return(exchange.NewRate("EUR", "USD", decimal.RequireFromString("1.2")), nil)
+		currencyService.EXPECT().GetCurrenciesRateByCodes("EUR", "USD").
+			Return(&service.Rate{Rate: decimal.RequireFromString("1.3"), ExchangeMargin: decimal.RequireFromString("2")}, nil)
+
+		rate, err := RateForCurrencies(rateSource, currencyService, "EUR", "USD")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rate.Rate.String()).To(Equal("1.2"))
+		Expect(rate.ExchangeMargin.String()).To(Equal("2"))
+	})
+
+	It("should use the rate without margin if currencies service is not available", func() {
+		rateSource.EXPECT().FindRate("EUR", "USD").
+			Return(exchange.NewRate("EUR", "USD", decimal.RequireFromString("1.2")), nil)
+		currencyService.EXPECT().GetCurrenciesRateByCodes("EUR", "USD").Return(nil, errors.New("unavailable"))
+
+		rate, err := RateForCurrencies(rateSource, currencyService, "EUR", "USD")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rate.Rate.String()).To(Equal("1.2"))
+		Expect(rate.ExchangeMargin.IsZero()).To(BeTrue())
+	})
+
+	It("should fail if the rate source fails", func() {
+		rateSource.EXPECT().FindRate("EUR", "USD").Return(exchange.Rate{}, exchange.ErrRateStale)
+
+		_, err := RateForCurrencies(rateSource, currencyService, "EUR", "USD")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should not look up the rate of the same currency", func() {
+		rate, err := RateForCurrencies(rateSource, currencyService, "EUR", "EUR")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rate.Rate.String()).To(Equal("1"))
+	})
+})
+
+var _ = Describe("OUR charge", func() {
+	var (
+		mock            sqlmock.Sqlmock
+		settingsService *settings.Service
+	)
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err := gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		settingsService = settings.NewService(settingsRepository.NewSettings(gdb))
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectSetting := func(value string) {
+		mock.ExpectQuery("SELECT \\* FROM `settings`").WillReturnRows(sqlmock.
+			NewRows([]string{"id", "name", "value"}).
+			AddRow(1, "owt_our_charge_percent", value))
+	}
+
+	It("should charge the configured percent if the sender bears all charges", func() {
+		expectSetting("1.5")
+
+		percent, err := OurChargePercent(settingsService, constants.ChargeBearerOur)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(percent.String()).To(Equal("1.5"))
+	})
+
+	It("should refuse transfers where the sender bears all charges if the charge is not configured", func() {
+		expectSetting("")
+
+		_, err := OurChargePercent(settingsService, constants.ChargeBearerOur)
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeOurChargeNotConfigured))
+	})
+
+	It("should not charge other charge bearers", func() {
+		percent, err := OurChargePercent(settingsService, constants.ChargeBearerShared)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(percent.IsZero()).To(BeTrue())
+	})
+})
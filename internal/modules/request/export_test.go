@@ -0,0 +1,74 @@
+package request
+
+import (
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/exchange"
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	feeModel "github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/service/camt"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+)
+
+// SetQuoteServiceClock replaces clock of the quote service
+func SetQuoteServiceClock(service *QuoteService, now func() time.Time) {
+	service.now = now
+}
+
+// ValidateConvertAccounts exposes validation of convert accounts
+var ValidateConvertAccounts = validateConvertAccounts
+
+// SimulationRange exposes parsing of the fee simulation range
+var SimulationRange = simulationRange
+
+// ReplayFees replays the given requests through the draft fee parameters
+func ReplayFees(
+	draft map[string]*feeModel.TransferFeeParameters,
+	requests []*model.Request,
+	feePurpose transactionConstants.Purpose,
+	groups map[string]uint64,
+) *FeeSimulation {
+	replay := newFeeReplay(draft)
+	for _, request := range requests {
+		replay.add(request, feePurpose)
+	}
+	return replay.result(groups)
+}
+
+// WireMessageId exposes generation of the exported file reference
+var WireMessageId = wireMessageId
+
+// WireSentAmount exposes calculation of the amount sent by wire
+var WireSentAmount = wireSentAmount
+
+// MatchIncomingPaymentAccount exposes matching of the account credited with the incoming payment
+func MatchIncomingPaymentAccount(service *IncomingPaymentService, entry *camt.Entry) (*accountModel.Account, string, error) {
+	return service.matchAccount(entry)
+}
+
+// ImportIncomingPaymentEntry exposes import of the single entry of camt file
+func ImportIncomingPaymentEntry(service *IncomingPaymentService, entry *camt.Entry) (*model.IncomingPayment, error) {
+	return service.importEntry(entry, "statement.xml", nil)
+}
+
+// RateForCurrencies exposes the rate used by requests of the creator with the given sources
+func RateForCurrencies(
+	rateSource exchange.RateSource,
+	currencyService service.CurrenciesServiceInterface,
+	currencyCodeFrom, currencyCodeTo string,
+) (*service.Rate, error) {
+	creator := &Creator{rateSource: rateSource, currencyService: currencyService, logger: log15.New()}
+	return creator.getRateForCurrencies(currencyCodeFrom, currencyCodeTo)
+}
+
+// OurChargePercent returns charge of other banks collected from the sender with the given charge bearer
+func OurChargePercent(settings *settings.Service, chargeBearer constants.ChargeBearer) (decimal.Decimal, error) {
+	return (&Creator{settings: settings}).getOurChargePercent(chargeBearer)
+}
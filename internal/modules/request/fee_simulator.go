@@ -0,0 +1,296 @@
+package request
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	errorsPkg "github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee"
+	feeForm "github.com/Confialink/wallet-accounts/internal/modules/fee/form"
+	feeModel "github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	userService "github.com/Confialink/wallet-accounts/internal/modules/user/service"
+	"github.com/Confialink/wallet-accounts/internal/transfer"
+	transferFee "github.com/Confialink/wallet-accounts/internal/transfer/fee"
+)
+
+const (
+	simulationDateLayout = "2006-01-02"
+	simulationBatchSize  = 500
+	// simulationMaxMonths limits the replayed period so that a simulation could not scan the whole history
+	simulationMaxMonths = 12
+)
+
+// FeeSimulator replays executed requests through a draft transfer fee configuration
+type FeeSimulator struct {
+	db          *gorm.DB
+	userService *userService.UserService
+	logger      log15.Logger
+}
+
+// FeeTotals compares fees charged by executed requests with fees of the draft configuration
+type FeeTotals struct {
+	CurrencyCode  string          `json:"currencyCode"`
+	UserGroupId   *uint64         `json:"userGroupId,omitempty"`
+	RequestsCount uint64          `json:"requestsCount"`
+	ActualFees    decimal.Decimal `json:"actualFees"`
+	SimulatedFees decimal.Decimal `json:"simulatedFees"`
+	Difference    decimal.Decimal `json:"difference"`
+}
+
+// FeeSimulation is a result of the simulation, totals are grouped by currency and by user group and currency
+type FeeSimulation struct {
+	RequestsCount uint64       `json:"requestsCount"`
+	Currencies    []*FeeTotals `json:"currencies"`
+	UserGroups    []*FeeTotals `json:"userGroups"`
+}
+
+// simulatedRequest is a fee of a single replayed request
+type simulatedRequest struct {
+	ownerId      string
+	currencyCode string
+	actual       decimal.Decimal
+	simulated    decimal.Decimal
+}
+
+func NewFeeSimulator(db *gorm.DB, userService *userService.UserService, logger log15.Logger) *FeeSimulator {
+	return &FeeSimulator{
+		db:          db,
+		userService: userService,
+		logger:      logger.New("service", "FeeSimulator"),
+	}
+}
+
+// Simulate calculates fees of executed requests of the given subject created within the date range using
+// the draft configuration. Free transfers quotas and volume discounts are replayed month by month
+// taking into account only the requests of the range.
+func (s *FeeSimulator) Simulate(simulationForm *feeForm.TransferFeeSimulation) (*FeeSimulation, error) {
+	from, to, err := simulationRange(*simulationForm.DateFrom, *simulationForm.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := make(map[string]*feeModel.TransferFeeParameters, len(simulationForm.Parameters))
+	for _, formParams := range simulationForm.Parameters {
+		params, err := formParams.ToModel()
+		if err != nil {
+			return nil, err
+		}
+		draft[*params.CurrencyCode] = params
+	}
+
+	subject := *simulationForm.RequestSubject
+	feePurpose := transactionConstants.PurposeFeeTransfer
+	if subject.EqualsTo(constants.SubjectTransferIncomingWireTransfer) {
+		feePurpose = transactionConstants.PurposeFeeIWT
+	}
+
+	replay := newFeeReplay(draft)
+	var lastId uint64
+	for {
+		var requests []*model.Request
+		err := s.db.
+			Preload("Transactions").
+			Preload("Transactions.Account").
+			Where(
+				"subject = ? AND status = ? AND created_at >= ? AND created_at < ? AND id > ?",
+				subject, constants.StatusExecuted, from, to, lastId,
+			).
+			Order("id").
+			Limit(simulationBatchSize).
+			Find(&requests).
+			Error
+		if err != nil {
+			return nil, errorsPkg.Wrap(err, "failed to load executed requests")
+		}
+		for _, request := range requests {
+			replay.add(request, feePurpose)
+			lastId = *request.Id
+		}
+		if len(requests) < simulationBatchSize {
+			break
+		}
+	}
+
+	groups, err := s.userGroups(replay.ownerIds())
+	if err != nil {
+		return nil, err
+	}
+	return replay.result(groups), nil
+}
+
+// userGroups returns user groups of the given users, users without group are omitted
+func (s *FeeSimulator) userGroups(uids []string) (map[string]uint64, error) {
+	groups := make(map[string]uint64, len(uids))
+	if len(uids) == 0 {
+		return groups, nil
+	}
+	users, err := s.userService.GetByUIDs(uids)
+	if err != nil {
+		s.logger.Error("failed to retrieve users", "error", err)
+		return nil, errorsPkg.Wrap(err, "failed to retrieve users")
+	}
+	for _, user := range users {
+		if user.GroupId != 0 {
+			groups[user.UID] = user.GroupId
+		}
+	}
+	return groups, nil
+}
+
+// feeReplay calculates draft fees of requests passed in order they were created
+type feeReplay struct {
+	draft     map[string]*feeModel.TransferFeeParameters
+	requests  []*simulatedRequest
+	counts    map[string]uint64
+	volumes   map[string]decimal.Decimal
+	ownersSet map[string]bool
+}
+
+func newFeeReplay(draft map[string]*feeModel.TransferFeeParameters) *feeReplay {
+	return &feeReplay{
+		draft:     draft,
+		counts:    make(map[string]uint64),
+		volumes:   make(map[string]decimal.Decimal),
+		ownersSet: make(map[string]bool),
+	}
+}
+
+func (r *feeReplay) add(request *model.Request, feePurpose transactionConstants.Purpose) {
+	if request.Amount == nil || request.BaseCurrencyCode == nil {
+		return
+	}
+	currencyCode := *request.BaseCurrencyCode
+	ownerId := requestOwnerId(request)
+	// free transfers quota and volume discounts are counted per currency like the fee parameters are defined
+	key := ownerId + "/" + request.CreatedAt.Format("2006-01") + "/" + currencyCode
+
+	simulated := decimal.Zero
+	if params, ok := r.draft[currencyCode]; ok {
+		feeParams := transferFeeModelToParams(params)
+		if params.FreePerMonth != nil && r.counts[key] < *params.FreePerMonth {
+			feeParams.Free = true
+		}
+		feeParams.DiscountPercent = fee.VolumeDiscountPercent(params.VolumeDiscounts, r.volumes[key])
+		amount := transfer.NewAmount(*transfer.NewCurrency(currencyCode, 0), *request.Amount)
+		simulated = transferFee.NewTransferFeeAmount(*feeParams, amount).Amount()
+	}
+	r.counts[key]++
+	r.volumes[key] = r.volumes[key].Add(*request.Amount)
+
+	actual := decimal.Zero
+	for _, transaction := range request.Transactions {
+		if transaction.Purpose != nil && *transaction.Purpose == feePurpose.String() &&
+			transaction.AccountId != nil && transaction.Amount != nil {
+			actual = actual.Sub(*transaction.Amount)
+		}
+	}
+
+	r.ownersSet[ownerId] = true
+	r.requests = append(r.requests, &simulatedRequest{
+		ownerId:      ownerId,
+		currencyCode: currencyCode,
+		actual:       actual,
+		simulated:    simulated,
+	})
+}
+
+func (r *feeReplay) ownerIds() []string {
+	ids := make([]string, 0, len(r.ownersSet))
+	for id := range r.ownersSet {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (r *feeReplay) result(groups map[string]uint64) *FeeSimulation {
+	currencies := make(map[string]*FeeTotals)
+	userGroups := make(map[string]*FeeTotals)
+	for _, request := range r.requests {
+		addToTotals(currencies, request.currencyCode, nil, request)
+
+		var groupId *uint64
+		if id, ok := groups[request.ownerId]; ok {
+			groupId = &id
+		}
+		addToTotals(userGroups, request.currencyCode, groupId, request)
+	}
+
+	return &FeeSimulation{
+		RequestsCount: uint64(len(r.requests)),
+		Currencies:    sortedTotals(currencies),
+		UserGroups:    sortedTotals(userGroups),
+	}
+}
+
+func addToTotals(totals map[string]*FeeTotals, currencyCode string, groupId *uint64, request *simulatedRequest) {
+	key := currencyCode
+	if groupId != nil {
+		key = currencyCode + "/" + strconv.FormatUint(*groupId, 10)
+	}
+	item, ok := totals[key]
+	if !ok {
+		item = &FeeTotals{CurrencyCode: currencyCode, UserGroupId: groupId}
+		totals[key] = item
+	}
+	item.RequestsCount++
+	item.ActualFees = item.ActualFees.Add(request.actual)
+	item.SimulatedFees = item.SimulatedFees.Add(request.simulated)
+	item.Difference = item.SimulatedFees.Sub(item.ActualFees)
+}
+
+func sortedTotals(totals map[string]*FeeTotals) []*FeeTotals {
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]*FeeTotals, len(keys))
+	for i, key := range keys {
+		result[i] = totals[key]
+	}
+	return result
+}
+
+// requestOwnerId returns owner of the account debited by the request or the initiator if there are no debits
+func requestOwnerId(request *model.Request) string {
+	for _, transaction := range request.Transactions {
+		if transaction.Account != nil && transaction.Amount != nil && transaction.Amount.IsNegative() {
+			return transaction.Account.UserId
+		}
+	}
+	if request.UserId != nil {
+		return *request.UserId
+	}
+	return ""
+}
+
+func simulationRange(dateFrom, dateTo string) (from, to time.Time, err error) {
+	from, err = time.Parse(simulationDateLayout, dateFrom)
+	if err != nil {
+		return from, to, errcodes.CreatePublicError(errcodes.CodeInvalidDateRange)
+	}
+	to, err = time.Parse(simulationDateLayout, dateTo)
+	if err != nil || to.Before(from) {
+		return from, to, errcodes.CreatePublicError(errcodes.CodeInvalidDateRange)
+	}
+	// the end date is inclusive
+	to = to.AddDate(0, 0, 1)
+	if to.After(from.AddDate(0, simulationMaxMonths, 0)) {
+		return from, to, errcodes.CreatePublicError(
+			errcodes.CodeInvalidDateRange,
+			"Date range must not exceed "+strconv.Itoa(simulationMaxMonths)+" months.",
+		)
+	}
+	return from, to, nil
+}
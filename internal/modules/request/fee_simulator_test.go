@@ -0,0 +1,165 @@
+package request_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	feeModel "github.com/Confialink/wallet-accounts/internal/modules/fee/model"
+	. "github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	transactionModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
+)
+
+func dec(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+// executedRequest creates request of the owner with the transfer fee actually charged from their account
+func executedRequest(ownerId, currencyCode, amount, actualFee string, createdAt time.Time) *model.Request {
+	owner := &accountModel.Account{AccountPublic: accountModel.AccountPublic{UserId: ownerId}}
+	transactions := []*transactionModel.Transaction{{
+		Purpose:   pointer.ToString(transactionConstants.PurposeTBAOutgoing.String()),
+		AccountId: pointer.ToUint64(1),
+		Account:   owner,
+		Amount:    dec("-" + amount),
+	}}
+	if actualFee != "0" {
+		transactions = append(transactions, &transactionModel.Transaction{
+			Purpose:   pointer.ToString(transactionConstants.PurposeFeeTransfer.String()),
+			AccountId: pointer.ToUint64(1),
+			Account:   owner,
+			Amount:    dec("-" + actualFee),
+		})
+	}
+	return &model.Request{
+		Amount:           dec(amount),
+		BaseCurrencyCode: pointer.ToString(currencyCode),
+		CreatedAt:        &createdAt,
+		Transactions:     transactions,
+	}
+}
+
+var _ = Describe("FeeSimulator", func() {
+	table.DescribeTable("simulation range",
+		func(dateFrom, dateTo string, valid bool) {
+			from, to, err := SimulationRange(dateFrom, dateTo)
+			if !valid {
+				Expect(publicErrorCode(err)).To(Equal(errcodes.CodeInvalidDateRange))
+				return
+			}
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(from.Format("2006-01-02")).To(Equal(dateFrom))
+			// the end date is inclusive
+			Expect(to.AddDate(0, 0, -1).Format("2006-01-02")).To(Equal(dateTo))
+		},
+		table.Entry("single day", "2020-03-01", "2020-03-01", true),
+		table.Entry("twelve months", "2020-01-01", "2020-12-31", true),
+		table.Entry("more than twelve months", "2020-01-01", "2021-01-01", false),
+		table.Entry("end before start", "2020-03-02", "2020-03-01", false),
+		table.Entry("invalid start", "2020-13-01", "2020-03-01", false),
+		table.Entry("invalid end", "2020-03-01", "03/05/2020", false),
+	)
+
+	Context("replay", func() {
+		march := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+		april := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+		draft := map[string]*feeModel.TransferFeeParameters{
+			"EUR": {
+				CurrencyCode: pointer.ToString("EUR"),
+				Base:         dec("1"),
+				Percent:      dec("1"),
+				FreePerMonth: pointer.ToUint64(1),
+				VolumeDiscounts: []*feeModel.TransferFeeVolumeDiscount{
+					{MinVolume: dec("300"), DiscountPercent: dec("50")},
+				},
+			},
+		}
+
+		It("should apply free transfers quota per owner and month", func() {
+			result := ReplayFees(draft, []*model.Request{
+				executedRequest("user-1", "EUR", "100", "2", march),
+				executedRequest("user-1", "EUR", "100", "2", march.Add(time.Hour)),
+				executedRequest("user-2", "EUR", "100", "2", march),
+				executedRequest("user-1", "EUR", "100", "2", april),
+			}, transactionConstants.PurposeFeeTransfer, nil)
+
+			Expect(result.RequestsCount).To(Equal(uint64(4)))
+			Expect(result.Currencies).To(HaveLen(1))
+			totals := result.Currencies[0]
+			Expect(totals.ActualFees.String()).To(Equal("8"))
+			// only the second request of user-1 in march is charged: 1 + 1% of 100
+			Expect(totals.SimulatedFees.String()).To(Equal("2"))
+			Expect(totals.Difference.String()).To(Equal("-6"))
+		})
+
+		It("should apply free transfers quota per currency", func() {
+			withUsd := map[string]*feeModel.TransferFeeParameters{
+				"EUR": draft["EUR"],
+				"USD": {CurrencyCode: pointer.ToString("USD"), Base: dec("1"), FreePerMonth: pointer.ToUint64(1)},
+			}
+			result := ReplayFees(withUsd, []*model.Request{
+				executedRequest("user-1", "EUR", "100", "0", march),
+				executedRequest("user-1", "USD", "100", "0", march.Add(time.Hour)),
+			}, transactionConstants.PurposeFeeTransfer, nil)
+
+			Expect(result.Currencies).To(HaveLen(2))
+			for _, totals := range result.Currencies {
+				Expect(totals.SimulatedFees.IsZero()).To(BeTrue(), totals.CurrencyCode)
+			}
+		})
+
+		It("should apply volume discount reached by previous requests of the month", func() {
+			result := ReplayFees(draft, []*model.Request{
+				executedRequest("user-1", "EUR", "100", "0", march),
+				executedRequest("user-1", "EUR", "200", "0", march.Add(time.Hour)),
+				executedRequest("user-1", "EUR", "100", "0", march.Add(2*time.Hour)),
+			}, transactionConstants.PurposeFeeTransfer, nil)
+
+			// free, then 1 + 2 without discount, then (1 + 1) with 50% discount
+			Expect(result.Currencies[0].SimulatedFees.String()).To(Equal("4"))
+		})
+
+		It("should not charge requests in currencies missing in the draft", func() {
+			result := ReplayFees(draft, []*model.Request{
+				executedRequest("user-1", "USD", "100", "3", march),
+			}, transactionConstants.PurposeFeeTransfer, nil)
+
+			Expect(result.Currencies[0].CurrencyCode).To(Equal("USD"))
+			Expect(result.Currencies[0].ActualFees.String()).To(Equal("3"))
+			Expect(result.Currencies[0].SimulatedFees.IsZero()).To(BeTrue())
+		})
+
+		It("should count only fees of the given purpose", func() {
+			result := ReplayFees(draft, []*model.Request{
+				executedRequest("user-1", "EUR", "100", "2", march),
+			}, transactionConstants.PurposeFeeIWT, nil)
+
+			Expect(result.Currencies[0].ActualFees.IsZero()).To(BeTrue())
+		})
+
+		It("should group totals by user group of the owner", func() {
+			result := ReplayFees(draft, []*model.Request{
+				executedRequest("user-1", "EUR", "100", "2", march),
+				executedRequest("user-2", "EUR", "100", "2", march),
+				executedRequest("user-3", "EUR", "100", "2", march),
+			}, transactionConstants.PurposeFeeTransfer, map[string]uint64{"user-1": 7, "user-2": 7})
+
+			Expect(result.UserGroups).To(HaveLen(2))
+			// users without group go first
+			Expect(result.UserGroups[0].UserGroupId).To(BeNil())
+			Expect(result.UserGroups[0].RequestsCount).To(Equal(uint64(1)))
+			Expect(*result.UserGroups[1].UserGroupId).To(Equal(uint64(7)))
+			Expect(result.UserGroups[1].RequestsCount).To(Equal(uint64(2)))
+			Expect(result.UserGroups[1].ActualFees.String()).To(Equal("4"))
+		})
+	})
+})
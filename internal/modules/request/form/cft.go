@@ -4,6 +4,7 @@ type CFTPreview struct {
 	AccountIdFrom  *uint64 `form:"accountIdFrom" json:"accountIdFrom" binding:"required"`
 	CardIdTo       *uint32 `form:"cardIdTo" json:"cardIdTo" binding:"required"`
 	OutgoingAmount *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
+	RateQuote
 }
 
 type CFT struct {
@@ -13,6 +14,7 @@ type CFT struct {
 	OutgoingAmount *string `json:"outgoingAmount,omitempty" binding:"required,decimalGT=0"`
 	Description    *string `json:"description,omitempty" binding:"required,max=65535"`
 	IncomingAmount *string `json:"incomingAmount,omitempty" binding:"required,decimalGT=0"`
+	QuoteId        *string `json:"quoteId,omitempty"`
 }
 
 func (c *CFT) ToCFTPreview() *CFTPreview {
@@ -20,11 +22,13 @@ func (c *CFT) ToCFTPreview() *CFTPreview {
 		AccountIdFrom:  c.AccountIdFrom,
 		CardIdTo:       c.CardIdTo,
 		OutgoingAmount: c.OutgoingAmount,
+		RateQuote:      RateQuote{QuoteId: c.QuoteId},
 	}
 }
 
 func (c CFT) TemplateData() interface{} {
 	c.BaseTemplate = nil
 	c.IncomingAmount = nil
+	c.QuoteId = nil
 	return c
 }
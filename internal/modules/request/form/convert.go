@@ -5,6 +5,7 @@ type ConvertPreview struct {
 	AccountIdTo    *uint64 `json:"accountIdTo" binding:"required"`
 	OutgoingAmount *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
 	Note           *string `json:"note" binding:"omitempty,max=65535"`
+	RateQuote
 }
 
 type Convert struct {
@@ -13,4 +14,15 @@ type Convert struct {
 	OutgoingAmount *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
 	IncomingAmount *string `json:"incomingAmount" binding:"required,decimalGT=0"`
 	Note           *string `json:"note" binding:"omitempty,max=65535"`
+	QuoteId        *string `json:"quoteId"`
+}
+
+func (f *Convert) ToConvertPreview() *ConvertPreview {
+	return &ConvertPreview{
+		AccountIdFrom:  f.AccountIdFrom,
+		AccountIdTo:    f.AccountIdTo,
+		OutgoingAmount: f.OutgoingAmount,
+		Note:           f.Note,
+		RateQuote:      RateQuote{QuoteId: f.QuoteId},
+	}
 }
@@ -0,0 +1,11 @@
+package form
+
+// IncomingPaymentResolve assigns an account to the payment from the exceptions queue
+type IncomingPaymentResolve struct {
+	AccountId uint64 `json:"accountId" binding:"required"`
+}
+
+// IncomingPaymentDismiss removes the payment from the exceptions queue without crediting
+type IncomingPaymentDismiss struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
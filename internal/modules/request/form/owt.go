@@ -4,7 +4,9 @@ import (
 	"reflect"
 
 	bankDetailsModel "github.com/Confialink/wallet-accounts/internal/modules/bank-details/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-pkg-utils/pointer"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -13,6 +15,15 @@ type OWTPreview struct {
 	ReferenceCurrencyCode *string `form:"referenceCurrencyCode" json:"referenceCurrencyCode" binding:"required"`
 	OutgoingAmount        *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
 	FeeId                 *uint64 `json:"feeId"`
+	// CustomerAccIban is needed in order to issue signing challenge bound to the beneficiary
+	CustomerAccIban *string `json:"customerAccIban"`
+	ChargeBearer    *string `json:"chargeBearer" binding:"omitempty,oneof=OUR SHA BEN"`
+	RateQuote
+}
+
+// GetChargeBearer returns who pays fees, charges are shared if it is not specified
+func (o *OWTPreview) GetChargeBearer() constants.ChargeBearer {
+	return chargeBearer(o.ChargeBearer)
 }
 
 type OWT struct {
@@ -34,6 +45,8 @@ type OWT struct {
 	CustomerAccIban            *string `json:"customerAccIban" binding:"required"`
 	IsIntermediaryBankRequired *bool   `json:"isIntermediaryBankRequired"`
 	FeeId                      *uint64 `json:"feeId"`
+	QuoteId                    *string `json:"quoteId,omitempty"`
+	ChargeBearer               *string `json:"chargeBearer" binding:"omitempty,oneof=OUR SHA BEN"`
 
 	IntermediaryBankSwiftBic  *string `json:"intermediaryBankSwiftBic"`
 	IntermediaryBankName      *string `json:"intermediaryBankName"`
@@ -50,12 +63,28 @@ func (o *OWT) ToOWTPreview() *OWTPreview {
 		ReferenceCurrencyCode: o.ReferenceCurrencyCode,
 		OutgoingAmount:        o.OutgoingAmount,
 		FeeId:                 o.FeeId,
+		CustomerAccIban:       o.CustomerAccIban,
+		ChargeBearer:          o.ChargeBearer,
+		RateQuote:             RateQuote{QuoteId: o.QuoteId},
+	}
+}
+
+// GetChargeBearer returns who pays fees, charges are shared if it is not specified
+func (o *OWT) GetChargeBearer() constants.ChargeBearer {
+	return chargeBearer(o.ChargeBearer)
+}
+
+func chargeBearer(value *string) constants.ChargeBearer {
+	if value == nil || *value == "" {
+		return constants.DefaultChargeBearer
 	}
+	return constants.ChargeBearer(*value)
 }
 
 func (o OWT) TemplateData() interface{} {
 	o.BaseTemplate = nil
 	o.ConfirmTotalOutgoingAmount = nil
+	o.QuoteId = nil
 	return o
 }
 
@@ -65,6 +94,7 @@ func (o *OWT) NewDataOwt() *model.DataOwt {
 		DestinationCurrencyCode: o.ReferenceCurrencyCode,
 		RefMessage:              o.RefMessage,
 		FeeId:                   o.FeeId,
+		ChargeBearer:            pointer.ToString(o.GetChargeBearer().String()),
 	}
 
 	bankDetails := &bankDetailsModel.BankDetailsModel{
@@ -0,0 +1,13 @@
+package form
+
+import "github.com/Confialink/wallet-accounts/internal/modules/request/model"
+
+// RateQuote is embedded into preview forms of transfers which may involve currency exchange
+type RateQuote struct {
+	// QuoteId is id of the quote which rate must be used instead of the current one
+	QuoteId *string `json:"quoteId"`
+	// IssueQuote asks creator to lock the current rate if quote id is not passed
+	IssueQuote bool `json:"-"`
+	// Quote is set by creator to the quote which has been used during evaluation
+	Quote *model.RateQuote `json:"-"`
+}
@@ -5,6 +5,7 @@ type TBAPreview struct {
 	AccountIdTo    *uint64 `form:"accountIdTo" json:"accountIdTo" binding:"required"`
 	OutgoingAmount *string `json:"outgoingAmount" binding:"omitempty,decimalGT=0"`
 	IncomingAmount *string `json:"incomingAmount" binding:"omitempty"`
+	RateQuote
 }
 
 type TBA struct {
@@ -13,6 +14,7 @@ type TBA struct {
 	OutgoingAmount *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
 	Description    *string `json:"description" binding:"required,max=65535"`
 	IncomingAmount *string `json:"incomingAmount" binding:"required,decimalGT=0"`
+	QuoteId        *string `json:"quoteId"`
 }
 
 func (f *TBA) ToTBAPreview() *TBAPreview {
@@ -20,5 +22,6 @@ func (f *TBA) ToTBAPreview() *TBAPreview {
 		AccountIdTo:    f.AccountIdTo,
 		AccountIdFrom:  f.AccountIdFrom,
 		OutgoingAmount: f.OutgoingAmount,
+		RateQuote:      RateQuote{QuoteId: f.QuoteId},
 	}
 }
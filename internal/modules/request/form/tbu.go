@@ -4,6 +4,7 @@ type TBUPreview struct {
 	AccountIdFrom   *uint64 `form:"accountIdFrom" json:"accountIdFrom" binding:"required"`
 	AccountNumberTo *string `form:"accountNumberTo" json:"accountNumberTo" binding:"required"`
 	OutgoingAmount  *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
+	RateQuote
 }
 
 type TBUReceive struct {
@@ -17,6 +18,7 @@ type TBU struct {
 	OutgoingAmount  *string `json:"outgoingAmount" binding:"required,decimalGT=0"`
 	Description     *string `json:"description,omitempty" binding:"omitempty,max=65535"`
 	IncomingAmount  *string `json:"incomingAmount,omitempty" binding:"required,decimalGT=0"`
+	QuoteId         *string `json:"quoteId,omitempty"`
 }
 
 func (t *TBU) ToTBUPreview() *TBUPreview {
@@ -24,11 +26,13 @@ func (t *TBU) ToTBUPreview() *TBUPreview {
 		AccountNumberTo: t.AccountNumberTo,
 		AccountIdFrom:   t.AccountIdFrom,
 		OutgoingAmount:  t.OutgoingAmount,
+		RateQuote:       RateQuote{QuoteId: t.QuoteId},
 	}
 }
 
 func (t TBU) TemplateData() interface{} {
 	t.BaseTemplate = nil
 	t.IncomingAmount = nil
+	t.QuoteId = nil
 	return t
 }
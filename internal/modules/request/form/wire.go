@@ -0,0 +1,10 @@
+package form
+
+// WireExport selects pending outgoing wire transfers to be exported into a bank file
+type WireExport struct {
+	RequestIds []uint64 `json:"requestIds" binding:"required,min=1"`
+	// Format is either "mt103" or "pain001"
+	Format string `json:"format" binding:"required,oneof=mt103 pain001"`
+	// ExecutionDate is requested execution date in YYYY-MM-DD format, it is today if not passed
+	ExecutionDate *string `json:"executionDate" binding:"omitempty,datetime=2006-01-02"`
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	errorsPkg "github.com/Confialink/wallet-pkg-errors"
 	"github.com/gin-gonic/gin"
@@ -14,9 +15,11 @@ import (
 	accountRepository "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
 	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
 	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	cardModel "github.com/Confialink/wallet-accounts/internal/modules/card/model"
 	cardRepository "github.com/Confialink/wallet-accounts/internal/modules/card/repository"
 	"github.com/Confialink/wallet-accounts/internal/modules/request"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
 	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
 )
 
@@ -25,6 +28,7 @@ type CftHandler struct {
 	accountRepository *accountRepository.AccountRepository
 	cardRepository    cardRepository.CardRepositoryInterface
 	requestCreator    *request.Creator
+	signer            *tan.Signer
 	logger            log15.Logger
 	db                *gorm.DB
 }
@@ -34,6 +38,7 @@ func NewCftHandler(
 	accountRepository *accountRepository.AccountRepository,
 	cardRepository cardRepository.CardRepositoryInterface,
 	requestCreator *request.Creator,
+	signer *tan.Signer,
 	db *gorm.DB,
 	logger log15.Logger,
 ) *CftHandler {
@@ -42,6 +47,7 @@ func NewCftHandler(
 		accountRepository: accountRepository,
 		cardRepository:    cardRepository,
 		requestCreator:    requestCreator,
+		signer:            signer,
 		logger:            logger.New("Handler", "CftHandler"),
 		db:                db,
 	}
@@ -138,6 +144,7 @@ func (h *CftHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	cftForm.IssueQuote = true
 	details, err := h.requestCreator.EvaluateCFTRequest(cftForm, user)
 	if err != nil {
 		errorsPkg.AddErrors(c, errcodes.ConvertToTyped(err))
@@ -152,7 +159,19 @@ func (h *CftHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response.New().SetData(&preview{Details: details, IncomingAmount: detail.Amount.String()}))
+	signingTx := signingTransaction("cft", sourceAcc, details, cardDestination(card))
+	challenge, err := issueSigningChallenge(h.signer, user.UID, signingTx)
+	if err != nil {
+		errorsPkg.AddErrors(c, &errorsPkg.PrivateError{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(&preview{
+		Details:          details,
+		IncomingAmount:   detail.Amount.String(),
+		SigningChallenge: challenge,
+		Quote:            cftForm.Quote,
+	}))
 }
 
 func (h *CftHandler) CreateRequestUser(c *gin.Context) {
@@ -207,6 +226,11 @@ func (h *CftHandler) CreateRequestUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("cft", sourceAcc, details, cardDestination(card))
+	if !verifySignature(c, h.signer, user.UID, signingTx) {
+		return
+	}
+
 	tx := h.db.Begin()
 	req, err := h.requestCreator.CreateCFTRequest(cftForm, user, tx)
 	if err != nil {
@@ -285,3 +309,11 @@ func (h *CftHandler) CreateRequestAdmin(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.New().SetData(req))
 }
+
+// cardDestination identifies the card in signing challenge
+func cardDestination(card *cardModel.Card) string {
+	if card.Number != nil {
+		return *card.Number
+	}
+	return strconv.FormatUint(uint64(*card.Id), 10)
+}
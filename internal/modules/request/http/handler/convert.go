@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	accountRepository "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+)
+
+// ConvertHandler handles currency conversions between own accounts of the current user
+type ConvertHandler struct {
+	contextService    service.ContextInterface
+	accountRepository *accountRepository.AccountRepository
+	requestCreator    *request.Creator
+	db                *gorm.DB
+	signer            *tan.Signer
+}
+
+func NewConvertHandler(
+	contextService service.ContextInterface,
+	accountRepository *accountRepository.AccountRepository,
+	requestCreator *request.Creator,
+	db *gorm.DB,
+	signer *tan.Signer,
+) *ConvertHandler {
+	return &ConvertHandler{
+		contextService:    contextService,
+		accountRepository: accountRepository,
+		requestCreator:    requestCreator,
+		db:                db,
+		signer:            signer,
+	}
+}
+
+func (h *ConvertHandler) CreatePreviewUser(c *gin.Context) {
+	initiator := h.contextService.MustGetCurrentUser(c)
+	convertForm := &form.ConvertPreview{}
+
+	if err := c.ShouldBind(convertForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	sourceAcc, destinationAcc, ok := h.checkOwner(c, initiator.UID, *convertForm.AccountIdFrom, *convertForm.AccountIdTo)
+	if !ok {
+		return
+	}
+
+	convertForm.IssueQuote = true
+	details, err := h.requestCreator.EvaluateConvertRequest(convertForm, initiator)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	incomingDetail, ok := details[transactionConstants.PurposeConvertIncoming]
+	if !ok {
+		errors.AddErrors(c, &errors.PrivateError{Message: "transaction detail PurposeConvertIncoming is not set"})
+		return
+	}
+
+	signingTx := signingTransaction("convert", sourceAcc, details, destinationAcc.Number)
+	challenge, err := issueSigningChallenge(h.signer, initiator.UID, signingTx)
+	if err != nil {
+		errors.AddErrors(c, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(&preview{
+		Details:              details,
+		IncomingAmount:       incomingDetail.Amount.String(),
+		IncomingCurrencyCode: incomingDetail.CurrencyCode,
+		TotalOutgoingAmount:  details.SumByAccountId(*convertForm.AccountIdFrom).String(),
+		SigningChallenge:     challenge,
+		Quote:                convertForm.Quote,
+	}))
+}
+
+func (h *ConvertHandler) CreateRequestUser(c *gin.Context) {
+	initiator := h.contextService.MustGetCurrentUser(c)
+	convertForm := &form.Convert{}
+
+	if err := c.ShouldBind(convertForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	sourceAcc, destinationAcc, ok := h.checkOwner(c, initiator.UID, *convertForm.AccountIdFrom, *convertForm.AccountIdTo)
+	if !ok {
+		return
+	}
+
+	details, err := h.requestCreator.EvaluateConvertRequest(convertForm.ToConvertPreview(), initiator)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	detail, ok := details[transactionConstants.PurposeConvertIncoming]
+	if !ok {
+		errors.AddErrors(c, &errors.PrivateError{Message: "transaction detail PurposeConvertIncoming is not set"})
+		return
+	}
+
+	formIncomingAmount, _ := decimal.NewFromString(*convertForm.IncomingAmount)
+	if !detail.Amount.Equal(formIncomingAmount) {
+		errcodes.AddError(c, errcodes.CodeRatesDoNotMatch)
+		return
+	}
+
+	signingTx := signingTransaction("convert", sourceAcc, details, destinationAcc.Number)
+	if !verifySignature(c, h.signer, initiator.UID, signingTx) {
+		return
+	}
+
+	tx := h.db.Begin()
+	req, err := h.requestCreator.CreateConvertRequest(convertForm, initiator, tx)
+	if err != nil {
+		tx.Rollback()
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+	tx.Commit()
+
+	c.JSON(http.StatusOK, response.New().SetData(req))
+}
+
+// checkOwner checks that both accounts belong to the given user, the source and destination accounts are returned
+func (h *ConvertHandler) checkOwner(
+	c *gin.Context,
+	userId string,
+	accountIdFrom, accountIdTo uint64,
+) (*model.Account, *model.Account, bool) {
+	accounts := make([]*model.Account, 0, 2)
+	for _, id := range []uint64{accountIdFrom, accountIdTo} {
+		account, err := h.accountRepository.FindByID(id)
+		if err != nil {
+			log.Printf("convertHandler unable to find account %d: %s", id, err.Error())
+			errcodes.AddError(c, errcodes.CodeAccountNotFound)
+			return nil, nil, false
+		}
+		if account.UserId != userId {
+			errcodes.AddError(c, errcodes.CodeInvalidAccountOwner)
+			return nil, nil, false
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts[0], accounts[1], true
+}
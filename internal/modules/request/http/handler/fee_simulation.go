@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	feeForm "github.com/Confialink/wallet-accounts/internal/modules/fee/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+)
+
+// FeeSimulationHandler shows impact of a draft transfer fee configuration on executed requests
+type FeeSimulationHandler struct {
+	simulator *request.FeeSimulator
+}
+
+func NewFeeSimulationHandler(simulator *request.FeeSimulator) *FeeSimulationHandler {
+	return &FeeSimulationHandler{simulator: simulator}
+}
+
+func (h *FeeSimulationHandler) Simulate(c *gin.Context) {
+	simulationForm := &feeForm.TransferFeeSimulation{}
+	if err := c.ShouldBind(simulationForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	result, err := h.simulator.Simulate(simulationForm)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+	c.JSON(http.StatusOK, response.New().SetData(result))
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+)
+
+type IncomingPaymentHandler struct {
+	contextService service.ContextInterface
+	paymentService *request.IncomingPaymentService
+	logger         log15.Logger
+}
+
+func NewIncomingPaymentHandler(
+	contextService service.ContextInterface,
+	paymentService *request.IncomingPaymentService,
+	logger log15.Logger,
+) *IncomingPaymentHandler {
+	return &IncomingPaymentHandler{
+		contextService: contextService,
+		paymentService: paymentService,
+		logger:         logger.New("Handler", "request.IncomingPaymentHandler"),
+	}
+}
+
+// Import credits accounts with incoming payments of uploaded camt.053 statement or camt.054 notification
+func (h *IncomingPaymentHandler) Import(c *gin.Context) {
+	logger := h.logger.New("action", "Import")
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		logger.Error("can't get file", "err", err)
+		errcodes.AddError(c, errcodes.CodeFileInvalid)
+		return
+	}
+	defer file.Close()
+
+	user := h.contextService.MustGetCurrentUser(c)
+	result, err := h.paymentService.Import(file, header.Filename, user)
+	if err != nil {
+		logger.Error("can't import camt file", "err", err)
+		errcodes.AddError(c, errcodes.CodeFileInvalid)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(result))
+}
+
+// Exceptions lists incoming payments which have not been matched to accounts
+func (h *IncomingPaymentHandler) Exceptions(c *gin.Context) {
+	payments, err := h.paymentService.Exceptions()
+	if err != nil {
+		privateError := errors.PrivateError{Message: "can't retrieve incoming payments"}
+		privateError.AddLogPair("error", err.Error())
+		errors.AddErrors(c, &privateError)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(payments))
+}
+
+// Resolve credits the selected account with the unmatched incoming payment
+func (h *IncomingPaymentHandler) Resolve(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	resolveForm := &form.IncomingPaymentResolve{}
+	if err := c.ShouldBind(resolveForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	user := h.contextService.MustGetCurrentUser(c)
+	payment, err := h.paymentService.Resolve(id, resolveForm, user)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(payment))
+}
+
+// Dismiss removes the unmatched incoming payment from the exceptions queue
+func (h *IncomingPaymentHandler) Dismiss(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	dismissForm := &form.IncomingPaymentDismiss{}
+	if err := c.ShouldBind(dismissForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	payment, err := h.paymentService.Dismiss(id, dismissForm)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(payment))
+}
@@ -18,6 +18,7 @@ import (
 	moneyRequestService "github.com/Confialink/wallet-accounts/internal/modules/moneyrequest/service"
 	"github.com/Confialink/wallet-accounts/internal/modules/request"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
 	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
 	userService "github.com/Confialink/wallet-accounts/internal/modules/user/service"
 )
@@ -30,6 +31,7 @@ type MoneyRequestTbuHandler struct {
 	currencyService     currencyService.CurrenciesServiceInterface
 	moneyRequestService *moneyRequestService.MoneyRequest
 	db                  *gorm.DB
+	signer              *tan.Signer
 	logger              log15.Logger
 }
 
@@ -41,6 +43,7 @@ func NewMoneyRequestTbuHandler(
 	currencyService currencyService.CurrenciesServiceInterface,
 	moneyRequestService *moneyRequestService.MoneyRequest,
 	db *gorm.DB,
+	signer *tan.Signer,
 	logger log15.Logger,
 
 ) *MoneyRequestTbuHandler {
@@ -52,6 +55,7 @@ func NewMoneyRequestTbuHandler(
 		currencyService:     currencyService,
 		moneyRequestService: moneyRequestService,
 		db:                  db,
+		signer:              signer,
 		logger:              logger.New("Handler", "TbuHandler"),
 	}
 }
@@ -114,6 +118,13 @@ func (t *MoneyRequestTbuHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("tbu", sourceAcc, details, destinationAcc.Number)
+	challenge, err := issueSigningChallenge(t.signer, initiator.UID, signingTx)
+	if err != nil {
+		errorsPkg.AddErrors(c, &errorsPkg.PrivateError{Message: err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, response.New().SetData(&preview{
 		Recipient: &recipient{
 			PhoneNumber: destinationUser.PhoneNumber,
@@ -123,6 +134,7 @@ func (t *MoneyRequestTbuHandler) CreatePreviewUser(c *gin.Context) {
 		Details:              details,
 		IncomingAmount:       detail.Amount.String(),
 		IncomingCurrencyCode: detail.CurrencyCode,
+		SigningChallenge:     challenge,
 	}))
 }
 
@@ -184,6 +196,11 @@ func (t *MoneyRequestTbuHandler) CreateRequestUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("tbu", sourceAcc, details, destinationAcc.Number)
+	if !verifySignature(c, t.signer, initiator.UID, signingTx) {
+		return
+	}
+
 	tx := t.db.Begin()
 	req, err := t.requestCreator.CreateTBURequest(&tbuForm, initiator, tx)
 	if err != nil {
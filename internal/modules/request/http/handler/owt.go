@@ -15,12 +15,16 @@ import (
 	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
 	"github.com/Confialink/wallet-accounts/internal/modules/request"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
 )
 
 type OwtHandler struct {
 	contextService    service.ContextInterface
 	accountRepository *accountRepository.AccountRepository
 	requestCreator    *request.Creator
+	signer            *tan.Signer
 	logger            log15.Logger
 	db                *gorm.DB
 }
@@ -29,6 +33,7 @@ func NewOwtHandler(
 	contextService service.ContextInterface,
 	accountRepository *accountRepository.AccountRepository,
 	requestCreator *request.Creator,
+	signer *tan.Signer,
 	db *gorm.DB,
 	logger log15.Logger,
 
@@ -37,6 +42,7 @@ func NewOwtHandler(
 		contextService:    contextService,
 		accountRepository: accountRepository,
 		requestCreator:    requestCreator,
+		signer:            signer,
 		logger:            logger.New("Handler", "OwtHandler"),
 		db:                db,
 	}
@@ -72,7 +78,12 @@ func (t *OwtHandler) CreatePreviewAdmin(c *gin.Context) {
 	}
 
 	totalOutgoingAmount := details.SumByAccountId(sourceAcc.ID)
-	c.JSON(http.StatusOK, response.New().SetData(&preview{Details: details, TotalOutgoingAmount: totalOutgoingAmount.String()}))
+	c.JSON(http.StatusOK, response.New().SetData(&preview{
+		Details:             details,
+		TotalOutgoingAmount: totalOutgoingAmount.String(),
+		SentAmount:          owtSentAmount(details),
+		ChargeBearer:        owtForm.GetChargeBearer().String(),
+	}))
 }
 
 func (t *OwtHandler) CreatePreviewUser(c *gin.Context) {
@@ -97,14 +108,32 @@ func (t *OwtHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	owtForm.IssueQuote = true
 	details, err := t.requestCreator.EvaluateOWTRequest(owtForm, user)
 	if err != nil {
 		errors.AddErrors(c, errcodes.ConvertToTyped(err))
 		return
 	}
 
+	var challenge *signingChallenge
+	if owtForm.CustomerAccIban != nil {
+		signingTx := signingTransaction("owt", sourceAcc, details, *owtForm.CustomerAccIban)
+		challenge, err = issueSigningChallenge(t.signer, user.UID, signingTx)
+		if err != nil {
+			errors.AddErrors(c, &errors.PrivateError{Message: err.Error()})
+			return
+		}
+	}
+
 	totalOutgoingAmount := details.SumByAccountId(sourceAcc.ID)
-	c.JSON(http.StatusOK, response.New().SetData(&preview{Details: details, TotalOutgoingAmount: totalOutgoingAmount.String()}))
+	c.JSON(http.StatusOK, response.New().SetData(&preview{
+		Details:             details,
+		TotalOutgoingAmount: totalOutgoingAmount.String(),
+		SentAmount:          owtSentAmount(details),
+		ChargeBearer:        owtForm.GetChargeBearer().String(),
+		SigningChallenge:    challenge,
+		Quote:               owtForm.Quote,
+	}))
 }
 
 func (t *OwtHandler) CreateRequestUser(c *gin.Context) {
@@ -148,6 +177,11 @@ func (t *OwtHandler) CreateRequestUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("owt", sourceAcc, details, *owtForm.CustomerAccIban)
+	if !verifySignature(c, t.signer, user.UID, signingTx) {
+		return
+	}
+
 	tx := t.db.Begin()
 	req, err := t.requestCreator.CreateOWTRequest(owtForm, user, tx)
 	if err != nil {
@@ -214,3 +248,13 @@ func (t *OwtHandler) CreateRequestAdmin(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.New().SetData(req))
 }
+
+// owtSentAmount returns amount of the outgoing wire which could be less than the total outgoing amount
+// if fees are deducted from it
+func owtSentAmount(details types.Details) string {
+	detail, ok := details[transactionConstants.PurposeOWTOutgoing]
+	if !ok {
+		return ""
+	}
+	return detail.Amount.Abs().String()
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	errorsPkg "github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	pkgErrors "github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
+)
+
+const (
+	headerSigningChallenge = "X-Signing-Challenge"
+	headerSigningCode      = "X-Signing-Code"
+)
+
+type signingChallenge struct {
+	Id        int64     `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// signingTransaction describes the total debit of the source account, so fees are covered by the signature too
+func signingTransaction(
+	subject string,
+	sourceAcc *model.Account,
+	details types.Details,
+	destination string,
+) *tan.Transaction {
+	currencyCode, _ := sourceAcc.GetCurrencyCode()
+	return &tan.Transaction{
+		Subject:     subject,
+		Amount:      details.SumByAccountId(sourceAcc.ID).Abs(),
+		Currency:    currencyCode,
+		Destination: destination,
+	}
+}
+
+// issueSigningChallenge issues challenge for the transaction if transfers must be signed, nil is returned otherwise
+func issueSigningChallenge(signer *tan.Signer, userId string, tx *tan.Transaction) (*signingChallenge, error) {
+	isRequired, err := signer.IsRequired()
+	if err != nil || !isRequired {
+		return nil, err
+	}
+	challenge, err := signer.Challenge(userId, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &signingChallenge{Id: challenge.ID, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// verifySignature checks signing code passed in headers if transfers must be signed.
+// It adds an error to the context and returns false if the code is missing or does not match the transaction.
+func verifySignature(c *gin.Context, signer *tan.Signer, userId string, tx *tan.Transaction) bool {
+	isRequired, err := signer.IsRequired()
+	if err != nil {
+		errorsPkg.AddErrors(c, &errorsPkg.PrivateError{Message: err.Error()})
+		return false
+	}
+	if !isRequired {
+		return true
+	}
+
+	code := c.GetHeader(headerSigningCode)
+	challengeId, err := strconv.ParseInt(c.GetHeader(headerSigningChallenge), 10, 64)
+	if err != nil || code == "" {
+		errcodes.AddError(c, errcodes.CodeTransferSignatureRequired)
+		return false
+	}
+
+	if err := signer.Verify(userId, challengeId, code, tx); err != nil {
+		if pkgErrors.Cause(err) == tan.ErrSignatureInvalid {
+			errcodes.AddError(c, errcodes.CodeTransferSignatureInvalid)
+			return false
+		}
+		errorsPkg.AddErrors(c, &errorsPkg.PrivateError{Message: err.Error()})
+		return false
+	}
+	return true
+}
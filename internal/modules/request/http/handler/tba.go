@@ -15,6 +15,7 @@ import (
 	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
 	"github.com/Confialink/wallet-accounts/internal/modules/request"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
 	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
 )
 
@@ -22,6 +23,7 @@ type TbaHandler struct {
 	contextService    service.ContextInterface
 	accountRepository *accountRepository.AccountRepository
 	requestCreator    *request.Creator
+	signer            *tan.Signer
 	db                *gorm.DB
 }
 
@@ -29,6 +31,7 @@ func NewTbaHandler(
 	contextService service.ContextInterface,
 	accountRepository *accountRepository.AccountRepository,
 	requestCreator *request.Creator,
+	signer *tan.Signer,
 	db *gorm.DB,
 
 ) *TbaHandler {
@@ -36,6 +39,7 @@ func NewTbaHandler(
 		contextService:    contextService,
 		accountRepository: accountRepository,
 		requestCreator:    requestCreator,
+		signer:            signer,
 		db:                db,
 	}
 }
@@ -112,6 +116,7 @@ func (t *TbaHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	tbaForm.IssueQuote = true
 	details, err := t.requestCreator.EvaluateTBARequest(tbaForm, initiator)
 	if err != nil {
 		errors.AddErrors(c, errcodes.ConvertToTyped(err))
@@ -129,9 +134,18 @@ func (t *TbaHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("tba", sourceAcc, details, destinationAcc.Number)
+	challenge, err := issueSigningChallenge(t.signer, initiator.UID, signingTx)
+	if err != nil {
+		errors.AddErrors(c, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, response.New().SetData(&preview{
 		Details: details, IncomingAmount: incomingDetail.Amount.String(),
 		TotalOutgoingAmount: details.SumByAccountId(*tbaForm.AccountIdFrom).String(),
+		SigningChallenge:    challenge,
+		Quote:               tbaForm.Quote,
 	}))
 }
 
@@ -181,6 +195,11 @@ func (t *TbaHandler) CreateRequestUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("tba", sourceAcc, details, destinationAcc.Number)
+	if !verifySignature(c, t.signer, initiator.UID, signingTx) {
+		return
+	}
+
 	tx := t.db.Begin()
 	req, err := t.requestCreator.CreateTBARequest(tbaForm, initiator, tx)
 	if err != nil {
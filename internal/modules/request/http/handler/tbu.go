@@ -18,6 +18,7 @@ import (
 	currencyService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
 	"github.com/Confialink/wallet-accounts/internal/modules/request"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
 	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
 	userService "github.com/Confialink/wallet-accounts/internal/modules/user/service"
 )
@@ -28,6 +29,7 @@ type TbuHandler struct {
 	requestCreator    *request.Creator
 	userService       *userService.UserService
 	currencyService   currencyService.CurrenciesServiceInterface
+	signer            *tan.Signer
 	db                *gorm.DB
 	logger            log15.Logger
 }
@@ -38,6 +40,7 @@ func NewTbuHandler(
 	requestCreator *request.Creator,
 	userService *userService.UserService,
 	currencyService currencyService.CurrenciesServiceInterface,
+	signer *tan.Signer,
 	db *gorm.DB,
 	logger log15.Logger,
 
@@ -48,6 +51,7 @@ func NewTbuHandler(
 		requestCreator:    requestCreator,
 		userService:       userService,
 		currencyService:   currencyService,
+		signer:            signer,
 		db:                db,
 		logger:            logger.New("Handler", "TbuHandler"),
 	}
@@ -126,6 +130,7 @@ func (t *TbuHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	tbuForm.IssueQuote = true
 	details, err := t.requestCreator.EvaluateTBURequest(tbuForm, initiator)
 	if err != nil {
 		errorsPkg.AddErrors(c, errcodes.ConvertToTyped(err))
@@ -139,10 +144,19 @@ func (t *TbuHandler) CreatePreviewUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("tbu", sourceAcc, details, destinationAcc.Number)
+	challenge, err := issueSigningChallenge(t.signer, initiator.UID, signingTx)
+	if err != nil {
+		errorsPkg.AddErrors(c, &errorsPkg.PrivateError{Message: err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, response.New().SetData(&preview{
 		Details:              details,
 		IncomingAmount:       detail.Amount.String(),
 		IncomingCurrencyCode: detail.CurrencyCode,
+		SigningChallenge:     challenge,
+		Quote:                tbuForm.Quote,
 	}))
 }
 
@@ -191,6 +205,11 @@ func (t *TbuHandler) CreateRequestUser(c *gin.Context) {
 		return
 	}
 
+	signingTx := signingTransaction("tbu", sourceAcc, details, destinationAcc.Number)
+	if !verifySignature(c, t.signer, initiator.UID, signingTx) {
+		return
+	}
+
 	tx := t.db.Begin()
 	req, err := t.requestCreator.CreateTBURequest(tbuForm, initiator, tx)
 	if err != nil {
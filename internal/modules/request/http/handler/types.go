@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	"github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
 	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
 )
@@ -18,8 +19,12 @@ type preview struct {
 	IncomingAmountInFiat string        `json:"incomingAmountInFiat,omitempty"`
 	IncomingCurrencyCode string        `json:"incomingCurrencyCode,omitempty"`
 	TotalOutgoingAmount  string        `json:"totalOutgoingAmount,omitempty"`
+	SentAmount           string        `json:"sentAmount,omitempty"`
+	ChargeBearer         string        `json:"chargeBearer,omitempty"`
 	Details              types.Details `json:"details"`
 	Recipient            *recipient
+	SigningChallenge     *signingChallenge
+	Quote                *model.RateQuote
 }
 
 func (p *preview) MarshalJSON() ([]byte, error) {
@@ -43,6 +48,12 @@ func (p *preview) MarshalJSON() ([]byte, error) {
 	if p.TotalOutgoingAmount != "" {
 		obj["totalOutgoingAmount"] = p.TotalOutgoingAmount
 	}
+	if p.SentAmount != "" {
+		obj["sentAmount"] = p.SentAmount
+	}
+	if p.ChargeBearer != "" {
+		obj["chargeBearer"] = p.ChargeBearer
+	}
 	if p.IncomingAmount != "" {
 		obj["incomingAmount"] = p.IncomingAmount
 	}
@@ -52,5 +63,11 @@ func (p *preview) MarshalJSON() ([]byte, error) {
 	if p.IncomingCurrencyCode != "" {
 		obj["incomingCurrencyCode"] = p.IncomingCurrencyCode
 	}
+	if p.Quote != nil {
+		obj["quote"] = p.Quote
+	}
+	if p.SigningChallenge != nil {
+		obj["signingChallenge"] = p.SigningChallenge
+	}
 	return json.Marshal(obj)
 }
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/service/wire"
+)
+
+type WireHandler struct {
+	contextService service.ContextInterface
+	wireService    *request.WireService
+	logger         log15.Logger
+}
+
+func NewWireHandler(
+	contextService service.ContextInterface,
+	wireService *request.WireService,
+	logger log15.Logger,
+) *WireHandler {
+	return &WireHandler{
+		contextService: contextService,
+		wireService:    wireService,
+		logger:         logger.New("Handler", "request.WireHandler"),
+	}
+}
+
+// Export downloads MT103 or pain.001 file of the selected pending outgoing wire transfers
+func (h *WireHandler) Export(c *gin.Context) {
+	exportForm := &form.WireExport{}
+	if err := c.ShouldBind(exportForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	file, err := h.wireService.Export(exportForm)
+	if validationErrs, ok := err.(wire.ValidationErrors); ok {
+		errcodes.AddErrorMeta(c, errcodes.CodeWireExportInvalid, validationErrs)
+		return
+	}
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	contentType := "text/plain"
+	if exportForm.Format == wire.FormatPain001 {
+		contentType = "application/xml"
+	}
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.Writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", file.Name))
+	c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+	_, _ = c.Writer.Write(file.Content)
+}
+
+// ImportStatuses executes or cancels exported requests according to uploaded pain.002 or CSV status report
+// CSV format: request id, status (executed or cancelled), reason
+func (h *WireHandler) ImportStatuses(c *gin.Context) {
+	logger := h.logger.New("action", "ImportStatuses")
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		logger.Error("can't get file", "err", err)
+		errcodes.AddError(c, errcodes.CodeFileInvalid)
+		return
+	}
+	defer file.Close()
+
+	user := h.contextService.MustGetCurrentUser(c)
+	result, err := h.wireService.ImportStatuses(file, header.Filename, user)
+	if err != nil {
+		logger.Error("can't parse status report", "err", err)
+		errcodes.AddError(c, errcodes.CodeFileInvalid)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(result))
+}
@@ -0,0 +1,309 @@
+package request
+
+import (
+	"io"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	errorsPkg "github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	accRepo "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/service/camt"
+)
+
+// IncomingPaymentsImport is a result of camt file import
+type IncomingPaymentsImport struct {
+	Credited  uint64 `json:"credited"`
+	Unmatched uint64 `json:"unmatched"`
+	// Duplicates is a count of entries which have been already imported from another file
+	Duplicates uint64 `json:"duplicates"`
+}
+
+// IncomingPaymentService credits accounts with incoming wire transfers reported by the bank in camt files,
+// payments which could not be matched to an account are put into the exceptions queue
+type IncomingPaymentService struct {
+	db                *gorm.DB
+	paymentRepository *repository.IncomingPayment
+	accountRepository *accRepo.AccountRepository
+	creator           *Creator
+	logger            log15.Logger
+}
+
+func NewIncomingPaymentService(
+	db *gorm.DB,
+	paymentRepository *repository.IncomingPayment,
+	accountRepository *accRepo.AccountRepository,
+	creator *Creator,
+	logger log15.Logger,
+) *IncomingPaymentService {
+	return &IncomingPaymentService{
+		db:                db,
+		paymentRepository: paymentRepository,
+		accountRepository: accountRepository,
+		creator:           creator,
+		logger:            logger.New("service", "IncomingPaymentService"),
+	}
+}
+
+// Import reads camt.053 or camt.054 file and creates CA requests with IWT fee for matched entries
+func (s *IncomingPaymentService) Import(r io.Reader, fileName string, user *users.User) (*IncomingPaymentsImport, error) {
+	entries, err := camt.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IncomingPaymentsImport{}
+	for _, entry := range entries {
+		payment, err := s.importEntry(entry, fileName, user)
+		if err != nil {
+			s.logger.Error("failed to import incoming payment", "error", err, "reference", entry.Reference)
+			return result, err
+		}
+		switch {
+		case payment == nil:
+			result.Duplicates++
+		case payment.Status == model.IncomingPaymentStatusCredited:
+			result.Credited++
+		default:
+			result.Unmatched++
+		}
+	}
+	return result, nil
+}
+
+// importEntry stores the entry and credits the matched account, nil is returned if the entry is a duplicate.
+// The reference is checked and saved within the transaction which credits the account, the unique index
+// of the reference makes concurrent imports of the same entry wait for each other so that it is credited once.
+func (s *IncomingPaymentService) importEntry(
+	entry *camt.Entry,
+	fileName string,
+	user *users.User,
+) (*model.IncomingPayment, error) {
+	payment := &model.IncomingPayment{
+		Reference:       entry.Reference,
+		FileName:        fileName,
+		Amount:          entry.Amount,
+		CurrencyCode:    entry.CurrencyCode,
+		BookingDate:     entry.BookingDate,
+		DebtorName:      entry.DebtorName,
+		DebtorAccount:   entry.DebtorAccount,
+		CreditorAccount: entry.CreditorAccount,
+		RemittanceInfo:  entry.RemittanceInfo,
+	}
+
+	account, reason, err := s.matchAccount(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.db.Begin()
+	exists, err := s.paymentRepository.WrapContext(tx).ExistsByReference(entry.Reference)
+	if err != nil {
+		tx.Rollback()
+		return nil, errorsPkg.Wrap(err, "failed to check incoming payment reference")
+	}
+	if exists {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	if account != nil {
+		reason, err = s.credit(tx, payment, account, user)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if reason != "" {
+			// changes made by the failed crediting are discarded, the payment is put into the exceptions queue
+			tx.Rollback()
+			tx = s.db.Begin()
+		}
+	}
+	if reason != "" {
+		payment.Status = model.IncomingPaymentStatusUnmatched
+		payment.Reason = &reason
+	}
+
+	created, err := s.paymentRepository.WrapContext(tx).CreateUnlessExists(payment)
+	if err != nil {
+		tx.Rollback()
+		return nil, errorsPkg.Wrap(err, "failed to save incoming payment")
+	}
+	if !created {
+		tx.Rollback()
+		return nil, nil
+	}
+	return payment, tx.Commit().Error
+}
+
+// matchAccount looks for the account by the creditor account number at first
+// and then by the IWT reference which is the account number passed in the remittance information,
+// reason is returned if the account is not found
+func (s *IncomingPaymentService) matchAccount(entry *camt.Entry) (*accountModel.Account, string, error) {
+	if entry.CreditorAccount != "" {
+		accounts, err := s.accountRepository.FindByNumbers([]string{entry.CreditorAccount})
+		if err != nil {
+			return nil, "", errorsPkg.Wrap(err, "failed to find account by creditor account")
+		}
+		accounts = notClosed(accounts)
+		if len(accounts) == 1 {
+			return accounts[0], "", nil
+		}
+	}
+
+	accounts, err := s.accountRepository.FindByNumbers(entry.Tokens())
+	if err != nil {
+		return nil, "", errorsPkg.Wrap(err, "failed to find account by reference")
+	}
+	accounts = notClosed(accounts)
+	switch len(accounts) {
+	case 0:
+		return nil, "No account matches the payment reference", nil
+	case 1:
+		return accounts[0], "", nil
+	}
+	return nil, "The payment reference matches several accounts", nil
+}
+
+// notClosed skips closed accounts, payments to them are left unmatched
+func notClosed(accounts []*accountModel.Account) []*accountModel.Account {
+	result := make([]*accountModel.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if !account.IsClosed() {
+			result = append(result, account)
+		}
+	}
+	return result
+}
+
+// credit creates CA request with IWT fee for the given account,
+// reason is returned if the account could not be credited with the payment
+func (s *IncomingPaymentService) credit(
+	tx *gorm.DB,
+	payment *model.IncomingPayment,
+	account *accountModel.Account,
+	user *users.User,
+) (string, error) {
+	if account.Type == nil || account.Type.CurrencyCode != payment.CurrencyCode {
+		return "Currency of the payment does not match currency of account " + account.Number, nil
+	}
+	if !payment.Amount.IsPositive() {
+		return "Amount of the payment must be positive", nil
+	}
+
+	applyIwtFee := true
+	debitFromRevenue := false
+	caForm := &form.CA{
+		AccountId:               account.ID,
+		Amount:                  payment.Amount.String(),
+		Description:             incomingPaymentDescription(payment),
+		DebitFromRevenueAccount: &debitFromRevenue,
+		ApplyIwtFee:             &applyIwtFee,
+	}
+	request, err := s.creator.CreateCARequest(caForm, user, tx)
+	if err != nil {
+		if pubErr, ok := err.(*errors.PublicError); ok {
+			return "Account could not be credited: " + pubErr.Title, nil
+		}
+		return "", errorsPkg.Wrap(err, "failed to create CA request")
+	}
+
+	payment.Status = model.IncomingPaymentStatusCredited
+	payment.Reason = nil
+	payment.AccountId = &account.ID
+	payment.RequestId = request.Id
+	return "", nil
+}
+
+// Resolve credits the given account with the payment from the exceptions queue
+func (s *IncomingPaymentService) Resolve(id uint64, resolveForm *form.IncomingPaymentResolve, user *users.User) (*model.IncomingPayment, error) {
+	tx := s.db.Begin()
+	payment, err := s.findUnmatchedForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	account, err := s.accountRepository.WrapContext(tx).FindByID(resolveForm.AccountId)
+	if err != nil {
+		tx.Rollback()
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errcodes.CreatePublicError(errcodes.CodeAccountNotFound)
+		}
+		return nil, errorsPkg.Wrap(err, "failed to find account")
+	}
+	if account.Type == nil || account.Type.CurrencyCode != payment.CurrencyCode {
+		tx.Rollback()
+		return nil, errcodes.CreatePublicError(errcodes.CodeCurrencyMismatch)
+	}
+
+	reason, err := s.credit(tx, payment, account, user)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if reason != "" {
+		tx.Rollback()
+		return nil, errcodes.CreatePublicError(errcodes.CodeIncomingPaymentNotCredited, reason)
+	}
+	if err := s.paymentRepository.WrapContext(tx).Update(payment); err != nil {
+		tx.Rollback()
+		return nil, errorsPkg.Wrap(err, "failed to update incoming payment")
+	}
+	return payment, tx.Commit().Error
+}
+
+// Dismiss removes the payment from the exceptions queue without crediting, e.g. if it has been returned to the sender
+func (s *IncomingPaymentService) Dismiss(id uint64, dismissForm *form.IncomingPaymentDismiss) (*model.IncomingPayment, error) {
+	tx := s.db.Begin()
+	payment, err := s.findUnmatchedForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	payment.Status = model.IncomingPaymentStatusDismissed
+	payment.Reason = &dismissForm.Reason
+	if err := s.paymentRepository.WrapContext(tx).Update(payment); err != nil {
+		tx.Rollback()
+		return nil, errorsPkg.Wrap(err, "failed to update incoming payment")
+	}
+	return payment, tx.Commit().Error
+}
+
+// Exceptions returns payments waiting in the exceptions queue
+func (s *IncomingPaymentService) Exceptions() ([]*model.IncomingPayment, error) {
+	return s.paymentRepository.FindByStatus(model.IncomingPaymentStatusUnmatched)
+}
+
+func (s *IncomingPaymentService) findUnmatchedForUpdate(tx *gorm.DB, id uint64) (*model.IncomingPayment, error) {
+	payment, err := s.paymentRepository.WrapContext(tx).FindByIdForUpdate(id)
+	if err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to find incoming payment")
+	}
+	if payment == nil {
+		return nil, errcodes.CreatePublicError(errcodes.CodeIncomingPaymentNotFound)
+	}
+	if payment.Status != model.IncomingPaymentStatusUnmatched {
+		return nil, errcodes.CreatePublicError(errcodes.CodeIncomingPaymentProcessed)
+	}
+	return payment, nil
+}
+
+func incomingPaymentDescription(payment *model.IncomingPayment) string {
+	description := "Incoming wire transfer"
+	if payment.DebtorName != "" {
+		description += " from " + payment.DebtorName
+	}
+	if payment.RemittanceInfo != "" {
+		description += ": " + payment.RemittanceInfo
+	}
+	return description
+}
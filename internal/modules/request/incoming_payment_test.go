@@ -0,0 +1,113 @@
+package request_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	accountRepository "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/service/camt"
+)
+
+var _ = Describe("IncomingPaymentService", func() {
+	var (
+		mock    sqlmock.Sqlmock
+		service *IncomingPaymentService
+	)
+	closedAt := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err := gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+		service = NewIncomingPaymentService(
+			gdb, repository.NewIncomingPayment(gdb), accountRepository.NewAccountRepository(gdb, nil), nil, log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectAccounts := func(rows *sqlmock.Rows) {
+		mock.ExpectQuery("SELECT \\* FROM `accounts` WHERE \\(number IN").WillReturnRows(rows)
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(3, "EUR"))
+	}
+	accountRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "number", "type_id", "closed_at"})
+	}
+	expectNoAccounts := func() {
+		mock.ExpectQuery("SELECT \\* FROM `accounts` WHERE \\(number IN").WillReturnRows(accountRows())
+	}
+
+	It("should skip closed creditor account and match by the reference", func() {
+		expectAccounts(accountRows().AddRow(1, "4000001", 3, closedAt))
+		expectAccounts(accountRows().AddRow(1, "4000001", 3, closedAt).AddRow(2, "4000002", 3, nil))
+
+		entry := &camt.Entry{CreditorAccount: "4000001", RemittanceInfo: "4000001 4000002"}
+		account, reason, err := MatchIncomingPaymentAccount(service, entry)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+		Expect(account.ID).To(Equal(uint64(2)))
+	})
+
+	It("should not match closed account", func() {
+		expectAccounts(accountRows().AddRow(1, "4000001", 3, closedAt))
+
+		entry := &camt.Entry{RemittanceInfo: "invoice 4000001"}
+		account, reason, err := MatchIncomingPaymentAccount(service, entry)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(account).To(BeNil())
+		Expect(reason).To(Equal("No account matches the payment reference"))
+	})
+
+	It("should skip the entry which has been already imported", func() {
+		expectNoAccounts()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `incoming_payments` WHERE \\(reference = \\?\\)").
+			WithArgs("BANKREF1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		payment, err := ImportIncomingPaymentEntry(service, &camt.Entry{Reference: "BANKREF1", RemittanceInfo: "invoice"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(payment).To(BeNil())
+	})
+
+	It("should skip the entry saved by the concurrent import", func() {
+		expectNoAccounts()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `incoming_payments`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec("INSERT IGNORE INTO `incoming_payments`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		payment, err := ImportIncomingPaymentEntry(service, &camt.Entry{Reference: "BANKREF1", RemittanceInfo: "invoice"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(payment).To(BeNil())
+	})
+
+	It("should put unmatched entry into the exceptions queue", func() {
+		expectNoAccounts()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `incoming_payments`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec("INSERT IGNORE INTO `incoming_payments`").WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectCommit()
+
+		payment, err := ImportIncomingPaymentEntry(service, &camt.Entry{Reference: "BANKREF1", RemittanceInfo: "invoice"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(payment.Status).To(Equal(model.IncomingPaymentStatusUnmatched))
+		Expect(*payment.Reason).To(Equal("No account matches the payment reference"))
+	})
+})
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
 	bankDetailsModel "github.com/Confialink/wallet-accounts/internal/modules/bank-details/model"
 	transferFeeModel "github.com/Confialink/wallet-accounts/internal/modules/fee/model"
@@ -16,6 +18,9 @@ type DataOwt struct {
 	BeneficiaryCustomerId     *uint64                                    `json:"_"`
 	IntermediaryBankDetailsId *uint64                                    `json:"_"`
 	RefMessage                *string                                    `json:"refMessage"`
+	ChargeBearer              *string                                    `json:"chargeBearer"`
+	ExportedAt                *time.Time                                 `json:"exportedAt"`
+	ExportReference           *string                                    `json:"exportReference"`
 	BankDetails               *bankDetailsModel.BankDetailsModel         `gorm:"foreignkey:BankDetailsId;association_foreignkey:ID" json:"bankDetails"`
 	BeneficiaryCustomer       *bankDetailsModel.BeneficiaryCustomerModel `gorm:"foreignkey:BeneficiaryCustomerId;association_foreignkey:ID" json:"beneficiaryCustomer"`
 	IntermediaryBankDetails   *bankDetailsModel.BankDetailsModel         `gorm:"foreignkey:IntermediaryBankDetailsId;association_foreignkey:ID" json:"intermediaryBankDetails"`
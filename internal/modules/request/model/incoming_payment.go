@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// IncomingPaymentStatusCredited means that the matched account has been credited
+	IncomingPaymentStatusCredited = "credited"
+	// IncomingPaymentStatusUnmatched means that the payment is waiting in the exceptions queue
+	IncomingPaymentStatusUnmatched = "unmatched"
+	// IncomingPaymentStatusDismissed means that the payment has been removed from the exceptions queue without crediting
+	IncomingPaymentStatusDismissed = "dismissed"
+)
+
+// IncomingPayment is a credit entry imported from camt.053 statement or camt.054 notification
+type IncomingPayment struct {
+	Id              uint64          `gorm:"primary_key" json:"id"`
+	Reference       string          `json:"reference" gorm:"unique_index:uix_incoming_payments_reference"`
+	FileName        string          `json:"fileName"`
+	Amount          decimal.Decimal `json:"amount"`
+	CurrencyCode    string          `json:"currencyCode"`
+	BookingDate     *time.Time      `json:"bookingDate"`
+	DebtorName      string          `json:"debtorName"`
+	DebtorAccount   string          `json:"debtorAccount"`
+	CreditorAccount string          `json:"creditorAccount"`
+	RemittanceInfo  string          `json:"remittanceInfo"`
+	Status          string          `json:"status"`
+	// Reason explains why the payment has not been matched or dismissed
+	Reason    *string   `json:"reason"`
+	AccountId *uint64   `json:"accountId"`
+	RequestId *uint64   `json:"requestId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (*IncomingPayment) TableName() string {
+	return "incoming_payments"
+}
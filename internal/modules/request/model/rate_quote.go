@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+)
+
+// RateQuote locks exchange rate between transfer preview and creation
+type RateQuote struct {
+	Id                    string            `json:"id"`
+	UserId                string            `json:"-"`
+	Subject               constants.Subject `json:"-"`
+	BaseCurrencyCode      string            `json:"baseCurrencyCode"`
+	ReferenceCurrencyCode string            `json:"referenceCurrencyCode"`
+	Amount                decimal.Decimal   `json:"amount"`
+	Rate                  decimal.Decimal   `json:"rate"`
+	ExchangeMargin        decimal.Decimal   `json:"exchangeMargin"`
+	ExpiresAt             time.Time         `json:"expiresAt"`
+	UsedAt                *time.Time        `json:"-"`
+	CreatedAt             time.Time         `json:"-"`
+}
+
+func (*RateQuote) TableName() string {
+	return "request_rate_quotes"
+}
+
+// IsExpired checks whether the quote can not be used any more
+func (q *RateQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
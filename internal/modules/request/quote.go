@@ -0,0 +1,206 @@
+package request
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	errorsPkg "github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/fee"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+const (
+	defaultRateQuoteTtlSeconds = 60
+	rateQuoteIdSize            = 16
+)
+
+// QuoteService locks exchange rates so the user is charged with the rate shown on preview
+type QuoteService struct {
+	repository *repository.RateQuote
+	settings   *settings.Service
+	now        func() time.Time
+}
+
+func NewQuoteService(
+	repository *repository.RateQuote,
+	settings *settings.Service,
+) *QuoteService {
+	return &QuoteService{
+		repository: repository,
+		settings:   settings,
+		now:        time.Now,
+	}
+}
+
+// RateQuery describes an exchange of a request which rate is looked for
+type RateQuery struct {
+	Subject constants.Subject
+	// UserId is the initiator of the request, quotes are issued for them
+	UserId string
+	// OwnerId is the owner of the debited account, exchange margin of their user group is applied
+	OwnerId          string
+	CurrencyCodeFrom string
+	CurrencyCodeTo   string
+	// Amount is the requested amount in CurrencyCodeFrom, it selects exchange margin tier
+	Amount decimal.Decimal
+}
+
+// Issue locks the given rate for the user, the quote could be used only for requests of the same subject and amount
+func (s *QuoteService) Issue(query *RateQuery, rate *service.Rate) (*model.RateQuote, error) {
+	random := make([]byte, rateQuoteIdSize)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	ttl, err := s.settings.Int64(SettingRateQuoteTtlSecondsInt64)
+	if err != nil || ttl <= 0 {
+		ttl = defaultRateQuoteTtlSeconds
+	}
+
+	now := s.now()
+	quote := &model.RateQuote{
+		Id:                    hex.EncodeToString(random),
+		UserId:                query.UserId,
+		Subject:               query.Subject,
+		BaseCurrencyCode:      query.CurrencyCodeFrom,
+		ReferenceCurrencyCode: query.CurrencyCodeTo,
+		Amount:                query.Amount,
+		Rate:                  rate.Rate,
+		ExchangeMargin:        rate.ExchangeMargin,
+		ExpiresAt:             now.Add(time.Duration(ttl) * time.Second),
+		CreatedAt:             now,
+	}
+	if err := s.repository.Create(quote); err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to save rate quote")
+	}
+	return quote, nil
+}
+
+// Find retrieves valid quote of the user for the given subject, currencies and amount
+func (s *QuoteService) Find(query *RateQuery, quoteId string) (*model.RateQuote, error) {
+	quote, err := s.repository.FindNotUsed(quoteId, query.UserId)
+	if err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to retrieve rate quote")
+	}
+	if quote == nil ||
+		!quote.Subject.EqualsTo(query.Subject) ||
+		quote.BaseCurrencyCode != query.CurrencyCodeFrom ||
+		quote.ReferenceCurrencyCode != query.CurrencyCodeTo ||
+		!quote.Amount.Equal(query.Amount) {
+		return nil, errcodes.CreatePublicError(errcodes.CodeRateQuoteNotFound)
+	}
+	if quote.IsExpired(s.now()) {
+		return nil, errcodes.CreatePublicError(errcodes.CodeRateQuoteExpired)
+	}
+	return quote, nil
+}
+
+// Use retrieves valid quote and marks it as used within the given transaction
+func (s *QuoteService) Use(db *gorm.DB, query *RateQuery, quoteId string) (*model.RateQuote, error) {
+	quote, err := s.Find(query, quoteId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.repository.WrapContext(db).Use(quote, s.now())
+	if err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to use rate quote")
+	}
+	if !ok {
+		return nil, errcodes.CreatePublicError(errcodes.CodeRateQuoteNotFound)
+	}
+	return quote, nil
+}
+
+// getQuotedRate returns rate for preview: rate of the passed quote, the current rate locked by new quote
+// if it is asked or just the current rate
+func (c *Creator) getQuotedRate(query *RateQuery, quote *form.RateQuote) (*service.Rate, error) {
+	if query.CurrencyCodeFrom == query.CurrencyCodeTo {
+		return c.getRateForQuery(nil, query)
+	}
+	if quote.QuoteId != nil {
+		found, err := c.quoteService.Find(query, *quote.QuoteId)
+		if err != nil {
+			return nil, err
+		}
+		quote.Quote = found
+		return quoteRate(found), nil
+	}
+
+	rate, err := c.getRateForQuery(nil, query)
+	if err != nil || !quote.IssueQuote {
+		return rate, err
+	}
+	issued, err := c.quoteService.Issue(query, rate)
+	if err != nil {
+		return nil, err
+	}
+	quote.Quote = issued
+	return rate, nil
+}
+
+// getLockedRate returns rate for request creation: rate of the passed quote which becomes used or the current rate
+func (c *Creator) getLockedRate(db *gorm.DB, query *RateQuery, quoteId *string) (*service.Rate, error) {
+	if quoteId == nil || query.CurrencyCodeFrom == query.CurrencyCodeTo {
+		return c.getRateForQuery(db, query)
+	}
+	quote, err := c.quoteService.Use(db, query, *quoteId)
+	if err != nil {
+		return nil, err
+	}
+	return quoteRate(quote), nil
+}
+
+// getRateForQuery returns the current rate with exchange margin taken from the most specific configured rule.
+// If there is no rule conversions use their own exchange margin setting and other requests the margin
+// of the currency service.
+func (c *Creator) getRateForQuery(db *gorm.DB, query *RateQuery) (*service.Rate, error) {
+	rate, err := c.getRateForCurrencies(query.CurrencyCodeFrom, query.CurrencyCodeTo)
+	if err != nil || query.CurrencyCodeFrom == query.CurrencyCodeTo {
+		return rate, err
+	}
+
+	marginQuery := &fee.MarginQuery{
+		BaseCurrencyCode:      query.CurrencyCodeFrom,
+		ReferenceCurrencyCode: query.CurrencyCodeTo,
+		Amount:                query.Amount,
+	}
+	owner, err := c.userService.GetByUID(query.OwnerId)
+	if err != nil {
+		// without the owner exchange margin of their user group would be silently skipped
+		return nil, errorsPkg.Wrapf(err, "failed to retrieve account owner %s", query.OwnerId)
+	}
+	if owner.GroupId != 0 {
+		marginQuery.UserGroupId = &owner.GroupId
+	}
+	rate, rule, err := c.exchangeMarginService.Apply(rate, marginQuery, db)
+	if err != nil || rule != nil || !query.Subject.EqualsTo(constants.SubjectConvert) {
+		return rate, err
+	}
+
+	margin, err := c.settings.String(SettingConvertExchangeMarginPercentString)
+	if err != nil || strings.TrimSpace(margin) == "" {
+		return rate, nil
+	}
+	exchangeMargin, err := decimal.NewFromString(strings.TrimSpace(margin))
+	if err != nil {
+		return nil, errorsPkg.Wrapf(err, "invalid setting %s", SettingConvertExchangeMarginPercentString)
+	}
+	return &service.Rate{Rate: rate.Rate, ExchangeMargin: exchangeMargin}, nil
+}
+
+func quoteRate(quote *model.RateQuote) *service.Rate {
+	return &service.Rate{
+		Rate:           quote.Rate,
+		ExchangeMargin: quote.ExchangeMargin,
+	}
+}
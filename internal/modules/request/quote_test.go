@@ -0,0 +1,160 @@
+package request_test
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	currencyService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	. "github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+)
+
+// timeArg matches time argument of a query
+type timeArg time.Time
+
+func (t timeArg) Match(v driver.Value) bool {
+	actual, ok := v.(time.Time)
+	return ok && actual.Equal(time.Time(t))
+}
+
+// publicErrorCode returns code of the public error
+func publicErrorCode(err error) string {
+	Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+	return err.(*errors.PublicError).Code
+}
+
+var _ = Describe("QuoteService", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *QuoteService
+		now     time.Time
+	)
+
+	query := func() *RateQuery {
+		return &RateQuery{
+			Subject:          constants.SubjectTransferBetweenAccounts,
+			UserId:           "user-1",
+			OwnerId:          "user-1",
+			CurrencyCodeFrom: "EUR",
+			CurrencyCodeTo:   "USD",
+			Amount:           decimal.RequireFromString("100"),
+		}
+	}
+
+	BeforeEach(func() {
+		db, m, err := sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		mock = m
+		gdb, err = gorm.Open("mysql", db)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		now = time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+		service = NewQuoteService(repository.NewRateQuote(gdb), settings.NewService(settingsRepository.NewSettings(gdb)))
+		SetQuoteServiceClock(service, func() time.Time { return now })
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectQuote := func(expiresAt time.Time) {
+		mock.ExpectQuery("SELECT \\* FROM `request_rate_quotes`").
+			WithArgs("quote-1", "user-1").
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "user_id", "subject", "base_currency_code", "reference_currency_code", "amount", "rate", "exchange_margin", "expires_at"}).
+				AddRow("quote-1", "user-1", "TBA", "EUR", "USD", "100.00", "1.2", "0.5", expiresAt))
+	}
+
+	It("should issue quote locking the rate for the configured time", func() {
+		mock.ExpectQuery("SELECT \\* FROM `settings`").WillReturnRows(sqlmock.
+			NewRows([]string{"id", "name", "value"}).
+			AddRow(1, "rate_quote_ttl_seconds", "30"))
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `request_rate_quotes`").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		quote, err := service.Issue(query(), &currencyService.Rate{Rate: decimal.RequireFromString("1.2")})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(quote.Id).To(HaveLen(32))
+		Expect(quote.ExpiresAt).To(Equal(now.Add(30 * time.Second)))
+		Expect(quote.Rate.String()).To(Equal("1.2"))
+	})
+
+	It("should use valid quote once", func() {
+		mock.ExpectBegin()
+		expectQuote(now.Add(time.Minute))
+		mock.ExpectExec("UPDATE `request_rate_quotes` SET `used_at` = \\? WHERE \\(id = \\? AND used_at IS NULL\\)").
+			WithArgs(timeArg(now), "quote-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		tx := gdb.Begin()
+		quote, err := service.Use(tx, query(), "quote-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(quote.Rate.String()).To(Equal("1.2"))
+		Expect(quote.UsedAt).NotTo(BeNil())
+
+		// a concurrent request has used the quote in the meantime
+		expectQuote(now.Add(time.Minute))
+		mock.ExpectExec("UPDATE `request_rate_quotes` SET `used_at`").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err = service.Use(tx, query(), "quote-1")
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeRateQuoteNotFound))
+	})
+
+	It("should not use already used quote", func() {
+		mock.ExpectQuery("SELECT \\* FROM `request_rate_quotes` WHERE \\(id = \\? AND user_id = \\? AND used_at IS NULL\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := service.Use(gdb, query(), "quote-1")
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeRateQuoteNotFound))
+	})
+
+	It("should not use expired quote", func() {
+		expectQuote(now)
+
+		_, err := service.Use(gdb, query(), "quote-1")
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeRateQuoteExpired))
+	})
+
+	It("should use quote only for the same amount, currency pair and subject", func() {
+		changes := []func(q *RateQuery){
+			func(q *RateQuery) { q.Amount = decimal.RequireFromString("100.01") },
+			func(q *RateQuery) { q.CurrencyCodeFrom = "GBP" },
+			func(q *RateQuery) { q.CurrencyCodeTo = "GBP" },
+			func(q *RateQuery) { q.CurrencyCodeFrom, q.CurrencyCodeTo = "USD", "EUR" },
+			func(q *RateQuery) { q.Subject = constants.SubjectTransferBetweenUsers },
+		}
+		for _, change := range changes {
+			expectQuote(now.Add(time.Minute))
+			changed := query()
+			change(changed)
+
+			_, err := service.Use(gdb, changed, "quote-1")
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeRateQuoteNotFound))
+		}
+	})
+
+	It("should ignore trailing zeros of the amount", func() {
+		expectQuote(now.Add(time.Minute))
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `request_rate_quotes` SET `used_at`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		changed := query()
+		changed.Amount = decimal.RequireFromString("100.000")
+		_, err := service.Use(gdb, changed, "quote-1")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+)
+
+type IncomingPayment struct {
+	db *gorm.DB
+}
+
+func NewIncomingPayment(db *gorm.DB) *IncomingPayment {
+	return &IncomingPayment{db: db}
+}
+
+func (r *IncomingPayment) Create(payment *model.IncomingPayment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *IncomingPayment) Update(payment *model.IncomingPayment) error {
+	return r.db.Save(payment).Error
+}
+
+// CreateUnlessExists saves the payment unless the payment with the same reference has been already saved,
+// false is returned for the duplicate. It waits for the concurrent transaction which has saved the same reference.
+func (r *IncomingPayment) CreateUnlessExists(payment *model.IncomingPayment) (bool, error) {
+	result := r.db.Set("gorm:insert_modifier", "IGNORE").Create(payment)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ExistsByReference checks whether the entry with the given bank reference has been already imported
+func (r *IncomingPayment) ExistsByReference(reference string) (bool, error) {
+	var count uint64
+	err := r.db.Model(&model.IncomingPayment{}).Where("reference = ?", reference).Count(&count).Error
+	return count > 0, err
+}
+
+// FindByIdForUpdate locks the payment, nil is returned if the payment is not found
+func (r *IncomingPayment) FindByIdForUpdate(id uint64) (*model.IncomingPayment, error) {
+	payment := &model.IncomingPayment{}
+	err := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", id).First(payment).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return payment, err
+}
+
+// FindByStatus retrieves payments of the given status starting from the oldest one
+func (r *IncomingPayment) FindByStatus(status string) ([]*model.IncomingPayment, error) {
+	payments := make([]*model.IncomingPayment, 0)
+	err := r.db.Where("status = ?", status).Order("id").Find(&payments).Error
+	return payments, err
+}
+
+func (r IncomingPayment) WrapContext(db *gorm.DB) *IncomingPayment {
+	r.db = db
+	return &r
+}
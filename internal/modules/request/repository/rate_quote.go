@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+)
+
+type RateQuote struct {
+	db *gorm.DB
+}
+
+func NewRateQuote(db *gorm.DB) *RateQuote {
+	return &RateQuote{db: db}
+}
+
+func (r *RateQuote) Create(quote *model.RateQuote) error {
+	return r.db.Create(quote).Error
+}
+
+// FindNotUsed retrieves quote of the user which has not been used yet, nil is returned if the quote is not found
+func (r *RateQuote) FindNotUsed(id, userId string) (*model.RateQuote, error) {
+	quote := &model.RateQuote{}
+	err := r.db.Where("id = ? AND user_id = ? AND used_at IS NULL", id, userId).First(quote).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return quote, err
+}
+
+// Use marks the quote as used, it returns false if the quote has been already used
+func (r *RateQuote) Use(quote *model.RateQuote, now time.Time) (bool, error) {
+	result := r.db.
+		Model(&model.RateQuote{}).
+		Where("id = ? AND used_at IS NULL", quote.Id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	quote.UsedAt = &now
+	return true, nil
+}
+
+func (r RateQuote) WrapContext(db *gorm.DB) *RateQuote {
+	r.db = db
+	return &r
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	currenciesService "github.com/Confialink/wallet-accounts/internal/modules/currency/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	usersService "github.com/Confialink/wallet-accounts/internal/modules/user/service"
 	"github.com/Confialink/wallet-pkg-list_params"
@@ -16,6 +17,7 @@ type RequestRepositoryInterface interface {
 	WrapContext(db *gorm.DB) RequestRepositoryInterface
 	Updates(request *model.Request) error
 	FindById(id uint64) (*model.Request, error)
+	FindPendingByAccountId(accountId uint64) ([]*model.Request, error)
 	GetList(*list_params.ListParams) ([]*model.Request, error)
 	GetListCount(*list_params.ListParams) (uint64, error)
 	FillUsers(requests []*model.Request) error
@@ -59,6 +61,18 @@ func (r *requestRepository) FindById(id uint64) (*model.Request, error) {
 	return &request, nil
 }
 
+// FindPendingByAccountId retrieves pending requests having transactions of the given account
+func (r *requestRepository) FindPendingByAccountId(accountId uint64) ([]*model.Request, error) {
+	var requests []*model.Request
+	err := r.db.
+		Where("status = ?", constants.StatusPending).
+		Where("id IN (SELECT request_id FROM transactions WHERE account_id = ?)", accountId).
+		Order("id").
+		Find(&requests).
+		Error
+	return requests, err
+}
+
 func (r requestRepository) WrapContext(db *gorm.DB) RequestRepositoryInterface {
 	r.db = db
 	return &r
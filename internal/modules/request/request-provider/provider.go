@@ -14,8 +14,14 @@ func Providers() []interface{} {
 		repository.NewRequestRepository,
 		repository.NewDataOwt,
 		repository.NewTemplate,
+		repository.NewRateQuote,
+		repository.NewIncomingPayment,
+		request.NewQuoteService,
 		request.NewCreator,
+		request.NewFeeSimulator,
 		request.NewCsvService,
+		request.NewWireService,
+		request.NewIncomingPaymentService,
 		service.NewRequestsService,
 		service.NewIncludes,
 		service.NewExecutor,
@@ -32,12 +38,16 @@ func Providers() []interface{} {
 		handler.NewDaHandler,
 		handler.NewTbaHandler,
 		handler.NewTbuHandler,
+		handler.NewConvertHandler,
+		handler.NewFeeSimulationHandler,
 		handler.NewMoneyRequestTbuHandler,
 		handler.NewOwtHandler,
 		handler.NewDraHandler,
 		handler.NewRequestHandler,
 		handler.NewTemplateHandler,
 		handler.NewCsvHandler,
+		handler.NewWireHandler,
+		handler.NewIncomingPaymentHandler,
 
 		transfers.NewDefaultPermissionFactory,
 	}
@@ -0,0 +1,308 @@
+package camt
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	creditIndicator = "CRDT"
+	bookedStatus    = "BOOK"
+	dateLayout      = "2006-01-02"
+	dateTimeLayout  = "2006-01-02T15:04:05"
+)
+
+var tokenSeparator = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Entry is a single incoming payment reported by the bank
+type Entry struct {
+	// Reference uniquely identifies the entry and is used in order to avoid double crediting
+	Reference       string
+	Amount          decimal.Decimal
+	CurrencyCode    string
+	BookingDate     *time.Time
+	DebtorName      string
+	DebtorAccount   string
+	CreditorAccount string
+	EndToEndId      string
+	RemittanceInfo  string
+}
+
+// Tokens returns words of the remittance information and the end to end id
+// which may contain the account number used as IWT reference
+func (e *Entry) Tokens() []string {
+	tokens := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, text := range []string{e.RemittanceInfo, e.EndToEndId} {
+		for _, token := range tokenSeparator.Split(text, -1) {
+			if token == "" || seen[token] {
+				continue
+			}
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+type document struct {
+	Statement    *statementMessage    `xml:"BkToCstmrStmt"`
+	Notification *notificationMessage `xml:"BkToCstmrDbtCdtNtfctn"`
+}
+
+type statementMessage struct {
+	Statements []*report `xml:"Stmt"`
+}
+
+type notificationMessage struct {
+	Notifications []*report `xml:"Ntfctn"`
+}
+
+type report struct {
+	Id      string   `xml:"Id"`
+	Entries []*entry `xml:"Ntry"`
+}
+
+type amount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type status struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+func (s *status) code() string {
+	if s == nil {
+		return ""
+	}
+	if s.Code != "" {
+		return strings.TrimSpace(s.Code)
+	}
+	return strings.TrimSpace(s.Value)
+}
+
+type date struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+func (d *date) time() *time.Time {
+	if d == nil {
+		return nil
+	}
+	if t, err := time.Parse(dateLayout, strings.TrimSpace(d.Date)); err == nil {
+		return &t
+	}
+	value := strings.TrimSpace(d.DateTime)
+	if len(value) >= len(dateTimeLayout) {
+		if t, err := time.Parse(dateTimeLayout, value[:len(dateTimeLayout)]); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+type account struct {
+	Iban  string `xml:"Id>IBAN"`
+	Other string `xml:"Id>Othr>Id"`
+}
+
+func (a *account) number() string {
+	if a == nil {
+		return ""
+	}
+	if a.Iban != "" {
+		return strings.Replace(strings.TrimSpace(a.Iban), " ", "", -1)
+	}
+	return strings.TrimSpace(a.Other)
+}
+
+type party struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
+func (p *party) name() string {
+	if p == nil {
+		return ""
+	}
+	if p.Name != "" {
+		return strings.TrimSpace(p.Name)
+	}
+	return strings.TrimSpace(p.PartyName)
+}
+
+type entry struct {
+	Reference      string     `xml:"NtryRef"`
+	Amount         amount     `xml:"Amt"`
+	Indicator      string     `xml:"CdtDbtInd"`
+	Reversal       bool       `xml:"RvslInd"`
+	Status         *status    `xml:"Sts"`
+	BookingDate    *date      `xml:"BookgDt"`
+	ServicerRef    string     `xml:"AcctSvcrRef"`
+	Transactions   []*details `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo string     `xml:"AddtlNtryInf"`
+}
+
+type details struct {
+	EndToEndId      string   `xml:"Refs>EndToEndId"`
+	ServicerRef     string   `xml:"Refs>AcctSvcrRef"`
+	Amount          *amount  `xml:"Amt"`
+	LegacyAmount    *amount  `xml:"AmtDtls>TxAmt>Amt"`
+	Indicator       string   `xml:"CdtDbtInd"`
+	Debtor          *party   `xml:"RltdPties>Dbtr"`
+	DebtorAccount   *account `xml:"RltdPties>DbtrAcct"`
+	CreditorAccount *account `xml:"RltdPties>CdtrAcct"`
+	Unstructured    []string `xml:"RmtInf>Ustrd"`
+	CreditorRefs    []string `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	AdditionalInfo  string   `xml:"AddtlTxInf"`
+}
+
+func (d *details) amount() *amount {
+	if d.Amount != nil && d.Amount.Value != "" {
+		return d.Amount
+	}
+	return d.LegacyAmount
+}
+
+// Parse reads booked credit entries of camt.053 bank statement or camt.054 debit/credit notification,
+// entries which contain several transactions are split into separate entries
+func Parse(r io.Reader) ([]*Entry, error) {
+	doc := &document{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, errors.Wrap(err, "failed to decode camt document")
+	}
+
+	var reports []*report
+	switch {
+	case doc.Statement != nil:
+		reports = doc.Statement.Statements
+	case doc.Notification != nil:
+		reports = doc.Notification.Notifications
+	default:
+		return nil, errors.New("document is neither camt.053 statement nor camt.054 notification")
+	}
+
+	entries := make([]*Entry, 0)
+	for _, rep := range reports {
+		for _, ntry := range rep.Entries {
+			if strings.TrimSpace(ntry.Indicator) != creditIndicator || ntry.Reversal {
+				continue
+			}
+			if code := ntry.Status.code(); code != "" && code != bookedStatus {
+				continue
+			}
+			parsed, err := ntry.entries()
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, parsed...)
+		}
+	}
+	return entries, nil
+}
+
+func (n *entry) entries() ([]*Entry, error) {
+	if len(n.Transactions) <= 1 {
+		var tx *details
+		if len(n.Transactions) == 1 {
+			tx = n.Transactions[0]
+		}
+		result, err := n.entry(tx, &n.Amount, "")
+		if err != nil {
+			return nil, err
+		}
+		return []*Entry{result}, nil
+	}
+
+	result := make([]*Entry, 0, len(n.Transactions))
+	for i, tx := range n.Transactions {
+		if indicator := strings.TrimSpace(tx.Indicator); indicator != "" && indicator != creditIndicator {
+			continue
+		}
+		amt := tx.amount()
+		if amt == nil {
+			return nil, errors.Errorf("amount of transaction %d of entry %q is missing", i+1, n.ServicerRef+n.Reference)
+		}
+		item, err := n.entry(tx, amt, "/"+strconv.Itoa(i+1))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (n *entry) entry(tx *details, amt *amount, suffix string) (*Entry, error) {
+	value, err := decimal.NewFromString(strings.TrimSpace(amt.Value))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid amount %q", amt.Value)
+	}
+	result := &Entry{
+		Amount:       value,
+		CurrencyCode: strings.ToUpper(strings.TrimSpace(amt.Currency)),
+		BookingDate:  n.BookingDate.time(),
+	}
+
+	info := make([]string, 0)
+	if tx != nil {
+		result.EndToEndId = strings.TrimSpace(tx.EndToEndId)
+		if result.EndToEndId == "NOTPROVIDED" {
+			result.EndToEndId = ""
+		}
+		result.DebtorName = tx.Debtor.name()
+		result.DebtorAccount = tx.DebtorAccount.number()
+		result.CreditorAccount = tx.CreditorAccount.number()
+		info = append(info, tx.Unstructured...)
+		info = append(info, tx.CreditorRefs...)
+		info = append(info, tx.AdditionalInfo)
+	}
+	info = append(info, n.AdditionalInfo)
+	result.RemittanceInfo = joinNotEmpty(info)
+
+	result.Reference = n.reference(tx, result) + suffix
+	return result, nil
+}
+
+// reference returns the bank reference of the entry,
+// hash of its content is used if the bank provides no reference. Only fields of the entry itself are hashed
+// so that the entry reported again in another statement or notification gets the same reference.
+func (n *entry) reference(tx *details, e *Entry) string {
+	if tx != nil && strings.TrimSpace(tx.ServicerRef) != "" {
+		return strings.TrimSpace(tx.ServicerRef)
+	}
+	for _, ref := range []string{n.ServicerRef, n.Reference} {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			return ref
+		}
+	}
+	bookingDate := ""
+	if e.BookingDate != nil {
+		bookingDate = e.BookingDate.Format(dateLayout)
+	}
+	sum := sha1.Sum([]byte(strings.Join([]string{
+		bookingDate, e.Amount.String(), e.CurrencyCode, e.DebtorAccount, e.EndToEndId, e.RemittanceInfo,
+	}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+func joinNotEmpty(values []string) string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return strings.Join(result, " ")
+}
@@ -0,0 +1,13 @@
+package camt_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCamt(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Camt Suite")
+}
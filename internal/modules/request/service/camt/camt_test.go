@@ -0,0 +1,154 @@
+package camt_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/request/service/camt"
+)
+
+var _ = Describe("Camt", func() {
+	Context("camt.053", func() {
+		statement := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-1</Id>
+      <Ntry>
+        <NtryRef>1</NtryRef>
+        <Amt Ccy="EUR">150.25</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2020-10-19</Dt></BookgDt>
+        <AcctSvcrRef>BANKREF1</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+            <RltdPties>
+              <Dbtr><Nm>John Doe</Nm></Dbtr>
+              <DbtrAcct><Id><IBAN>DE89 3704 0044 0532 0130 00</IBAN></Id></DbtrAcct>
+            </RltdPties>
+            <RmtInf><Ustrd>Top up 1234567</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">10</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">20</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">30</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <RvslInd>true</RvslInd>
+        <Sts>BOOK</Sts>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+		It("should read booked credit entries only", func() {
+			entries, err := Parse(strings.NewReader(statement))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+
+			bookingDate := time.Date(2020, 10, 19, 0, 0, 0, 0, time.UTC)
+			Expect(entries[0]).To(Equal(&Entry{
+				Reference:      "BANKREF1",
+				Amount:         decimal.RequireFromString("150.25"),
+				CurrencyCode:   "EUR",
+				BookingDate:    &bookingDate,
+				DebtorName:     "John Doe",
+				DebtorAccount:  "DE89370400440532013000",
+				RemittanceInfo: "Top up 1234567",
+			}))
+			Expect(entries[0].Tokens()).To(Equal([]string{"Top", "up", "1234567"}))
+		})
+	})
+
+	Context("camt.054", func() {
+		notification := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.054.001.08">
+  <BkToCstmrDbtCdtNtfctn>
+    <Ntfctn>
+      <Id>NTF-1</Id>
+      <Ntry>
+        <Amt Ccy="USD">300</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><DtTm>2020-10-19T10:15:00+02:00</DtTm></BookgDt>
+        <AcctSvcrRef>BATCH7</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>E2E-1</EndToEndId></Refs>
+            <Amt Ccy="USD">100</Amt>
+            <RltdPties>
+              <Dbtr><Pty><Nm>ACME Ltd</Nm></Pty></Dbtr>
+              <CdtrAcct><Id><Othr><Id>7654321</Id></Othr></Id></CdtrAcct>
+            </RltdPties>
+          </TxDtls>
+          <TxDtls>
+            <Amt Ccy="USD">200</Amt>
+            <RmtInf><Strd><CdtrRefInf><Ref>1234567</Ref></CdtrRefInf></Strd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+    </Ntfctn>
+  </BkToCstmrDbtCdtNtfctn>
+</Document>`
+
+		It("should split batched entry into transactions", func() {
+			entries, err := Parse(strings.NewReader(notification))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(entries[0].Reference).To(Equal("BATCH7/1"))
+			Expect(entries[0].Amount.String()).To(Equal("100"))
+			Expect(entries[0].CurrencyCode).To(Equal("USD"))
+			Expect(entries[0].DebtorName).To(Equal("ACME Ltd"))
+			Expect(entries[0].CreditorAccount).To(Equal("7654321"))
+			Expect(entries[0].Tokens()).To(Equal([]string{"E2E", "1"}))
+			Expect(entries[0].BookingDate.Format("2006-01-02")).To(Equal("2020-10-19"))
+
+			Expect(entries[1].Reference).To(Equal("BATCH7/2"))
+			Expect(entries[1].Amount.String()).To(Equal("200"))
+			Expect(entries[1].RemittanceInfo).To(Equal("1234567"))
+		})
+
+		It("should generate the same reference if the bank does not provide it", func() {
+			document := strings.Replace(notification, "<AcctSvcrRef>BATCH7</AcctSvcrRef>", "", 1)
+			first, err := Parse(strings.NewReader(document))
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := Parse(strings.NewReader(document))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(first[0].Reference).NotTo(BeEmpty())
+			Expect(first[0].Reference).To(Equal(second[0].Reference))
+			Expect(first[0].Reference).NotTo(Equal(first[1].Reference))
+		})
+
+		It("should generate the same reference for the entry reported in another notification", func() {
+			document := strings.Replace(notification, "<AcctSvcrRef>BATCH7</AcctSvcrRef>", "", 1)
+			first, err := Parse(strings.NewReader(document))
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := Parse(strings.NewReader(strings.Replace(document, "<Id>NTF-1</Id>", "<Id>NTF-2</Id>", 1)))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(first[0].Reference).To(Equal(second[0].Reference))
+		})
+	})
+
+	It("should fail on unknown document", func() {
+		_, err := Parse(strings.NewReader(`<Document><CstmrPmtStsRpt/></Document>`))
+		Expect(err).To(HaveOccurred())
+	})
+})
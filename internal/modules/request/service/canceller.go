@@ -57,3 +57,26 @@ func (c *Canceller) Call(request *model.Request, reason string, currentUser *use
 
 	return err
 }
+
+// CallInTx cancels pending request within the given db transaction. PendingRequestCancelled event is not emitted
+// since the transaction could still be rolled back, NotifyCancelled must be called once it is committed.
+func (c *Canceller) CallInTx(tx *gorm.DB, request *model.Request, reason string) error {
+	canceller, err := transfers.CreateCanceller(tx, request, c.currencyProvider, c.pf)
+	if err != nil {
+		return err
+	}
+
+	return canceller.Cancel(request, reason)
+}
+
+// NotifyCancelled emits PendingRequestCancelled event of the request cancelled by CallInTx
+func (c *Canceller) NotifyCancelled(request *model.Request, reason string) {
+	eventContext := &event.ContextPendingRequestCancelled{
+		Tx:        c.db,
+		UserID:    *request.UserId,
+		RequestID: *request.Id,
+		Reason:    reason,
+	}
+
+	<-c.emitter.Emit(event.PendingRequestCancelled, eventContext)
+}
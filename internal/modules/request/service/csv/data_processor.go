@@ -1,6 +1,8 @@
 package csv
 
 import (
+	"fmt"
+
 	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
 )
@@ -21,6 +23,32 @@ func (p *dataProcessor) getSourceAccountCurrency() string {
 	return *p.request.BaseCurrencyCode
 }
 
+// exchangeRate returns rate of requests with currency exchange
+func (p *dataProcessor) exchangeRate() string {
+	if !p.isExchange() || p.request.Rate == nil {
+		return ""
+	}
+	return p.request.Rate.String()
+}
+
+// exchangeMarginPercent returns exchange margin charged by requests with currency exchange
+func (p *dataProcessor) exchangeMarginPercent() string {
+	if !p.isExchange() {
+		return ""
+	}
+	margin, ok := p.request.GetInput()["exchangeMarginPercent"]
+	if !ok || margin == nil {
+		return ""
+	}
+	return fmt.Sprint(margin)
+}
+
+func (p *dataProcessor) isExchange() bool {
+	return p.request.BaseCurrencyCode != nil &&
+		p.request.ReferenceCurrencyCode != nil &&
+		*p.request.BaseCurrencyCode != *p.request.ReferenceCurrencyCode
+}
+
 func (p *dataProcessor) owtFeeType() string {
 	if *p.request.Subject == constants.SubjectTransferOutgoingWireTransfer && p.request.DataOwt.Fee != nil {
 		return *p.request.DataOwt.Fee.Name
@@ -98,6 +126,17 @@ func (p *dataProcessor) refMessage() string {
 	return ""
 }
 
+// chargeBearer returns who pays fees of outgoing wire transfers, requests created without it share charges
+func (p *dataProcessor) chargeBearer() string {
+	if *p.request.Subject != constants.SubjectTransferOutgoingWireTransfer {
+		return ""
+	}
+	if p.request.DataOwt.ChargeBearer != nil {
+		return *p.request.DataOwt.ChargeBearer
+	}
+	return constants.DefaultChargeBearer.String()
+}
+
 func (p *dataProcessor) intermediaryBankSwift() string {
 	if *p.request.Subject == constants.SubjectTransferOutgoingWireTransfer && p.request.DataOwt.IntermediaryBankDetails != nil {
 		return p.request.DataOwt.IntermediaryBankDetails.SwiftCode
@@ -36,6 +36,8 @@ func getAdminHeader() []string {
 		"OWT fee type",
 		"Payment amount",
 		"Payment currency",
+		"Exchange rate",
+		"Exchange margin %",
 		"Beneficiary Bank: SWIFT / BIC",
 		"Beneficiary Bank: Name",
 		"Beneficiary Bank: Address",
@@ -46,6 +48,7 @@ func getAdminHeader() []string {
 		"Beneficiary: Address",
 		"Beneficiary: Acc#/IBAN",
 		"Ref message",
+		"Charge bearer",
 		"Intermediary Bank SWIFT / BIC",
 		"Intermediary Bank Name",
 		"Intermediary Bank Address",
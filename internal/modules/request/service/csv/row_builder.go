@@ -68,6 +68,8 @@ func (b *RowBuilder) getAdminRow() ([]string, error) {
 		b.owtFeeType(),
 		b.paymentAmount(),
 		b.paymentCurrency(),
+		b.dataProcessor.exchangeRate(),
+		b.dataProcessor.exchangeMarginPercent(),
 		b.beneficiaryBankSwiftCode(),
 		b.beneficiaryBankName(),
 		b.beneficiaryBankAddress(),
@@ -78,6 +80,7 @@ func (b *RowBuilder) getAdminRow() ([]string, error) {
 		b.beneficiaryAddress(),
 		b.beneficiaryIban(),
 		b.refMessage(),
+		b.dataProcessor.chargeBearer(),
 		b.intermediaryBankSwift(),
 		b.intermediaryBankName(),
 		b.intermediaryBankAddress(),
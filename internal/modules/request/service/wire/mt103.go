@@ -0,0 +1,160 @@
+package wire
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	mtLineLength   = 35
+	mtNewLine      = "\r\n"
+	mtMaxReference = 16
+)
+
+// mtCharacters replaces characters which are not allowed by SWIFT X character set
+var mtCharacters = strings.NewReplacer(
+	"&", "+",
+	"_", "-",
+	"@", "(AT)",
+	"#", "",
+	"!", ".",
+	"\"", "",
+	";", ",",
+	"*", "",
+	"%", "",
+	"=", "-",
+)
+
+// WriteMT103 writes payments of the batch as a sequence of MT103 FIN messages
+func WriteMT103(w io.Writer, batch *Batch) error {
+	if !IsBic(batch.OrderingBic) {
+		return errors.Errorf("ordering bank BIC %q is invalid", batch.OrderingBic)
+	}
+	if err := batch.Validate(); err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriter(w)
+	for _, payment := range batch.Payments {
+		writeMT103Message(buf, batch, payment)
+	}
+	return buf.Flush()
+}
+
+func writeMT103Message(w *bufio.Writer, batch *Batch, payment *Payment) {
+	receiver := payment.BeneficiaryBank.Bic
+	if payment.IntermediaryBank != nil {
+		receiver = payment.IntermediaryBank.Bic
+	}
+
+	_, _ = w.WriteString("{1:F01" + logicalTerminal(batch.OrderingBic) + "0000000000}")
+	_, _ = w.WriteString("{2:I103" + logicalTerminal(receiver) + "N}")
+	_, _ = w.WriteString("{3:{108:" + truncate(batch.MessageId, mtMaxReference) + "}}")
+	_, _ = w.WriteString("{4:" + mtNewLine)
+
+	field := func(tag string, lines ...string) {
+		_, _ = w.WriteString(":" + tag + ":" + strings.Join(lines, mtNewLine) + mtNewLine)
+	}
+
+	field("20", truncate(mtText(payment.EndToEndId), mtMaxReference))
+	field("23B", "CRED")
+	field("32A", batch.ExecutionDate.Format("060102")+payment.CurrencyCode+mtAmount(payment.Amount))
+	if payment.Charges.IsPositive() {
+		field("33B", payment.CurrencyCode+mtAmount(payment.InstructedAmount))
+	}
+	field("50K", mtParty(payment.OrderingAccount, payment.OrderingName)...)
+	if payment.IntermediaryBank != nil {
+		field("56A", payment.IntermediaryBank.Bic)
+	}
+	field("57A", payment.BeneficiaryBank.Bic)
+	field("59", mtParty(payment.BeneficiaryAccount, payment.BeneficiaryName, payment.BeneficiaryAddress)...)
+	if remittance := mtLines(payment.RemittanceInfo, 4); len(remittance) > 0 {
+		field("70", remittance...)
+	}
+	field("71A", payment.ChargeBearer)
+	if payment.Charges.IsPositive() {
+		field("71F", payment.CurrencyCode+mtAmount(payment.Charges))
+	}
+	_, _ = w.WriteString("-}" + mtNewLine)
+}
+
+// logicalTerminal returns 12 characters address of the given BIC
+func logicalTerminal(bic string) string {
+	if len(bic) == 8 {
+		return bic + "XXXX"
+	}
+	return bic[:8] + "X" + bic[8:]
+}
+
+// mtAmount formats amount using comma as decimal separator, the comma is mandatory
+func mtAmount(amount decimal.Decimal) string {
+	value := amount.String()
+	if !strings.Contains(value, ".") {
+		return value + ","
+	}
+	return strings.Replace(value, ".", ",", 1)
+}
+
+// mtParty returns account line followed by name and address lines, 4 lines at most
+func mtParty(account, name string, address ...string) []string {
+	lines := []string{"/" + truncate(normalizeAccount(account), mtLineLength-1)}
+	lines = append(lines, mtLines(name, 1)...)
+	lines = append(lines, mtLines(strings.Join(address, " "), 3)...)
+	if len(lines) > 4 {
+		lines = lines[:4]
+	}
+	return lines
+}
+
+// mtLines splits text into lines of the allowed length
+func mtLines(text string, maxLines int) []string {
+	words := strings.Fields(mtText(text))
+	lines := make([]string, 0, maxLines)
+	line := ""
+	for _, word := range words {
+		for len(word) > 0 {
+			if line != "" && len(line)+1+len(word) > mtLineLength {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			chunk := truncate(word, mtLineLength-len(line))
+			line += chunk
+			word = word[len(chunk):]
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+	return lines
+}
+
+// mtText replaces characters which could not be sent, non ASCII characters are replaced by dots
+func mtText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		if r < ' ' || r > '~' {
+			return '.'
+		}
+		return r
+	}, mtCharacters.Replace(text))
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
@@ -0,0 +1,210 @@
+package wire
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	pain001Namespace   = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+	painDateLayout     = "2006-01-02"
+	painDateTimeLayout = "2006-01-02T15:04:05"
+	painMaxText        = 140
+	painMaxName        = 70
+)
+
+// painChargeBearers maps MT103 charge codes to ISO 20022 ones
+var painChargeBearers = map[string]string{
+	"OUR": "DEBT",
+	"SHA": "SHAR",
+	"BEN": "CRED",
+}
+
+type painDocument struct {
+	XMLName    xml.Name       `xml:"Document"`
+	Namespace  string         `xml:"xmlns,attr"`
+	Initiation painInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type painInitiation struct {
+	GroupHeader  painGroupHeader    `xml:"GrpHdr"`
+	PaymentInfos []*painPaymentInfo `xml:"PmtInf"`
+}
+
+type painGroupHeader struct {
+	MessageId            string    `xml:"MsgId"`
+	CreationDateTime     string    `xml:"CreDtTm"`
+	NumberOfTransactions string    `xml:"NbOfTxs"`
+	ControlSum           string    `xml:"CtrlSum"`
+	InitiatingParty      painParty `xml:"InitgPty"`
+}
+
+type painPaymentInfo struct {
+	PaymentInfoId          string             `xml:"PmtInfId"`
+	PaymentMethod          string             `xml:"PmtMtd"`
+	NumberOfTransactions   string             `xml:"NbOfTxs"`
+	ControlSum             string             `xml:"CtrlSum"`
+	RequestedExecutionDate string             `xml:"ReqdExctnDt"`
+	Debtor                 painParty          `xml:"Dbtr"`
+	DebtorAccount          painAccount        `xml:"DbtrAcct"`
+	DebtorAgent            painAgent          `xml:"DbtrAgt"`
+	Transactions           []*painTransaction `xml:"CdtTrfTxInf"`
+}
+
+type painTransaction struct {
+	PaymentId         painPaymentId   `xml:"PmtId"`
+	Amount            painAmount      `xml:"Amt"`
+	ChargeBearer      string          `xml:"ChrgBr"`
+	IntermediaryAgent *painAgent      `xml:"IntrmyAgt1,omitempty"`
+	CreditorAgent     painAgent       `xml:"CdtrAgt"`
+	Creditor          painParty       `xml:"Cdtr"`
+	CreditorAccount   painAccount     `xml:"CdtrAcct"`
+	RemittanceInfo    *painRemittance `xml:"RmtInf,omitempty"`
+}
+
+type painPaymentId struct {
+	EndToEndId string `xml:"EndToEndId"`
+}
+
+type painAmount struct {
+	Instructed painCurrencyAmount `xml:"InstdAmt"`
+}
+
+type painCurrencyAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type painParty struct {
+	Name          string             `xml:"Nm"`
+	PostalAddress *painPostalAddress `xml:"PstlAdr,omitempty"`
+}
+
+type painPostalAddress struct {
+	AddressLines []string `xml:"AdrLine"`
+}
+
+type painAccount struct {
+	Id painAccountId `xml:"Id"`
+}
+
+type painAccountId struct {
+	Iban  string       `xml:"IBAN,omitempty"`
+	Other *painOtherId `xml:"Othr,omitempty"`
+}
+
+type painOtherId struct {
+	Id string `xml:"Id"`
+}
+
+type painAgent struct {
+	FinancialInstitution painFinancialInstitution `xml:"FinInstnId"`
+}
+
+type painFinancialInstitution struct {
+	Bic  string `xml:"BIC,omitempty"`
+	Name string `xml:"Nm,omitempty"`
+}
+
+type painRemittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+// WritePain001 writes payments of the batch as ISO 20022 pain.001.001.03 customer credit transfer initiation,
+// every payment has its own payment information block because payments are debited from different accounts
+func WritePain001(w io.Writer, batch *Batch) error {
+	if err := batch.Validate(); err != nil {
+		return err
+	}
+
+	document := &painDocument{
+		Namespace: pain001Namespace,
+		Initiation: painInitiation{
+			GroupHeader: painGroupHeader{
+				MessageId:            batch.MessageId,
+				CreationDateTime:     batch.CreatedAt.Format(painDateTimeLayout),
+				NumberOfTransactions: strconv.Itoa(len(batch.Payments)),
+				ControlSum:           painDecimal(batch.ControlSum()),
+				InitiatingParty:      painParty{Name: painText(batch.InitiatingPartyName, painMaxName)},
+			},
+			PaymentInfos: make([]*painPaymentInfo, 0, len(batch.Payments)),
+		},
+	}
+
+	for _, payment := range batch.Payments {
+		document.Initiation.PaymentInfos = append(document.Initiation.PaymentInfos, &painPaymentInfo{
+			PaymentInfoId:          batch.MessageId + "-" + payment.EndToEndId,
+			PaymentMethod:          "TRF",
+			NumberOfTransactions:   "1",
+			ControlSum:             painDecimal(payment.Amount),
+			RequestedExecutionDate: batch.ExecutionDate.Format(painDateLayout),
+			Debtor:                 painParty{Name: painText(payment.OrderingName, painMaxName)},
+			DebtorAccount:          newPainAccount(payment.OrderingAccount),
+			DebtorAgent:            painAgent{FinancialInstitution: painFinancialInstitution{Bic: batch.OrderingBic}},
+			Transactions:           []*painTransaction{newPainTransaction(payment)},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(document)
+}
+
+func newPainTransaction(payment *Payment) *painTransaction {
+	transaction := &painTransaction{
+		PaymentId: painPaymentId{EndToEndId: payment.EndToEndId},
+		Amount: painAmount{Instructed: painCurrencyAmount{
+			Currency: payment.CurrencyCode,
+			Value:    painDecimal(payment.Amount),
+		}},
+		ChargeBearer: painChargeBearers[payment.ChargeBearer],
+		CreditorAgent: painAgent{FinancialInstitution: painFinancialInstitution{
+			Bic:  payment.BeneficiaryBank.Bic,
+			Name: painText(payment.BeneficiaryBank.Name, painMaxName),
+		}},
+		Creditor:        painParty{Name: painText(payment.BeneficiaryName, painMaxName)},
+		CreditorAccount: newPainAccount(payment.BeneficiaryAccount),
+	}
+	if address := painText(payment.BeneficiaryAddress, painMaxName); address != "" {
+		transaction.Creditor.PostalAddress = &painPostalAddress{AddressLines: []string{address}}
+	}
+	if payment.IntermediaryBank != nil {
+		transaction.IntermediaryAgent = &painAgent{FinancialInstitution: painFinancialInstitution{
+			Bic: payment.IntermediaryBank.Bic,
+		}}
+	}
+	if payment.RemittanceInfo != "" {
+		transaction.RemittanceInfo = &painRemittance{Unstructured: painText(payment.RemittanceInfo, painMaxText)}
+	}
+	return transaction
+}
+
+func newPainAccount(account string) painAccount {
+	if IsIban(account) {
+		return painAccount{Id: painAccountId{Iban: normalizeAccount(account)}}
+	}
+	return painAccount{Id: painAccountId{Other: &painOtherId{Id: strings.TrimSpace(account)}}}
+}
+
+func painDecimal(value decimal.Decimal) string {
+	return value.StringFixed(2)
+}
+
+func painText(text string, length int) string {
+	return truncateRunes(strings.Join(strings.Fields(text), " "), length)
+}
+
+func truncateRunes(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
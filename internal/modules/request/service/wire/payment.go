@@ -0,0 +1,176 @@
+package wire
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FormatMT103   = "mt103"
+	FormatPain001 = "pain001"
+)
+
+var (
+	bicRegexp      = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	ibanRegexp     = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	currencyRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Bank is a financial institution taking part in a payment
+type Bank struct {
+	Bic  string
+	Name string
+}
+
+// Payment is a single outgoing wire transfer
+type Payment struct {
+	// EndToEndId identifies the payment in status reports
+	EndToEndId string
+	// Amount is the amount to be received by the beneficiary bank
+	Amount decimal.Decimal
+	// InstructedAmount is the amount requested by the sender before charges deducted from it
+	InstructedAmount decimal.Decimal
+	// Charges are deducted from the instructed amount if the beneficiary bears charges
+	Charges      decimal.Decimal
+	CurrencyCode string
+	// ChargeBearer is one of OUR, SHA or BEN
+	ChargeBearer       string
+	OrderingAccount    string
+	OrderingName       string
+	BeneficiaryName    string
+	BeneficiaryAddress string
+	BeneficiaryAccount string
+	BeneficiaryBank    Bank
+	IntermediaryBank   *Bank
+	RemittanceInfo     string
+}
+
+// Batch is a set of payments sent to the bank in a single file
+type Batch struct {
+	MessageId           string
+	CreatedAt           time.Time
+	ExecutionDate       time.Time
+	OrderingBic         string
+	InitiatingPartyName string
+	Payments            []*Payment
+}
+
+// ValidationError lists invalid or missing fields of a payment
+type ValidationError struct {
+	EndToEndId string   `json:"endToEndId"`
+	Fields     []string `json:"fields"`
+}
+
+// ValidationErrors is returned if some payments could not be exported
+type ValidationErrors []*ValidationError
+
+// Error returns error message
+func (v ValidationErrors) Error() string {
+	ids := make([]string, len(v))
+	for i, err := range v {
+		ids[i] = err.EndToEndId
+	}
+	return "invalid payments: " + strings.Join(ids, ", ")
+}
+
+// Validate checks that the payment contains all fields required by MT103 and pain.001
+func (p *Payment) Validate() *ValidationError {
+	fields := make([]string, 0)
+	if p.EndToEndId == "" {
+		fields = append(fields, "endToEndId")
+	}
+	if !p.Amount.IsPositive() {
+		fields = append(fields, "amount")
+	}
+	if !currencyRegexp.MatchString(p.CurrencyCode) {
+		fields = append(fields, "currencyCode")
+	}
+	switch p.ChargeBearer {
+	case "OUR", "SHA", "BEN":
+	default:
+		fields = append(fields, "chargeBearer")
+	}
+	if strings.TrimSpace(p.OrderingAccount) == "" {
+		fields = append(fields, "orderingAccount")
+	}
+	if strings.TrimSpace(p.OrderingName) == "" {
+		fields = append(fields, "orderingName")
+	}
+	if strings.TrimSpace(p.BeneficiaryName) == "" {
+		fields = append(fields, "beneficiaryName")
+	}
+	if strings.TrimSpace(p.BeneficiaryAccount) == "" {
+		fields = append(fields, "beneficiaryAccount")
+	}
+	if !IsBic(p.BeneficiaryBank.Bic) {
+		fields = append(fields, "beneficiaryBank.bic")
+	}
+	if p.IntermediaryBank != nil && !IsBic(p.IntermediaryBank.Bic) {
+		fields = append(fields, "intermediaryBank.bic")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{EndToEndId: p.EndToEndId, Fields: fields}
+}
+
+// Validate checks all payments of the batch
+func (b *Batch) Validate() error {
+	errs := make(ValidationErrors, 0)
+	for _, payment := range b.Payments {
+		if err := payment.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ControlSum returns sum of amounts of all payments
+func (b *Batch) ControlSum() decimal.Decimal {
+	sum := decimal.Zero
+	for _, payment := range b.Payments {
+		sum = sum.Add(payment.Amount)
+	}
+	return sum
+}
+
+// IsBic checks whether the given string is a valid BIC
+func IsBic(bic string) bool {
+	return bicRegexp.MatchString(bic)
+}
+
+// IsIban checks whether the given account number looks like IBAN
+func IsIban(account string) bool {
+	return ibanRegexp.MatchString(normalizeAccount(account))
+}
+
+func normalizeAccount(account string) string {
+	return strings.ToUpper(strings.Replace(account, " ", "", -1))
+}
+
+// Write writes the batch in the given format
+func Write(w io.Writer, format string, batch *Batch) error {
+	switch format {
+	case FormatMT103:
+		return WriteMT103(w, batch)
+	case FormatPain001:
+		return WritePain001(w, batch)
+	}
+	return errors.Errorf("unknown wire format %q", format)
+}
+
+// FileExtension returns extension of files of the given format
+func FileExtension(format string) string {
+	if format == FormatPain001 {
+		return ".xml"
+	}
+	return ".fin"
+}
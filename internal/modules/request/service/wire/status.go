@@ -0,0 +1,112 @@
+package wire
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Status is a final state of a payment reported by the bank
+type Status struct {
+	EndToEndId string
+	Rejected   bool
+	Reason     string
+}
+
+// pain002Document is a subset of ISO 20022 customer payment status report
+type pain002Document struct {
+	Report struct {
+		PaymentInfos []struct {
+			Transactions []struct {
+				EndToEndId string `xml:"OrgnlEndToEndId"`
+				Status     string `xml:"TxSts"`
+				Reasons    []struct {
+					Code           string   `xml:"Rsn>Cd"`
+					AdditionalInfo []string `xml:"AddtlInf"`
+				} `xml:"StsRsnInf"`
+			} `xml:"TxInfAndSts"`
+		} `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+// ParseStatusReport reads payment statuses, files with ".xml" extension are parsed as pain.002
+// and any other files as CSV
+func ParseStatusReport(r io.Reader, fileName string) ([]*Status, error) {
+	if strings.EqualFold(filepath.Ext(fileName), ".xml") {
+		return ParsePain002(r)
+	}
+	return ParseStatusCsv(r)
+}
+
+// ParsePain002 reads final statuses of transactions from pain.002 status report,
+// transactions which are still being processed are omitted
+func ParsePain002(r io.Reader) ([]*Status, error) {
+	document := &pain002Document{}
+	if err := xml.NewDecoder(r).Decode(document); err != nil {
+		return nil, errors.Wrap(err, "failed to read pain.002 status report")
+	}
+
+	statuses := make([]*Status, 0)
+	for _, info := range document.Report.PaymentInfos {
+		for _, transaction := range info.Transactions {
+			status := &Status{EndToEndId: strings.TrimSpace(transaction.EndToEndId)}
+			switch strings.ToUpper(strings.TrimSpace(transaction.Status)) {
+			case "ACSC", "ACCC":
+			case "RJCT":
+				status.Rejected = true
+				for _, reason := range transaction.Reasons {
+					parts := append([]string{reason.Code}, reason.AdditionalInfo...)
+					status.Reason = strings.TrimSpace(strings.Join(parts, " "))
+				}
+			default:
+				continue
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// ParseStatusCsv reads statuses from CSV with "end to end id,status,reason" columns where status is
+// "executed" or "cancelled", the header row is optional
+func ParseStatusCsv(r io.Reader) ([]*Status, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	statuses := make([]*Status, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CSV statuses")
+		}
+		if len(record) < 2 {
+			return nil, errors.Errorf("expected at least 2 columns on line %d", line)
+		}
+		status := &Status{EndToEndId: strings.TrimSpace(record[0])}
+		switch strings.ToLower(strings.TrimSpace(record[1])) {
+		case "executed":
+		case "cancelled":
+			status.Rejected = true
+			if len(record) > 2 {
+				status.Reason = strings.TrimSpace(record[2])
+			}
+		default:
+			if line == 1 {
+				// header
+				continue
+			}
+			return nil, errors.Errorf("unknown status %q on line %d", record[1], line)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
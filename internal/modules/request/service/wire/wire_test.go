@@ -0,0 +1,171 @@
+package wire_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/request/service/wire"
+)
+
+var _ = Describe("Wire", func() {
+	var batch *Batch
+
+	BeforeEach(func() {
+		batch = &Batch{
+			MessageId:           "OWT201019120000",
+			CreatedAt:           time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC),
+			ExecutionDate:       time.Date(2020, 10, 20, 0, 0, 0, 0, time.UTC),
+			OrderingBic:         "ORDBDEFF",
+			InitiatingPartyName: "Wallet",
+			Payments: []*Payment{
+				{
+					EndToEndId:         "42",
+					Amount:             decimal.RequireFromString("1000.5"),
+					InstructedAmount:   decimal.RequireFromString("1000.5"),
+					CurrencyCode:       "EUR",
+					ChargeBearer:       "SHA",
+					OrderingAccount:    "1234567",
+					OrderingName:       "John Doe",
+					BeneficiaryName:    "Jane Roe",
+					BeneficiaryAddress: "Main street 1, Berlin",
+					BeneficiaryAccount: "DE89 3704 0044 0532 0130 00",
+					BeneficiaryBank:    Bank{Bic: "COBADEFFXXX", Name: "Commerzbank"},
+					RemittanceInfo:     "Invoice #7",
+				},
+			},
+		}
+	})
+
+	Context("Validate", func() {
+		It("should list invalid fields", func() {
+			batch.Payments[0].BeneficiaryBank.Bic = "invalid"
+			batch.Payments[0].BeneficiaryName = ""
+			batch.Payments[0].ChargeBearer = "XYZ"
+
+			err := batch.Validate()
+			Expect(err).To(HaveOccurred())
+			errs, ok := err.(ValidationErrors)
+			Expect(ok).To(BeTrue())
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].EndToEndId).To(Equal("42"))
+			Expect(errs[0].Fields).To(ConsistOf("chargeBearer", "beneficiaryName", "beneficiaryBank.bic"))
+		})
+	})
+
+	Context("MT103", func() {
+		It("should write message", func() {
+			buf := bytes.NewBuffer(nil)
+			Expect(WriteMT103(buf, batch)).To(Succeed())
+
+			message := buf.String()
+			Expect(message).To(HavePrefix("{1:F01ORDBDEFFXXXX0000000000}{2:I103COBADEFFXXXXN}"))
+			Expect(message).To(ContainSubstring(":20:42\r\n"))
+			Expect(message).To(ContainSubstring(":32A:201020EUR1000,5\r\n"))
+			Expect(message).To(ContainSubstring(":50K:/1234567\r\nJohn Doe\r\n"))
+			Expect(message).To(ContainSubstring(":57A:COBADEFFXXX\r\n"))
+			Expect(message).To(ContainSubstring(":59:/DE89370400440532013000\r\nJane Roe\r\nMain street 1, Berlin\r\n"))
+			Expect(message).To(ContainSubstring(":70:Invoice 7\r\n"))
+			Expect(message).To(ContainSubstring(":71A:SHA\r\n"))
+			Expect(message).NotTo(ContainSubstring(":71F:"))
+			Expect(message).To(HaveSuffix("-}\r\n"))
+		})
+
+		It("should write instructed amount and charges if the beneficiary bears charges", func() {
+			payment := batch.Payments[0]
+			payment.ChargeBearer = "BEN"
+			payment.Amount = decimal.RequireFromString("990")
+			payment.Charges = decimal.RequireFromString("10.5")
+
+			buf := bytes.NewBuffer(nil)
+			Expect(WriteMT103(buf, batch)).To(Succeed())
+
+			message := buf.String()
+			Expect(message).To(ContainSubstring(":32A:201020EUR990,\r\n"))
+			Expect(message).To(ContainSubstring(":33B:EUR1000,5\r\n"))
+			Expect(message).To(ContainSubstring(":71A:BEN\r\n:71F:EUR10,5\r\n"))
+		})
+
+		It("should send message to the intermediary bank", func() {
+			batch.Payments[0].IntermediaryBank = &Bank{Bic: "CHASUS33"}
+
+			buf := bytes.NewBuffer(nil)
+			Expect(WriteMT103(buf, batch)).To(Succeed())
+
+			message := buf.String()
+			Expect(message).To(ContainSubstring("{2:I103CHASUS33XXXXN}"))
+			Expect(message).To(ContainSubstring(":56A:CHASUS33\r\n:57A:COBADEFFXXX\r\n"))
+		})
+
+		It("should fail if ordering BIC is invalid", func() {
+			batch.OrderingBic = ""
+			Expect(WriteMT103(bytes.NewBuffer(nil), batch)).NotTo(Succeed())
+		})
+	})
+
+	Context("pain.001", func() {
+		It("should write credit transfer initiation", func() {
+			batch.Payments[0].ChargeBearer = "OUR"
+
+			buf := bytes.NewBuffer(nil)
+			Expect(WritePain001(buf, batch)).To(Succeed())
+
+			document := buf.String()
+			Expect(document).To(ContainSubstring(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">`))
+			Expect(document).To(ContainSubstring("<MsgId>OWT201019120000</MsgId>"))
+			Expect(document).To(ContainSubstring("<NbOfTxs>1</NbOfTxs>"))
+			Expect(document).To(ContainSubstring("<CtrlSum>1000.50</CtrlSum>"))
+			Expect(document).To(ContainSubstring("<ReqdExctnDt>2020-10-20</ReqdExctnDt>"))
+			Expect(document).To(ContainSubstring("<Id>1234567</Id>"))
+			Expect(document).To(ContainSubstring("<EndToEndId>42</EndToEndId>"))
+			Expect(document).To(ContainSubstring(`<InstdAmt Ccy="EUR">1000.50</InstdAmt>`))
+			Expect(document).To(ContainSubstring("<ChrgBr>DEBT</ChrgBr>"))
+			Expect(document).To(ContainSubstring("<IBAN>DE89370400440532013000</IBAN>"))
+			Expect(document).To(ContainSubstring("<Ustrd>Invoice #7</Ustrd>"))
+		})
+	})
+
+	Context("status report", func() {
+		It("should read final statuses from pain.002", func() {
+			report := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">
+  <CstmrPmtStsRpt>
+    <OrgnlPmtInfAndSts>
+      <TxInfAndSts><OrgnlEndToEndId>42</OrgnlEndToEndId><TxSts>ACSC</TxSts></TxInfAndSts>
+      <TxInfAndSts><OrgnlEndToEndId>43</OrgnlEndToEndId><TxSts>PDNG</TxSts></TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>44</OrgnlEndToEndId>
+        <TxSts>RJCT</TxSts>
+        <StsRsnInf><Rsn><Cd>AC01</Cd></Rsn><AddtlInf>Incorrect account number</AddtlInf></StsRsnInf>
+      </TxInfAndSts>
+    </OrgnlPmtInfAndSts>
+  </CstmrPmtStsRpt>
+</Document>`
+			statuses, err := ParseStatusReport(strings.NewReader(report), "report.XML")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(statuses).To(Equal([]*Status{
+				{EndToEndId: "42"},
+				{EndToEndId: "44", Rejected: true, Reason: "AC01 Incorrect account number"},
+			}))
+		})
+
+		It("should read statuses from CSV", func() {
+			report := "request id,status,reason\n42,executed\n44,cancelled,Account closed\n"
+			statuses, err := ParseStatusReport(strings.NewReader(report), "report.csv")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(statuses).To(Equal([]*Status{
+				{EndToEndId: "42"},
+				{EndToEndId: "44", Rejected: true, Reason: "Account closed"},
+			}))
+		})
+
+		It("should fail on unknown CSV status", func() {
+			_, err := ParseStatusCsv(strings.NewReader("42,executed\n43,unknown\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
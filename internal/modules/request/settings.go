@@ -12,9 +12,24 @@ const (
 	//Outgoing Wire Transfer
 	SettingOwtActionRequired = settings.Name("owt_action_required")
 	SettingOwtTanRequired    = settings.Name("owt_tan_required")
+	// SettingOwtOurChargePercentString is a percent of the sent amount charged to the sender for fees of
+	// correspondent and beneficiary banks if the sender bears all charges (OUR), such transfers are refused if it is not set
+	SettingOwtOurChargePercentString = settings.Name("owt_our_charge_percent")
 	//Card Funding Transfer
 	SettingCftActionRequired = settings.Name("cft_action_required")
 	SettingCftTanRequired    = settings.Name("cft_tan_required")
+	//Currency conversion between own accounts
+	SettingConvertActionRequired = settings.Name("convert_action_required")
+	SettingConvertTanRequired    = settings.Name("convert_tan_required")
+	// SettingConvertExchangeMarginPercentString overrides exchange margin of currency pairs for conversions if set
+	SettingConvertExchangeMarginPercentString = settings.Name("convert_exchange_margin_percent")
 	//CreditFromAlias Account
 	SettingCreditAccountActionRequired = settings.Name("credit_account_action_required")
+	//Exchange rate quotes
+	SettingRateQuoteTtlSecondsInt64 = settings.Name("rate_quote_ttl_seconds")
+	//Outgoing wires export
+	// SettingWireOrderingBicString is BIC of the bank which sends exported outgoing wires
+	SettingWireOrderingBicString = settings.Name("wire_ordering_bic")
+	// SettingWireInitiatingPartyString is name of the initiating party of exported pain.001 files
+	SettingWireInitiatingPartyString = settings.Name("wire_initiating_party")
 )
@@ -20,7 +20,7 @@ func CreateExecutor(
 	pf PermissionFactory,
 ) (Executor, error) {
 	switch request.Subject.String() {
-	case "TBA", "TBU":
+	case "TBA", "TBU", "CONVERT":
 		return baTransfer(db, request, provider, pf), nil
 	case "OWT":
 		return owTransfer(db, request, provider, pf), nil
@@ -47,7 +47,7 @@ func CreateCanceller(
 	pf PermissionFactory,
 ) (Canceller, error) {
 	switch request.Subject.String() {
-	case "TBA", "TBU":
+	case "TBA", "TBU", "CONVERT":
 		return baTransfer(db, request, provider, pf), nil
 	case "OWT":
 		return owTransfer(db, request, provider, pf), nil
@@ -73,7 +73,7 @@ func CreateModifier(
 	pf PermissionFactory,
 ) (Modifier, error) {
 	switch request.Subject.String() {
-	case "TBA", "TBU":
+	case "TBA", "TBU", "CONVERT":
 		return baTransfer(db, request, provider, pf), nil
 	case "OWT":
 		return owTransfer(db, request, provider, pf), nil
@@ -1039,6 +1039,45 @@ var _ = Describe("Transfers", func() {
 			Expect(details).To(HaveLen(2))
 			Expect(ensureTransactionsOrder(unit.Transactions())).To(Succeed())
 		})
+		It("should evaluate currency convert between own accounts", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockPF := mockTransfers.NewMockPermissionFactory(ctrl)
+			mockPF.
+				EXPECT().
+				WrapContext(gomock.Any()).
+				Return(mockPF).
+				AnyTimes()
+
+			input := NewBetweenAccountsInput(
+				sourceAccountEur,      // from this accounts
+				destinationAccountUsd, // to account of the same user in another currency
+				revenueAccountEur,     // exchange margin fee must be credited to this revenue account
+				str2Dec("10"),         // exchange margin is 10%
+				nil,                   // no transfer fee
+			)
+			// 100 EUR -> to -> USD
+			rqs := request("100", "EUR", "USD")
+			rqs.Rate = pointer.ToDecimal(str2Dec("1.10")) // rate EUR/USD = 1.10
+
+			unit := NewBetweenAccounts("CONVERT", currencyBox, input, nil, mockPF)
+
+			details, err := unit.Evaluate(rqs)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(details).To(HaveLen(4))
+			Expect(details).To(HaveKey(constants.PurposeConvertOutgoing))
+			Expect(details).To(HaveKey(constants.PurposeConvertIncoming))
+			Expect(details).To(HaveKey(constants.PurposeFeeExchangeMargin))
+			Expect(details).To(HaveKey(constants.PurposeRevenueExchangeMargin))
+			Expect(sourceAccountEur.Balance).To(decEqual(str2Dec("900")))
+			Expect(revenueAccountEur.Balance).To(decEqual(str2Dec("10")))
+			Expect(destinationAccountUsd.Balance).To(decEqual(str2Dec("99")))
+
+			// main transactions of convert are recognized in transactions history
+			Expect(details[constants.PurposeConvertOutgoing].Transaction.IsTargetOutgoing()).To(BeTrue())
+			Expect(details[constants.PurposeConvertIncoming].Transaction.IsIncoming()).To(BeTrue())
+		})
 	})
 })
 
@@ -17,8 +17,14 @@ const (
 	ErrSubjectNotSupported    = Error("subject not supported")
 	ErrMissingRequestData     = Error("required request data is missing")
 
-	ErrWithdrawalNotAllowed = Error(errcodes.CodeWithdrawalNotAllowed)
-	ErrDepositNotAllowed    = Error(errcodes.CodeDepositNotAllowed)
-	ErrInsufficientBalance  = Error(errcodes.CodeInsufficientFunds)
-	ErrAccountInactive      = Error(errcodes.CodeAccountInactive)
+	ErrWithdrawalNotAllowed   = Error(errcodes.CodeWithdrawalNotAllowed)
+	ErrDepositNotAllowed      = Error(errcodes.CodeDepositNotAllowed)
+	ErrInsufficientBalance    = Error(errcodes.CodeInsufficientFunds)
+	ErrAccountInactive        = Error(errcodes.CodeAccountInactive)
+	ErrCardExpired            = Error(errcodes.CodeCardExpired)
+	ErrCardCurrencyNotAllowed = Error(errcodes.CodeCardCurrencyNotAllowed)
+	ErrFeeExceedsAmount       = Error(errcodes.CodeFeeExceedsAmount)
+	ErrTermDepositNotMatured  = Error(errcodes.CodeTermDepositNotMatured)
+	ErrAccountDormant         = Error(errcodes.CodeAccountDormant)
+	ErrAccountClosed          = Error(errcodes.CodeAccountClosed)
 )
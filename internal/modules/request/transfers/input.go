@@ -77,6 +77,25 @@ func transferFeeParamsFromRequest(request *model.Request) (result *fee.TransferF
 		}
 		result.Max = max
 
+		// tiers, discount and free flag are optional as they are absent in input of earlier requests
+		if tiersParam, ok := sm["tiers"]; ok {
+			tiers, err := transferFeeTiersFromInterface(tiersParam)
+			if err != nil {
+				return result, err
+			}
+			result.Tiers = tiers
+		}
+		if discountParam, ok := sm["discountPercent"]; ok {
+			discount, err := decimalFromInterface(discountParam, "transferFeeParams.discountPercent")
+			if err != nil {
+				return result, err
+			}
+			result.DiscountPercent = discount
+		}
+		if free, ok := sm["free"].(bool); ok {
+			result.Free = free
+		}
+
 	case fee.TransferFeeParams:
 		tfp := param
 		result = &tfp
@@ -92,6 +111,41 @@ func transferFeeParamsFromRequest(request *model.Request) (result *fee.TransferF
 	return
 }
 
+func transferFeeTiersFromInterface(param interface{}) ([]fee.TransferFeeTier, error) {
+	items, ok := param.([]interface{})
+	if !ok {
+		if param == nil {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(
+			ErrMissingInputData,
+			`parameter "transferFeeParams.tiers" has wrong type, expected type is "[]interface{}" but got "%T"`,
+			param,
+		)
+	}
+	tiers := make([]fee.TransferFeeTier, len(items))
+	for i, item := range items {
+		tier, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.Wrapf(
+				ErrMissingInputData,
+				`parameter "transferFeeParams.tiers" item has wrong type, expected type is "map[string]interface{}" but got "%T"`,
+				item,
+			)
+		}
+		from, err := decimalFromInterface(tier["from"], "transferFeeParams.tiers.from")
+		if err != nil {
+			return nil, err
+		}
+		percent, err := decimalFromInterface(tier["percent"], "transferFeeParams.tiers.percent")
+		if err != nil {
+			return nil, err
+		}
+		tiers[i] = fee.TransferFeeTier{From: from, Percent: percent}
+	}
+	return tiers, nil
+}
+
 func getAccountWithTypeForUpdateById(db *gorm.DB, accountId int64) (*accountModel.Account, error) {
 	account := &accountModel.Account{}
 	err := db.
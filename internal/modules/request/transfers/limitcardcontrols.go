@@ -0,0 +1,187 @@
+package transfers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/limit"
+	"github.com/Confialink/wallet-accounts/internal/modules/balance"
+	cardModel "github.com/Confialink/wallet-accounts/internal/modules/card/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
+)
+
+// cardControls checks user defined card controls (see card module) for every card
+// which balance is debited or credited by a transfer
+type cardControls struct {
+	details            types.Details
+	limitService       *limit.Service
+	aggregationService *balance.AggregationService
+	dayFrom            time.Time
+	dayTill            time.Time
+	logger             log15.Logger
+}
+
+func NewCardControls(
+	details types.Details,
+	limitService *limit.Service,
+	aggregationService *balance.AggregationService,
+	dayFrom time.Time,
+	dayTill time.Time,
+	logger log15.Logger,
+) PermissionChecker {
+	return &cardControls{
+		details:            details,
+		limitService:       limitService,
+		aggregationService: aggregationService,
+		dayFrom:            dayFrom,
+		dayTill:            dayTill,
+		logger:             logger,
+	}
+}
+
+func (c *cardControls) Check() error {
+	cards := make(map[uint32]*cardModel.Card)
+	amountByCard := make(map[uint32]balance.AggregationResult)
+	// currencies of accounts which take part in the transfer
+	currencies := make(map[string]bool)
+	for _, detail := range c.details {
+		if detail.Account != nil {
+			currencies[detail.CurrencyCode] = true
+		}
+		if detail.Card == nil || detail.Card.Id == nil {
+			continue
+		}
+		id := *detail.Card.Id
+		cards[id] = detail.Card
+		amountByCard[id] = append(
+			amountByCard[id],
+			balance.AggregationItem{ItemAmount: detail.Amount.Abs(), ItemCurrencyCode: detail.CurrencyCode},
+		)
+	}
+
+	for id := range cards {
+		lims, err := c.limitService.Find(limit.Identifier{
+			Entity:   cardModel.ControlsLimitEntity,
+			EntityId: fmt.Sprint(id),
+		})
+		if errors.Cause(err) == limit.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to check card controls: limit service returned error")
+		}
+
+		allowed := make([]string, 0)
+		for _, lim := range lims {
+			if lim.Available().NoLimit() {
+				continue
+			}
+			switch name := lim.Identifier().Name; {
+			case name == cardModel.ControlMaxPerTransaction:
+				err = c.checkMaxPerTransaction(id, lim, amountByCard[id])
+			case name == cardModel.ControlMaxPerDay:
+				err = c.checkMaxPerDay(id, lim, amountByCard[id])
+			case cardModel.IsAllowedCurrencyControl(name):
+				allowed = append(allowed, lim.Available().CurrencyAmount().CurrencyCode())
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := c.checkAllowedCurrencies(id, allowed, currencies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cardControls) Name() string {
+	return "card_controls"
+}
+
+func (c *cardControls) checkMaxPerTransaction(cardId uint32, lim limit.Limit, aggregation balance.AggregationResult) error {
+	limitAmount := lim.Available().CurrencyAmount()
+	total, err := c.aggregationService.Reduce(aggregation, limitAmount.CurrencyCode())
+	if err != nil {
+		return errors.Wrap(err, "failed to check card max per transaction: aggregation service returned error")
+	}
+	if err = lim.WithinLimit(&total); err != nil {
+		if errors.Cause(err) == limit.ErrLimitExceeded {
+			err = errors.Wrapf(
+				err,
+				"card max per transaction control is exceeded: card with id %d has limit %s %s, but the amount is %s %s",
+				cardId,
+				limitAmount.Amount().String(),
+				limitAmount.CurrencyCode(),
+				total.ItemAmount.String(),
+				total.ItemCurrencyCode,
+			)
+			c.logger.Info(err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *cardControls) checkMaxPerDay(cardId uint32, lim limit.Limit, aggregation balance.AggregationResult) error {
+	limitAmount := lim.Available().CurrencyAmount()
+	totalPerDay, err := c.aggregationService.TotalByCardPerPeriod(cardId, c.dayFrom, c.dayTill, limitAmount.CurrencyCode())
+	if err != nil {
+		return errors.Wrap(err, "failed to check card max per day: aggregation service returned error")
+	}
+	aggregation = append(aggregation, totalPerDay)
+	totalAfter, err := c.aggregationService.Reduce(aggregation, limitAmount.CurrencyCode())
+	if err != nil {
+		return errors.Wrap(err, "failed to check card max per day: aggregation service failed to reduce total")
+	}
+	if err = lim.WithinLimit(&totalAfter); err != nil {
+		if errors.Cause(err) == limit.ErrLimitExceeded {
+			err = errors.Wrapf(
+				err,
+				"card max per day control is exceeded: card with id %d has limit %s %s, but the amount after the transfer would be %s %s",
+				cardId,
+				limitAmount.Amount().String(),
+				limitAmount.CurrencyCode(),
+				totalAfter.ItemAmount.String(),
+				totalAfter.ItemCurrencyCode,
+			)
+			c.logger.Info(err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// checkAllowedCurrencies makes sure that all currencies involved into the transfer are allowed,
+// empty list of allowed currencies means that any currency is allowed
+func (c *cardControls) checkAllowedCurrencies(cardId uint32, allowed []string, currencies map[string]bool) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for code := range currencies {
+		found := false
+		for _, allowedCode := range allowed {
+			if strings.EqualFold(code, allowedCode) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := errors.Wrapf(
+				ErrCardCurrencyNotAllowed,
+				"card with id %d allows only the following currencies: %s, got %s",
+				cardId,
+				strings.Join(allowed, ", "),
+				code,
+			)
+			c.logger.Info(err.Error())
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package transfers
 import (
 	"github.com/Confialink/wallet-accounts/internal/exchange"
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	requestConstants "github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	"github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
 	txModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
@@ -204,10 +205,12 @@ func (o *OutgoingWireTransfer) Cancel(request *model.Request, reason string) err
 		return err
 	}
 	o.transactions = transactions
+	released := decimal.Zero
 	for _, t := range transactions {
 		t.Status = pointer.ToString("cancelled")
 		if t.AccountId != nil && *t.AccountId == sourceAccount.ID {
 			sourceAccount.AvailableAmount = sourceAccount.AvailableAmount.Add(t.Amount.Neg())
+			released = released.Add(t.Amount.Neg())
 		}
 	}
 
@@ -215,6 +218,12 @@ func (o *OutgoingWireTransfer) Cancel(request *model.Request, reason string) err
 	if err != nil {
 		return err
 	}
+	if sourceAccount.IsClosed() && released.IsPositive() {
+		err = o.moveToRevenue(request, sourceAccount, released)
+		if err != nil {
+			return err
+		}
+	}
 	// update source and destination account balances
 	err = updateAccount(o.db, sourceAccount)
 	if err != nil {
@@ -228,6 +237,74 @@ func (o *OutgoingWireTransfer) Cancel(request *model.Request, reason string) err
 	return nil
 }
 
+// moveToRevenue credits the revenue account with the amount released by cancellation of the transfer
+// from the closed account, e.g. the closing balance swept by wire which was rejected by the bank.
+// The closed account could not be used anymore, so the amount is held on the revenue account
+// until it is paid out to the owner by admin.
+func (o *OutgoingWireTransfer) moveToRevenue(
+	request *model.Request,
+	sourceAccount *accountModel.Account,
+	amount decimal.Decimal,
+) error {
+	revenueAccount, err := o.input.RevenueAccount()
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve revenue account, request id = %d", *request.Id)
+	}
+	currency, err := o.currencyProvider.Get(sourceAccount.Type.CurrencyCode)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve currency %s", sourceAccount.Type.CurrencyCode)
+	}
+
+	description := pointer.ToString(fmt.Sprintf("Refund of cancelled transfer from closed account %s", sourceAccount.Number))
+	transactions := make([]*txModel.Transaction, 0, 2)
+	err = builder.
+		Debit(amount).
+		From(makeDebitable(currency, &sourceAccount.Balance, &sourceAccount.AvailableAmount)).
+		As("refund").
+		WithCallback(func(action transfer.Action) error {
+			err := action.Perform()
+			transactions = append(transactions, &txModel.Transaction{
+				RequestId:                request.Id,
+				AccountId:                &sourceAccount.ID,
+				Description:              description,
+				Amount:                   pointer.ToDecimal(action.Amount().Neg()),
+				IsVisible:                pointer.ToBool(true),
+				AvailableBalanceSnapshot: pointer.ToDecimal(sourceAccount.AvailableAmount),
+				CurrentBalanceSnapshot:   pointer.ToDecimal(sourceAccount.Balance),
+				Type:                     pointer.ToString("account"),
+				Purpose:                  pointer.ToString(constants.PurposeDebitAccount.String()),
+			})
+			return err
+		}).
+		CreditFromAlias("refund").
+		To(makeCreditable(currency, &revenueAccount.Balance, &revenueAccount.AvailableAmount)).
+		WithCallback(func(action transfer.Action) error {
+			err := action.Perform()
+			transactions = append(transactions, &txModel.Transaction{
+				RequestId:                request.Id,
+				RevenueAccountId:         &revenueAccount.ID,
+				Description:              description,
+				Amount:                   pointer.ToDecimal(action.Amount()),
+				IsVisible:                pointer.ToBool(true),
+				AvailableBalanceSnapshot: pointer.ToDecimal(revenueAccount.AvailableAmount),
+				CurrentBalanceSnapshot:   pointer.ToDecimal(revenueAccount.Balance),
+				Type:                     pointer.ToString("revenue"),
+				Purpose:                  pointer.ToString(constants.PurposeCreditRevenue.String()),
+			})
+			return err
+		}).
+		Execute()
+	if err != nil {
+		return err
+	}
+
+	err = saveTransactions(o.db, transactions, "executed")
+	if err != nil {
+		return err
+	}
+	return updateRevenueAccount(o.db, revenueAccount)
+}
+
 func (o *OutgoingWireTransfer) Modify(request *model.Request) (types.Details, error) {
 	if *request.Status != "pending" {
 		return nil, errors.Wrapf(
@@ -448,6 +525,34 @@ func (o *OutgoingWireTransfer) evaluate(
 		return nil, errors.Wrapf(err, "failed to retrieve exchange margin, request id = %d", request.Id)
 	}
 
+	feeParams, err := input.TransferFeeParams()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve transfer fee params, request id = %d", request.Id)
+	}
+	chargeBearer, err := input.ChargeBearer()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve charge bearer, request id = %d", request.Id)
+	}
+
+	// if the beneficiary bears charges the fee is deducted from the sent amount,
+	// so that the sender is debited by the converted amount in total
+	outgoingAmount := transfer.CurrencyAmount(convertedAmount)
+	var feeAmount transfer.CurrencyAmount
+	if feeParams != nil {
+		feeAmount = fee.NewTransferFeeAmount(*feeParams, convertedAmount)
+		if chargeBearer == requestConstants.ChargeBearerBeneficiary {
+			if feeAmount.Amount().GreaterThanOrEqual(convertedAmount.Amount()) {
+				return nil, errors.Wrapf(
+					ErrFeeExceedsAmount,
+					"transfer fee %s must be less than the amount %s",
+					feeAmount.Amount().String(),
+					convertedAmount.Amount().String(),
+				)
+			}
+			outgoingAmount = transfer.NewAmountDifference(convertedAmount, feeAmount)
+		}
+	}
+
 	chain := builder.New()
 	marginFeeApplied := false
 	exchangeMarginMultiplier := exchangeMarginPercent.Div(decimal.NewFromInt(100))
@@ -488,7 +593,7 @@ func (o *OutgoingWireTransfer) evaluate(
 	}
 
 	chain.
-		Debit(convertedAmount).
+		Debit(outgoingAmount).
 		From(source).
 		IncludeToGroup("showAmount").
 		WithCallback(func(action transfer.Action) error {
@@ -532,13 +637,7 @@ func (o *OutgoingWireTransfer) evaluate(
 			return nil
 		})
 
-	feeParams, err := input.TransferFeeParams()
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve transfer fee params, request id = %d", request.Id)
-	}
-
-	if feeParams != nil {
-		feeAmount := fee.NewTransferFeeAmount(*feeParams, convertedAmount)
+	if feeAmount != nil {
 		description := "Transfer Fee: OWT Fee"
 		chain.
 			Debit(feeAmount).
@@ -612,6 +711,17 @@ func (o *OutgoingWireTransfer) evaluate(
 			})
 	}
 
+	if chargeBearer == requestConstants.ChargeBearerOur {
+		ourChargePercent, err := input.OurChargePercent()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve OUR charge, request id = %d", request.Id)
+		}
+		if ourChargePercent.GreaterThan(decimal.NewFromInt(0)) {
+			o.addOurCharge(chain, details, request, sourceAccount, revenueAccount, source, revenue,
+				transfer.NewAmountMultiplier(convertedAmount, ourChargePercent.Div(decimal.NewFromInt(100))))
+		}
+	}
+
 	if marginFeeApplied {
 		chain.
 			CreditFromAlias("exchangeMargin").
@@ -647,6 +757,84 @@ func (o *OutgoingWireTransfer) evaluate(
 	return details, chain.Execute()
 }
 
+// addOurCharge debits the sender by fees of correspondent and beneficiary banks if the sender bears all charges,
+// the charge is collected to the revenue account so that the beneficiary receives the full sent amount
+func (o *OutgoingWireTransfer) addOurCharge(
+	chain *builder.Transfer,
+	details types.Details,
+	request *model.Request,
+	sourceAccount *accountModel.Account,
+	revenueAccount *accountModel.RevenueAccountModel,
+	source transfer.Debitable,
+	revenue transfer.Creditable,
+	chargeAmount transfer.CurrencyAmount,
+) {
+	description := "Transfer Fee: OWT Charges of Other Banks"
+	chain.
+		Debit(chargeAmount).
+		From(source).
+		WithCallback(func(action transfer.Action) error {
+			err := action.Perform()
+			currency := action.Currency()
+			if err != nil {
+				return err
+			}
+			transaction := &txModel.Transaction{
+				RequestId:                request.Id,
+				AccountId:                &sourceAccount.ID,
+				Description:              &description,
+				Amount:                   pointer.ToDecimal(action.Amount().Neg()),
+				IsVisible:                pointer.ToBool(true),
+				AvailableBalanceSnapshot: pointer.ToDecimal(sourceAccount.AvailableAmount),
+				CurrentBalanceSnapshot:   pointer.ToDecimal(sourceAccount.Balance),
+				Type:                     pointer.ToString("fee"),
+				Purpose:                  pointer.ToString(constants.PurposeFeeOwtOurCharge.String()),
+			}
+			o.appendTransaction(transaction)
+
+			details[constants.PurposeFeeOwtOurCharge] = &types.Detail{
+				Purpose:      constants.PurposeFeeOwtOurCharge,
+				Amount:       action.Amount().Neg(),
+				CurrencyCode: currency.Code(),
+				Transaction:  transaction,
+				AccountId:    pointer.ToUint64(sourceAccount.ID),
+				Account:      sourceAccount,
+			}
+			return nil
+		}).
+		Credit(chargeAmount).
+		To(revenue).
+		WithCallback(func(action transfer.Action) error {
+			err := action.Perform()
+			currency := action.Currency()
+			if err != nil {
+				return err
+			}
+			transaction := &txModel.Transaction{
+				RequestId:                request.Id,
+				RevenueAccountId:         &revenueAccount.ID,
+				Description:              &description,
+				Amount:                   pointer.ToDecimal(action.Amount()),
+				IsVisible:                pointer.ToBool(true),
+				AvailableBalanceSnapshot: pointer.ToDecimal(revenueAccount.AvailableAmount),
+				CurrentBalanceSnapshot:   pointer.ToDecimal(revenueAccount.Balance),
+				Type:                     pointer.ToString("revenue"),
+				Purpose:                  pointer.ToString(constants.PurposeRevenueOwtOurCharge.String()),
+			}
+			o.appendTransaction(transaction)
+
+			details[constants.PurposeRevenueOwtOurCharge] = &types.Detail{
+				Purpose:          constants.PurposeRevenueOwtOurCharge,
+				Amount:           action.Amount(),
+				CurrencyCode:     currency.Code(),
+				Transaction:      transaction,
+				RevenueAccountId: &revenueAccount.ID,
+				RevenueAccount:   revenueAccount,
+			}
+			return nil
+		})
+}
+
 func (o *OutgoingWireTransfer) outgoingDescription(request *model.Request, outgoingAmount decimal.Decimal) (string, error) {
 	d := "Outgoing Wire Transfer"
 
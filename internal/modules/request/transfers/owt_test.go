@@ -2,16 +2,20 @@ package transfers_test
 
 import (
 	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	requestConstants "github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	. "github.com/Confialink/wallet-accounts/internal/modules/request/transfers"
 	mockTransfers "github.com/Confialink/wallet-accounts/internal/modules/request/transfers/mock"
 	"github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
 	"github.com/Confialink/wallet-accounts/internal/transfer/fee"
 	"github.com/Confialink/wallet-pkg-utils/pointer"
 	"database/sql"
+	"time"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
 	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -79,6 +83,8 @@ var _ = Describe("Transfers", func() {
 				nil,
 				"",
 				"",
+				requestConstants.ChargeBearerShared,
+				str2Dec("0"),
 			)
 			rqs := owtRequest("100", "EUR")
 			owt := NewOutgoingWireTransfer(input, currencyBox, gdb, mockPF)
@@ -111,6 +117,8 @@ var _ = Describe("Transfers", func() {
 				nil,
 				"",
 				"",
+				requestConstants.ChargeBearerShared,
+				str2Dec("0"),
 			)
 			rqs := owtRequest("100", "EUR", "USD")
 			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
@@ -148,6 +156,8 @@ var _ = Describe("Transfers", func() {
 				feeParams,
 				"",
 				"",
+				requestConstants.ChargeBearerShared,
+				str2Dec("0"),
 			)
 			rqs := owtRequest("100", "EUR", "USD")
 			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
@@ -168,6 +178,122 @@ var _ = Describe("Transfers", func() {
 			Expect(revenueAccountEur.AvailableAmount).To(decEqual(str2Dec("19")))
 		})
 
+		It("should deduct transfer fee from the sent amount if the beneficiary bears charges", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockPF := mockTransfers.NewMockPermissionFactory(ctrl)
+			mockPF.
+				EXPECT().
+				WrapContext(gomock.Any()).
+				Return(mockPF).
+				AnyTimes()
+
+			feeParams := &fee.TransferFeeParams{Base: str2Dec("10")}
+			input := NewOwtInput(
+				sourceAccountEur,
+				revenueAccountEur,
+				str2Dec("10"),
+				feeParams,
+				"",
+				"",
+				requestConstants.ChargeBearerBeneficiary,
+				str2Dec("0"),
+			)
+			rqs := owtRequest("100", "EUR", "USD")
+			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
+			owt := NewOutgoingWireTransfer(input, currencyBox, gdb, mockPF)
+
+			details, err := owt.Evaluate(rqs)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(details).To(HaveLen(5))
+			// (100 * 0.9)converted - (10)transfer fee
+			Expect(details[constants.PurposeOWTOutgoing].Amount).To(decEqual(str2Dec("-80")))
+			Expect(details[constants.PurposeFeeTransfer].Amount).To(decEqual(str2Dec("-10")))
+			// 1000 - (100 * 0.9)outgoing including transfer fee - (100 * 0.9 * 0.1)margin
+			Expect(sourceAccountEur.Balance).To(decEqual(str2Dec("901")))
+			Expect(sourceAccountEur.AvailableAmount).To(decEqual(str2Dec("901")))
+			Expect(revenueAccountEur.Balance).To(decEqual(str2Dec("19")))
+		})
+
+		It("should charge the sender with fees of other banks if the sender bears all charges", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockPF := mockTransfers.NewMockPermissionFactory(ctrl)
+			mockPF.
+				EXPECT().
+				WrapContext(gomock.Any()).
+				Return(mockPF).
+				AnyTimes()
+
+			evaluate := func(chargeBearer requestConstants.ChargeBearer) (types.Details, *model.Account) {
+				sourceAccount := account("EUR", "1000")
+				input := NewOwtInput(
+					sourceAccount,
+					revenueAccount("EUR", "0"),
+					str2Dec("0"),
+					&fee.TransferFeeParams{Base: str2Dec("10")},
+					"",
+					"",
+					chargeBearer,
+					str2Dec("2"), // 2% of the sent amount are charged by other banks
+				)
+				rqs := owtRequest("100", "EUR", "USD")
+				rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
+
+				details, err := NewOutgoingWireTransfer(input, currencyBox, gdb, mockPF).Evaluate(rqs)
+				Expect(err).ShouldNot(HaveOccurred())
+				return details, sourceAccount
+			}
+
+			shared, sharedAccount := evaluate(requestConstants.ChargeBearerShared)
+			Expect(shared).NotTo(HaveKey(constants.PurposeFeeOwtOurCharge))
+			// 1000 - (100 * 0.9)outgoing - (10)transfer fee
+			Expect(sharedAccount.Balance).To(decEqual(str2Dec("900")))
+
+			our, ourAccount := evaluate(requestConstants.ChargeBearerOur)
+			Expect(our).To(HaveLen(len(shared) + 2))
+			Expect(our[constants.PurposeOWTOutgoing].Amount).To(decEqual(str2Dec("-90")))
+			Expect(our[constants.PurposeFeeTransfer].Amount).To(decEqual(str2Dec("-10")))
+			Expect(our[constants.PurposeFeeOwtOurCharge].Amount).To(decEqual(str2Dec("-1.8")))
+			Expect(our[constants.PurposeRevenueOwtOurCharge].Amount).To(decEqual(str2Dec("1.8")))
+			// 1000 - (100 * 0.9)outgoing - (10)transfer fee - (100 * 0.9 * 0.02)charges of other banks
+			Expect(ourAccount.Balance).To(decEqual(str2Dec("898.2")))
+			Expect(our.SumByAccountId(ourAccount.ID)).To(decEqual(str2Dec("-101.8")))
+		})
+
+		It("should fail if transfer fee borne by the beneficiary exceeds the sent amount", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockPF := mockTransfers.NewMockPermissionFactory(ctrl)
+			mockPF.
+				EXPECT().
+				WrapContext(gomock.Any()).
+				Return(mockPF).
+				AnyTimes()
+
+			feeParams := &fee.TransferFeeParams{Base: str2Dec("100")}
+			input := NewOwtInput(
+				sourceAccountEur,
+				revenueAccountEur,
+				str2Dec("0"),
+				feeParams,
+				"",
+				"",
+				requestConstants.ChargeBearerBeneficiary,
+				str2Dec("0"),
+			)
+			rqs := owtRequest("100", "EUR", "USD")
+			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
+			owt := NewOutgoingWireTransfer(input, currencyBox, gdb, mockPF)
+
+			_, err := owt.Evaluate(rqs)
+			Expect(errors.Cause(err)).To(Equal(ErrFeeExceedsAmount))
+			Expect(sourceAccountEur.Balance).To(decEqual(str2Dec("1000")))
+		})
+
 		It("should make pending transfer request", func() {
 			ctrl := gomock.NewController(GinkgoT())
 			defer ctrl.Finish()
@@ -313,6 +439,8 @@ var _ = Describe("Transfers", func() {
 				feeParams,
 				"",
 				"",
+				requestConstants.ChargeBearerShared,
+				str2Dec("0"),
 			)
 			rqs := owtRequest("100", "EUR", "USD")
 			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
@@ -485,6 +613,8 @@ var _ = Describe("Transfers", func() {
 				feeParams,
 				"",
 				"",
+				requestConstants.ChargeBearerShared,
+				str2Dec("0"),
 			)
 			rqs := owtRequest("100", "EUR", "USD")
 			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
@@ -766,6 +896,88 @@ var _ = Describe("Transfers", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("should move amount released by cancellation from closed account to revenue account", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			mockPF := mockTransfers.NewMockPermissionFactory(ctrl)
+			mockPF.
+				EXPECT().
+				WrapContext(gomock.Any()).
+				Return(mockPF).
+				AnyTimes()
+
+			mock.ExpectBegin()
+			tx := gdb.Begin()
+
+			// the closing balance stays reserved by the sweep transfer
+			sourceAccRows := sqlmock.NewRows([]string{
+				"id",
+				"number",
+				"type_id",
+				"user_id",
+				"available_amount",
+				"balance",
+				"is_active",
+				"closed_at",
+			})
+			sourceAccRows.AddRow(1, "EUR_1", 1, "user-uid", "0", "99", false, time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC))
+
+			txRows := sqlmock.NewRows([]string{"id", "request_id", "account_id", "revenue_account_id", "status", "amount", "purpose"})
+			txRows.AddRow(1, 100, 1, nil, "pending", "-9", "fee_exchange_margin")
+			txRows.AddRow(2, 100, 1, nil, "pending", "-90", "owt_outgoing")
+			txRows.AddRow(3, 100, nil, 3, "pending", "9", "revenue_exchange_margin")
+
+			mock.
+				ExpectQuery("^SELECT (.+) FROM `transactions` WHERE (.+)").
+				WithArgs(100).
+				WillReturnRows(txRows)
+			mock.
+				ExpectQuery("SELECT (.+) FROM `accounts` WHERE `accounts`.`id` .* FOR UPDATE").
+				WithArgs(1).
+				WillReturnRows(sourceAccRows)
+			mock.ExpectQuery("SELECT (.+) FROM `account_types`.*").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "currency_code"}).AddRow(1, "EUR_TYPE", "EUR"))
+
+			mock.ExpectExec("UPDATE `transactions`.*").
+				WithArgs("cancelled", 100).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			// select revenue account
+			mock.ExpectQuery("SELECT (.+) FROM `revenue_accounts`.*").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code", "balance", "available_amount"}).AddRow(3, "EUR", "0", "0"))
+			// debit closed account and credit revenue account
+			mock.ExpectExec("INSERT INTO `transactions`.*").
+				WillReturnResult(sqlmock.NewResult(4, 1))
+			mock.ExpectExec("INSERT INTO `transactions`.*").
+				WillReturnResult(sqlmock.NewResult(5, 1))
+			mock.ExpectExec("UPDATE `revenue_accounts`.*").
+				// available_amount, balance, id
+				WithArgs(str2Dec("99"), str2Dec("99"), 3).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			// update source account
+			mock.ExpectExec("UPDATE `accounts`.*").
+				// available_amount, balance, id
+				WithArgs(str2Dec("0"), str2Dec("0"), 1).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("UPDATE `requests`.*").
+				WithArgs("cancelled", "rejected", AnyTime{}, 100).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			rqs := owtRequest("90", "EUR", "USD")
+			rqs.Status = pointer.ToString("pending")
+			rqs.Rate = pointer.ToDecimal(str2Dec("0.9"))
+			rqs.GetInput().Set("sourceAccountId", 1)
+			rqs.GetInput().Set("revenueAccountId", 3)
+
+			input := NewDbOWTInput(tx, rqs, nil)
+			unit := NewOutgoingWireTransfer(input, currencyBox, tx, mockPF)
+
+			err := unit.Cancel(rqs, "rejected")
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
 		It("should modify pending transaction", func() {
 			ctrl := gomock.NewController(GinkgoT())
 			defer ctrl.Finish()
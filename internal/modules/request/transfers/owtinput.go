@@ -3,6 +3,7 @@ package transfers
 import (
 	"github.com/Confialink/wallet-accounts/internal/conv"
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	requestConstants "github.com/Confialink/wallet-accounts/internal/modules/request/constants"
 	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	"github.com/Confialink/wallet-accounts/internal/transfer/fee"
 	"github.com/jinzhu/gorm"
@@ -17,6 +18,8 @@ type OWTInput interface {
 	TransferFeeParams() (*fee.TransferFeeParams, error)
 	BeneficiaryCustomerAccountName() (string, error)
 	RefMessage() (string, error)
+	ChargeBearer() (requestConstants.ChargeBearer, error)
+	OurChargePercent() (decimal.Decimal, error)
 }
 
 type owtInput struct {
@@ -26,6 +29,8 @@ type owtInput struct {
 	transferFeeParams              *fee.TransferFeeParams
 	beneficiaryCustomerAccountName string
 	refMessage                     string
+	chargeBearer                   requestConstants.ChargeBearer
+	ourChargePercent               decimal.Decimal
 }
 
 type OWTInputCache struct {
@@ -35,6 +40,8 @@ type OWTInputCache struct {
 	TransferFeeParams              *fee.TransferFeeParams
 	BeneficiaryCustomerAccountName *string
 	RefMessage                     *string
+	ChargeBearer                   *requestConstants.ChargeBearer
+	OurChargePercent               *decimal.Decimal
 }
 
 func NewOwtInput(
@@ -44,6 +51,8 @@ func NewOwtInput(
 	transferFeeParams *fee.TransferFeeParams,
 	beneficiaryCustomerAccountName string,
 	refMessage string,
+	chargeBearer requestConstants.ChargeBearer,
+	ourChargePercent decimal.Decimal,
 ) OWTInput {
 	return &owtInput{
 		sourceAccount:                  sourceAccount,
@@ -52,6 +61,8 @@ func NewOwtInput(
 		transferFeeParams:              transferFeeParams,
 		beneficiaryCustomerAccountName: beneficiaryCustomerAccountName,
 		refMessage:                     refMessage,
+		chargeBearer:                   chargeBearer,
+		ourChargePercent:               ourChargePercent,
 	}
 }
 
@@ -79,6 +90,14 @@ func (o *owtInput) RefMessage() (string, error) {
 	return o.refMessage, nil
 }
 
+func (o *owtInput) ChargeBearer() (requestConstants.ChargeBearer, error) {
+	return o.chargeBearer, nil
+}
+
+func (o *owtInput) OurChargePercent() (decimal.Decimal, error) {
+	return o.ourChargePercent, nil
+}
+
 type dbOWTInput struct {
 	db      *gorm.DB
 	request *requestModel.Request
@@ -196,3 +215,39 @@ func (d *dbOWTInput) RefMessage() (result string, err error) {
 	result = param.(string)
 	return
 }
+
+// ChargeBearer returns who pays fees, requests created without it are considered as shared
+func (d *dbOWTInput) ChargeBearer() (requestConstants.ChargeBearer, error) {
+	if d.cache.ChargeBearer != nil {
+		return *d.cache.ChargeBearer, nil
+	}
+	result := requestConstants.DefaultChargeBearer
+	if param, ok := d.request.GetInput()["chargeBearer"]; ok && param != nil {
+		bearer, ok := param.(string)
+		if !ok {
+			return result, errors.Wrap(
+				ErrMissingInputData,
+				`request input field "chargeBearer" must be a string`,
+			)
+		}
+		result = requestConstants.ChargeBearer(bearer)
+	}
+	d.cache.ChargeBearer = &result
+	return result, nil
+}
+
+// OurChargePercent returns charge of other banks collected from the sender,
+// requests created without it are not charged
+func (d *dbOWTInput) OurChargePercent() (result decimal.Decimal, err error) {
+	if d.cache.OurChargePercent != nil {
+		return *d.cache.OurChargePercent, nil
+	}
+	if param, ok := d.request.GetInput()["ourChargePercent"]; ok && param != nil {
+		result, err = decimalFromInterface(param, "ourChargePercent")
+		if err != nil {
+			return
+		}
+	}
+	d.cache.OurChargePercent = &result
+	return result, nil
+}
@@ -1,9 +1,12 @@
 package transfers
 
 import (
+	"time"
+
 	"github.com/Confialink/wallet-accounts/internal/limit"
 	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
 	"github.com/Confialink/wallet-accounts/internal/modules/balance"
+	cardModel "github.com/Confialink/wallet-accounts/internal/modules/card/model"
 	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
 	"github.com/inconshreveable/log15"
@@ -95,6 +98,87 @@ func (w *WithdrawalPermission) Name() string {
 	return "withdrawal_allowed"
 }
 
+// TermDepositPermission checks whether withdrawal from the term deposit is allowed before its maturity,
+// early withdrawals are allowed only if penalty is specified, admins and the system are not restricted
+type TermDepositPermission struct {
+	account *model.Account
+	request *requestModel.Request
+	now     time.Time
+}
+
+// NewTermDepositPermission is TermDepositPermission constructor
+func NewTermDepositPermission(account *model.Account, request *requestModel.Request, now time.Time) *TermDepositPermission {
+	return &TermDepositPermission{account: account, request: request, now: now}
+}
+
+// Check checks whether rule is satisfied
+func (t *TermDepositPermission) Check() error {
+	if t.account.IsMatured(t.now) {
+		return nil
+	}
+	if t.request != nil && !t.request.IsInitiatedByUser() {
+		return nil
+	}
+	if t.account.EarlyWithdrawalPenaltyPercent != nil && t.account.EarlyWithdrawalPenaltyPercent.IsPositive() {
+		return nil
+	}
+	return ErrTermDepositNotMatured
+}
+
+func (t *TermDepositPermission) Name() string {
+	return "term_deposit_matured"
+}
+
+// DormantAccountPermission checks whether outgoing transfer from the account is allowed,
+// owners of dormant accounts are restricted until the account is reactivated, admins and the system are not restricted
+type DormantAccountPermission struct {
+	account *model.Account
+	request *requestModel.Request
+}
+
+// NewDormantAccountPermission is DormantAccountPermission constructor
+func NewDormantAccountPermission(account *model.Account, request *requestModel.Request) *DormantAccountPermission {
+	return &DormantAccountPermission{account: account, request: request}
+}
+
+// Check checks whether rule is satisfied
+func (d *DormantAccountPermission) Check() error {
+	if !d.account.IsDormant() {
+		return nil
+	}
+	if d.request != nil && !d.request.IsInitiatedByUser() {
+		return nil
+	}
+	return ErrAccountDormant
+}
+
+func (d *DormantAccountPermission) Name() string {
+	return "account_not_dormant"
+}
+
+// AccountNotClosedPermission checks whether the account has not been closed,
+// a closed account can not be involved in transfers even if it has been activated again
+type AccountNotClosedPermission struct {
+	account *model.Account
+}
+
+// NewAccountNotClosedPermission is AccountNotClosedPermission constructor
+func NewAccountNotClosedPermission(account *model.Account) *AccountNotClosedPermission {
+	return &AccountNotClosedPermission{account: account}
+}
+
+// Check checks whether rule is satisfied
+func (a *AccountNotClosedPermission) Check() error {
+	if a.account.IsClosed() {
+		return errors.Wrapf(ErrAccountClosed, "account id %d", a.account.ID)
+	}
+	return nil
+}
+
+func (a *AccountNotClosedPermission) Name() string {
+	return "account_not_closed"
+}
+
 // AccountActivePermission checks whether account is active
 type AccountActivePermission struct {
 	account *model.Account
@@ -123,6 +207,29 @@ func (a *AccountActivePermission) Name() string {
 	return "account_active"
 }
 
+// CardNotExpiredPermission checks whether card is not expired
+type CardNotExpiredPermission struct {
+	card *cardModel.Card
+	now  time.Time
+}
+
+// NewCardNotExpiredPermission is CardNotExpiredPermission constructor
+func NewCardNotExpiredPermission(card *cardModel.Card, now time.Time) *CardNotExpiredPermission {
+	return &CardNotExpiredPermission{card: card, now: now}
+}
+
+// Check checks whether rule is satisfied
+func (c *CardNotExpiredPermission) Check() error {
+	if c.card.IsExpired(c.now) {
+		return ErrCardExpired
+	}
+	return nil
+}
+
+func (c *CardNotExpiredPermission) Name() string {
+	return "card_not_expired"
+}
+
 // PermissionFactory is used in order to define permissions
 type PermissionFactory interface {
 	CreatePermission(request *requestModel.Request, details types.Details) (PermissionChecker, error)
@@ -160,7 +267,15 @@ func (d *defaultPermissionFactory) CreatePermission(request *requestModel.Reques
 
 	debitAccounts := make(map[uint64]*model.Account)
 	creditAccounts := make(map[uint64]*model.Account)
+	creditCards := make(map[uint32]*cardModel.Card)
+	cardsInvolved := false
 	for _, detail := range details {
+		if detail.Card != nil {
+			cardsInvolved = true
+		}
+		if detail.Card != nil && detail.Card.Id != nil && detail.IsCredit() {
+			creditCards[*detail.Card.Id] = detail.Card
+		}
 		if detail.Account != nil {
 			if detail.IsCredit() {
 				creditAccounts[detail.Account.ID] = detail.Account
@@ -177,6 +292,8 @@ func (d *defaultPermissionFactory) CreatePermission(request *requestModel.Reques
 			permissions,
 			NewAccountActivePermission(account),
 			NewWithdrawalPermission(account),
+			NewTermDepositPermission(account, request, time.Now()),
+			NewDormantAccountPermission(account, request),
 			NewSufficientBalancePermission(requestedAmount, availableAmount),
 		)
 	}
@@ -184,9 +301,20 @@ func (d *defaultPermissionFactory) CreatePermission(request *requestModel.Reques
 		permissions = append(
 			permissions,
 			NewAccountActivePermission(account),
+			NewAccountNotClosedPermission(account),
 			NewDepositPermission(account),
 		)
 	}
+	for _, card := range creditCards {
+		permissions = append(permissions, NewCardNotExpiredPermission(card, time.Now()))
+	}
+
+	if cardsInvolved {
+		permissions = append(
+			permissions,
+			NewCardControls(details, limitService, aggregationService, now.BeginningOfDay(), now.EndOfDay(), d.logger),
+		)
+	}
 
 	if LimitMaxTotalBalanceEnabled {
 		permissions = append(
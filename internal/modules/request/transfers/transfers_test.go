@@ -99,6 +99,82 @@ var _ = Describe("Transfers", func() {
 				Expect(errors.Cause(err)).To(Equal(ErrAccountInactive))
 			})
 		})
+		When("term deposit is not matured", func() {
+			It("should raise error unless penalty is specified", func() {
+				acc := account("EUR", "100")
+				maturityDate := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+				acc.MaturityDate = &maturityDate
+				request := &model.Request{
+					IsInitiatedByAdmin:  pointer.ToBool(false),
+					IsInitiatedBySystem: pointer.ToBool(false),
+				}
+
+				beforeMaturity := maturityDate.Add(-time.Hour)
+				err := NewTermDepositPermission(acc, request, beforeMaturity).Check()
+				Expect(err).Should(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(ErrTermDepositNotMatured))
+
+				Expect(NewTermDepositPermission(acc, request, maturityDate).Check()).To(Succeed())
+
+				request.IsInitiatedByAdmin = pointer.ToBool(true)
+				Expect(NewTermDepositPermission(acc, request, beforeMaturity).Check()).To(Succeed())
+
+				request.IsInitiatedByAdmin = pointer.ToBool(false)
+				penalty := decimal.NewFromInt(2)
+				acc.EarlyWithdrawalPenaltyPercent = &penalty
+				Expect(NewTermDepositPermission(acc, request, beforeMaturity).Check()).To(Succeed())
+			})
+		})
+		When("account is dormant", func() {
+			It("should raise error for requests initiated by user", func() {
+				acc := account("EUR", "100")
+				request := &model.Request{
+					IsInitiatedByAdmin:  pointer.ToBool(false),
+					IsInitiatedBySystem: pointer.ToBool(false),
+				}
+				Expect(NewDormantAccountPermission(acc, request).Check()).To(Succeed())
+
+				dormantAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+				acc.DormantAt = &dormantAt
+				err := NewDormantAccountPermission(acc, request).Check()
+				Expect(err).Should(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(ErrAccountDormant))
+
+				request.IsInitiatedBySystem = pointer.ToBool(true)
+				Expect(NewDormantAccountPermission(acc, request).Check()).To(Succeed())
+			})
+		})
+		When("account is closed", func() {
+			It("should raise error", func() {
+				acc := account("EUR", "100")
+				Expect(NewAccountNotClosedPermission(acc).Check()).To(Succeed())
+
+				closedAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+				acc.ClosedAt = &closedAt
+				err := NewAccountNotClosedPermission(acc).Check()
+				Expect(err).Should(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(ErrAccountClosed))
+			})
+		})
+		When("card is expired", func() {
+			It("should raise error", func() {
+				c := card("EUR", "100")
+				c.ExpirationYear = pointer.ToInt32(2020)
+				c.ExpirationMonth = pointer.ToInt32(5)
+
+				lastValidDay := time.Date(2020, 5, 31, 23, 59, 0, 0, time.UTC)
+				Expect(NewCardNotExpiredPermission(c, lastValidDay).Check()).To(Succeed())
+
+				firstExpiredDay := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+				err := NewCardNotExpiredPermission(c, firstExpiredDay).Check()
+				Expect(err).Should(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(ErrCardExpired))
+
+				c.Status = pointer.ToString("expired")
+				err = NewCardNotExpiredPermission(c, lastValidDay).Check()
+				Expect(errors.Cause(err)).To(Equal(ErrCardExpired))
+			})
+		})
 		It(`should check "SufficientBalancePermission"`, func() {
 			type testData struct {
 				requested int64
@@ -626,6 +702,75 @@ var _ = Describe("Transfers", func() {
 			Expect(maxDebitPermission.Check()).To(Succeed())
 		})
 
+		It("should check card controls", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+
+			acc := account("USD", "10000")
+			c := card("USD", "0")
+
+			details := types.Details{
+				txConstants.Purpose("debit1"): {
+					Amount:       dec(-300),
+					CurrencyCode: "USD",
+					Account:      acc,
+				},
+				txConstants.Purpose("credit1"): {
+					Amount:       dec(300),
+					CurrencyCode: "USD",
+					Card:         c,
+				},
+			}
+
+			cardId := limit.Identifier{Entity: "card", EntityId: "234"}
+			limitStorage := mockLimit.NewMockStorage(ctrl)
+			limitStorage.
+				EXPECT().
+				Find(cardId).
+				Return([]limit.Model{
+					{
+						Identifier: limit.Identifier{Name: "card_max_per_transaction", Entity: "card", EntityId: "234"},
+						Value:      limit.Val(dec(300), "USD"),
+					},
+					{
+						Identifier: limit.Identifier{Name: "card_allowed_currency:USD", Entity: "card", EntityId: "234"},
+						Value:      limit.Val(dec(0), "USD"),
+					},
+					{
+						Identifier: limit.Identifier{Name: "card_allowed_currency:EUR", Entity: "card", EntityId: "234"},
+						Value:      limit.Val(dec(0), "EUR"),
+					},
+				}, nil).
+				AnyTimes()
+			limitService := limit.NewService(limitStorage, limit.NewFactory())
+
+			rateSource := exchange.NewDirectRateSource()
+			_ = rateSource.Set(exchange.NewRate("EUR", "USD", dec(1)))
+			_ = rateSource.Set(exchange.NewRate("USD", "EUR", dec(1)))
+			aggregationService := balance.NewAggregationService(
+				balance.NewDefaultReducer(rateSource),
+				mockBalance.NewMockAggregationFactory(ctrl),
+			)
+			now := time.Now()
+
+			cardControls := NewCardControls(details, limitService, aggregationService, now, now, &mockLogger{})
+			Expect(cardControls.Check()).To(Succeed())
+
+			details[txConstants.Purpose("credit1")].Amount = dec(301)
+			err := cardControls.Check()
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Cause(err)).To(Equal(limit.ErrLimitExceeded))
+
+			details[txConstants.Purpose("credit1")].Amount = dec(300)
+			details[txConstants.Purpose("debit1")].CurrencyCode = "EUR"
+			Expect(cardControls.Check()).To(Succeed())
+
+			details[txConstants.Purpose("debit1")].CurrencyCode = "GBP"
+			err = cardControls.Check()
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Cause(err)).To(Equal(ErrCardCurrencyNotAllowed))
+		})
+
 		It("should check max total credit per transfer limit", func() {
 			ctrl := gomock.NewController(GinkgoT())
 			defer ctrl.Finish()
@@ -882,6 +1027,9 @@ var _ = Describe("Transfers", func() {
 			expectedPermissions := map[string]int{
 				"deposit_allowed":          1,
 				"withdrawal_allowed":       1,
+				"term_deposit_matured":     1,
+				"account_not_dormant":      1,
+				"account_not_closed":       1,
 				"sufficient_balance":       1,
 				"account_active":           2,
 				LimitMaxTotalBalance:       btoi[LimitMaxTotalBalanceEnabled],
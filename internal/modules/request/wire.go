@@ -0,0 +1,356 @@
+package request
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	errorsPkg "github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/service/wire"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	"github.com/Confialink/wallet-accounts/internal/transfer"
+)
+
+const (
+	wireDateLayout = "2006-01-02"
+	// random part of message id, 7 hex characters are left by the date within 16 characters of MT103 reference
+	wireMessageIdRandomSize   = 4
+	wireMessageIdRandomLength = 7
+)
+
+var wireUserFields = []string{
+	"UID",
+	"IsCorporate",
+	"CompanyDetails.CompanyName",
+	"FirstName",
+	"LastName",
+}
+
+// WireFile is an exported bank file
+type WireFile struct {
+	Name      string
+	Reference string
+	Content   []byte
+}
+
+// WireStatusFailure describes a status which could not be applied
+type WireStatusFailure struct {
+	EndToEndId string `json:"endToEndId"`
+	Error      string `json:"error"`
+}
+
+// WireStatusImport is a result of statuses import
+type WireStatusImport struct {
+	Executed  uint64               `json:"executed"`
+	Cancelled uint64               `json:"cancelled"`
+	Failures  []*WireStatusFailure `json:"failures"`
+}
+
+// WireService exports pending outgoing wire transfers into bank files and applies statuses reported by the bank
+type WireService struct {
+	db               *gorm.DB
+	settings         *settings.Service
+	requestsService  *service.RequestsService
+	executor         *service.Executor
+	canceller        *service.Canceller
+	currencyProvider transfer.CurrencyProvider
+	logger           log15.Logger
+	now              func() time.Time
+}
+
+func NewWireService(
+	db *gorm.DB,
+	settings *settings.Service,
+	requestsService *service.RequestsService,
+	executor *service.Executor,
+	canceller *service.Canceller,
+	currencyProvider transfer.CurrencyProvider,
+	logger log15.Logger,
+) *WireService {
+	return &WireService{
+		db:               db,
+		settings:         settings,
+		requestsService:  requestsService,
+		executor:         executor,
+		canceller:        canceller,
+		currencyProvider: currencyProvider,
+		logger:           logger.New("service", "WireService"),
+		now:              time.Now,
+	}
+}
+
+// Export generates the bank file of the given requests and marks them as exported,
+// requests which have been already exported are rejected in order to avoid double sending
+func (s *WireService) Export(exportForm *form.WireExport) (*WireFile, error) {
+	orderingBic, _ := s.settings.String(SettingWireOrderingBicString)
+	orderingBic = strings.ToUpper(strings.TrimSpace(orderingBic))
+	if !wire.IsBic(orderingBic) {
+		return nil, errcodes.CreatePublicError(errcodes.CodeWireOrderingBicMissing)
+	}
+	initiatingParty, _ := s.settings.String(SettingWireInitiatingPartyString)
+
+	now := s.now()
+	executionDate := now
+	if exportForm.ExecutionDate != nil {
+		date, err := time.Parse(wireDateLayout, *exportForm.ExecutionDate)
+		if err != nil {
+			return nil, errorsPkg.Wrap(err, "invalid execution date")
+		}
+		executionDate = date
+	}
+
+	messageId, err := wireMessageId(now)
+	if err != nil {
+		return nil, err
+	}
+	batch := &wire.Batch{
+		MessageId:           messageId,
+		CreatedAt:           now,
+		ExecutionDate:       executionDate,
+		OrderingBic:         orderingBic,
+		InitiatingPartyName: initiatingParty,
+	}
+
+	tx := s.db.Begin()
+	requests, err := s.loadExportableRequests(tx, exportForm.RequestIds)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, request := range requests {
+		currency, err := s.currencyProvider.Get(*request.ReferenceCurrencyCode)
+		if err != nil {
+			tx.Rollback()
+			return nil, errorsPkg.Wrapf(err, "failed to retrieve currency %s", *request.ReferenceCurrencyCode)
+		}
+		batch.Payments = append(batch.Payments, newWirePayment(request, currency))
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := wire.Write(buf, exportForm.Format, batch); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.
+		Model(&model.DataOwt{}).
+		Where("request_id IN (?)", exportForm.RequestIds).
+		Updates(map[string]interface{}{"exported_at": now, "export_reference": batch.MessageId}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return nil, errorsPkg.Wrap(err, "failed to mark requests as exported")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to commit export")
+	}
+
+	return &WireFile{
+		Name:      batch.MessageId + wire.FileExtension(exportForm.Format),
+		Reference: batch.MessageId,
+		Content:   buf.Bytes(),
+	}, nil
+}
+
+// loadExportableRequests locks the given requests and checks that all of them could be exported
+func (s *WireService) loadExportableRequests(tx *gorm.DB, ids []uint64) ([]*model.Request, error) {
+	var requests []*model.Request
+	err := tx.
+		Set("gorm:query_option", "FOR UPDATE").
+		Preload("Transactions").
+		Preload("DataOwt").
+		Preload("DataOwt.SourceAccount").
+		Preload("DataOwt.BankDetails").
+		Preload("DataOwt.BeneficiaryCustomer").
+		Preload("DataOwt.IntermediaryBankDetails").
+		Where("id IN (?)", ids).
+		Order("id").
+		Find(&requests).
+		Error
+	if err != nil {
+		return nil, errorsPkg.Wrap(err, "failed to load requests")
+	}
+
+	found := make(map[uint64]bool, len(requests))
+	errs := make(wire.ValidationErrors, 0)
+	for _, request := range requests {
+		found[*request.Id] = true
+		fields := make([]string, 0)
+		if !request.Subject.EqualsTo(constants.SubjectTransferOutgoingWireTransfer) || request.DataOwt == nil {
+			fields = append(fields, "subject")
+		} else if request.DataOwt.ExportedAt != nil {
+			fields = append(fields, "exportedAt")
+		}
+		if *request.Status != constants.StatusPending {
+			fields = append(fields, "status")
+		}
+		if len(fields) > 0 {
+			errs = append(errs, &wire.ValidationError{EndToEndId: wireEndToEndId(request), Fields: fields})
+		}
+	}
+	for _, id := range ids {
+		if !found[id] {
+			errs = append(errs, &wire.ValidationError{EndToEndId: strconv.FormatUint(id, 10), Fields: []string{"id"}})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err := s.requestsService.LoadFullUsers(requests, wireUserFields); err != nil {
+		s.logger.Error("failed to load users", "error", err)
+		return nil, errorsPkg.Wrap(err, "failed to load users")
+	}
+	return requests, nil
+}
+
+// ImportStatuses executes or cancels exported requests according to the status report of the bank
+func (s *WireService) ImportStatuses(r io.Reader, fileName string, user *users.User) (*WireStatusImport, error) {
+	statuses, err := wire.ParseStatusReport(r, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WireStatusImport{Failures: make([]*WireStatusFailure, 0)}
+	for _, status := range statuses {
+		if err := s.applyStatus(status, user); err != nil {
+			s.logger.Error("failed to apply wire status", "error", err, "endToEndId", status.EndToEndId)
+			result.Failures = append(result.Failures, &WireStatusFailure{EndToEndId: status.EndToEndId, Error: err.Error()})
+			continue
+		}
+		if status.Rejected {
+			result.Cancelled++
+		} else {
+			result.Executed++
+		}
+	}
+	return result, nil
+}
+
+func (s *WireService) applyStatus(status *wire.Status, user *users.User) error {
+	id, err := strconv.ParseUint(status.EndToEndId, 10, 64)
+	if err != nil {
+		return errorsPkg.New("unknown end to end id")
+	}
+
+	request := &model.Request{}
+	if err := s.db.Preload("DataOwt").Where("id = ?", id).First(request).Error; err != nil {
+		return errorsPkg.Wrap(err, "request is not found")
+	}
+	if request.DataOwt == nil || request.DataOwt.ExportedAt == nil {
+		return errorsPkg.New("request has not been exported")
+	}
+	if *request.Status != constants.StatusPending {
+		return errorsPkg.Errorf("request is already %s", *request.Status)
+	}
+
+	if status.Rejected {
+		reason := "Rejected by the bank"
+		if status.Reason != "" {
+			reason += ": " + status.Reason
+		}
+		return s.canceller.Call(request, reason, user)
+	}
+	return s.executor.Call(request, user)
+}
+
+// newWirePayment maps outgoing wire transfer request to the payment in the given reference currency
+func newWirePayment(request *model.Request, currency transfer.Currency) *wire.Payment {
+	data := request.DataOwt
+	instructed := decimal.Zero
+	if request.InputAmount != nil {
+		instructed = *request.InputAmount
+	}
+	chargeBearer := constants.DefaultChargeBearer.String()
+	if data.ChargeBearer != nil {
+		chargeBearer = *data.ChargeBearer
+	}
+
+	payment := &wire.Payment{
+		EndToEndId:       wireEndToEndId(request),
+		Amount:           instructed,
+		InstructedAmount: instructed,
+		CurrencyCode:     *request.ReferenceCurrencyCode,
+		ChargeBearer:     chargeBearer,
+	}
+	if chargeBearer == constants.ChargeBearerBeneficiary.String() {
+		payment.Amount = wireSentAmount(request, instructed, currency)
+		payment.Charges = instructed.Sub(payment.Amount)
+	}
+	if data.SourceAccount != nil {
+		payment.OrderingAccount = data.SourceAccount.Number
+	}
+	if request.FullUser != nil {
+		payment.OrderingName = strings.TrimSpace(request.FullUser.FirstName + " " + request.FullUser.LastName)
+		if request.FullUser.IsCorporate && request.FullUser.Company != nil && request.FullUser.Company.CompanyName != "" {
+			payment.OrderingName = request.FullUser.Company.CompanyName
+		}
+	}
+	if data.BeneficiaryCustomer != nil {
+		payment.BeneficiaryName = data.BeneficiaryCustomer.AccountName
+		payment.BeneficiaryAddress = data.BeneficiaryCustomer.Address
+		payment.BeneficiaryAccount = data.BeneficiaryCustomer.Iban
+	}
+	if data.BankDetails != nil {
+		payment.BeneficiaryBank = wire.Bank{
+			Bic:  strings.ToUpper(strings.TrimSpace(data.BankDetails.SwiftCode)),
+			Name: data.BankDetails.BankName,
+		}
+	}
+	if data.IntermediaryBankDetails != nil {
+		payment.IntermediaryBank = &wire.Bank{
+			Bic:  strings.ToUpper(strings.TrimSpace(data.IntermediaryBankDetails.SwiftCode)),
+			Name: data.IntermediaryBankDetails.BankName,
+		}
+	}
+	if data.RefMessage != nil {
+		payment.RemittanceInfo = *data.RefMessage
+	}
+	return payment
+}
+
+// wireSentAmount returns the instructed amount reduced in proportion to the outgoing transaction
+// which does not include fees deducted from the amount, it is rounded to decimals of the currency
+func wireSentAmount(request *model.Request, instructed decimal.Decimal, currency transfer.Currency) decimal.Decimal {
+	if request.Amount == nil || request.Amount.IsZero() {
+		return instructed
+	}
+	outgoing := decimal.Zero
+	for _, transaction := range request.Transactions {
+		if transaction.Purpose != nil && *transaction.Purpose == transactionConstants.PurposeOWTOutgoing.String() &&
+			transaction.Amount != nil {
+			outgoing = outgoing.Sub(*transaction.Amount)
+		}
+	}
+	return instructed.Mul(outgoing).Div(*request.Amount).Round(int32(currency.Fraction()))
+}
+
+// wireMessageId generates reference of the exported file, it is unique even for files exported within
+// the same second and fits MT103 user reference (16 characters)
+func wireMessageId(now time.Time) (string, error) {
+	random := make([]byte, wireMessageIdRandomSize)
+	if _, err := rand.Read(random); err != nil {
+		return "", errorsPkg.Wrap(err, "failed to generate message id")
+	}
+	suffix := strings.ToUpper(hex.EncodeToString(random))[:wireMessageIdRandomLength]
+	return "OWT" + now.Format("060102") + suffix, nil
+}
+
+func wireEndToEndId(request *model.Request) string {
+	return strconv.FormatUint(*request.Id, 10)
+}
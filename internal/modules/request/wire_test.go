@@ -0,0 +1,52 @@
+package request_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	transactionConstants "github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	transactionModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
+	"github.com/Confialink/wallet-accounts/internal/transfer"
+)
+
+var _ = Describe("WireService", func() {
+	It("should generate unique message ids within the same second", func() {
+		now := time.Date(2020, 10, 19, 12, 0, 0, 0, time.UTC)
+		ids := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			id, err := WireMessageId(now)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(id).To(HavePrefix("OWT201019"))
+			// MT103 user reference is limited by 16 characters
+			Expect(id).To(HaveLen(16))
+			Expect(ids).NotTo(HaveKey(id))
+			ids[id] = true
+		}
+	})
+
+	table.DescribeTable("sent amount is rounded to decimals of the currency",
+		func(currency *transfer.Currency, expected string) {
+			// 10 of 30 debited are deducted fees, so 2/3 of the instructed amount is sent
+			request := &model.Request{
+				Amount: dec("30"),
+				Transactions: []*transactionModel.Transaction{{
+					Purpose: pointer.ToString(transactionConstants.PurposeOWTOutgoing.String()),
+					Amount:  dec("-20"),
+				}},
+			}
+
+			amount := WireSentAmount(request, decimal.RequireFromString("1000"), *currency)
+			Expect(amount.String()).To(Equal(expected))
+		},
+		table.Entry("two decimals", transfer.NewCurrency("EUR", 2), "666.67"),
+		table.Entry("no decimals", transfer.NewCurrency("JPY", 0), "667"),
+		table.Entry("three decimals", transfer.NewCurrency("BHD", 3), "666.667"),
+	)
+})
@@ -0,0 +1,335 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	accRepo "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	requestForm "github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	requestRepo "github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	requestService "github.com/Confialink/wallet-accounts/internal/modules/request/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/form"
+	system_logs "github.com/Confialink/wallet-accounts/internal/modules/system-logs"
+	"github.com/Confialink/wallet-accounts/internal/modules/user"
+)
+
+const accountClosureDescription = "Account Closure"
+
+// ClosingStatement summarizes the final settlement of the closed account
+type ClosingStatement struct {
+	AccountId     uint64  `json:"accountId"`
+	AccountNumber string  `json:"accountNumber"`
+	CurrencyCode  string  `json:"currencyCode"`
+	Reason        *string `json:"reason"`
+	// OpeningBalance is the balance before the settlement
+	OpeningBalance                 decimal.Decimal `json:"openingBalance"`
+	CancelledRequestIds            []uint64        `json:"cancelledRequestIds"`
+	SettledScheduledTransactionIds []uint64        `json:"settledScheduledTransactionIds"`
+	InterestPaid                   decimal.Decimal `json:"interestPaid"`
+	FeesCharged                    decimal.Decimal `json:"feesCharged"`
+	SweptAmount                    decimal.Decimal `json:"sweptAmount"`
+	SweepAccountId                 *uint64         `json:"sweepAccountId"`
+	SweepRequestId                 *uint64         `json:"sweepRequestId"`
+	// ClosingBalance stays reserved by the sweeping outgoing wire transfer until it is executed
+	ClosingBalance decimal.Decimal `json:"closingBalance"`
+	ClosedAt       time.Time       `json:"closedAt"`
+
+	// cancelledRequests are notified of cancellation once the closure is committed
+	cancelledRequests []*requestModel.Request
+}
+
+// closureRequestCreator creates requests which settle and sweep the closed account, it is implemented by request.Creator
+type closureRequestCreator interface {
+	transferCreator
+	CreateOWTRequest(form *requestForm.OWT, user *users.User, db *gorm.DB) (*requestModel.Request, error)
+}
+
+// AccountClosureService closes accounts: pending requests are cancelled, pending scheduled interest and fees
+// are settled, the remaining balance is swept and the account is deactivated, closed accounts are kept for history
+type AccountClosureService struct {
+	db                *gorm.DB
+	repo              *Repository
+	accountRepository *accRepo.AccountRepository
+	requestRepository requestRepo.RequestRepositoryInterface
+	canceller         *requestService.Canceller
+	requestCreator    closureRequestCreator
+	systemLogsService *system_logs.SystemLogsService
+	logger            log15.Logger
+}
+
+func NewAccountClosureService(
+	db *gorm.DB,
+	repo *Repository,
+	accountRepository *accRepo.AccountRepository,
+	requestRepository requestRepo.RequestRepositoryInterface,
+	canceller *requestService.Canceller,
+	requestCreator *request.Creator,
+	systemLogsService *system_logs.SystemLogsService,
+	logger log15.Logger,
+) *AccountClosureService {
+	return &AccountClosureService{
+		db:                db,
+		repo:              repo,
+		accountRepository: accountRepository,
+		requestRepository: requestRepository,
+		canceller:         canceller,
+		requestCreator:    requestCreator,
+		systemLogsService: systemLogsService,
+		logger:            logger.New("service", "AccountClosure"),
+	}
+}
+
+// Close settles and closes the account within a single db transaction, the account is locked meanwhile
+// so that no new activity could happen
+func (s *AccountClosureService) Close(
+	accountId uint64,
+	closeForm *form.Close,
+	currentUser *users.User,
+	now time.Time,
+) (*ClosingStatement, error) {
+	if closeForm.SweepAccountId != nil && closeForm.SweepOwt != nil {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidFormModel, "either sweep account or outgoing wire transfer must be specified")
+	}
+	if closeForm.SweepOwt != nil && !closeForm.SweepOwt.IntermediaryBankIsComplete() {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidFormModel, "details of the intermediary bank are required")
+	}
+
+	tx := s.db.Begin()
+	statement, closed, err := s.close(tx, accountId, closeForm, currentUser, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	for _, req := range statement.cancelledRequests {
+		s.canceller.NotifyCancelled(req, accountClosureDescription)
+	}
+	s.systemLogsService.LogCloseAccountAsync(closed, statement, currentUser.UID)
+	return statement, nil
+}
+
+func (s *AccountClosureService) close(
+	tx *gorm.DB,
+	accountId uint64,
+	closeForm *form.Close,
+	currentUser *users.User,
+	now time.Time,
+) (*ClosingStatement, *accountModel.Account, error) {
+	accounts := s.accountRepository.WrapContext(tx)
+	account, err := accounts.FindByIDForUpdate(accountId)
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil, errcodes.CreatePublicError(errcodes.CodeAccountNotFound)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve account")
+	}
+	if account.IsClosed() {
+		return nil, nil, errcodes.CreatePublicError(errcodes.CodeAccountClosed)
+	}
+
+	var sweepAccount *accountModel.Account
+	if closeForm.SweepAccountId != nil {
+		sweepAccount, err = s.findSweepAccount(accounts, account, *closeForm.SweepAccountId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	statement := &ClosingStatement{
+		AccountId:                      account.ID,
+		AccountNumber:                  account.Number,
+		CurrencyCode:                   account.Type.CurrencyCode,
+		Reason:                         closeForm.Reason,
+		OpeningBalance:                 account.Balance,
+		CancelledRequestIds:            make([]uint64, 0),
+		SettledScheduledTransactionIds: make([]uint64, 0),
+		ClosedAt:                       now,
+	}
+
+	if err := s.cancelPendingRequests(tx, account, statement); err != nil {
+		return nil, nil, err
+	}
+	if err := s.settleScheduledTransactions(tx, account, statement); err != nil {
+		return nil, nil, err
+	}
+
+	balance, err := accounts.GetBalance(account.ID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve balance")
+	}
+	if balance.IsNegative() {
+		return nil, nil, errcodes.CreatePublicError(errcodes.CodeClosureNegativeBalance)
+	}
+	if balance.IsPositive() {
+		if err := s.sweep(tx, account, balance, sweepAccount, closeForm.SweepOwt, currentUser, statement); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	statement.ClosingBalance, err = accounts.GetBalance(account.ID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve balance")
+	}
+
+	update := &accountModel.Account{}
+	update.ID = account.ID
+	update.IsActive = pointer.ToBool(false)
+	update.AllowDeposits = pointer.ToBool(false)
+	update.ClosedAt = &now
+	if err := accounts.Updates(update); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to close account")
+	}
+
+	closed, err := accounts.FindByID(account.ID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve closed account")
+	}
+	return statement, closed, nil
+}
+
+// findSweepAccount checks the remaining balance could be swept to the given account
+func (s *AccountClosureService) findSweepAccount(
+	accounts *accRepo.AccountRepository,
+	account *accountModel.Account,
+	sweepAccountId uint64,
+) (*accountModel.Account, error) {
+	sweepAccount, err := accounts.FindByID(sweepAccountId)
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidSweepAccount)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve sweep account")
+	}
+	if sweepAccount.ID == account.ID || sweepAccount.UserId != account.UserId || sweepAccount.IsClosed() ||
+		sweepAccount.IsActive == nil || !*sweepAccount.IsActive ||
+		sweepAccount.Type.CurrencyCode != account.Type.CurrencyCode {
+		return nil, errcodes.CreatePublicError(errcodes.CodeInvalidSweepAccount)
+	}
+	return sweepAccount, nil
+}
+
+// cancelPendingRequests cancels requests which reserve the balance of the account,
+// owners are notified only once the closure is committed since it could still be rolled back
+func (s *AccountClosureService) cancelPendingRequests(
+	tx *gorm.DB,
+	account *accountModel.Account,
+	statement *ClosingStatement,
+) error {
+	requests, err := s.requestRepository.WrapContext(tx).FindPendingByAccountId(account.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve pending requests")
+	}
+	for _, req := range requests {
+		if err := s.canceller.CallInTx(tx, req, accountClosureDescription); err != nil {
+			return errors.Wrapf(err, "failed to cancel request #%d", *req.Id)
+		}
+		statement.CancelledRequestIds = append(statement.CancelledRequestIds, *req.Id)
+		statement.cancelledRequests = append(statement.cancelledRequests, req)
+	}
+	return nil
+}
+
+// settleScheduledTransactions executes pending interest payouts and fees without waiting for their scheduled dates
+func (s *AccountClosureService) settleScheduledTransactions(
+	tx *gorm.DB,
+	account *accountModel.Account,
+	statement *ClosingStatement,
+) error {
+	transactions, err := s.repo.WrapContext(tx).FindPendingByAccountId(account.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve pending scheduled transactions")
+	}
+
+	systemUser := user.GetSystemUser()
+	for _, transaction := range transactions {
+		transaction, err := lockPending(tx, s.repo, *transaction.Id)
+		if err != nil {
+			return errors.Wrap(err, "failed to lock scheduled transaction")
+		}
+		if transaction == nil {
+			continue
+		}
+		req, err := executeTransaction(tx, transaction, s.requestCreator, &systemUser)
+		if err != nil {
+			return errors.Wrapf(err, "failed to execute scheduled transaction #%d", *transaction.Id)
+		}
+		if err := markExecuted(tx, transaction, req, s.repo); err != nil {
+			return errors.Wrap(err, "failed to update scheduled transaction")
+		}
+
+		statement.SettledScheduledTransactionIds = append(statement.SettledScheduledTransactionIds, *transaction.Id)
+		if transaction.Amount.IsPositive() {
+			statement.InterestPaid = statement.InterestPaid.Add(transaction.Amount)
+		} else {
+			statement.FeesCharged = statement.FeesCharged.Add(transaction.Amount.Abs())
+		}
+	}
+	return nil
+}
+
+// sweep transfers the remaining balance to the sweep account or sends it by outgoing wire transfer,
+// the wire transfer is left pending for approval
+func (s *AccountClosureService) sweep(
+	tx *gorm.DB,
+	account *accountModel.Account,
+	balance decimal.Decimal,
+	sweepAccount *accountModel.Account,
+	sweepOwt *form.SweepOwt,
+	currentUser *users.User,
+	statement *ClosingStatement,
+) error {
+	description := accountClosureDescription + "\nfrom #: " + account.Number
+
+	switch {
+	case sweepAccount != nil:
+		systemUser := user.GetSystemUser()
+		req, err := s.requestCreator.CreateDARequest(&requestForm.DA{
+			AccountId:              account.ID,
+			Amount:                 balance.String(),
+			Description:            description,
+			CreditToRevenueAccount: pointer.ToBool(false),
+		}, &systemUser, tx)
+		if err != nil {
+			return errors.Wrap(err, "failed to debit closed account")
+		}
+
+		_, err = s.requestCreator.CreateCARequest(&requestForm.CA{
+			AccountId:               sweepAccount.ID,
+			Amount:                  balance.String(),
+			Description:             description,
+			DebitFromRevenueAccount: pointer.ToBool(false),
+			ApplyIwtFee:             pointer.ToBool(false),
+		}, &systemUser, tx)
+		if err != nil {
+			return errors.Wrap(err, "failed to credit sweep account")
+		}
+
+		statement.SweepAccountId = &sweepAccount.ID
+		statement.SweepRequestId = req.Id
+	case sweepOwt != nil:
+		owtForm := sweepOwt.ToOWT(account.ID, balance.String(), account.Type.CurrencyCode, description)
+		req, err := s.requestCreator.CreateOWTRequest(owtForm, currentUser, tx)
+		if err != nil {
+			return errors.Wrap(err, "failed to create outgoing wire transfer")
+		}
+		statement.SweepRequestId = req.Id
+	default:
+		return errcodes.CreatePublicError(errcodes.CodeClosureSweepRequired)
+	}
+
+	statement.SweptAmount = balance
+	return nil
+}
@@ -0,0 +1,281 @@
+package scheduled_transaction_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	accountRepository "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	requestForm "github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	requestRepository "github.com/Confialink/wallet-accounts/internal/modules/request/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/form"
+	system_logs "github.com/Confialink/wallet-accounts/internal/modules/system-logs"
+)
+
+// noPendingRequests is the request repository which finds no pending requests of the account
+type noPendingRequests struct {
+	requestRepository.RequestRepositoryInterface
+}
+
+func (r *noPendingRequests) WrapContext(db *gorm.DB) requestRepository.RequestRepositoryInterface {
+	return r
+}
+
+func (r *noPendingRequests) FindPendingByAccountId(accountId uint64) ([]*requestModel.Request, error) {
+	return nil, nil
+}
+
+// recordingCreator records forms of the created requests
+type recordingCreator struct {
+	da  []*requestForm.DA
+	ca  []*requestForm.CA
+	owt []*requestForm.OWT
+}
+
+func (c *recordingCreator) CreateDARequest(form *requestForm.DA, user *users.User, db *gorm.DB) (*requestModel.Request, error) {
+	c.da = append(c.da, form)
+	return &requestModel.Request{Id: pointer.ToUint64(10)}, nil
+}
+
+func (c *recordingCreator) CreateCARequest(
+	form *requestForm.CA,
+	user *users.User,
+	db *gorm.DB,
+	isInitialBalanceRequest ...bool,
+) (*requestModel.Request, error) {
+	c.ca = append(c.ca, form)
+	return &requestModel.Request{Id: pointer.ToUint64(11)}, nil
+}
+
+func (c *recordingCreator) CreateOWTRequest(form *requestForm.OWT, user *users.User, db *gorm.DB) (*requestModel.Request, error) {
+	c.owt = append(c.owt, form)
+	return &requestModel.Request{Id: pointer.ToUint64(12)}, nil
+}
+
+var _ = Describe("AccountClosureService", func() {
+	var (
+		mock    sqlmock.Sqlmock
+		service *AccountClosureService
+		creator *recordingCreator
+	)
+	any := sqlmock.AnyArg()
+	currentTime := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+	admin := &users.User{UID: "admin"}
+
+	BeforeEach(func() {
+		gdb, sqlMock := newMockDB()
+		mock = sqlMock
+		logsService := system_logs.NewSystemLogsService(
+			nil, nil, system_logs.NewAccountLogCreator(nil, log15.New()), nil, nil, nil, nil,
+		)
+		service = NewAccountClosureService(
+			gdb,
+			NewRepository(gdb),
+			accountRepository.NewAccountRepository(gdb, nil),
+			&noPendingRequests{},
+			nil,
+			nil,
+			logsService,
+			log15.New(),
+		)
+		creator = &recordingCreator{}
+		SetClosureRequestCreator(service, creator)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectAccountOf := func(query string, id uint64, userId string, closedAt *time.Time) {
+		mock.ExpectQuery(query).
+			WithArgs(id).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "number", "user_id", "type_id", "balance", "is_active", "closed_at"}).
+				AddRow(id, "4000001", userId, 3, "150", true, closedAt))
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(3, "EUR"))
+	}
+	expectAccount := func(query string, id uint64, closedAt *time.Time) {
+		expectAccountOf(query, id, "owner", closedAt)
+	}
+	expectLocked := func(closedAt *time.Time) {
+		mock.ExpectBegin()
+		expectAccount("SELECT \\* FROM `accounts` WHERE `accounts`.`id` = \\? FOR UPDATE", 1, closedAt)
+	}
+	expectFound := func(id uint64) {
+		expectAccount("SELECT \\* FROM `accounts` WHERE `accounts`.`id` = \\?", id, nil)
+	}
+	expectNothingToSettle := func() {
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(account_id = \\? AND status = \\?\\)").
+			WithArgs(1, StatusPending).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+	expectBalance := func(balance string) {
+		mock.ExpectQuery("SELECT balance FROM `accounts` WHERE \\(id = \\?\\)").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(balance))
+	}
+	expectClosed := func() {
+		mock.ExpectExec("UPDATE `accounts` SET `allow_deposits` = \\?, `closed_at` = \\?, `id` = \\?, `is_active` = \\?").
+			WithArgs(false, currentTime, 1, false, any, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectFound(1)
+		mock.ExpectCommit()
+	}
+
+	It("should sweep the remaining balance to another account", func() {
+		expectLocked(nil)
+		expectFound(2)
+		expectNothingToSettle()
+		expectBalance("150")
+		expectBalance("0")
+		expectClosed()
+
+		statement, err := service.Close(1, &form.Close{SweepAccountId: pointer.ToUint64(2)}, admin, currentTime)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(creator.da).To(HaveLen(1))
+		Expect(creator.da[0].AccountId).To(Equal(uint64(1)))
+		Expect(creator.da[0].Amount).To(Equal("150"))
+		Expect(creator.ca).To(HaveLen(1))
+		Expect(creator.ca[0].AccountId).To(Equal(uint64(2)))
+		Expect(creator.ca[0].Amount).To(Equal("150"))
+		Expect(creator.owt).To(BeEmpty())
+		Expect(statement.SweptAmount.Equal(decimal.New(150, 0))).To(BeTrue())
+		Expect(*statement.SweepAccountId).To(Equal(uint64(2)))
+		Expect(*statement.SweepRequestId).To(Equal(uint64(10)))
+		Expect(statement.ClosingBalance.IsZero()).To(BeTrue())
+		Expect(statement.ClosedAt).To(Equal(currentTime))
+	})
+
+	It("should sweep the remaining balance by outgoing wire transfer", func() {
+		expectLocked(nil)
+		expectNothingToSettle()
+		expectBalance("150")
+		// the balance stays reserved until the wire transfer is executed
+		expectBalance("150")
+		expectClosed()
+
+		sweepOwt := &form.SweepOwt{BankName: pointer.ToString("Bank"), CustomerAccIban: pointer.ToString("DE89370400440532013000")}
+		statement, err := service.Close(1, &form.Close{SweepOwt: sweepOwt}, admin, currentTime)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(creator.da).To(BeEmpty())
+		Expect(creator.ca).To(BeEmpty())
+		Expect(creator.owt).To(HaveLen(1))
+		Expect(*creator.owt[0].AccountIdFrom).To(Equal(uint64(1)))
+		Expect(*creator.owt[0].OutgoingAmount).To(Equal("150"))
+		Expect(*creator.owt[0].ReferenceCurrencyCode).To(Equal("EUR"))
+		Expect(*creator.owt[0].CustomerAccIban).To(Equal("DE89370400440532013000"))
+		Expect(statement.SweepAccountId).To(BeNil())
+		Expect(*statement.SweepRequestId).To(Equal(uint64(12)))
+		Expect(statement.ClosingBalance.Equal(decimal.New(150, 0))).To(BeTrue())
+	})
+
+	It("should settle scheduled transactions which are still pending once locked", func() {
+		expectLocked(nil)
+		expectFound(2)
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(account_id = \\? AND status = \\?\\)").
+			WithArgs(1, StatusPending).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "reason", "amount", "status"}).
+				AddRow(7, 1, ReasonMaintenanceFee, "-5", StatusPending).
+				AddRow(8, 1, ReasonMaintenanceFee, "-5", StatusPending))
+		mock.ExpectQuery("SELECT \\* FROM `accounts` WHERE \\(`id` IN \\(\\?,\\?\\)\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id"}).AddRow(1, 3))
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(3, "EUR"))
+		expectLockedTransaction := func(id uint64, status Status) {
+			mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(id = \\?\\) .* FOR UPDATE").
+				WithArgs(id).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "reason", "amount", "status"}).
+					AddRow(id, 1, ReasonMaintenanceFee, "-5", status))
+			expectAccount("SELECT \\* FROM `accounts` WHERE \\(`id` IN \\(\\?\\)\\)", 1, nil)
+		}
+		expectLockedTransaction(7, StatusPending)
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET `amount` = \\?, `request_id` = \\?, `status` = \\?, `updated_at` = \\? WHERE \\(id = \\? AND status = \\?\\)").
+			WithArgs("-5", 10, StatusExecuted, any, 7, StatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// executed by a parallel run after the list has been retrieved
+		expectLockedTransaction(8, StatusExecuted)
+		expectBalance("145")
+		expectBalance("0")
+		expectClosed()
+
+		statement, err := service.Close(1, &form.Close{SweepAccountId: pointer.ToUint64(2)}, admin, currentTime)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(creator.da).To(HaveLen(2))
+		Expect(creator.da[0].Amount).To(Equal("5"))
+		Expect(creator.da[1].Amount).To(Equal("145"))
+		Expect(statement.SettledScheduledTransactionIds).To(Equal([]uint64{7}))
+		Expect(statement.FeesCharged.Equal(decimal.New(5, 0))).To(BeTrue())
+	})
+
+	It("should not settle scheduled transaction which is not pending anymore", func() {
+		expectLocked(nil)
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(account_id = \\? AND status = \\?\\)").
+			WithArgs(1, StatusPending).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "reason", "amount", "status"}).
+				AddRow(7, 1, ReasonMaintenanceFee, "-5", StatusPending))
+		expectAccount("SELECT \\* FROM `accounts` WHERE \\(`id` IN \\(\\?\\)\\)", 1, nil)
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(id = \\?\\) .* FOR UPDATE").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "reason", "amount", "status"}).
+				AddRow(7, 1, ReasonMaintenanceFee, "-5", StatusPending))
+		expectAccount("SELECT \\* FROM `accounts` WHERE \\(`id` IN \\(\\?\\)\\)", 1, nil)
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := service.Close(1, &form.Close{}, admin, currentTime)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is not pending anymore"))
+	})
+
+	It("should not sweep the remaining balance to an account of another owner", func() {
+		expectLocked(nil)
+		expectAccountOf("SELECT \\* FROM `accounts` WHERE `accounts`.`id` = \\?", 2, "stranger", nil)
+		mock.ExpectRollback()
+
+		_, err := service.Close(1, &form.Close{SweepAccountId: pointer.ToUint64(2)}, admin, currentTime)
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeInvalidSweepAccount))
+		Expect(creator.da).To(BeEmpty())
+	})
+
+	It("should not close the account with negative balance", func() {
+		expectLocked(nil)
+		expectFound(2)
+		expectNothingToSettle()
+		expectBalance("-10")
+		mock.ExpectRollback()
+
+		_, err := service.Close(1, &form.Close{SweepAccountId: pointer.ToUint64(2)}, admin, currentTime)
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeClosureNegativeBalance))
+	})
+
+	It("should require sweeping of the remaining balance", func() {
+		expectLocked(nil)
+		expectNothingToSettle()
+		expectBalance("150")
+		mock.ExpectRollback()
+
+		_, err := service.Close(1, &form.Close{}, admin, currentTime)
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeClosureSweepRequired))
+	})
+
+	It("should not close the account which is already closed", func() {
+		closedAt := currentTime.AddDate(0, 0, -1)
+		expectLocked(&closedAt)
+		mock.ExpectRollback()
+
+		_, err := service.Close(1, &form.Close{SweepAccountId: pointer.ToUint64(2)}, admin, currentTime)
+		Expect(publicErrorCode(err)).To(Equal(errcodes.CodeAccountClosed))
+		Expect(creator.da).To(BeEmpty())
+	})
+})
@@ -0,0 +1,54 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+)
+
+// AccruedInterest is interest accrued to the account since the last payout
+type AccruedInterest struct {
+	AccountId    uint64             `json:"accountId"`
+	CurrencyCode string             `json:"currencyCode"`
+	Amount       decimal.Decimal    `json:"amount"`
+	From         *time.Time         `json:"from"`
+	To           *time.Time         `json:"to"`
+	PayoutDate   *time.Time         `json:"payoutDate"`
+	Accruals     []*InterestAccrual `json:"accruals"`
+}
+
+// AccruedInterest returns interest accrued to date which will be paid out by the next scheduled payout
+func (s *Service) AccruedInterest(account *accountModel.Account) (*AccruedInterest, error) {
+	accruals, err := s.interestAccrualRepository.FindNotPaidByAccountId(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccruedInterest{
+		AccountId: account.ID,
+		Amount:    decimal.Zero,
+		Accruals:  accruals,
+	}
+	if account.Type != nil {
+		result.CurrencyCode = account.Type.CurrencyCode
+	}
+	for _, accrual := range accruals {
+		result.Amount = result.Amount.Add(accrual.Amount)
+	}
+	if len(accruals) > 0 {
+		result.From = accruals[0].Date
+		result.To = accruals[len(accruals)-1].Date
+	}
+
+	payout, err := s.scheduledTransactionRepository.FindNextPendingByAccountIdAndReason(account.ID, ReasonInterestGeneration, time.Now())
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err == nil {
+		result.PayoutDate = payout.ScheduledDate
+	}
+	return result, nil
+}
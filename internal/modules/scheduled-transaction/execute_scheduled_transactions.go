@@ -1,6 +1,7 @@
 package scheduled_transaction
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -16,35 +17,47 @@ import (
 )
 
 func ExecuteScheduledTransactions(
+	ctx context.Context,
 	scheduledTransactions []*ScheduledTransaction,
 	repo *Repository,
 	requestCreator *request.Creator,
 	db *gorm.DB,
 	logger log15.Logger,
-) {
+) JobResult {
 	logger = logger.New("Task", "ExecuteScheduledTransactions")
 
 	systemUser := user.GetSystemUser()
 	successfullyExecutedTransfersCount := 0
+	errorsCount := 0
 	for _, transaction := range scheduledTransactions {
+		if isAborted(ctx, logger) {
+			break
+		}
 		tx := db.Begin()
+		transaction, err := lockPending(tx, repo, *transaction.Id)
+		if err != nil {
+			logger.Error("failed to lock transaction", "error", err)
+			tx.Rollback()
+			errorsCount++
+			continue
+		}
+		if transaction == nil {
+			tx.Rollback()
+			continue
+		}
 		req, err := executeTransaction(tx, transaction, requestCreator, &systemUser)
 		if err != nil {
 			logger.Error("failed to execute transaction", "error", err)
 			tx.Rollback()
+			errorsCount++
 			continue
 		}
 
-		txRepo := repo.WrapContext(tx)
-		err = txRepo.Updates(&ScheduledTransaction{
-			Id:        transaction.Id,
-			RequestId: req.Id,
-			Status:    StatusExecuted,
-		})
-
+		err = markExecuted(tx, transaction, req, repo)
 		if err != nil {
 			tx.Rollback()
 			logger.Crit("failed to update scheduled transaction", err)
+			errorsCount++
 			continue
 		}
 		tx.Commit()
@@ -57,12 +70,49 @@ func ExecuteScheduledTransactions(
 			)
 		}
 	}
+
+	return JobResult{
+		Processed: uint64(successfullyExecutedTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
+}
+
+// lockPending locks the scheduled transaction within the given db transaction and reloads it,
+// nil is returned if the transaction is not pending anymore, e.g. it has been executed by a parallel run
+func lockPending(tx *gorm.DB, repo *Repository, id uint64) (*ScheduledTransaction, error) {
+	transaction, err := repo.WrapContext(tx).FindByIdForUpdate(id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil || transaction.Status != StatusPending {
+		return nil, nil
+	}
+	return transaction, nil
+}
+
+// markExecuted marks the transaction as executed, it fails if the transaction is not pending anymore
+// so that the created request is rolled back
+func markExecuted(tx *gorm.DB, transaction *ScheduledTransaction, req *requestModel.Request, repo *Repository) error {
+	updated, err := repo.WrapContext(tx).MarkExecuted(*transaction.Id, transaction.Amount, req.Id)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("scheduled transaction #%d is not pending anymore", *transaction.Id)
+	}
+	return nil
+}
+
+// transferCreator creates requests of scheduled transactions, it is implemented by request.Creator
+type transferCreator interface {
+	CreateDARequest(form *form.DA, user *pb.User, db *gorm.DB) (*requestModel.Request, error)
+	CreateCARequest(form *form.CA, user *pb.User, db *gorm.DB, isInitialBalanceRequest ...bool) (*requestModel.Request, error)
 }
 
 func executeTransaction(
 	tx *gorm.DB,
 	transaction *ScheduledTransaction,
-	requestCreator *request.Creator,
+	requestCreator transferCreator,
 	onBehalfUser *pb.User,
 ) (*requestModel.Request, error) {
 	if transaction.Status != StatusPending {
@@ -70,6 +120,14 @@ func executeTransaction(
 	}
 	description := transaction.Reason.Description()
 
+	if transaction.Reason == ReasonInterestGeneration {
+		amount, err := interestPayoutAmount(tx, transaction)
+		if err != nil {
+			return nil, err
+		}
+		transaction.Amount = amount
+	}
+
 	if transaction.Amount.LessThan(decimal.Zero) {
 		params := &form.DA{
 			Amount:                 transaction.Amount.Abs().String(),
@@ -97,3 +155,16 @@ func executeTransaction(
 
 	return requestCreator.CreateCARequest(params, onBehalfUser, tx)
 }
+
+// interestPayoutAmount sums up interest accrued during the period,
+// the amount of the transaction is used for payouts scheduled before accruals have been recorded
+func interestPayoutAmount(tx *gorm.DB, transaction *ScheduledTransaction) (decimal.Decimal, error) {
+	sum, count, err := NewInterestAccrualRepository(tx).SumByScheduledTransactionId(*transaction.Id)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if count == 0 {
+		return transaction.Amount, nil
+	}
+	return sum, nil
+}
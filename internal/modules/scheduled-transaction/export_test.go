@@ -0,0 +1,56 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/robfig/cron"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+)
+
+// SetHeartbeatInterval changes how often the runner extends leases of running jobs
+func SetHeartbeatInterval(runner *JobRunner, interval time.Duration) {
+	runner.heartbeatInterval = interval
+}
+
+// CatchUpSlots returns missed scheduled times of the job with the given schedule
+func CatchUpSlots(schedule cron.Schedule, eachSlot bool, last, till time.Time) ([]time.Time, bool) {
+	return catchUpSlots(&scheduledJob{schedule: schedule, eachSlot: eachSlot}, last, till)
+}
+
+// BackfillSlots returns scheduled times of the job with the given schedule within the given dates
+func BackfillSlots(schedule cron.Schedule, eachSlot bool, from, till, latest time.Time) []time.Time {
+	return backfillSlots(&scheduledJob{schedule: schedule, eachSlot: eachSlot}, from, till, latest)
+}
+
+var LatestScheduledTime = latestScheduledTime
+
+var ConfiguredSchedule = configuredSchedule
+
+// ScheduleSettingNames returns names of settings read by configuredSchedule in order
+func ScheduleSettingNames() []string {
+	names := []string{SettingJobScheduleTimeZoneString.String()}
+	for _, definition := range jobScheduleDefinitions {
+		names = append(names, jobScheduleSetting(definition.job).String())
+	}
+	return names
+}
+
+func (j *Jobs) ReloadSchedule(current *cron.Cron) *cron.Cron {
+	return j.reloadSchedule(current)
+}
+
+type ClosureRequestCreator = closureRequestCreator
+
+// SetClosureRequestCreator replaces creator of the requests which settle and sweep the closed account
+func SetClosureRequestCreator(s *AccountClosureService, creator ClosureRequestCreator) {
+	s.requestCreator = creator
+}
+
+// PayoutPrincipal exposes payout of the matured term deposit to its interest account
+func PayoutPrincipal(s *TermDepositService, tx *gorm.DB, account *accountModel.Account) error {
+	return s.payoutPrincipal(tx, account)
+}
+
+var BalanceAt = balanceAt
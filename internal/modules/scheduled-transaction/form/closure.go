@@ -0,0 +1,90 @@
+package form
+
+import (
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+
+	requestConstants "github.com/Confialink/wallet-accounts/internal/modules/request/constants"
+	requestForm "github.com/Confialink/wallet-accounts/internal/modules/request/form"
+)
+
+// Close settles and closes the account, the remaining balance is swept
+// either to another account or by outgoing wire transfer
+type Close struct {
+	SweepAccountId *uint64   `json:"sweepAccountId"`
+	SweepOwt       *SweepOwt `json:"sweepOwt"`
+	Reason         *string   `json:"reason" binding:"omitempty,max=255"`
+}
+
+// SweepOwt contains beneficiary details of the outgoing wire transfer which sweeps the remaining balance,
+// the whole balance is sent in the currency of the account and charges are deducted from it
+type SweepOwt struct {
+	RefMessage                 *string `json:"refMessage" binding:"required"`
+	BankSwiftBic               *string `json:"bankSwiftBic" binding:"required"`
+	BankName                   *string `json:"bankName" binding:"required"`
+	BankAddress                *string `json:"bankAddress" binding:"required"`
+	BankLocation               *string `json:"bankLocation" binding:"required"`
+	BankCountryId              *uint64 `json:"bankCountryId" binding:"required"`
+	BankAbaRtn                 *string `json:"bankAbaRtn" binding:"notRequiredAlphanumericPointer"`
+	CustomerName               *string `json:"customerName" binding:"required"`
+	CustomerAddress            *string `json:"customerAddress" binding:"required"`
+	CustomerAccIban            *string `json:"customerAccIban" binding:"required"`
+	IsIntermediaryBankRequired *bool   `json:"isIntermediaryBankRequired"`
+	FeeId                      *uint64 `json:"feeId"`
+
+	IntermediaryBankSwiftBic  *string `json:"intermediaryBankSwiftBic"`
+	IntermediaryBankName      *string `json:"intermediaryBankName"`
+	IntermediaryBankAddress   *string `json:"intermediaryBankAddress"`
+	IntermediaryBankLocation  *string `json:"intermediaryBankLocation"`
+	IntermediaryBankCountryId *uint64 `json:"intermediaryBankCountryId"`
+	IntermediaryBankAbaRtn    *string `json:"intermediaryBankAbaRtn" binding:"notRequiredAlphanumericPointer"`
+	IntermediaryBankAccIban   *string `json:"intermediaryBankAccIban"`
+}
+
+// IntermediaryBankIsComplete checks that details of the intermediary bank are specified if it is required
+func (s *SweepOwt) IntermediaryBankIsComplete() bool {
+	if s.IsIntermediaryBankRequired == nil || !*s.IsIntermediaryBankRequired {
+		return true
+	}
+	return s.IntermediaryBankSwiftBic != nil && s.IntermediaryBankName != nil && s.IntermediaryBankAddress != nil &&
+		s.IntermediaryBankLocation != nil && s.IntermediaryBankCountryId != nil && s.IntermediaryBankAccIban != nil
+}
+
+// ToOWT creates the request form which sends the given amount from the account
+func (s *SweepOwt) ToOWT(accountId uint64, amount, currencyCode, description string) *requestForm.OWT {
+	abaRtn := s.BankAbaRtn
+	if abaRtn == nil {
+		abaRtn = pointer.ToString("")
+	}
+	intermediaryAbaRtn := s.IntermediaryBankAbaRtn
+	if intermediaryAbaRtn == nil {
+		intermediaryAbaRtn = pointer.ToString("")
+	}
+
+	return &requestForm.OWT{
+		AccountIdFrom:              &accountId,
+		ReferenceCurrencyCode:      &currencyCode,
+		OutgoingAmount:             &amount,
+		ConfirmTotalOutgoingAmount: &amount,
+		Description:                &description,
+		RefMessage:                 s.RefMessage,
+		BankSwiftBic:               s.BankSwiftBic,
+		BankName:                   s.BankName,
+		BankAddress:                s.BankAddress,
+		BankLocation:               s.BankLocation,
+		BankCountryId:              s.BankCountryId,
+		BankAbaRtn:                 abaRtn,
+		CustomerName:               s.CustomerName,
+		CustomerAddress:            s.CustomerAddress,
+		CustomerAccIban:            s.CustomerAccIban,
+		IsIntermediaryBankRequired: s.IsIntermediaryBankRequired,
+		FeeId:                      s.FeeId,
+		ChargeBearer:               pointer.ToString(requestConstants.ChargeBearerBeneficiary.String()),
+		IntermediaryBankSwiftBic:   s.IntermediaryBankSwiftBic,
+		IntermediaryBankName:       s.IntermediaryBankName,
+		IntermediaryBankAddress:    s.IntermediaryBankAddress,
+		IntermediaryBankLocation:   s.IntermediaryBankLocation,
+		IntermediaryBankCountryId:  s.IntermediaryBankCountryId,
+		IntermediaryBankAbaRtn:     intermediaryAbaRtn,
+		IntermediaryBankAccIban:    s.IntermediaryBankAccIban,
+	}
+}
@@ -0,0 +1,12 @@
+package form
+
+// Waive cancels the pending fee
+type Waive struct {
+	Reason *string `json:"reason" binding:"required,max=255"`
+}
+
+// Update changes the pending transaction, only specified fields are changed
+type Update struct {
+	ScheduledDate *string `json:"scheduledDate" binding:"omitempty,datetime=2006-01-02"`
+	Amount        *string `json:"amount" binding:"omitempty,decimal"`
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	scheduled_transaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/form"
+)
+
+type AccountClosureHandler struct {
+	service        *scheduled_transaction.AccountClosureService
+	contextService appHttpService.ContextInterface
+	logger         log15.Logger
+}
+
+func NewAccountClosureHandler(
+	service *scheduled_transaction.AccountClosureService,
+	contextService appHttpService.ContextInterface,
+	logger log15.Logger,
+) *AccountClosureHandler {
+	return &AccountClosureHandler{
+		service:        service,
+		contextService: contextService,
+		logger:         logger.New("Handler", "AccountClosureHandler"),
+	}
+}
+
+// CloseHandler settles and closes the requested account, returns the closing statement
+func (h *AccountClosureHandler) CloseHandler(c *gin.Context) {
+	account := h.contextService.GetRequestedAccount(c)
+	if account == nil {
+		errcodes.AddError(c, errcodes.CodeAccountNotFound)
+		return
+	}
+
+	var closeForm form.Close
+	if err := c.ShouldBindJSON(&closeForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	user := h.contextService.MustGetCurrentUser(c)
+	statement, err := h.service.Close(account.ID, &closeForm, user, time.Now())
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(statement))
+}
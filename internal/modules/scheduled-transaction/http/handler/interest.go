@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	scheduled_transaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+type InterestHandler struct {
+	service        *scheduled_transaction.Service
+	contextService appHttpService.ContextInterface
+	logger         log15.Logger
+}
+
+func NewInterestHandler(
+	service *scheduled_transaction.Service,
+	contextService appHttpService.ContextInterface,
+	logger log15.Logger,
+) *InterestHandler {
+	return &InterestHandler{
+		service:        service,
+		contextService: contextService,
+		logger:         logger.New("Handler", "InterestHandler"),
+	}
+}
+
+// AccruedInterest returns interest accrued to the requested account since the last payout
+func (h *InterestHandler) AccruedInterest(c *gin.Context) {
+	account := h.contextService.GetRequestedAccount(c)
+	if account == nil {
+		errcodes.AddError(c, errcodes.CodeAccountNotFound)
+		return
+	}
+
+	accrued, err := h.service.AccruedInterest(account)
+	if err != nil {
+		privateError := errors.PrivateError{Message: "can't retrieve accrued interest"}
+		privateError.AddLogPair("error", err.Error())
+		errors.AddErrors(c, &privateError)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(accrued))
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	scheduled_transaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+const (
+	defaultPlannedRunsCount = 5
+	maxPlannedRunsCount     = 50
+)
+
+type JobsHandler struct {
+	jobs   *scheduled_transaction.Jobs
+	logger log15.Logger
+}
+
+func NewJobsHandler(jobs *scheduled_transaction.Jobs, logger log15.Logger) *JobsHandler {
+	return &JobsHandler{
+		jobs:   jobs,
+		logger: logger.New("Handler", "JobsHandler"),
+	}
+}
+
+// PlannedRunsHandler returns next planned run times of each scheduled job,
+// the number of times is specified by count query parameter
+func (h *JobsHandler) PlannedRunsHandler(c *gin.Context) {
+	count := defaultPlannedRunsCount
+	if value := c.Query("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxPlannedRunsCount {
+			errors.AddErrors(c, errcodes.CreatePublicError(
+				errcodes.CodeInvalidQueryParameters,
+				"count must be an integer from 1 to "+strconv.Itoa(maxPlannedRunsCount),
+			))
+			return
+		}
+		count = parsed
+	}
+
+	runs, err := h.jobs.PlannedRuns(count, time.Now())
+	if err != nil {
+		privateError := errors.PrivateError{Message: "can't retrieve planned runs of jobs"}
+		privateError.AddLogPair("error", err.Error())
+		errors.AddErrors(c, &privateError)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(runs))
+}
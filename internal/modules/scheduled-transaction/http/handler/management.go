@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	scheduled_transaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/form"
+)
+
+type ManagementHandler struct {
+	service        *scheduled_transaction.ManagementService
+	contextService appHttpService.ContextInterface
+	logger         log15.Logger
+}
+
+func NewManagementHandler(
+	service *scheduled_transaction.ManagementService,
+	contextService appHttpService.ContextInterface,
+	logger log15.Logger,
+) *ManagementHandler {
+	return &ManagementHandler{
+		service:        service,
+		contextService: contextService,
+		logger:         logger.New("Handler", "ManagementHandler"),
+	}
+}
+
+// WaiveHandler cancels the pending fee
+func (h *ManagementHandler) WaiveHandler(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	var waiveForm form.Waive
+	if err := c.ShouldBindJSON(&waiveForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	user := h.contextService.MustGetCurrentUser(c)
+	transaction, err := h.service.Waive(id, &waiveForm, user.UID)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(transaction))
+}
+
+// UpdateHandler moves scheduled date and adjusts amount of the pending transaction
+func (h *ManagementHandler) UpdateHandler(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	var updateForm form.Update
+	if err := c.ShouldBindJSON(&updateForm); err != nil {
+		errors.AddShouldBindError(c, err)
+		return
+	}
+
+	user := h.contextService.MustGetCurrentUser(c)
+	transaction, err := h.service.Update(id, &updateForm, user.UID, time.Now())
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(transaction))
+}
+
+// ExecuteHandler executes the pending transaction immediately
+func (h *ManagementHandler) ExecuteHandler(c *gin.Context) {
+	id, typedErr := h.contextService.GetIdParam(c)
+	if typedErr != nil {
+		errors.AddErrors(c, typedErr)
+		return
+	}
+
+	user := h.contextService.MustGetCurrentUser(c)
+	transaction, err := h.service.Execute(id, user.UID)
+	if err != nil {
+		errors.AddErrors(c, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.New().SetData(transaction))
+}
@@ -0,0 +1,26 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// InterestAccrual is interest accrued on the account balance for a single day,
+// accruals are paid out by the scheduled transaction they are linked to
+type InterestAccrual struct {
+	Id                     *uint64         `json:"id"`
+	AccountId              *uint64         `json:"accountId"`
+	ScheduledTransactionId *uint64         `json:"scheduledTransactionId"`
+	Date                   *time.Time      `json:"date"`
+	Balance                decimal.Decimal `json:"balance"`
+	// Rate is annual interest rate in percents
+	Rate      decimal.Decimal `json:"rate"`
+	DayCount  string          `json:"dayCount"`
+	Amount    decimal.Decimal `json:"amount"`
+	CreatedAt *time.Time      `json:"createdAt"`
+}
+
+func (*InterestAccrual) TableName() string {
+	return "interest_accruals"
+}
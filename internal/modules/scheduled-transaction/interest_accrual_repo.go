@@ -0,0 +1,50 @@
+package scheduled_transaction
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+)
+
+type InterestAccrualRepository struct {
+	db *gorm.DB
+}
+
+func NewInterestAccrualRepository(db *gorm.DB) *InterestAccrualRepository {
+	return &InterestAccrualRepository{db: db}
+}
+
+func (s *InterestAccrualRepository) Create(accrual *InterestAccrual) error {
+	return s.db.Create(accrual).Error
+}
+
+// SumByScheduledTransactionId returns total amount and count of accruals paid out by the scheduled transaction
+func (s *InterestAccrualRepository) SumByScheduledTransactionId(id uint64) (decimal.Decimal, uint64, error) {
+	var (
+		sum   decimal.Decimal
+		count uint64
+	)
+	err := s.db.
+		Model(&InterestAccrual{}).
+		Select("COALESCE(SUM(amount), 0), COUNT(*)").
+		Where("scheduled_transaction_id = ?", id).
+		Row().
+		Scan(&sum, &count)
+	return sum, count, err
+}
+
+// FindNotPaidByAccountId retrieves deposit interest accruals of the account which have not been paid out yet
+func (s *InterestAccrualRepository) FindNotPaidByAccountId(accountId uint64) ([]*InterestAccrual, error) {
+	accruals := make([]*InterestAccrual, 0)
+	err := s.db.
+		Joins("INNER JOIN scheduled_transactions st ON st.id = interest_accruals.scheduled_transaction_id").
+		Where("interest_accruals.account_id = ? AND st.status = ? AND st.reason = ?", accountId, StatusPending, ReasonInterestGeneration).
+		Order("interest_accruals.date").
+		Find(&accruals).
+		Error
+	return accruals, err
+}
+
+func (s InterestAccrualRepository) WrapContext(db *gorm.DB) *InterestAccrualRepository {
+	s.db = db
+	return &s
+}
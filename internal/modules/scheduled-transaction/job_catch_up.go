@@ -0,0 +1,196 @@
+package scheduled_transaction
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/now"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron"
+)
+
+const (
+	// catchUpPeriod limits how far back missed runs are caught up on startup,
+	// older runs must be backfilled with the command
+	catchUpPeriod = 31 * 24 * time.Hour
+	// catchUpMaxPasses limits how many times missed runs are looked up again while catching up
+	catchUpMaxPasses = 3
+)
+
+// missedRun is a run of the job at the scheduled time which has not happened
+type missedRun struct {
+	job         *scheduledJob
+	scheduledAt time.Time
+}
+
+// Start catches up missed runs and then starts cron which is rebuilt once its schedule is changed in settings
+func (j *Jobs) Start(localizedCron *cron.Cron) {
+	for pass := 0; pass < catchUpMaxPasses; pass++ {
+		if j.CatchUp(time.Now()) == 0 {
+			break
+		}
+	}
+	localizedCron.Start()
+	j.watchSchedule(localizedCron)
+}
+
+// CatchUp runs jobs for scheduled times missed since their last completed runs in order of the scheduled times.
+// Jobs which have never been completed or depend on current balances are not caught up. The number of runs is returned.
+func (j *Jobs) CatchUp(till time.Time) int {
+	schedule, _, err := j.scheduleConfig()
+	if err != nil {
+		j.logger.Error("failed to retrieve schedule config", "error", err)
+		return 0
+	}
+	till = till.In(schedule.Location)
+
+	var missed []*missedRun
+	for _, job := range j.list(schedule) {
+		last, err := j.runs.FindLastCompleted(job.name)
+		if err != nil {
+			j.logger.Error("failed to retrieve last run of the job", "error", err, "job", job.name)
+			continue
+		}
+		if last == nil || last.ScheduledAt == nil {
+			continue
+		}
+
+		from := last.ScheduledAt.In(schedule.Location)
+		slots, limited := catchUpSlots(job, from, till)
+		if len(slots) == 0 {
+			continue
+		}
+		if job.balanceDependent {
+			j.logger.Warn("missed runs of the job are not caught up since it depends on current balances",
+				"job", job.name, "lastScheduledAt", from)
+			continue
+		}
+		if limited {
+			j.logger.Warn("job has not been run for too long, older runs must be backfilled",
+				"job", job.name, "lastScheduledAt", from)
+		}
+		for _, slot := range slots {
+			missed = append(missed, &missedRun{job: job, scheduledAt: slot})
+		}
+	}
+
+	sort.SliceStable(missed, func(a, b int) bool {
+		return missed[a].scheduledAt.Before(missed[b].scheduledAt)
+	})
+
+	count := 0
+	for _, run := range missed {
+		// the run could have been caught up by another replica meanwhile
+		last, err := j.runs.FindLastCompleted(run.job.name)
+		if err != nil {
+			j.logger.Error("failed to retrieve last run of the job", "error", err, "job", run.job.name)
+			continue
+		}
+		if last != nil && last.ScheduledAt != nil && !last.ScheduledAt.Before(run.scheduledAt) {
+			continue
+		}
+
+		j.logger.Info("catching up missed run of the job", "job", run.job.name, "scheduledAt", run.scheduledAt)
+		if j.runAt(run.job, run.scheduledAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// Backfill runs the job for scheduled times within the given dates inclusively.
+// The job which handles all missed scheduled times at once is run only for the last one,
+// the job which depends on current balances can not be backfilled.
+func (j *Jobs) Backfill(name string, from, till time.Time) (int, error) {
+	schedule, _, err := j.scheduleConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	var job *scheduledJob
+	for _, item := range j.list(schedule) {
+		if item.name == name {
+			job = item
+			break
+		}
+	}
+	if job == nil {
+		return 0, errors.Errorf("unknown job %q", name)
+	}
+	if job.balanceDependent {
+		return 0, errors.Errorf("job %q depends on current balances and can not be backfilled", name)
+	}
+
+	slots := backfillSlots(job, from.In(schedule.Location), till.In(schedule.Location), time.Now())
+	count := 0
+	for _, slot := range slots {
+		j.logger.Info("backfilling run of the job", "job", job.name, "scheduledAt", slot)
+		if j.runAt(job, slot) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// runAt runs the job as if the current time was the given scheduled time
+func (j *Jobs) runAt(job *scheduledJob, scheduledAt time.Time) bool {
+	return j.runner.Run(job.name, scheduledAt, func(ctx context.Context) JobResult {
+		return job.run(ctx, fixedTimeSource{now: scheduledAt})
+	})
+}
+
+// catchUpSlots returns missed scheduled times of the job after the last one and not after till.
+// Scheduled times older than catchUpPeriod are skipped, limited is true then.
+func catchUpSlots(job *scheduledJob, last, till time.Time) (slots []time.Time, limited bool) {
+	if limit := till.Add(-catchUpPeriod); last.Before(limit) {
+		last = limit
+		limited = true
+	}
+	return lastSlotUnlessEach(job, scheduledTimes(job.schedule, last, till)), limited
+}
+
+// backfillSlots returns scheduled times of the job within the given dates inclusively and not after latest
+func backfillSlots(job *scheduledJob, from, till, latest time.Time) []time.Time {
+	from = now.New(from).BeginningOfDay()
+	till = now.New(till).EndOfDay()
+	if till.After(latest) {
+		till = latest
+	}
+	// scheduled times are looked up after the given time, so the time at midnight is included
+	return lastSlotUnlessEach(job, scheduledTimes(job.schedule, from.Add(-time.Nanosecond), till))
+}
+
+// lastSlotUnlessEach keeps only the last scheduled time for the job which handles all of them in a single run
+func lastSlotUnlessEach(job *scheduledJob, slots []time.Time) []time.Time {
+	if len(slots) == 0 || job.eachSlot {
+		return slots
+	}
+	return slots[len(slots)-1:]
+}
+
+// scheduledTimes returns scheduled times of the schedule after the given time and not after till
+func scheduledTimes(schedule cron.Schedule, after, till time.Time) []time.Time {
+	var result []time.Time
+	for next := schedule.Next(after); !next.IsZero() && !next.After(till); next = schedule.Next(next) {
+		result = append(result, next)
+	}
+	return result
+}
+
+// fixedTimeSource is the time source which always returns the given time
+type fixedTimeSource struct {
+	now time.Time
+}
+
+func (t fixedTimeSource) Now() time.Time {
+	return t.now
+}
+
+func (t fixedTimeSource) BeginningOfDay() time.Time {
+	return now.New(t.now).BeginningOfDay()
+}
+
+func (t fixedTimeSource) EndOfDay() time.Time {
+	return now.New(t.now).EndOfDay()
+}
@@ -0,0 +1,129 @@
+package scheduled_transaction_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/robfig/cron"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+var _ = Describe("Catching up jobs", func() {
+	location := time.FixedZone("CET", 3600)
+	date := func(day, hour, minute int) time.Time {
+		return time.Date(2020, 3, day, hour, minute, 0, 0, location)
+	}
+	// at 00:30 every day
+	daily, _ := cron.Parse("0 30 0 * *")
+
+	Context("catchUpSlots", func() {
+		It("should return every missed scheduled time of the job handling a single period", func() {
+			slots, limited := CatchUpSlots(daily, true, date(7, 0, 30), date(10, 12, 0))
+			Expect(limited).To(BeFalse())
+			Expect(slots).To(Equal([]time.Time{date(8, 0, 30), date(9, 0, 30), date(10, 0, 30)}))
+		})
+
+		It("should return only the last missed scheduled time of the job handling all of them at once", func() {
+			slots, limited := CatchUpSlots(daily, false, date(7, 0, 30), date(10, 12, 0))
+			Expect(limited).To(BeFalse())
+			Expect(slots).To(Equal([]time.Time{date(10, 0, 30)}))
+		})
+
+		It("should not return anything if nothing is missed", func() {
+			slots, _ := CatchUpSlots(daily, true, date(10, 0, 30), date(10, 12, 0))
+			Expect(slots).To(BeEmpty())
+		})
+
+		It("should skip scheduled times older than 31 days", func() {
+			till := time.Date(2020, 4, 20, 12, 0, 0, 0, location)
+			slots, limited := CatchUpSlots(daily, true, date(1, 0, 30), till)
+			Expect(limited).To(BeTrue())
+			Expect(slots).To(HaveLen(31))
+			Expect(slots[0]).To(Equal(time.Date(2020, 3, 21, 0, 30, 0, 0, location)))
+			Expect(slots[30]).To(Equal(time.Date(2020, 4, 20, 0, 30, 0, 0, location)))
+		})
+	})
+
+	Context("backfillSlots", func() {
+		It("should return scheduled times within the given dates inclusively", func() {
+			slots := BackfillSlots(daily, true, date(8, 15, 0), date(9, 0, 0), date(20, 0, 0))
+			Expect(slots).To(Equal([]time.Time{date(8, 0, 30), date(9, 0, 30)}))
+		})
+
+		It("should include the scheduled time at midnight", func() {
+			midnight, _ := cron.Parse("0 0 0 * *")
+			slots := BackfillSlots(midnight, true, date(8, 0, 0), date(8, 0, 0), date(20, 0, 0))
+			Expect(slots).To(Equal([]time.Time{date(8, 0, 0)}))
+		})
+
+		It("should not return scheduled times in the future", func() {
+			slots := BackfillSlots(daily, true, date(8, 0, 0), date(12, 0, 0), date(10, 12, 0))
+			Expect(slots).To(Equal([]time.Time{date(8, 0, 30), date(9, 0, 30), date(10, 0, 30)}))
+		})
+
+		It("should return only the last scheduled time of the job handling all of them at once", func() {
+			slots := BackfillSlots(daily, false, date(8, 0, 0), date(9, 0, 0), date(20, 0, 0))
+			Expect(slots).To(Equal([]time.Time{date(9, 0, 30)}))
+		})
+	})
+
+	Context("latestScheduledTime", func() {
+		It("should return the scheduled time cron has run the job for", func() {
+			Expect(LatestScheduledTime(daily, date(8, 0, 30), date(8, 0, 31))).To(Equal(date(8, 0, 30)))
+		})
+
+		It("should skip scheduled times missed by cron", func() {
+			Expect(LatestScheduledTime(daily, date(8, 0, 30), date(10, 1, 0))).To(Equal(date(10, 0, 30)))
+		})
+	})
+
+	Context("Jobs", func() {
+		var (
+			mock sqlmock.Sqlmock
+			jobs *Jobs
+		)
+
+		BeforeEach(func() {
+			gdb, sqlMock := newMockDB()
+			mock = sqlMock
+			SetScheduleConfigurator(DefaultSchedule)
+			jobs = NewJobs(nil, nil, gdb, nil, nil, nil, nil, nil, nil, nil, NewJobRunRepository(gdb), log15.New())
+		})
+		AfterEach(func() {
+			SetScheduleConfigurator(nil)
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		})
+
+		It("should not backfill jobs depending on current balances", func() {
+			for _, name := range []string{JobCollectMinimumBalance, JobCollectCreditLineFee, JobCollectUnarrangedOverdraft} {
+				_, err := jobs.Backfill(name, date(8, 0, 0), date(9, 0, 0))
+				Expect(err).To(MatchError(ContainSubstring("depends on current balances")))
+			}
+		})
+
+		It("should not backfill unknown jobs", func() {
+			_, err := jobs.Backfill("unknown", date(8, 0, 0), date(9, 0, 0))
+			Expect(err).To(MatchError(ContainSubstring("unknown job")))
+		})
+
+		It("should not catch up jobs depending on current balances", func() {
+			planned, err := jobs.PlannedRuns(1, date(10, 12, 0))
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, runs := range planned {
+				rows := sqlmock.NewRows([]string{"id", "job_name", "scheduled_at"})
+				switch runs.Job {
+				case JobCollectMinimumBalance, JobCollectCreditLineFee, JobCollectUnarrangedOverdraft:
+					rows.AddRow(1, runs.Job, date(1, 0, 0))
+				}
+				mock.ExpectQuery("SELECT \\* FROM `job_runs`").WithArgs(runs.Job, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(rows)
+			}
+
+			// the runner would be called for any missed run
+			Expect(jobs.CatchUp(date(10, 12, 0))).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,20 @@
+package scheduled_transaction
+
+import "time"
+
+// JobLease allows only one replica to run the scheduled job at a time,
+// the lease is taken over by another replica once it has expired
+type JobLease struct {
+	JobName string `gorm:"primary_key" json:"jobName"`
+	// Holder identifies the process which holds the lease
+	Holder      string     `json:"holder"`
+	AcquiredAt  *time.Time `json:"acquiredAt"`
+	HeartbeatAt *time.Time `json:"heartbeatAt"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	// Version is incremented every time the lease is acquired
+	Version uint64 `json:"version"`
+}
+
+func (*JobLease) TableName() string {
+	return "job_leases"
+}
@@ -0,0 +1,81 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type JobLeaseRepository struct {
+	db *gorm.DB
+}
+
+func NewJobLeaseRepository(db *gorm.DB) *JobLeaseRepository {
+	return &JobLeaseRepository{db: db}
+}
+
+// Acquire takes the lease of the job for the given time if it is free, expired or already held by the holder.
+// Expiration is compared with the DB clock so that clock skew of replicas can not let two of them hold the lease.
+func (s *JobLeaseRepository) Acquire(jobName, holder string, ttl time.Duration) (bool, error) {
+	table := (&JobLease{}).TableName()
+	seconds := leaseSeconds(ttl)
+	result := s.db.Exec(
+		"INSERT IGNORE INTO "+table+" (job_name, holder, acquired_at, heartbeat_at, expires_at, version)"+
+			" VALUES (?, ?, NOW(), NOW(), NOW() + INTERVAL ? SECOND, 1)",
+		jobName, holder, seconds,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	// version is incremented so the row is always changed once the condition matches
+	result = s.db.Exec(
+		"UPDATE "+table+" SET holder = ?, acquired_at = NOW(), heartbeat_at = NOW(), expires_at = NOW() + INTERVAL ? SECOND,"+
+			" version = version + 1 WHERE job_name = ? AND (expires_at <= NOW() OR holder = ?)",
+		holder, seconds, jobName, holder,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Heartbeat extends the lease held by the holder for the given time, false is returned if the lease has been lost.
+// The lease which has expired is lost even if nobody has taken it over yet.
+func (s *JobLeaseRepository) Heartbeat(jobName, holder string, ttl time.Duration) (bool, error) {
+	result := s.db.Exec(
+		"UPDATE "+(&JobLease{}).TableName()+" SET heartbeat_at = NOW(), expires_at = NOW() + INTERVAL ? SECOND"+
+			" WHERE job_name = ? AND holder = ? AND expires_at > NOW()",
+		leaseSeconds(ttl), jobName, holder,
+	)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Release sets expiration of the lease held by the holder to the given time after its acquisition
+// or to the current time if it is later
+func (s *JobLeaseRepository) Release(jobName, holder string, ttl time.Duration) error {
+	return s.db.Exec(
+		"UPDATE "+(&JobLease{}).TableName()+" SET expires_at = GREATEST(acquired_at + INTERVAL ? SECOND, NOW())"+
+			" WHERE job_name = ? AND holder = ?",
+		leaseSeconds(ttl), jobName, holder,
+	).Error
+}
+
+// FindAll returns leases of all jobs which have been run at least once
+func (s *JobLeaseRepository) FindAll() ([]*JobLease, error) {
+	leases := make([]*JobLease, 0)
+	err := s.db.Order("job_name").Find(&leases).Error
+	return leases, err
+}
+
+func (s JobLeaseRepository) WrapContext(db *gorm.DB) *JobLeaseRepository {
+	s.db = db
+	return &s
+}
+
+func leaseSeconds(ttl time.Duration) int64 {
+	return int64(ttl / time.Second)
+}
@@ -0,0 +1,35 @@
+package scheduled_transaction
+
+import "time"
+
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning = JobRunStatus("running")
+	// JobRunStatusSucceeded means that all items have been processed
+	JobRunStatusSucceeded = JobRunStatus("succeeded")
+	// JobRunStatusCompletedWithErrors means that the job has finished but some items have failed
+	JobRunStatusCompletedWithErrors = JobRunStatus("completed_with_errors")
+	// JobRunStatusFailed means that the job has been interrupted
+	JobRunStatusFailed = JobRunStatus("failed")
+)
+
+// JobRun is a single run of the scheduled job, the job is run once for each scheduled time
+type JobRun struct {
+	Id      *uint64      `json:"id"`
+	JobName string       `json:"jobName" gorm:"unique_index:uix_job_runs_job_name_scheduled_at"`
+	Holder  string       `json:"holder"`
+	Status  JobRunStatus `json:"status"`
+	// ScheduledAt is the time the run is scheduled at, it is in the past for caught up and backfilled runs
+	ScheduledAt *time.Time `json:"scheduledAt" gorm:"unique_index:uix_job_runs_job_name_scheduled_at"`
+	StartedAt   *time.Time `json:"startedAt"`
+	FinishedAt  *time.Time `json:"finishedAt"`
+	// Processed and Failed are counts of items (e.g. accounts or transfers) handled by the run
+	Processed uint64  `json:"processed"`
+	Failed    uint64  `json:"failed"`
+	Error     *string `json:"error"`
+}
+
+func (*JobRun) TableName() string {
+	return "job_runs"
+}
@@ -0,0 +1,88 @@
+package scheduled_transaction
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+type JobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) *JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+// Start records the run of the job at its scheduled time, false is returned if the job has already been run
+// for the scheduled time. The run which has failed or has been left running by a stopped replica is taken over,
+// so it must be called by the holder of the job lease only.
+func (s *JobRunRepository) Start(run *JobRun) (bool, error) {
+	result := s.db.Set("gorm:insert_modifier", "IGNORE").Create(run)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+	run.Id = nil
+
+	result = s.db.
+		Model(&JobRun{}).
+		Where(
+			"job_name = ? AND scheduled_at = ? AND status IN (?)",
+			run.JobName, run.ScheduledAt, []JobRunStatus{JobRunStatusRunning, JobRunStatusFailed},
+		).
+		Updates(map[string]interface{}{
+			"holder":      run.Holder,
+			"status":      run.Status,
+			"started_at":  run.StartedAt,
+			"finished_at": nil,
+			"processed":   0,
+			"failed":      0,
+			"error":       nil,
+		})
+	if result.Error != nil || result.RowsAffected != 1 {
+		return false, result.Error
+	}
+
+	existing := &JobRun{}
+	if err := s.db.Where("job_name = ? AND scheduled_at = ?", run.JobName, run.ScheduledAt).First(existing).Error; err != nil {
+		return false, err
+	}
+	run.Id = existing.Id
+	return true, nil
+}
+
+// Finish records the result of the run unless the run has been taken over by another holder
+func (s *JobRunRepository) Finish(run *JobRun) error {
+	return s.db.
+		Model(&JobRun{}).
+		Where("id = ? AND holder = ?", run.Id, run.Holder).
+		Updates(map[string]interface{}{
+			"status":      run.Status,
+			"finished_at": run.FinishedAt,
+			"processed":   run.Processed,
+			"failed":      run.Failed,
+			"error":       run.Error,
+		}).
+		Error
+}
+
+// FindLastCompleted returns the latest by scheduled time run of the job which has not been interrupted,
+// nil is returned if the job has never been completed
+func (s *JobRunRepository) FindLastCompleted(jobName string) (*JobRun, error) {
+	run := &JobRun{}
+	err := s.db.
+		Where("job_name = ? AND status IN (?)", jobName, []JobRunStatus{JobRunStatusSucceeded, JobRunStatusCompletedWithErrors}).
+		Order("scheduled_at desc").
+		First(run).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return run, err
+}
+
+func (s JobRunRepository) WrapContext(db *gorm.DB) *JobRunRepository {
+	s.db = db
+	return &s
+}
@@ -0,0 +1,207 @@
+package scheduled_transaction
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/robfig/cron"
+)
+
+const (
+	jobLeaseTTL          = time.Minute
+	jobHeartbeatInterval = 20 * time.Second
+)
+
+// JobResult is reported by the job in order to be recorded into its run
+type JobResult struct {
+	Processed uint64
+	Failed    uint64
+}
+
+// JobRunner runs scheduled jobs under the DB lease so that each job is run by a single replica at a time
+type JobRunner struct {
+	leases            *JobLeaseRepository
+	runs              *JobRunRepository
+	holder            string
+	heartbeatInterval time.Duration
+	logger            log15.Logger
+}
+
+func NewJobRunner(leases *JobLeaseRepository, runs *JobRunRepository, logger log15.Logger) *JobRunner {
+	return &JobRunner{
+		leases:            leases,
+		runs:              runs,
+		holder:            jobHolder(),
+		heartbeatInterval: jobHeartbeatInterval,
+		logger:            logger.New("service", "JobRunner"),
+	}
+}
+
+// Job wraps the function into the cron job which is run only if the lease of the job is acquired.
+// The run is recorded with the time it has been scheduled at by cron rather than the time it has been started,
+// scheduled times are calculated in the location of the cron so that they match the ones of catch-up.
+func (r *JobRunner) Job(
+	name string,
+	schedule cron.Schedule,
+	location *time.Location,
+	job func(ctx context.Context) JobResult,
+) cron.Job {
+	var mutex sync.Mutex
+	next := schedule.Next(time.Now().In(location))
+	return cron.FuncJob(func() {
+		mutex.Lock()
+		scheduledAt := latestScheduledTime(schedule, next, time.Now().In(location))
+		next = schedule.Next(scheduledAt)
+		mutex.Unlock()
+
+		r.Run(name, scheduledAt, job)
+	})
+}
+
+// Run runs the job scheduled at the given time and records its run,
+// false is returned if the job is being run by another replica or it has already been run for the scheduled time.
+// The context passed to the job is cancelled once the lease is lost, the job must stop processing items then.
+func (r *JobRunner) Run(name string, scheduledAt time.Time, job func(ctx context.Context) JobResult) bool {
+	logger := r.logger.New("job", name, "scheduledAt", scheduledAt)
+
+	acquired, err := r.leases.Acquire(name, r.holder, jobLeaseTTL)
+	if err != nil {
+		logger.Error("failed to acquire job lease", "error", err)
+		return false
+	}
+	if !acquired {
+		logger.Debug("job is skipped since it is run by another replica")
+		return false
+	}
+
+	startedAt := time.Now()
+	run := &JobRun{
+		JobName:     name,
+		Holder:      r.holder,
+		Status:      JobRunStatusRunning,
+		ScheduledAt: &scheduledAt,
+		StartedAt:   &startedAt,
+	}
+	started, err := r.runs.Start(run)
+	if err != nil || !started {
+		if err != nil {
+			logger.Error("failed to record job run", "error", err)
+		} else {
+			logger.Debug("job is skipped since it has already been run for the scheduled time")
+		}
+		r.release(name, logger)
+		return false
+	}
+
+	// the context is cancelled only by heartbeat until the job is finished
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := make(chan struct{})
+	go r.heartbeat(name, stop, cancel, logger)
+	result, runErr := r.execute(ctx, job)
+	close(stop)
+	isLost := ctx.Err() != nil
+	cancel()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Processed = result.Processed
+	run.Failed = result.Failed
+	switch {
+	case isLost:
+		message := "job lease has been lost, the run has been aborted"
+		run.Status = JobRunStatusFailed
+		run.Error = &message
+	case runErr != nil:
+		message := runErr.Error()
+		run.Status = JobRunStatusFailed
+		run.Error = &message
+		logger.Error("job failed", "error", runErr)
+	case result.Failed > 0:
+		run.Status = JobRunStatusCompletedWithErrors
+	default:
+		run.Status = JobRunStatusSucceeded
+	}
+	if err := r.runs.Finish(run); err != nil {
+		logger.Error("failed to record job run", "error", err)
+	}
+
+	// the lost lease belongs to another replica or it is free already,
+	// the lease which could not be extended expires by itself
+	if !isLost {
+		r.release(name, logger)
+	}
+	return true
+}
+
+// release keeps the lease until its initial expiration,
+// so replicas which start the same job a bit later skip it instead of running it once again
+func (r *JobRunner) release(name string, logger log15.Logger) {
+	if err := r.leases.Release(name, r.holder, jobLeaseTTL); err != nil {
+		logger.Error("failed to release job lease", "error", err)
+	}
+}
+
+func (r *JobRunner) execute(ctx context.Context, job func(ctx context.Context) JobResult) (result JobResult, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return job(ctx), nil
+}
+
+// heartbeat extends the lease while the job is running, the job is aborted once the lease is lost
+// or it could not be extended, since the lease may expire and be taken by another replica then
+func (r *JobRunner) heartbeat(name string, stop <-chan struct{}, abort context.CancelFunc, logger log15.Logger) {
+	ticker := time.NewTicker(r.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			held, err := r.leases.Heartbeat(name, r.holder, jobLeaseTTL)
+			if err != nil {
+				logger.Error("failed to extend job lease, the run is aborted", "error", err)
+				abort()
+				return
+			}
+			if !held {
+				logger.Error("job lease has been lost, the run is aborted")
+				abort()
+				return
+			}
+		}
+	}
+}
+
+// latestScheduledTime returns the latest scheduled time of the schedule starting from the given one and not after now
+func latestScheduledTime(schedule cron.Schedule, from, now time.Time) time.Time {
+	scheduledAt := from
+	for next := schedule.Next(scheduledAt); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		scheduledAt = next
+	}
+	return scheduledAt
+}
+
+// isAborted checks whether the run has been aborted, the job must stop processing items then
+func isAborted(ctx context.Context, logger log15.Logger) bool {
+	if ctx.Err() != nil {
+		logger.Warn("job run has been aborted")
+		return true
+	}
+	return false
+}
+
+// jobHolder identifies the current process among replicas
+func jobHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
@@ -0,0 +1,235 @@
+package scheduled_transaction_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+func newMockDB() (*gorm.DB, sqlmock.Sqlmock) {
+	var db *sql.DB
+	db, mock, err := sqlmock.New()
+	Expect(err).ShouldNot(HaveOccurred())
+	gdb, err := gorm.Open("mysql", db)
+	Expect(err).ShouldNot(HaveOccurred())
+	return gdb, mock
+}
+
+var _ = Describe("JobLeaseRepository", func() {
+	var (
+		mock   sqlmock.Sqlmock
+		leases *JobLeaseRepository
+	)
+
+	BeforeEach(func() {
+		var gdb *gorm.DB
+		gdb, mock = newMockDB()
+		leases = NewJobLeaseRepository(gdb)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	It("should acquire free lease with expiration by the DB clock", func() {
+		mock.ExpectExec("INSERT IGNORE INTO job_leases .* VALUES \\(\\?, \\?, NOW\\(\\), NOW\\(\\), NOW\\(\\) \\+ INTERVAL \\? SECOND, 1\\)").
+			WithArgs("job", "replica-1", 60).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(leases.Acquire("job", "replica-1", time.Minute)).To(BeTrue())
+	})
+
+	It("should take over expired lease", func() {
+		mock.ExpectExec("INSERT IGNORE INTO job_leases").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE job_leases SET .* WHERE job_name = \\? AND \\(expires_at <= NOW\\(\\) OR holder = \\?\\)").
+			WithArgs("replica-1", 60, "job", "replica-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(leases.Acquire("job", "replica-1", time.Minute)).To(BeTrue())
+	})
+
+	It("should not acquire lease held by another replica", func() {
+		mock.ExpectExec("INSERT IGNORE INTO job_leases").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE job_leases").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(leases.Acquire("job", "replica-1", time.Minute)).To(BeFalse())
+	})
+
+	It("should extend only not expired lease of the holder", func() {
+		mock.ExpectExec("UPDATE job_leases SET heartbeat_at = NOW\\(\\), expires_at = NOW\\(\\) \\+ INTERVAL \\? SECOND WHERE job_name = \\? AND holder = \\? AND expires_at > NOW\\(\\)").
+			WithArgs(60, "job", "replica-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(leases.Heartbeat("job", "replica-1", time.Minute)).To(BeTrue())
+
+		mock.ExpectExec("UPDATE job_leases SET heartbeat_at").WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(leases.Heartbeat("job", "replica-1", time.Minute)).To(BeFalse())
+	})
+
+	It("should keep released lease until its initial expiration", func() {
+		mock.ExpectExec("UPDATE job_leases SET expires_at = GREATEST\\(acquired_at \\+ INTERVAL \\? SECOND, NOW\\(\\)\\) WHERE job_name = \\? AND holder = \\?").
+			WithArgs(60, "job", "replica-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(leases.Release("job", "replica-1", time.Minute)).To(Succeed())
+	})
+})
+
+var _ = Describe("JobRunner", func() {
+	var (
+		mock   sqlmock.Sqlmock
+		runner *JobRunner
+	)
+	any := sqlmock.AnyArg()
+	scheduledAt := time.Date(2020, 3, 10, 1, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		var gdb *gorm.DB
+		gdb, mock = newMockDB()
+		runner = NewJobRunner(NewJobLeaseRepository(gdb), NewJobRunRepository(gdb), log15.New())
+		SetHeartbeatInterval(runner, 10*time.Millisecond)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectLeaseAcquired := func() {
+		mock.ExpectExec("INSERT IGNORE INTO job_leases").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	expectRunStarted := func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT IGNORE INTO `job_runs`").
+			WithArgs("job", any, JobRunStatusRunning, scheduledAt, any, nil, 0, 0, nil).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectCommit()
+	}
+	expectRunFinished := func(status JobRunStatus, processed, failed int) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `job_runs` SET").
+			WithArgs(any, failed, any, processed, status, 7, any).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectLeaseReleased := func() {
+		mock.ExpectExec("UPDATE job_leases SET expires_at = GREATEST").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	It("should run the job and record its result", func() {
+		expectLeaseAcquired()
+		expectRunStarted()
+		expectRunFinished(JobRunStatusCompletedWithErrors, 2, 1)
+		expectLeaseReleased()
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			return JobResult{Processed: 2, Failed: 1}
+		})
+		Expect(ran).To(BeTrue())
+	})
+
+	It("should record panicked job as failed", func() {
+		expectLeaseAcquired()
+		expectRunStarted()
+		expectRunFinished(JobRunStatusFailed, 0, 0)
+		expectLeaseReleased()
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			panic("boom")
+		})
+		Expect(ran).To(BeTrue())
+	})
+
+	It("should skip the job run by another replica", func() {
+		mock.ExpectExec("INSERT IGNORE INTO job_leases").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE job_leases").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			Fail("job must not be run")
+			return JobResult{}
+		})
+		Expect(ran).To(BeFalse())
+	})
+
+	It("should skip the job which has already been run for the scheduled time", func() {
+		expectLeaseAcquired()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT IGNORE INTO `job_runs`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `job_runs` SET .* WHERE \\(job_name = \\? AND scheduled_at = \\? AND status IN \\(\\?,\\?\\)\\)").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		expectLeaseReleased()
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			Fail("job must not be run")
+			return JobResult{}
+		})
+		Expect(ran).To(BeFalse())
+	})
+
+	It("should take over the failed run of the scheduled time", func() {
+		expectLeaseAcquired()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT IGNORE INTO `job_runs`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `job_runs` SET").
+			WithArgs(nil, 0, nil, any, 0, any, JobRunStatusRunning, "job", scheduledAt, JobRunStatusRunning, JobRunStatusFailed).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT \\* FROM `job_runs`").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		expectRunFinished(JobRunStatusSucceeded, 1, 0)
+		expectLeaseReleased()
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			return JobResult{Processed: 1}
+		})
+		Expect(ran).To(BeTrue())
+	})
+
+	It("should abort the run once the lease is lost", func() {
+		expectLeaseAcquired()
+		expectRunStarted()
+		mock.ExpectExec("UPDATE job_leases SET heartbeat_at").WillReturnResult(sqlmock.NewResult(0, 0))
+		// the lease is not released since it is not held anymore
+		expectRunFinished(JobRunStatusFailed, 1, 0)
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			select {
+			case <-ctx.Done():
+				return JobResult{Processed: 1}
+			case <-time.After(time.Second):
+				Fail("job has not been aborted")
+				return JobResult{}
+			}
+		})
+		Expect(ran).To(BeTrue())
+	})
+
+	It("should abort the run once the lease could not be extended", func() {
+		expectLeaseAcquired()
+		expectRunStarted()
+		mock.ExpectExec("UPDATE job_leases SET heartbeat_at").WillReturnError(errors.New("connection lost"))
+		expectRunFinished(JobRunStatusFailed, 0, 0)
+
+		ran := runner.Run("job", scheduledAt, func(ctx context.Context) JobResult {
+			select {
+			case <-ctx.Done():
+				return JobResult{}
+			case <-time.After(time.Second):
+				Fail("job has not been aborted")
+				return JobResult{}
+			}
+		})
+		Expect(ran).To(BeTrue())
+	})
+})
@@ -0,0 +1,82 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// scheduleReloadInterval defines how often settings are checked for changes of the schedule
+const scheduleReloadInterval = time.Minute
+
+// PlannedRuns lists next times the job is planned to be run at
+type PlannedRuns struct {
+	Job      string      `json:"job"`
+	NextRuns []time.Time `json:"nextRuns"`
+}
+
+// scheduleConfig returns the schedule configured in settings unless it is overridden
+func (j *Jobs) scheduleConfig() (*ScheduleConfig, string, error) {
+	if getScheduleConfig != nil {
+		config, err := getScheduleConfig()
+		return config, "", err
+	}
+	config, signature := configuredSchedule(j.settingsService, j.logger)
+	return config, signature, nil
+}
+
+// watchSchedule replaces the running cron by the new one once the schedule is changed in settings
+func (j *Jobs) watchSchedule(current *cron.Cron) {
+	ticker := time.NewTicker(scheduleReloadInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		current = j.reloadSchedule(current)
+	}
+}
+
+// reloadSchedule starts the new cron instead of the given one if the schedule has been changed,
+// runs missed while the schedule has been changed are caught up. The running cron is returned.
+func (j *Jobs) reloadSchedule(current *cron.Cron) *cron.Cron {
+	_, signature, err := j.scheduleConfig()
+	if err != nil || signature == j.signature {
+		return current
+	}
+
+	next, err := j.Schedule()
+	if err != nil {
+		j.logger.Error("failed to reload schedule of jobs", "error", err)
+		return current
+	}
+	current.Stop()
+	next.Start()
+	j.logger.Info("schedule of jobs has been reloaded")
+
+	// scheduled times which have passed between the previous and the new schedule are run
+	j.CatchUp(time.Now())
+	return next
+}
+
+// PlannedRuns returns the given number of next run times of each job according to the schedule in settings,
+// the changed schedule is applied by running cron within a minute
+func (j *Jobs) PlannedRuns(count int, now time.Time) ([]*PlannedRuns, error) {
+	schedule, _, err := j.scheduleConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := j.list(schedule)
+	result := make([]*PlannedRuns, len(jobs))
+	for i, job := range jobs {
+		runs := &PlannedRuns{Job: job.name, NextRuns: make([]time.Time, 0, count)}
+		next := now.In(schedule.Location)
+		for len(runs.NextRuns) < count {
+			next = job.schedule.Next(next)
+			if next.IsZero() {
+				break
+			}
+			runs.NextRuns = append(runs.NextRuns, next)
+		}
+		result[i] = runs
+	}
+	return result, nil
+}
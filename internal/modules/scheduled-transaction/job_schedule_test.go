@@ -0,0 +1,147 @@
+package scheduled_transaction_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+)
+
+// expectScheduleSettings expects settings of the schedule to be read, settings which are not given are not set
+func expectScheduleSettings(mock sqlmock.Sqlmock, values map[string]string) {
+	for _, name := range ScheduleSettingNames() {
+		rows := sqlmock.NewRows([]string{"name", "value"})
+		if value, ok := values[name]; ok {
+			rows.AddRow(name, value)
+		}
+		mock.ExpectQuery("SELECT \\* FROM `settings`").WithArgs(name).WillReturnRows(rows)
+	}
+}
+
+var _ = Describe("Job schedule", func() {
+	var (
+		mock            sqlmock.Sqlmock
+		settingsService *settings.Service
+	)
+	now := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		gdb, sqlMock := newMockDB()
+		mock = sqlMock
+		settingsService = settings.NewService(settingsRepository.NewSettings(gdb))
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	Context("configuredSchedule", func() {
+		It("should use defaults if nothing is set", func() {
+			expectScheduleSettings(mock, nil)
+			config, _ := ConfiguredSchedule(settingsService, log15.New())
+
+			defaults, err := DefaultSchedule()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(config).To(Equal(defaults))
+		})
+
+		It("should use defaults instead of invalid or empty settings", func() {
+			expectScheduleSettings(mock, nil)
+			_, defaultSignature := ConfiguredSchedule(settingsService, log15.New())
+
+			expectScheduleSettings(mock, map[string]string{
+				"job_schedule_time_zone":             "Mars/Olympus",
+				"job_schedule_expire_cards":          "every now and then",
+				"job_schedule_import_exchange_rates": "",
+			})
+			config, signature := ConfiguredSchedule(settingsService, log15.New())
+
+			defaults, err := DefaultSchedule()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(config).To(Equal(defaults))
+			Expect(signature).To(Equal(defaultSignature))
+		})
+
+		It("should apply valid settings and change the signature", func() {
+			expectScheduleSettings(mock, nil)
+			_, defaultSignature := ConfiguredSchedule(settingsService, log15.New())
+
+			expectScheduleSettings(mock, map[string]string{"job_schedule_expire_cards": "0 15 3 * *"})
+			config, signature := ConfiguredSchedule(settingsService, log15.New())
+			Expect(signature).NotTo(Equal(defaultSignature))
+			Expect(config.ExpireCards.Next(now.In(config.Location))).
+				To(Equal(time.Date(2020, 3, 11, 3, 15, 0, 0, config.Location)))
+
+			expectScheduleSettings(mock, map[string]string{"job_schedule_time_zone": "Europe/Berlin"})
+			config, signature = ConfiguredSchedule(settingsService, log15.New())
+			Expect(signature).NotTo(Equal(defaultSignature))
+			Expect(config.Location.String()).To(Equal("Europe/Berlin"))
+		})
+	})
+
+	Context("Jobs", func() {
+		var jobs *Jobs
+
+		BeforeEach(func() {
+			gdb, sqlMock := newMockDB()
+			mock = sqlMock
+			settingsService = settings.NewService(settingsRepository.NewSettings(gdb))
+			jobs = NewJobs(nil, nil, gdb, nil, nil, nil, nil, nil, settingsService, nil, NewJobRunRepository(gdb), log15.New())
+		})
+
+		It("should plan runs in the configured time zone", func() {
+			expectScheduleSettings(mock, map[string]string{"job_schedule_time_zone": "America/New_York"})
+			planned, err := jobs.PlannedRuns(2, now)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			newYork, err := time.LoadLocation("America/New_York")
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, runs := range planned {
+				if runs.Job != JobCollectAccountInterest {
+					continue
+				}
+				// at 00:30 in New York, which has already switched to summer time (UTC-4)
+				Expect(runs.NextRuns).To(Equal([]time.Time{
+					time.Date(2020, 3, 11, 0, 30, 0, 0, newYork),
+					time.Date(2020, 3, 12, 0, 30, 0, 0, newYork),
+				}))
+				Expect(runs.NextRuns[0].UTC()).To(Equal(time.Date(2020, 3, 11, 4, 30, 0, 0, time.UTC)))
+				return
+			}
+			Fail("job is not planned")
+		})
+
+		It("should keep running cron while the schedule is not changed", func() {
+			expectScheduleSettings(mock, nil)
+			current, err := jobs.Schedule()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			expectScheduleSettings(mock, nil)
+			Expect(jobs.ReloadSchedule(current)).To(BeIdenticalTo(current))
+		})
+
+		It("should replace cron and catch up missed runs once the schedule is changed", func() {
+			expectScheduleSettings(mock, nil)
+			current, err := jobs.Schedule()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			changed := map[string]string{"job_schedule_expire_cards": "0 15 3 * *"}
+			// the schedule is checked, rebuilt and then used for catching up
+			expectScheduleSettings(mock, changed)
+			expectScheduleSettings(mock, changed)
+			expectScheduleSettings(mock, changed)
+			for range ScheduleSettingNames()[1:] {
+				mock.ExpectQuery("SELECT \\* FROM `job_runs`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			}
+
+			next := jobs.ReloadSchedule(current)
+			defer next.Stop()
+			Expect(next).NotTo(BeIdenticalTo(current))
+		})
+	})
+})
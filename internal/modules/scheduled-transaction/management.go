@@ -0,0 +1,186 @@
+package scheduled_transaction
+
+import (
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	"github.com/jinzhu/now"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/form"
+	system_logs "github.com/Confialink/wallet-accounts/internal/modules/system-logs"
+	"github.com/Confialink/wallet-accounts/internal/modules/user"
+)
+
+const scheduledDateLayout = "2006-01-02"
+
+// ManagementService allows administrators to waive, change and execute pending scheduled transactions,
+// all changes are recorded into system logs
+type ManagementService struct {
+	db                *gorm.DB
+	repo              *Repository
+	requestCreator    *request.Creator
+	systemLogsService *system_logs.SystemLogsService
+	logger            log15.Logger
+}
+
+func NewManagementService(
+	db *gorm.DB,
+	repo *Repository,
+	requestCreator *request.Creator,
+	systemLogsService *system_logs.SystemLogsService,
+	logger log15.Logger,
+) *ManagementService {
+	return &ManagementService{
+		db:                db,
+		repo:              repo,
+		requestCreator:    requestCreator,
+		systemLogsService: systemLogsService,
+		logger:            logger.New("service", "ScheduledTransactionManagement"),
+	}
+}
+
+// Waive cancels the pending fee with the given reason
+func (s *ManagementService) Waive(id uint64, waiveForm *form.Waive, userId string) (*ScheduledTransaction, error) {
+	tx := s.db.Begin()
+	transaction, err := s.findPending(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !transaction.Reason.IsFee() {
+		tx.Rollback()
+		return nil, errcodes.CreatePublicError(errcodes.CodeScheduledTransactionNotFee)
+	}
+
+	old := *transaction
+	transaction.Status = StatusWaived
+	transaction.WaiveReason = waiveForm.Reason
+	if err := s.repo.WrapContext(tx).Updates(&ScheduledTransaction{
+		Id:          transaction.Id,
+		Status:      transaction.Status,
+		WaiveReason: transaction.WaiveReason,
+	}); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to waive scheduled transaction")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	s.systemLogsService.LogModifyScheduledTransactionAsync(
+		system_logs.SubjectWaiveScheduledTransaction, id, &old, transaction, userId,
+	)
+	return transaction, nil
+}
+
+// Update moves scheduled date and adjusts amount of the pending transaction,
+// the amount could be adjusted only for fees
+func (s *ManagementService) Update(
+	id uint64,
+	updateForm *form.Update,
+	userId string,
+	currentTime time.Time,
+) (*ScheduledTransaction, error) {
+	update := &ScheduledTransaction{}
+	if updateForm.ScheduledDate != nil {
+		scheduledDate, err := time.ParseInLocation(scheduledDateLayout, *updateForm.ScheduledDate, currentTime.Location())
+		if err != nil {
+			return nil, errcodes.CreatePublicError(errcodes.CodeInvalidQueryParameters, err.Error())
+		}
+		if scheduledDate.Before(now.New(currentTime).BeginningOfDay()) {
+			return nil, errcodes.CreatePublicError(errcodes.CodeScheduledDateInPast)
+		}
+		update.ScheduledDate = &scheduledDate
+	}
+	if updateForm.Amount != nil {
+		amount, err := decimal.NewFromString(*updateForm.Amount)
+		if err != nil || !amount.IsNegative() {
+			return nil, errcodes.CreatePublicError(errcodes.CodeInvalidScheduledAmount)
+		}
+		update.Amount = amount
+	}
+
+	tx := s.db.Begin()
+	transaction, err := s.findPending(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if updateForm.Amount != nil && !transaction.Reason.IsFee() {
+		tx.Rollback()
+		return nil, errcodes.CreatePublicError(errcodes.CodeScheduledTransactionNotFee)
+	}
+
+	old := *transaction
+	update.Id = transaction.Id
+	if err := s.repo.WrapContext(tx).Updates(update); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to update scheduled transaction")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	if update.ScheduledDate != nil {
+		transaction.ScheduledDate = update.ScheduledDate
+	}
+	if updateForm.Amount != nil {
+		transaction.Amount = update.Amount
+	}
+	s.systemLogsService.LogModifyScheduledTransactionAsync(
+		system_logs.SubjectModifyScheduledTransaction, id, &old, transaction, userId,
+	)
+	return transaction, nil
+}
+
+// Execute executes the pending transaction without waiting for its scheduled date
+func (s *ManagementService) Execute(id uint64, userId string) (*ScheduledTransaction, error) {
+	tx := s.db.Begin()
+	transaction, err := s.findPending(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	old := *transaction
+	systemUser := user.GetSystemUser()
+	req, err := executeTransaction(tx, transaction, s.requestCreator, &systemUser)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := markExecuted(tx, transaction, req, s.repo); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "failed to update scheduled transaction")
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	transaction.Status = StatusExecuted
+	transaction.RequestId = req.Id
+	s.systemLogsService.LogModifyScheduledTransactionAsync(
+		system_logs.SubjectExecuteScheduledTransaction, id, &old, transaction, userId,
+	)
+	return transaction, nil
+}
+
+// findPending locks the transaction within the given db transaction and checks it is pending
+func (s *ManagementService) findPending(tx *gorm.DB, id uint64) (*ScheduledTransaction, error) {
+	transaction, err := s.repo.WrapContext(tx).FindByIdForUpdate(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find scheduled transaction")
+	}
+	if transaction == nil {
+		return nil, errcodes.CreatePublicError(errcodes.CodeTransactionNotFound)
+	}
+	if transaction.Status != StatusPending {
+		return nil, errcodes.CreatePublicError(errcodes.CodeScheduledTransactionNotPending)
+	}
+	return transaction, nil
+}
@@ -0,0 +1,171 @@
+package scheduled_transaction_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/form"
+	system_logs "github.com/Confialink/wallet-accounts/internal/modules/system-logs"
+)
+
+// publicErrorCode returns code of the public error
+func publicErrorCode(err error) string {
+	Expect(err).To(BeAssignableToTypeOf(&errors.PublicError{}))
+	return err.(*errors.PublicError).Code
+}
+
+var _ = Describe("ManagementService", func() {
+	var (
+		mock    sqlmock.Sqlmock
+		service *ManagementService
+	)
+	any := sqlmock.AnyArg()
+	currentTime := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+	waiveReason := "goodwill"
+
+	BeforeEach(func() {
+		gdb, sqlMock := newMockDB()
+		mock = sqlMock
+		logsService := system_logs.NewSystemLogsService(
+			nil, nil, nil, nil, nil, nil, system_logs.NewScheduledTransactionLogCreator(log15.New()),
+		)
+		service = NewManagementService(gdb, NewRepository(gdb), nil, logsService, log15.New())
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectLocked := func(reason Reason, status Status) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(id = \\?\\) .* FOR UPDATE").
+			WithArgs(5).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "reason", "amount", "status", "scheduled_date"}).
+				AddRow(5, string(reason), "-10", string(status), currentTime.AddDate(0, 0, 5)))
+	}
+
+	Context("Waive", func() {
+		It("should waive the pending fee", func() {
+			expectLocked(ReasonMaintenanceFee, StatusPending)
+			mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+				WithArgs(5, StatusWaived, any, waiveReason, 5).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			transaction, err := service.Waive(5, &form.Waive{Reason: &waiveReason}, "admin")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(transaction.Status).To(Equal(StatusWaived))
+			Expect(*transaction.WaiveReason).To(Equal(waiveReason))
+		})
+
+		It("should not waive transactions other than fees", func() {
+			expectLocked(ReasonInterestGeneration, StatusPending)
+			mock.ExpectRollback()
+
+			_, err := service.Waive(5, &form.Waive{Reason: &waiveReason}, "admin")
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeScheduledTransactionNotFee))
+		})
+
+		It("should not waive the fee which is not pending", func() {
+			expectLocked(ReasonMaintenanceFee, StatusExecuted)
+			mock.ExpectRollback()
+
+			_, err := service.Waive(5, &form.Waive{Reason: &waiveReason}, "admin")
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeScheduledTransactionNotPending))
+		})
+
+		It("should not waive unknown transactions", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectRollback()
+
+			_, err := service.Waive(5, &form.Waive{Reason: &waiveReason}, "admin")
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeTransactionNotFound))
+		})
+	})
+
+	Context("Update", func() {
+		date := func(value string) *form.Update {
+			return &form.Update{ScheduledDate: &value}
+		}
+		amount := func(value string) *form.Update {
+			return &form.Update{Amount: &value}
+		}
+
+		It("should move the pending transaction to another date", func() {
+			expectLocked(ReasonInterestGeneration, StatusPending)
+			mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+				WithArgs(5, time.Date(2020, 3, 12, 0, 0, 0, 0, time.UTC), any, 5).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			transaction, err := service.Update(5, date("2020-03-12"), "admin", currentTime)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(*transaction.ScheduledDate).To(Equal(time.Date(2020, 3, 12, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should adjust amount of the pending fee", func() {
+			expectLocked(ReasonMaintenanceFee, StatusPending)
+			mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+				WithArgs("-5", 5, any, 5).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			transaction, err := service.Update(5, amount("-5"), "admin", currentTime)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(transaction.Amount.String()).To(Equal("-5"))
+		})
+
+		It("should not adjust amount of transactions other than fees", func() {
+			expectLocked(ReasonInterestGeneration, StatusPending)
+			mock.ExpectRollback()
+
+			_, err := service.Update(5, amount("-5"), "admin", currentTime)
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeScheduledTransactionNotFee))
+		})
+
+		It("should not update the transaction which is not pending", func() {
+			expectLocked(ReasonMaintenanceFee, StatusWaived)
+			mock.ExpectRollback()
+
+			_, err := service.Update(5, date("2020-03-12"), "admin", currentTime)
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeScheduledTransactionNotPending))
+		})
+
+		It("should not move the transaction to the past", func() {
+			_, err := service.Update(5, date("2020-03-09"), "admin", currentTime)
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeScheduledDateInPast))
+		})
+
+		It("should accept only negative fee amounts", func() {
+			_, err := service.Update(5, amount("5"), "admin", currentTime)
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeInvalidScheduledAmount))
+		})
+	})
+
+	Context("Execute", func() {
+		It("should not execute the transaction which is not pending", func() {
+			expectLocked(ReasonMaintenanceFee, StatusExecuted)
+			mock.ExpectRollback()
+
+			_, err := service.Execute(5, "admin")
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeScheduledTransactionNotPending))
+		})
+
+		It("should not execute unknown transactions", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectRollback()
+
+			_, err := service.Execute(5, "admin")
+			Expect(publicErrorCode(err)).To(Equal(errcodes.CodeTransactionNotFound))
+		})
+	})
+})
@@ -16,6 +16,7 @@ type ScheduledTransaction struct {
 	Status        Status          `json:"status"`
 	RequestId     *uint64         `json:"requestId"`
 	ScheduledDate *time.Time      `json:"scheduledDate"`
+	WaiveReason   *string         `json:"waiveReason"`
 	CreatedAt     *time.Time      `json:"createdAt"`
 	UpdatedAt     *time.Time      `json:"updatedAt"`
 }
@@ -1,6 +1,7 @@
 package scheduled_transaction
 
 import (
+	"context"
 	"sync"
 
 	"github.com/robfig/cron"
@@ -9,114 +10,245 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/jinzhu/gorm"
 
+	accountTypeService "github.com/Confialink/wallet-accounts/internal/modules/account-type/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/calculation"
+	cardService "github.com/Confialink/wallet-accounts/internal/modules/card/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/currency"
 	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
 )
 
-func Schedule(
+// names of scheduled jobs identify their leases and runs
+const (
+	JobCollectCreditLineFee         = "collect_credit_line_fee"
+	JobCollectAccountInterest       = "collect_account_interest"
+	JobCollectMaintenanceFee        = "collect_maintenance_fee"
+	JobCollectMinimumBalance        = "collect_minimum_balance"
+	JobChargeCreditLine             = "charge_credit_line"
+	JobPayoutAccountInterest        = "payout_account_interest"
+	JobChargeMinimumBalance         = "charge_minimum_balance"
+	JobChargeAccountMaintenanceFee  = "charge_account_maintenance_fee"
+	JobNotifyExpiringCards          = "notify_expiring_cards"
+	JobExpireCards                  = "expire_cards"
+	JobNotifyMaturingDeposits       = "notify_maturing_deposits"
+	JobProcessMaturedDeposits       = "process_matured_deposits"
+	JobChargeEarlyWithdrawalPenalty = "charge_early_withdrawal_penalty"
+	JobCollectUnarrangedOverdraft   = "collect_unarranged_overdraft"
+	JobChargeUnarrangedOverdraft    = "charge_unarranged_overdraft"
+	JobDetectDormantAccounts        = "detect_dormant_accounts"
+	JobChargeDormancyFee            = "charge_dormancy_fee"
+	JobImportExchangeRates          = "import_exchange_rates"
+)
+
+// Jobs are scheduled jobs of the service, they are run by cron and can be run for past scheduled times
+// in order to catch up runs missed while the service was down
+type Jobs struct {
+	repo               *Repository
+	requestCreator     *request.Creator
+	db                 *gorm.DB
+	scheduler          *Service
+	cardExpiryService  *cardService.ExpiryService
+	interestRates      *accountTypeService.InterestRateService
+	termDepositService *TermDepositService
+	ratesImporter      *currency.RatesImporter
+	settingsService    *settings.Service
+	runner             *JobRunner
+	runs               *JobRunRepository
+	logger             log15.Logger
+	mutex              sync.Mutex
+	// signature of the schedule which cron has been built from
+	signature string
+}
+
+func NewJobs(
 	repo *Repository,
 	requestCreator *request.Creator,
 	db *gorm.DB,
 	scheduler *Service,
+	cardExpiryService *cardService.ExpiryService,
+	interestRates *accountTypeService.InterestRateService,
+	termDepositService *TermDepositService,
+	ratesImporter *currency.RatesImporter,
+	settingsService *settings.Service,
+	runner *JobRunner,
+	runs *JobRunRepository,
 	logger log15.Logger,
-) (*cron.Cron, error) {
-	mutex := sync.Mutex{}
+) *Jobs {
+	return &Jobs{
+		repo:               repo,
+		requestCreator:     requestCreator,
+		db:                 db,
+		scheduler:          scheduler,
+		cardExpiryService:  cardExpiryService,
+		interestRates:      interestRates,
+		termDepositService: termDepositService,
+		ratesImporter:      ratesImporter,
+		settingsService:    settingsService,
+		runner:             runner,
+		runs:               runs,
+		logger:             logger.New("module", "scheduled_transaction"),
+	}
+}
+
+// scheduledJob is a single job along with its schedule
+type scheduledJob struct {
+	name     string
+	schedule cron.Schedule
+	// eachSlot means that every missed scheduled time is run separately since the job handles a single period
+	// (e.g. accrues interest for the day), otherwise a single run handles all missed scheduled times
+	eachSlot bool
+	// balanceDependent means that the job handles current balances of accounts (e.g. charges fees on them),
+	// its missed runs can not be caught up or backfilled since past balances are not known
+	balanceDependent bool
+	run              func(ctx context.Context, timeSource utils.Time) JobResult
+}
 
-	schedule, err := getScheduleConfig()
+// Schedule registers all jobs in cron
+func (j *Jobs) Schedule() (*cron.Cron, error) {
+	schedule, signature, err := j.scheduleConfig()
 	if err != nil {
 		return nil, err
 	}
+	j.signature = signature
 
 	timeSource := utils.NewTimeSource(schedule.Location)
 
 	localizedCron := cron.NewWithLocation(schedule.Location)
-
-	// Collect line of credit data
-	localizedCron.Schedule(schedule.CollectCreditLineFee, cron.FuncJob(func() {
-		logger.Info("running scheduled job: collect line of credit data")
-		mutex.Lock()
-		defer mutex.Unlock()
-		WatchCreditLine(scheduler, db, logger, timeSource)
-	}))
-	// Collect account interest data
-	localizedCron.Schedule(schedule.CollectAccountInterest, cron.FuncJob(func() {
-		logger.Info("running scheduled job: collect account interest data")
-		mutex.Lock()
-		defer mutex.Unlock()
-		WatchInterestGeneration(scheduler, db, logger, timeSource)
-	}))
-	// Collect maintenance fee
-	localizedCron.Schedule(schedule.CollectMaintenanceFee, cron.FuncJob(func() {
-		logger.Info("running scheduled job: collect maintenance fee")
-		mutex.Lock()
-		defer mutex.Unlock()
-		WatchMaintenanceFee(scheduler, db, logger, timeSource)
-	}))
-	// Collect maintenance fee
-	localizedCron.Schedule(schedule.CollectMinimumBalance, cron.FuncJob(func() {
-		logger.Info("running scheduled job: collect minimum balance fee")
-		mutex.Lock()
-		defer mutex.Unlock()
-		WatchLimitBalance(scheduler, db, logger, timeSource)
-	}))
-	// Charge account maintenance fee
-	localizedCron.Schedule(schedule.ChargeAccountMaintenanceFee, cron.FuncJob(func() {
-		logger.Info("running scheduled job: charge account maintenance fee")
-		mutex.Lock()
-		defer mutex.Unlock()
-		ExecuteScheduledTransactions(
-			getScheduledTransactions(ReasonMaintenanceFee, timeSource, repo, logger),
-			repo,
-			requestCreator,
-			db,
-			logger,
-		)
-	}))
-
-	// Payout account interest
-	localizedCron.Schedule(schedule.PayoutAccountInterest, cron.FuncJob(func() {
-		logger.Info("running scheduled job: payout account interest")
-		mutex.Lock()
-		defer mutex.Unlock()
-		ExecuteScheduledTransactions(
-			getScheduledTransactions(ReasonInterestGeneration, timeSource, repo, logger),
-			repo,
-			requestCreator,
-			db,
-			logger,
-		)
-	}))
-
-	// Charge minimum balance fee
-	localizedCron.Schedule(schedule.ChargeMinimumBalance, cron.FuncJob(func() {
-		logger.Info("running scheduled job: charge minimum balance fee")
-		mutex.Lock()
-		defer mutex.Unlock()
-		ExecuteScheduledTransactions(
-			getScheduledTransactions(ReasonLimitBalanceFee, timeSource, repo, logger),
-			repo,
-			requestCreator,
-			db,
-			logger,
-		)
-	}))
-
-	// Charge line of credit fee
-	localizedCron.Schedule(schedule.ChargeCreditLine, cron.FuncJob(func() {
-		logger.Info("running scheduled job: charge line of credit fee")
-		mutex.Lock()
-		defer mutex.Unlock()
-		ExecuteScheduledTransactions(
-			getScheduledTransactions(ReasonCreditLineFee, timeSource, repo, logger),
-			repo,
-			requestCreator,
-			db,
-			logger,
-		)
-	}))
+	for _, job := range j.list(schedule) {
+		run := job.run
+		localizedCron.Schedule(job.schedule, j.runner.Job(job.name, job.schedule, schedule.Location, func(ctx context.Context) JobResult {
+			return run(ctx, timeSource)
+		}))
+	}
 
 	return localizedCron, nil
 }
 
+func (j *Jobs) list(schedule *ScheduleConfig) []*scheduledJob {
+	logger := j.logger
+	return []*scheduledJob{
+		// Collect line of credit data
+		{name: JobCollectCreditLineFee, schedule: schedule.CollectCreditLineFee, eachSlot: true, balanceDependent: true, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: collect line of credit data")
+			j.mutex.Lock()
+			defer j.mutex.Unlock()
+			return WatchCreditLine(ctx, j.scheduler, j.db, logger, timeSource, interestDayCountConvention(j.settingsService, logger), j.interestRates)
+		}},
+		// Collect account interest data
+		{name: JobCollectAccountInterest, schedule: schedule.CollectAccountInterest, eachSlot: true, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: collect account interest data")
+			j.mutex.Lock()
+			defer j.mutex.Unlock()
+			return WatchInterestGeneration(ctx, j.scheduler, j.db, logger, timeSource, interestDayCountConvention(j.settingsService, logger), j.interestRates)
+		}},
+		// Collect maintenance fee
+		{name: JobCollectMaintenanceFee, schedule: schedule.CollectMaintenanceFee, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: collect maintenance fee")
+			j.mutex.Lock()
+			defer j.mutex.Unlock()
+			return WatchMaintenanceFee(ctx, j.scheduler, j.db, logger, timeSource)
+		}},
+		// Collect minimum balance fee
+		{name: JobCollectMinimumBalance, schedule: schedule.CollectMinimumBalance, balanceDependent: true, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: collect minimum balance fee")
+			j.mutex.Lock()
+			defer j.mutex.Unlock()
+			return WatchLimitBalance(ctx, j.scheduler, j.db, logger, timeSource)
+		}},
+		// Charge account maintenance fee
+		{name: JobChargeAccountMaintenanceFee, schedule: schedule.ChargeAccountMaintenanceFee, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: charge account maintenance fee")
+			return j.execute(ctx, ReasonMaintenanceFee, timeSource)
+		}},
+		// Payout account interest
+		{name: JobPayoutAccountInterest, schedule: schedule.PayoutAccountInterest, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: payout account interest")
+			return j.execute(ctx, ReasonInterestGeneration, timeSource)
+		}},
+		// Charge minimum balance fee
+		{name: JobChargeMinimumBalance, schedule: schedule.ChargeMinimumBalance, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: charge minimum balance fee")
+			return j.execute(ctx, ReasonLimitBalanceFee, timeSource)
+		}},
+		// Charge line of credit fee
+		{name: JobChargeCreditLine, schedule: schedule.ChargeCreditLine, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: charge line of credit fee")
+			return j.execute(ctx, ReasonCreditLineFee, timeSource)
+		}},
+		// Notify owners about expiring cards
+		{name: JobNotifyExpiringCards, schedule: schedule.NotifyExpiringCards, eachSlot: true, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: notify expiring cards")
+			j.cardExpiryService.NotifyExpiring(timeSource.Now())
+			return JobResult{}
+		}},
+		// Expire (and renew if enabled) cards
+		{name: JobExpireCards, schedule: schedule.ExpireCards, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: expire cards")
+			j.mutex.Lock()
+			defer j.mutex.Unlock()
+			j.cardExpiryService.ExpireCards(timeSource.Now())
+			return JobResult{}
+		}},
+		// Notify owners about maturing term deposits
+		{name: JobNotifyMaturingDeposits, schedule: schedule.NotifyMaturingDeposits, eachSlot: true, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: notify maturing term deposits")
+			return j.termDepositService.NotifyMaturing(ctx, timeSource.Now())
+		}},
+		// Payout or rollover matured term deposits
+		{name: JobProcessMaturedDeposits, schedule: schedule.ProcessMaturedDeposits, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: process matured term deposits")
+			j.mutex.Lock()
+			defer j.mutex.Unlock()
+			return j.termDepositService.ProcessMatured(ctx, timeSource.Now())
+		}},
+		// Charge penalties for early withdrawals from term deposits
+		{name: JobChargeEarlyWithdrawalPenalty, schedule: schedule.ChargeEarlyWithdrawalPenalty, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: charge early withdrawal penalty")
+			return j.execute(ctx, ReasonEarlyWithdrawalPenalty, timeSource)
+		}},
+		// Schedule daily fees for balances below arranged overdraft limits
+		{name: JobCollectUnarrangedOverdraft, schedule: schedule.CollectUnarrangedOverdraft, eachSlot: true, balanceDependent: true, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: collect unarranged overdraft fee")
+			return WatchUnarrangedOverdraft(ctx, j.scheduler, j.db, logger, timeSource)
+		}},
+		// Charge unarranged overdraft fees
+		{name: JobChargeUnarrangedOverdraft, schedule: schedule.ChargeUnarrangedOverdraft, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: charge unarranged overdraft fee")
+			return j.execute(ctx, ReasonUnarrangedOverdraftFee, timeSource)
+		}},
+		// Flag accounts without customer activity as dormant and schedule dormancy fees
+		{name: JobDetectDormantAccounts, schedule: schedule.DetectDormantAccounts, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: detect dormant accounts")
+			return WatchDormancy(ctx, j.scheduler, j.db, logger, timeSource, dormancyPeriodDays(j.settingsService))
+		}},
+		// Charge dormancy fees
+		{name: JobChargeDormancyFee, schedule: schedule.ChargeDormancyFee, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: charge dormancy fee")
+			return j.execute(ctx, ReasonDormancyFee, timeSource)
+		}},
+		// Import exchange rates file into local rates
+		{name: JobImportExchangeRates, schedule: schedule.ImportExchangeRates, run: func(ctx context.Context, timeSource utils.Time) JobResult {
+			logger.Info("running scheduled job: import exchange rates")
+			j.ratesImporter.ImportConfigured()
+			return JobResult{}
+		}},
+	}
+}
+
+// execute executes scheduled transactions with the given reason which are due by now
+func (j *Jobs) execute(ctx context.Context, reason Reason, timeSource utils.Time) JobResult {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return ExecuteScheduledTransactions(
+		ctx,
+		getScheduledTransactions(reason, timeSource, j.repo, j.logger),
+		j.repo,
+		j.requestCreator,
+		j.db,
+		j.logger,
+	)
+}
+
 func getScheduledTransactions(reason Reason, timeSource utils.Time, repo *Repository, logger log15.Logger) []*ScheduledTransaction {
 	scheduledTransactions, err := repo.GetScheduled(string(reason), timeSource.Now())
 	if err != nil {
@@ -125,3 +257,17 @@ func getScheduledTransactions(reason Reason, timeSource utils.Time, repo *Reposi
 	}
 	return scheduledTransactions
 }
+
+// interestDayCountConvention returns configured day count convention, the default one is used if it is not valid
+func interestDayCountConvention(settingsService *settings.Service, logger log15.Logger) calculation.DayCountConvention {
+	value, err := settingsService.String(SettingInterestDayCountConventionString)
+	if err != nil || value == "" {
+		return calculation.DefaultDayCountConvention
+	}
+	convention, err := calculation.DayCountConventionFromString(value)
+	if err != nil {
+		logger.Error("invalid interest day count convention", "error", err)
+		return calculation.DefaultDayCountConvention
+	}
+	return convention
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Confialink/wallet-pkg-list_params"
 	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
 )
 
 type Repository struct {
@@ -41,11 +42,12 @@ func (s *Repository) GetScheduled(reason string, now time.Time) ([]*ScheduledTra
 	return result, err
 }
 
-// FindNextPendingByAccountIdAndReason looking scheduled for future transaction with the given account id and reason
-// having status "pending"
+// FindNextPendingByAccountIdAndReason looking scheduled after the given time transaction with the given account id
+// and reason having status "pending"
 func (s *Repository) FindNextPendingByAccountIdAndReason(
 	accountId uint64,
 	reason Reason,
+	after time.Time,
 ) (*ScheduledTransaction, error) {
 
 	scheduledTransaction := &ScheduledTransaction{}
@@ -55,12 +57,68 @@ func (s *Repository) FindNextPendingByAccountIdAndReason(
 		accountId,
 		reason,
 		StatusPending,
-		time.Now(),
+		after,
 	).Error
 
 	return scheduledTransaction, err
 }
 
+// FindPendingByAccountId retrieves all pending transactions of the account in order of their scheduled dates
+func (s *Repository) FindPendingByAccountId(accountId uint64) ([]*ScheduledTransaction, error) {
+	var result []*ScheduledTransaction
+
+	err := s.db.
+		Preload("Account").
+		Preload("Account.Type").
+		Order("scheduled_date").
+		Find(
+			&result,
+			"account_id = ? AND status = ?",
+			accountId,
+			StatusPending,
+		).Error
+
+	return result, err
+}
+
+// FindPendingByAccountIdAndReason returns all pending transactions with the given account id and reason
+// regardless of scheduled date
+func (s *Repository) FindPendingByAccountIdAndReason(accountId uint64, reason Reason) ([]*ScheduledTransaction, error) {
+	var result []*ScheduledTransaction
+
+	err := s.db.
+		Preload("Account").
+		Preload("Account.Type").
+		Find(
+			&result,
+			"account_id = ? AND reason = ? AND status = ?",
+			accountId,
+			reason,
+			StatusPending,
+		).Error
+
+	return result, err
+}
+
+// WaivePendingByAccountIdAndReason waives all pending transactions with the given account id and reason
+func (s *Repository) WaivePendingByAccountIdAndReason(accountId uint64, reason Reason, waiveReason string) error {
+	return s.db.
+		Model(&ScheduledTransaction{}).
+		Where("account_id = ? AND reason = ? AND status = ?", accountId, reason, StatusPending).
+		Updates(map[string]interface{}{"status": StatusWaived, "waive_reason": waiveReason}).
+		Error
+}
+
+// MarkExecuted marks the pending transaction as executed by the given request,
+// false is returned if the transaction is not pending anymore
+func (s *Repository) MarkExecuted(id uint64, amount decimal.Decimal, requestId *uint64) (bool, error) {
+	result := s.db.
+		Model(&ScheduledTransaction{}).
+		Where("id = ? AND status = ?", id, StatusPending).
+		Updates(map[string]interface{}{"amount": amount, "request_id": requestId, "status": StatusExecuted})
+	return result.RowsAffected > 0, result.Error
+}
+
 func (s Repository) WrapContext(db *gorm.DB) *Repository {
 	s.db = db
 	return &s
@@ -134,3 +192,19 @@ func (repo *Repository) FindByID(id uint64) (*ScheduledTransaction, error) {
 	}
 	return &transaction, nil
 }
+
+// FindByIdForUpdate locks the scheduled transaction, nil is returned if the transaction is not found
+func (repo *Repository) FindByIdForUpdate(id uint64) (*ScheduledTransaction, error) {
+	transaction := &ScheduledTransaction{}
+	err := repo.db.
+		Set("gorm:query_option", "FOR UPDATE").
+		Preload("Account").
+		Preload("Account.Type").
+		Where("id = ?", id).
+		First(transaction).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return transaction, err
+}
@@ -1,12 +1,17 @@
 package scheduled_transaction
 
 import (
+	"strings"
 	"time"
 
+	"github.com/inconshreveable/log15"
 	"github.com/robfig/cron"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
 )
 
-var getScheduleConfig ScheduleConfigurator = DefaultSchedule
+// getScheduleConfig overrides the schedule configured in settings if it is set
+var getScheduleConfig ScheduleConfigurator
 
 type ScheduleConfig struct {
 	Location               *time.Location
@@ -19,34 +24,124 @@ type ScheduleConfig struct {
 	PayoutAccountInterest       cron.Schedule
 	ChargeMinimumBalance        cron.Schedule
 	ChargeAccountMaintenanceFee cron.Schedule
+
+	NotifyExpiringCards cron.Schedule
+	ExpireCards         cron.Schedule
+
+	NotifyMaturingDeposits       cron.Schedule
+	ProcessMaturedDeposits       cron.Schedule
+	ChargeEarlyWithdrawalPenalty cron.Schedule
+
+	CollectUnarrangedOverdraft cron.Schedule
+	ChargeUnarrangedOverdraft  cron.Schedule
+
+	DetectDormantAccounts cron.Schedule
+	ChargeDormancyFee     cron.Schedule
+
+	ImportExchangeRates cron.Schedule
 }
 
 type ScheduleConfigurator func() (*ScheduleConfig, error)
 
-func DefaultSchedule() (*ScheduleConfig, error) {
+// defaultScheduleLocation is used if the time zone is not configured
+var defaultScheduleLocation = time.FixedZone("UTC+1(CET)", 3600)
+
+// jobScheduleDefinition is the default schedule of the job which can be overridden in settings
+type jobScheduleDefinition struct {
+	job string
 	// Second | Minute | Hour | Dom(day of month) | Month | DowOptional(day of week optional) | Descriptor
-	collectCreditLine, _ := cron.Parse("0 10 0 * *")       // at 00:10 every day
-	collectAccountInterest, _ := cron.Parse("0 30 0 * *")  // at 00:30 every day
-	collectMaintenanceFee, _ := cron.Parse("0 */10 * * *") // every 10 minutes
-	collectMinimumBalance, _ := cron.Parse("0 */12 * * *") // every 12 minutes
-
-	chargeCreditLine, _ := cron.Parse("0 20 0 * *")             // at 00:20 every day
-	payoutAccountInterest, _ := cron.Parse("0 40 0 * *")        // at 00:40 every day
-	chargeMinimumBalance, _ := cron.Parse("0 50 0 * *")         // at 00:50 every day
-	chargeAccountMaintenanceFee, _ := cron.Parse("0 0 1 * * *") // at 01:00 every day
-
-	return &ScheduleConfig{
-		Location:               time.FixedZone("UTC+1(CET)", 3600),
-		CollectCreditLineFee:   collectCreditLine,
-		CollectAccountInterest: collectAccountInterest,
-		CollectMaintenanceFee:  collectMaintenanceFee,
-		CollectMinimumBalance:  collectMinimumBalance,
-
-		ChargeCreditLine:            chargeCreditLine,
-		PayoutAccountInterest:       payoutAccountInterest,
-		ChargeMinimumBalance:        chargeMinimumBalance,
-		ChargeAccountMaintenanceFee: chargeAccountMaintenanceFee,
-	}, nil
+	expression string
+	field      func(config *ScheduleConfig) *cron.Schedule
+}
+
+var jobScheduleDefinitions = []jobScheduleDefinition{
+	// at 00:10 every day
+	{JobCollectCreditLineFee, "0 10 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.CollectCreditLineFee }},
+	// at 00:30 every day
+	{JobCollectAccountInterest, "0 30 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.CollectAccountInterest }},
+	// every 10 minutes
+	{JobCollectMaintenanceFee, "0 */10 * * *", func(c *ScheduleConfig) *cron.Schedule { return &c.CollectMaintenanceFee }},
+	// every 12 minutes
+	{JobCollectMinimumBalance, "0 */12 * * *", func(c *ScheduleConfig) *cron.Schedule { return &c.CollectMinimumBalance }},
+
+	// at 00:20 every day
+	{JobChargeCreditLine, "0 20 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ChargeCreditLine }},
+	// at 00:40 every day
+	{JobPayoutAccountInterest, "0 40 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.PayoutAccountInterest }},
+	// at 00:50 every day
+	{JobChargeMinimumBalance, "0 50 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ChargeMinimumBalance }},
+	// at 01:00 every day
+	{JobChargeAccountMaintenanceFee, "0 0 1 * * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ChargeAccountMaintenanceFee }},
+
+	// at 09:00 every day
+	{JobNotifyExpiringCards, "0 0 9 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.NotifyExpiringCards }},
+	// at 00:05 every day
+	{JobExpireCards, "0 5 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ExpireCards }},
+
+	// at 09:15 every day
+	{JobNotifyMaturingDeposits, "0 15 9 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.NotifyMaturingDeposits }},
+	// at 00:45 every day, after interest payout
+	{JobProcessMaturedDeposits, "0 45 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ProcessMaturedDeposits }},
+	// every 15 minutes
+	{JobChargeEarlyWithdrawalPenalty, "0 */15 * * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ChargeEarlyWithdrawalPenalty }},
+
+	// at 23:55 every day, before the day is over
+	{JobCollectUnarrangedOverdraft, "0 55 23 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.CollectUnarrangedOverdraft }},
+	// at 00:55 every day
+	{JobChargeUnarrangedOverdraft, "0 55 0 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ChargeUnarrangedOverdraft }},
+
+	// at 02:00 every day
+	{JobDetectDormantAccounts, "0 0 2 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.DetectDormantAccounts }},
+	// at 01:10 every day
+	{JobChargeDormancyFee, "0 10 1 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ChargeDormancyFee }},
+
+	// at 16:30 every day, ECB publishes rates around 16:00 CET
+	{JobImportExchangeRates, "0 30 16 * *", func(c *ScheduleConfig) *cron.Schedule { return &c.ImportExchangeRates }},
+}
+
+func DefaultSchedule() (*ScheduleConfig, error) {
+	config := &ScheduleConfig{Location: defaultScheduleLocation}
+	for _, definition := range jobScheduleDefinitions {
+		schedule, err := cron.Parse(definition.expression)
+		if err != nil {
+			return nil, err
+		}
+		*definition.field(config) = schedule
+	}
+	return config, nil
+}
+
+// configuredSchedule builds the schedule from settings, defaults are used for settings which are not specified or invalid.
+// Signature of the schedule is returned along with it in order to detect changes of settings.
+func configuredSchedule(settingsService *settings.Service, logger log15.Logger) (*ScheduleConfig, string) {
+	var signature strings.Builder
+
+	config := &ScheduleConfig{Location: defaultScheduleLocation}
+	if zone, err := settingsService.String(SettingJobScheduleTimeZoneString); err == nil && zone != "" {
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			logger.Error("invalid time zone of job schedules, default one is used", "error", err, "timeZone", zone)
+		} else {
+			config.Location = location
+		}
+	}
+	signature.WriteString(config.Location.String())
+
+	for _, definition := range jobScheduleDefinitions {
+		expression := definition.expression
+		if value, err := settingsService.String(jobScheduleSetting(definition.job)); err == nil && value != "" {
+			expression = value
+		}
+		schedule, err := cron.Parse(expression)
+		if err != nil {
+			logger.Error("invalid schedule of the job, default one is used", "error", err, "job", definition.job)
+			expression = definition.expression
+			schedule, _ = cron.Parse(expression)
+		}
+		*definition.field(config) = schedule
+		signature.WriteString("|" + definition.job + "=" + expression)
+	}
+	return config, signature.String()
 }
 
 func SetScheduleConfigurator(configurator ScheduleConfigurator) {
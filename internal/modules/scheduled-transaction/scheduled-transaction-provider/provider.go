@@ -9,8 +9,20 @@ func Providers() []interface{} {
 	return []interface{}{
 		scheduled_transaction.NewRepository,
 		scheduled_transaction.NewScheduledTransactionLogRepository,
+		scheduled_transaction.NewInterestAccrualRepository,
 		scheduled_transaction.NewService,
+		scheduled_transaction.NewTermDepositService,
+		scheduled_transaction.NewJobLeaseRepository,
+		scheduled_transaction.NewJobRunRepository,
+		scheduled_transaction.NewJobRunner,
+		scheduled_transaction.NewJobs,
+		scheduled_transaction.NewManagementService,
+		scheduled_transaction.NewAccountClosureService,
 
 		handler.NewTransactionsHandler,
+		handler.NewInterestHandler,
+		handler.NewManagementHandler,
+		handler.NewJobsHandler,
+		handler.NewAccountClosureHandler,
 	}
 }
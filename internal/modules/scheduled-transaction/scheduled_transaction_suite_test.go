@@ -0,0 +1,13 @@
+package scheduled_transaction_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestScheduledTransaction(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scheduled Transaction Suite")
+}
@@ -7,12 +7,13 @@ import (
 	"github.com/jinzhu/now"
 )
 
-func findNextDate(period Period, day int) time.Time {
+// findNextDate returns the date of the next period relative to the given current time
+func findNextDate(currentTime time.Time, period Period, day int) time.Time {
 	if day <= 0 || day > 31 {
 		panic("invalid day is provided")
 	}
-	month := findMonth(period)
-	year := findYear(month)
+	month := findMonth(currentTime, period)
+	year := findYear(currentTime, month)
 
 	layout := "2006-1-2"
 	date, _ := time.Parse(layout, fmt.Sprintf("%d-%d-1", year, month))
@@ -25,8 +26,7 @@ func findNextDate(period Period, day int) time.Time {
 	return result
 }
 
-func findYear(scheduledMonth time.Month) int {
-	currentTime := time.Now()
+func findYear(currentTime time.Time, scheduledMonth time.Month) int {
 	currentMonth := currentTime.Month()
 
 	if currentMonth < scheduledMonth {
@@ -35,8 +35,8 @@ func findYear(scheduledMonth time.Month) int {
 	return currentTime.Year() + 1
 }
 
-func findMonth(period Period) time.Month {
-	currentMonth := time.Now().Month()
+func findMonth(currentTime time.Time, period Period) time.Month {
+	currentMonth := currentTime.Month()
 	switch period {
 	case PeriodMonthly:
 		if currentMonth == time.December {
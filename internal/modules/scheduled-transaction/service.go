@@ -12,6 +12,7 @@ import (
 type Service struct {
 	scheduledTransactionRepository    *Repository
 	scheduledTransactionLogRepository *TransactionLogRepository
+	interestAccrualRepository         *InterestAccrualRepository
 	db                                *gorm.DB
 	logger                            log15.Logger
 }
@@ -19,12 +20,14 @@ type Service struct {
 func NewService(
 	scheduledTransactionRepository *Repository,
 	scheduledTransactionLogRepository *TransactionLogRepository,
+	interestAccrualRepository *InterestAccrualRepository,
 	db *gorm.DB,
 	logger log15.Logger,
 ) *Service {
 	return &Service{
 		scheduledTransactionRepository:    scheduledTransactionRepository,
 		scheduledTransactionLogRepository: scheduledTransactionLogRepository,
+		interestAccrualRepository:         interestAccrualRepository,
 		db:                                db,
 		logger:                            logger.New("service", "ScheduledTransactionService"),
 	}
@@ -46,11 +49,46 @@ func (s *Service) ScheduleTransfer(params *ScheduleParams, tx *gorm.DB) error {
 		}()
 	}
 
-	err = s.validate(params, tx)
+	_, err = s.scheduleTransfer(params, tx)
+	return err
+}
+
+// AccrueInterest stores daily interest accrual and adds its amount to the next scheduled interest payout
+func (s *Service) AccrueInterest(params *ScheduleParams, accrual *InterestAccrual, tx *gorm.DB) error {
+	var err error
+	if tx == nil {
+		tx = s.db.Begin()
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+				return
+			}
+			tx.Commit()
+		}()
+	}
+
+	transaction, err := s.scheduleTransfer(params, tx)
 	if err != nil {
 		return err
 	}
 
+	accrual.ScheduledTransactionId = transaction.Id
+	err = s.interestAccrualRepository.WrapContext(tx).Create(accrual)
+	return err
+}
+
+// WaivePending waives pending transactions of the account with the given reason,
+// e.g. dormancy fee is not charged once the account is reactivated
+func (s *Service) WaivePending(accountId uint64, reason Reason, waiveReason string, tx *gorm.DB) error {
+	return s.scheduledTransactionRepository.WrapContext(tx).WaivePendingByAccountIdAndReason(accountId, reason, waiveReason)
+}
+
+func (s *Service) scheduleTransfer(params *ScheduleParams, tx *gorm.DB) (*ScheduledTransaction, error) {
+	err := s.validate(params, tx)
+	if err != nil {
+		return nil, err
+	}
+
 	logger := s.logger.New("method", "ScheduleTransfer")
 	txRepo := s.scheduledTransactionRepository.WrapContext(tx)
 	logRepo := s.scheduledTransactionLogRepository.WrapContext(tx)
@@ -58,7 +96,7 @@ func (s *Service) ScheduleTransfer(params *ScheduleParams, tx *gorm.DB) error {
 	transaction, err := s.findOrCreateScheduledTransaction(params, tx)
 	if err != nil {
 		logger.Error("failed to find or create scheduled transaction", "error", err, "params", s.paramsToString(params))
-		return err
+		return nil, err
 	}
 
 	newAmount := transaction.Amount.Add(params.Amount)
@@ -67,7 +105,7 @@ func (s *Service) ScheduleTransfer(params *ScheduleParams, tx *gorm.DB) error {
 		Amount: newAmount,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = logRepo.Create(&ScheduledTransactionLog{
@@ -76,20 +114,26 @@ func (s *Service) ScheduleTransfer(params *ScheduleParams, tx *gorm.DB) error {
 		CreatedAt:              &params.Now,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return transaction, nil
 }
 
 func (s *Service) findOrCreateScheduledTransaction(params *ScheduleParams, tx *gorm.DB) (*ScheduledTransaction, error) {
-	transaction, err := s.scheduledTransactionRepository.FindNextPendingByAccountIdAndReason(params.Account.ID, params.Reason)
+	transaction, err := s.scheduledTransactionRepository.FindNextPendingByAccountIdAndReason(params.Account.ID, params.Reason, params.currentTime())
 
 	if err == gorm.ErrRecordNotFound {
-		scheduleDate := findNextDate(params.Period, params.PaymentDay)
-		if params.Month != nil {
-			y, _, d := scheduleDate.Date()
-			scheduleDate = time.Date(y, *params.Month, d, 0, 0, 0, 0, scheduleDate.Location())
+		// the exact date does not require period and payment day to be specified
+		var scheduleDate time.Time
+		if params.Date != nil {
+			scheduleDate = *params.Date
+		} else {
+			scheduleDate = findNextDate(params.currentTime(), params.Period, params.PaymentDay)
+			if params.Month != nil {
+				y, _, d := scheduleDate.Date()
+				scheduleDate = time.Date(y, *params.Month, d, 0, 0, 0, 0, scheduleDate.Location())
+			}
 		}
 
 		transaction = &ScheduledTransaction{
@@ -0,0 +1,62 @@
+package scheduled_transaction
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+const (
+	// SettingInterestDayCountConventionString is one of ACT/ACT, ACT/365 or ACT/360, ACT/ACT is used if not set
+	SettingInterestDayCountConventionString = settings.Name("interest_day_count_convention")
+	// SettingTermDepositMaturityNoticeDaysInt64 defines how many days before maturity owners of term deposits are notified
+	SettingTermDepositMaturityNoticeDaysInt64 = settings.Name("term_deposit_maturity_notice_days")
+	// SettingJobScheduleTimeZoneString is IANA time zone (e.g. Europe/Berlin) of job schedules, UTC+1 is used if not set
+	SettingJobScheduleTimeZoneString = settings.Name("job_schedule_time_zone")
+	// SettingAccountDormancyPeriodDaysInt64 defines after how many days without customer initiated transactions
+	// accounts are flagged as dormant, dormancy detection is disabled if not set or zero
+	SettingAccountDormancyPeriodDaysInt64 = settings.Name("account_dormancy_period_days")
+)
+
+const defaultTermDepositMaturityNoticeDays = 7
+
+// jobScheduleSettingPrefix prefixes the name of the job in the name of the setting with its cron expression
+// e.g. job_schedule_charge_account_maintenance_fee, the default schedule is used if the setting is not set
+const jobScheduleSettingPrefix = "job_schedule_"
+
+func jobScheduleSetting(job string) settings.Name {
+	return settings.Name(jobScheduleSettingPrefix + job)
+}
+
+func init() {
+	settings.RegisterValidator(SettingJobScheduleTimeZoneString, func(value string) error {
+		if value == "" {
+			return nil
+		}
+		_, err := time.LoadLocation(value)
+		return err
+	})
+	settings.RegisterValidator(SettingAccountDormancyPeriodDaysInt64, func(value string) error {
+		if value == "" {
+			return nil
+		}
+		days, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && days < 0 {
+			err = errors.New("dormancy period must not be negative")
+		}
+		return err
+	})
+	for _, definition := range jobScheduleDefinitions {
+		settings.RegisterValidator(jobScheduleSetting(definition.job), func(value string) error {
+			if value == "" {
+				return nil
+			}
+			_, err := cron.Parse(value)
+			return err
+		})
+	}
+}
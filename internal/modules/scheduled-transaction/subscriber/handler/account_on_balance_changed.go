@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"time"
+
 	accountEvent "github.com/Confialink/wallet-accounts/internal/modules/account/event"
 	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
 	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/now"
 	"github.com/olebedev/emitter"
 	"github.com/shopspring/decimal"
 )
@@ -18,6 +21,7 @@ func AccountOnBalanceChanged(
 		context := event.Args[0].(*accountEvent.ContextAccountBalanceChanged)
 
 		balanceLimitFee(context, scheduledTransactionService, logger)
+		unarrangedOverdraftFee(context, scheduledTransactionService, logger)
 		event.Flags = event.Flags | emitter.FlagSync
 	}
 
@@ -68,3 +72,37 @@ func balanceLimitFee(
 		}
 	}
 }
+
+// unarrangedOverdraftFee schedules the fee for the current day once the balance goes below the arranged overdraft limit,
+// the fee is charged once the day is over
+func unarrangedOverdraftFee(
+	context *accountEvent.ContextAccountBalanceChanged,
+	service *scheduledTransaction.Service,
+	logger log15.Logger,
+) {
+	account := context.Account
+	if account.Type == nil || account.Type.UnarrangedOverdraftFee == nil || !account.Type.UnarrangedOverdraftFee.IsPositive() {
+		return
+	}
+	if !account.IsInUnarrangedOverdraft() {
+		return
+	}
+
+	currentTime := time.Now()
+	endOfDay := now.New(currentTime).EndOfDay()
+	err := service.ScheduleTransfer(
+		&scheduledTransaction.ScheduleParams{
+			Account: account,
+			Reason:  scheduledTransaction.ReasonUnarrangedOverdraftFee,
+			Amount:  account.Type.UnarrangedOverdraftFee.Neg(),
+			Date:    &endOfDay,
+			Now:     currentTime,
+		},
+		context.DbTransaction,
+	)
+
+	// ignore if already scheduled
+	if err != nil && err != scheduledTransaction.ErrorAlreadyScheduled {
+		logger.Error("failed to schedule unarranged overdraft fee", "error", err, "accountId", account.ID)
+	}
+}
@@ -0,0 +1,104 @@
+package handler_test
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	accountTypeModel "github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+	accountEvent "github.com/Confialink/wallet-accounts/internal/modules/account/event"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/subscriber/handler"
+)
+
+func newMockDB() (*gorm.DB, sqlmock.Sqlmock) {
+	var db *sql.DB
+	db, mock, err := sqlmock.New()
+	Expect(err).ShouldNot(HaveOccurred())
+	gdb, err := gorm.Open("mysql", db)
+	Expect(err).ShouldNot(HaveOccurred())
+	return gdb, mock
+}
+
+func dec(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+var _ = Describe("Unarranged overdraft fee on balance changed", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *scheduledTransaction.Service
+	)
+	any := sqlmock.AnyArg()
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		service = scheduledTransaction.NewService(
+			scheduledTransaction.NewRepository(gdb),
+			scheduledTransaction.NewScheduledTransactionLogRepository(gdb),
+			scheduledTransaction.NewInterestAccrualRepository(gdb),
+			gdb,
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	balanceChanged := func(balance string) *accountEvent.ContextAccountBalanceChanged {
+		account := &model.Account{}
+		account.ID = 1
+		account.Type = &accountTypeModel.AccountType{}
+		account.Balance = decimal.RequireFromString(balance)
+		account.Type.CreditLimitAmount = dec("100")
+		account.Type.UnarrangedOverdraftFee = dec("5")
+		return &accountEvent.ContextAccountBalanceChanged{DbTransaction: gdb, Account: account}
+	}
+
+	expectPendingFee := func(found bool) {
+		rows := sqlmock.NewRows([]string{"id", "account_id", "reason", "status"})
+		if found {
+			rows.AddRow(9, 1, scheduledTransaction.ReasonUnarrangedOverdraftFee, scheduledTransaction.StatusPending)
+		}
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(account_id = \\? AND reason = \\? AND status = \\? AND scheduled_date > \\?\\)").
+			WithArgs(1, scheduledTransaction.ReasonUnarrangedOverdraftFee, scheduledTransaction.StatusPending, any).
+			WillReturnRows(rows)
+	}
+
+	It("should not schedule the fee within the arranged overdraft", func() {
+		UnarrangedOverdraftFee(balanceChanged("-100"), service, log15.New())
+	})
+
+	It("should schedule the fee only once a day", func() {
+		// the first time the balance goes beyond the arranged overdraft
+		expectPendingFee(false)
+		expectPendingFee(false)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `scheduled_transactions`").
+			WithArgs(scheduledTransaction.ReasonUnarrangedOverdraftFee, 1, any, scheduledTransaction.StatusPending, nil, any, nil, any, any).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+			WithArgs("-5", 9, any, 9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `scheduled_transaction_logs`").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		UnarrangedOverdraftFee(balanceChanged("-150"), service, log15.New())
+
+		// the balance changes again the same day, the fee is already scheduled
+		expectPendingFee(true)
+		UnarrangedOverdraftFee(balanceChanged("-170"), service, log15.New())
+	})
+})
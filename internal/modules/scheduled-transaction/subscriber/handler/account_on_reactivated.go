@@ -0,0 +1,46 @@
+package handler
+
+import (
+	accountEvent "github.com/Confialink/wallet-accounts/internal/modules/account/event"
+	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/inconshreveable/log15"
+	"github.com/olebedev/emitter"
+)
+
+const dormancyFeeWaiveReason = "Account has been reactivated"
+
+func AccountOnReactivated(
+	eventEmitter *emitter.Emitter,
+	scheduledTransactionService *scheduledTransaction.Service,
+	logger log15.Logger,
+) {
+	logger = logger.New("eventHandler", "scheduled-transaction.AccountOnReactivated")
+	onReactivated := func(event *emitter.Event) {
+		context := event.Args[0].(*accountEvent.ContextAccountReactivated)
+
+		waiveDormancyFee(context, scheduledTransactionService, logger)
+	}
+
+	// must be used as middleware (synchronous call)
+	// empty loop is aimed to free chanel once event is emitted
+	for range eventEmitter.On(accountEvent.AccountReactivated, onReactivated) { /* empty */
+	}
+}
+
+// waiveDormancyFee waives the pending dormancy fee since the account is not dormant anymore,
+// reactivation is rolled back if the fee could not be waived
+func waiveDormancyFee(
+	context *accountEvent.ContextAccountReactivated,
+	service *scheduledTransaction.Service,
+	logger log15.Logger,
+) {
+	context.Error = service.WaivePending(
+		context.Account.ID,
+		scheduledTransaction.ReasonDormancyFee,
+		dormancyFeeWaiveReason,
+		context.DbTransaction,
+	)
+	if context.Error != nil {
+		logger.Error("failed to waive dormancy fee", "error", context.Error, "accountId", context.Account.ID)
+	}
+}
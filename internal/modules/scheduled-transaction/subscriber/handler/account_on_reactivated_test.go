@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	accountEvent "github.com/Confialink/wallet-accounts/internal/modules/account/event"
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/subscriber/handler"
+)
+
+var _ = Describe("Dormancy fee on account reactivated", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *scheduledTransaction.Service
+	)
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		service = scheduledTransaction.NewService(
+			scheduledTransaction.NewRepository(gdb),
+			scheduledTransaction.NewScheduledTransactionLogRepository(gdb),
+			scheduledTransaction.NewInterestAccrualRepository(gdb),
+			gdb,
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	reactivated := func(tx *gorm.DB) *accountEvent.ContextAccountReactivated {
+		account := &model.Account{}
+		account.ID = 1
+		return &accountEvent.ContextAccountReactivated{DbTransaction: tx, Account: account}
+	}
+
+	It("should waive the pending dormancy fee within the reactivation transaction", func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET `status` = \\?, `updated_at` = \\?, `waive_reason` = \\? WHERE \\(account_id = \\? AND reason = \\? AND status = \\?\\)").
+			WithArgs(scheduledTransaction.StatusWaived, sqlmock.AnyArg(), "Account has been reactivated",
+				1, scheduledTransaction.ReasonDormancyFee, scheduledTransaction.StatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		context := reactivated(gdb.Begin())
+		WaiveDormancyFee(context, service, log15.New())
+		Expect(context.Error).ShouldNot(HaveOccurred())
+	})
+
+	It("should report failure so that reactivation is rolled back", func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET `status`").WillReturnError(errors.New("lock wait timeout"))
+
+		context := reactivated(gdb.Begin())
+		WaiveDormancyFee(context, service, log15.New())
+		Expect(context.Error).Should(HaveOccurred())
+	})
+})
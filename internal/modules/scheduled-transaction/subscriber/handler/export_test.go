@@ -0,0 +1,10 @@
+package handler
+
+// UnarrangedOverdraftFee exposes unarrangedOverdraftFee handler to tests
+var UnarrangedOverdraftFee = unarrangedOverdraftFee
+
+// WaiveDormancyFee exposes waiveDormancyFee handler to tests
+var WaiveDormancyFee = waiveDormancyFee
+
+// EarlyWithdrawalPenalty exposes earlyWithdrawalPenalty handler to tests
+var EarlyWithdrawalPenalty = earlyWithdrawalPenalty
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"time"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	requestEvent "github.com/Confialink/wallet-accounts/internal/modules/request/event"
+	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/utils"
+	"github.com/Confialink/wallet-accounts/internal/transfer"
+	"github.com/inconshreveable/log15"
+	"github.com/olebedev/emitter"
+	"github.com/shopspring/decimal"
+)
+
+func TermDepositOnRequestExecuted(
+	eventEmitter *emitter.Emitter,
+	scheduledTransactionService *scheduledTransaction.Service,
+	currencyProvider transfer.CurrencyProvider,
+	logger log15.Logger,
+) {
+	logger = logger.New("eventHandler", "scheduled-transaction.TermDepositOnRequestExecuted")
+	onRequestExecuted := func(event *emitter.Event) {
+		context := event.Args[0].(*requestEvent.ContextRequestExecuted)
+
+		earlyWithdrawalPenalty(context, scheduledTransactionService, currencyProvider, logger)
+		event.Flags = event.Flags | emitter.FlagSync
+	}
+
+	// must be used as middleware (synchronous call)
+	// empty loop is aimed to free chanel once event is emitted
+	for range eventEmitter.On(requestEvent.RequestExecuted, onRequestExecuted) { /* empty */
+	}
+}
+
+// earlyWithdrawalPenalty schedules the penalty for withdrawals made by the owner from immature term deposits,
+// the penalty is charged by the scheduled job in order to not create requests inside of the executed one.
+// Only the withdrawn principal is charged, fees of the withdrawal are not.
+func earlyWithdrawalPenalty(
+	context *requestEvent.ContextRequestExecuted,
+	service *scheduledTransaction.Service,
+	currencyProvider transfer.CurrencyProvider,
+	logger log15.Logger,
+) {
+	if !context.Request.IsInitiatedByUser() {
+		return
+	}
+
+	now := time.Now()
+	accounts := make(map[uint64]*accountModel.Account)
+	withdrawn := make(map[uint64]decimal.Decimal)
+	currencyCodes := make(map[uint64]string)
+	for _, detail := range context.Details {
+		account := detail.Account
+		if account == nil || !detail.IsDebit() || !utils.IsMainTransactionPurpose(detail.Purpose) || account.IsMatured(now) {
+			continue
+		}
+		if account.EarlyWithdrawalPenaltyPercent == nil || !account.EarlyWithdrawalPenaltyPercent.IsPositive() {
+			continue
+		}
+		accounts[account.ID] = account
+		withdrawn[account.ID] = withdrawn[account.ID].Add(detail.Amount.Abs())
+		currencyCodes[account.ID] = detail.CurrencyCode
+	}
+
+	for id, account := range accounts {
+		currency, err := currencyProvider.Get(currencyCodes[id])
+		if err != nil {
+			logger.Error("failed to retrieve currency of term deposit", "error", err, "accountId", id)
+			continue
+		}
+		// withdrawn amount * (percent / 100)
+		penalty := withdrawn[id].
+			Mul(*account.EarlyWithdrawalPenaltyPercent).
+			Div(decimal.New(100, 0)).
+			Round(int32(currency.Fraction()))
+		if !penalty.IsPositive() {
+			continue
+		}
+
+		err = service.ScheduleTransfer(
+			&scheduledTransaction.ScheduleParams{
+				Account: account,
+				Reason:  scheduledTransaction.ReasonEarlyWithdrawalPenalty,
+				Amount:  penalty.Neg(),
+				Date:    &now,
+				Now:     now,
+			},
+			context.Tx,
+		)
+		if err != nil {
+			logger.Error("failed to schedule early withdrawal penalty", "error", err, "accountId", id)
+		}
+	}
+}
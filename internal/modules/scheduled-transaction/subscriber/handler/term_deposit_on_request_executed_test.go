@@ -0,0 +1,99 @@
+package handler_test
+
+import (
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	requestEvent "github.com/Confialink/wallet-accounts/internal/modules/request/event"
+	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
+	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/subscriber/handler"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/constants"
+	"github.com/Confialink/wallet-accounts/internal/modules/transaction/types"
+	"github.com/Confialink/wallet-accounts/internal/transfer"
+)
+
+// currencies provides currencies with the given decimal places
+type currencies map[string]uint
+
+func (c currencies) Get(code string) (transfer.Currency, error) {
+	return *transfer.NewCurrency(code, c[code]), nil
+}
+
+var _ = Describe("Early withdrawal penalty on request executed", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *scheduledTransaction.Service
+	)
+	any := sqlmock.AnyArg()
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		service = scheduledTransaction.NewService(
+			scheduledTransaction.NewRepository(gdb),
+			scheduledTransaction.NewScheduledTransactionLogRepository(gdb),
+			scheduledTransaction.NewInterestAccrualRepository(gdb),
+			gdb,
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	It("should charge the withdrawn principal without fees rounded to decimals of the currency", func() {
+		maturityDate := time.Now().AddDate(1, 0, 0)
+		deposit := &model.Account{}
+		deposit.ID = 1
+		deposit.MaturityDate = &maturityDate
+		deposit.EarlyWithdrawalPenaltyPercent = dec("1.55")
+
+		context := &requestEvent.ContextRequestExecuted{
+			Tx: gdb,
+			Request: &requestModel.Request{
+				IsInitiatedByAdmin:  pointer.ToBool(false),
+				IsInitiatedBySystem: pointer.ToBool(false),
+			},
+			Details: types.Details{
+				constants.PurposeTBUOutgoing: {
+					Purpose:      constants.PurposeTBUOutgoing,
+					Amount:       *dec("-1000"),
+					CurrencyCode: "JPY",
+					Account:      deposit,
+				},
+				constants.PurposeFeeTransfer: {
+					Purpose:      constants.PurposeFeeTransfer,
+					Amount:       *dec("-100"),
+					CurrencyCode: "JPY",
+					Account:      deposit,
+				},
+			},
+		}
+
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions`").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `scheduled_transactions`").
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectCommit()
+		// 1000 * 1.55% = 15.5 is rounded to whole yens
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+			WithArgs("-16", 9, any, 9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `scheduled_transaction_logs`").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		EarlyWithdrawalPenalty(context, service, currencies{"JPY": 0}, log15.New())
+	})
+})
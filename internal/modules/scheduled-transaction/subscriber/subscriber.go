@@ -5,6 +5,7 @@ import (
 
 	scheduledTransaction "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
 	"github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction/subscriber/handler"
+	"github.com/Confialink/wallet-accounts/internal/transfer"
 	"github.com/inconshreveable/log15"
 	"github.com/olebedev/emitter"
 )
@@ -12,8 +13,11 @@ import (
 func Subscribe(
 	eventEmitter *emitter.Emitter,
 	scheduledTransactionService *scheduledTransaction.Service,
+	currencyProvider transfer.CurrencyProvider,
 	logger log15.Logger,
 ) {
 	go handler.AccountOnBalanceChanged(eventEmitter, scheduledTransactionService, logger)
+	go handler.TermDepositOnRequestExecuted(eventEmitter, scheduledTransactionService, currencyProvider, logger)
+	go handler.AccountOnReactivated(eventEmitter, scheduledTransactionService, logger)
 	log.Println("module scheduled-transaction subscribed on application events")
 }
@@ -0,0 +1,249 @@
+package scheduled_transaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-utils/pointer"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	accRepo "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	"github.com/Confialink/wallet-accounts/internal/modules/notifications"
+	"github.com/Confialink/wallet-accounts/internal/modules/request"
+	"github.com/Confialink/wallet-accounts/internal/modules/request/form"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	"github.com/Confialink/wallet-accounts/internal/modules/user"
+)
+
+const termDepositMaxErrors = 3
+
+// TermDepositService handles term deposits which reach maturity date:
+// pending interest is paid out and the principal is either transferred to the interest account or rolled over
+// into a new term
+type TermDepositService struct {
+	db                   *gorm.DB
+	repo                 *Repository
+	accountRepository    *accRepo.AccountRepository
+	requestCreator       *request.Creator
+	notificationsService *notifications.Service
+	settingsService      *settings.Service
+	logger               log15.Logger
+}
+
+func NewTermDepositService(
+	db *gorm.DB,
+	repo *Repository,
+	accountRepository *accRepo.AccountRepository,
+	requestCreator *request.Creator,
+	notificationsService *notifications.Service,
+	settingsService *settings.Service,
+	logger log15.Logger,
+) *TermDepositService {
+	return &TermDepositService{
+		db:                   db,
+		repo:                 repo,
+		accountRepository:    accountRepository,
+		requestCreator:       requestCreator,
+		notificationsService: notificationsService,
+		settingsService:      settingsService,
+		logger:               logger.New("service", "TermDeposit"),
+	}
+}
+
+// NotifyMaturing notifies owners of term deposits which mature in configured amount of days.
+// It is supposed to be called once a day.
+func (s *TermDepositService) NotifyMaturing(ctx context.Context, now time.Time) JobResult {
+	logger := s.logger.New("method", "NotifyMaturing")
+
+	noticeDays, err := s.settingsService.Int64(SettingTermDepositMaturityNoticeDaysInt64)
+	if err != nil {
+		noticeDays = defaultTermDepositMaturityNoticeDays
+	}
+	if noticeDays <= 0 {
+		return JobResult{}
+	}
+
+	year, month, day := now.AddDate(0, 0, int(noticeDays)).Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	accounts, err := s.accountRepository.FindTermDepositsMaturingBetween(from, from.AddDate(0, 0, 1))
+	if err != nil {
+		logger.Error("failed to retrieve maturing term deposits", "error", err)
+		return JobResult{Failed: 1}
+	}
+
+	result := JobResult{}
+	for _, account := range accounts {
+		if isAborted(ctx, logger) {
+			break
+		}
+		if err := s.notificationsService.TriggerTermDepositMaturing(account.UserId, account.ID, account.Number); err != nil {
+			logger.Error("failed to notify term deposit owner", "error", err, "accountId", account.ID)
+			result.Failed++
+			continue
+		}
+		result.Processed++
+	}
+	return result
+}
+
+// ProcessMatured pays out or rolls over all term deposits which have reached maturity date
+func (s *TermDepositService) ProcessMatured(ctx context.Context, now time.Time) JobResult {
+	logger := s.logger.New("method", "ProcessMatured")
+
+	accounts, err := s.accountRepository.FindMaturedTermDeposits(now)
+	if err != nil {
+		logger.Error("failed to retrieve matured term deposits", "error", err)
+		return JobResult{Failed: 1}
+	}
+
+	errorsCount := 0
+	processedCount := 0
+	for _, account := range accounts {
+		if isAborted(ctx, logger) {
+			break
+		}
+		if err := s.process(account, now); err != nil {
+			errorsCount++
+			logger.Error("failed to process matured term deposit", "error", err, "accountId", account.ID)
+			if errorsCount >= termDepositMaxErrors {
+				break
+			}
+			continue
+		}
+		processedCount++
+
+		if err := s.notificationsService.TriggerTermDepositMatured(account.UserId, account.ID, account.Number); err != nil {
+			logger.Error("failed to notify term deposit owner", "error", err, "accountId", account.ID)
+		}
+	}
+
+	if processedCount > 0 {
+		logger.Info("successfully processed matured term deposits", "count", processedCount)
+	}
+
+	return JobResult{Processed: uint64(processedCount), Failed: uint64(errorsCount)}
+}
+
+func (s *TermDepositService) process(account *accountModel.Account, now time.Time) error {
+	tx := s.db.Begin()
+	if err := s.payoutInterest(tx, account); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	action := account.GetMaturityAction()
+	if action == accountModel.MaturityActionRollover && (account.TermMonths == nil || *account.TermMonths == 0) {
+		s.logger.Warn("term of the deposit is not specified, it is paid out instead of rollover", "accountId", account.ID)
+		action = accountModel.MaturityActionPayout
+	}
+
+	update := &accountModel.Account{}
+	update.ID = account.ID
+	if action == accountModel.MaturityActionRollover {
+		maturityDate := *account.MaturityDate
+		for !maturityDate.After(now) {
+			maturityDate = maturityDate.AddDate(0, int(*account.TermMonths), 0)
+		}
+		update.MaturityDate = &maturityDate
+	} else {
+		if err := s.payoutPrincipal(tx, account); err != nil {
+			tx.Rollback()
+			return err
+		}
+		update.MaturedAt = &now
+	}
+
+	if err := s.accountRepository.WrapContext(tx).Updates(update); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "failed to update term deposit")
+	}
+	return tx.Commit().Error
+}
+
+// payoutInterest executes pending interest payout of the term deposit without waiting for its scheduled date
+func (s *TermDepositService) payoutInterest(tx *gorm.DB, account *accountModel.Account) error {
+	transactions, err := s.repo.WrapContext(tx).FindPendingByAccountIdAndReason(account.ID, ReasonInterestGeneration)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve pending interest payouts")
+	}
+
+	systemUser := user.GetSystemUser()
+	for _, transaction := range transactions {
+		transaction, err := lockPending(tx, s.repo, *transaction.Id)
+		if err != nil {
+			return errors.Wrap(err, "failed to lock interest payout")
+		}
+		if transaction == nil {
+			continue
+		}
+		req, err := executeTransaction(tx, transaction, s.requestCreator, &systemUser)
+		if err != nil {
+			return errors.Wrap(err, "failed to execute interest payout")
+		}
+		if err := markExecuted(tx, transaction, req, s.repo); err != nil {
+			return errors.Wrap(err, "failed to update scheduled transaction")
+		}
+	}
+	return nil
+}
+
+// payoutPrincipal transfers the balance of the term deposit to its interest account,
+// the balance is left on the deposit if the interest account is not specified or it is in another currency
+func (s *TermDepositService) payoutPrincipal(tx *gorm.DB, account *accountModel.Account) error {
+	if account.InterestAccountId == nil || *account.InterestAccountId == account.ID {
+		return nil
+	}
+
+	accounts := s.accountRepository.WrapContext(tx)
+	balance, err := accounts.GetBalance(account.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve balance of term deposit")
+	}
+	if !balance.IsPositive() {
+		return nil
+	}
+
+	deposit, err := accounts.FindByID(account.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve term deposit")
+	}
+	interestAccount, err := accounts.FindByID(*account.InterestAccountId)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve interest account")
+	}
+	if interestAccount.Type.CurrencyCode != deposit.Type.CurrencyCode {
+		s.logger.Warn(
+			"principal is left on the term deposit since its interest account is in another currency",
+			"accountId", account.ID,
+			"interestAccountId", interestAccount.ID,
+		)
+		return nil
+	}
+
+	systemUser := user.GetSystemUser()
+	description := "Term Deposit Payout\nfrom #: " + account.Number
+	_, err = s.requestCreator.CreateDARequest(&form.DA{
+		AccountId:              account.ID,
+		Amount:                 balance.String(),
+		Description:            description,
+		CreditToRevenueAccount: pointer.ToBool(false),
+	}, &systemUser, tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to debit term deposit")
+	}
+
+	_, err = s.requestCreator.CreateCARequest(&form.CA{
+		AccountId:               *account.InterestAccountId,
+		Amount:                  balance.String(),
+		Description:             description,
+		DebitFromRevenueAccount: pointer.ToBool(false),
+		ApplyIwtFee:             pointer.ToBool(false),
+	}, &systemUser, tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to credit interest account")
+	}
+	return nil
+}
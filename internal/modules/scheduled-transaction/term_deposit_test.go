@@ -0,0 +1,59 @@
+package scheduled_transaction_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	accountRepository "github.com/Confialink/wallet-accounts/internal/modules/account/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+var _ = Describe("TermDepositService", func() {
+	var (
+		gdb     *gorm.DB
+		mock    sqlmock.Sqlmock
+		service *TermDepositService
+	)
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		service = NewTermDepositService(
+			gdb,
+			NewRepository(gdb),
+			accountRepository.NewAccountRepository(gdb, nil),
+			nil,
+			nil,
+			nil,
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectAccount := func(id uint64, typeId uint64, currencyCode string) {
+		mock.ExpectQuery("SELECT \\* FROM `accounts`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id"}).AddRow(id, typeId))
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency_code"}).AddRow(typeId, currencyCode))
+	}
+
+	It("should leave the principal on the deposit if the interest account is in another currency", func() {
+		mock.ExpectQuery("SELECT balance FROM `accounts` WHERE \\(id = \\?\\)").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("1000"))
+		expectAccount(1, 3, "EUR")
+		expectAccount(2, 4, "USD")
+
+		interestAccountId := uint64(2)
+		account := &accountModel.Account{}
+		account.ID = 1
+		account.InterestAccountId = &interestAccountId
+
+		Expect(PayoutPrincipal(service, gdb, account)).To(Succeed())
+	})
+})
@@ -16,13 +16,37 @@ const (
 	ReasonLimitBalanceFee    = Reason("limit_balance_fee")
 	ReasonCreditLineFee      = Reason("credit_line_fee")
 	ReasonInterestGeneration = Reason("interest_generation")
+	// ReasonEarlyWithdrawalPenalty is charged for withdrawals from term deposits before maturity date
+	ReasonEarlyWithdrawalPenalty = Reason("early_withdrawal_penalty")
+	// ReasonUnarrangedOverdraftFee is charged for each day the balance is below the arranged overdraft limit
+	ReasonUnarrangedOverdraftFee = Reason("unarranged_overdraft_fee")
+	// ReasonDormancyFee is charged monthly while the account is dormant
+	ReasonDormancyFee = Reason("dormancy_fee")
 )
 
 var reasonHumanReadable = map[Reason]string{
-	ReasonMaintenanceFee:     "Monthly Maintenance Fee",
-	ReasonLimitBalanceFee:    "Minimum CurrentBalance Fee",
-	ReasonInterestGeneration: "Account Interest Payout",
-	ReasonCreditLineFee:      "Line of CreditFromAlias Fee",
+	ReasonMaintenanceFee:         "Monthly Maintenance Fee",
+	ReasonLimitBalanceFee:        "Minimum CurrentBalance Fee",
+	ReasonInterestGeneration:     "Account Interest Payout",
+	ReasonCreditLineFee:          "Line of CreditFromAlias Fee",
+	ReasonEarlyWithdrawalPenalty: "Early Withdrawal Penalty",
+	ReasonUnarrangedOverdraftFee: "Unarranged Overdraft Fee",
+	ReasonDormancyFee:            "Dormancy Fee",
+}
+
+// feeReasons are reasons of transactions which charge the account, they can be waived by an administrator
+var feeReasons = map[Reason]bool{
+	ReasonMaintenanceFee:         true,
+	ReasonLimitBalanceFee:        true,
+	ReasonCreditLineFee:          true,
+	ReasonEarlyWithdrawalPenalty: true,
+	ReasonUnarrangedOverdraftFee: true,
+	ReasonDormancyFee:            true,
+}
+
+// IsFee checks whether the transaction with the reason charges the account
+func (r Reason) IsFee() bool {
+	return feeReasons[r]
 }
 
 func (r Reason) Description() string {
@@ -37,6 +61,8 @@ type Status string
 const (
 	StatusPending  = Status("pending")
 	StatusExecuted = Status("executed")
+	// StatusWaived means that the fee has been cancelled by an administrator
+	StatusWaived = Status("waived")
 )
 
 type Period string
@@ -70,5 +96,15 @@ type ScheduleParams struct {
 	PaymentDay int
 	// Month overrides found month if specified
 	Month *time.Month
-	Now   time.Time // current date can be changed when we run simulations
+	// Date overrides calculated schedule date if specified
+	Date *time.Time
+	Now  time.Time // current date can be changed when we run simulations or catch up missed runs
+}
+
+// currentTime returns the time the transfer is scheduled at, it is the current time if Now is not specified
+func (p *ScheduleParams) currentTime() time.Time {
+	if p.Now.IsZero() {
+		return time.Now()
+	}
+	return p.Now
 }
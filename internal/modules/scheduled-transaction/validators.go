@@ -40,7 +40,7 @@ var (
 	) error {
 		logger = logger.New("validator", "validatorTransactionIsNotExist")
 
-		_, err := repo.FindNextPendingByAccountIdAndReason(params.Account.ID, params.Reason)
+		_, err := repo.FindNextPendingByAccountIdAndReason(params.Account.ID, params.Reason, params.currentTime())
 		if err == gorm.ErrRecordNotFound {
 			return nil
 		}
@@ -66,10 +66,13 @@ var (
 )
 
 var validators = map[Reason]scheduleValidator{
-	ReasonMaintenanceFee:     validatorChain(validatorAmountIsNegative, validatorTransactionIsNotExist),
-	ReasonCreditLineFee:      validatorChain(validatorAmountIsNegative),
-	ReasonLimitBalanceFee:    validatorChain(validatorAmountIsNegative, validatorTransactionIsNotExist),
-	ReasonInterestGeneration: validatorChain(validatorAmountIsPositive),
+	ReasonMaintenanceFee:         validatorChain(validatorAmountIsNegative, validatorTransactionIsNotExist),
+	ReasonCreditLineFee:          validatorChain(validatorAmountIsNegative),
+	ReasonLimitBalanceFee:        validatorChain(validatorAmountIsNegative, validatorTransactionIsNotExist),
+	ReasonInterestGeneration:     validatorChain(validatorAmountIsPositive),
+	ReasonEarlyWithdrawalPenalty: validatorChain(validatorAmountIsNegative),
+	ReasonUnarrangedOverdraftFee: validatorChain(validatorAmountIsNegative, validatorTransactionIsNotExist),
+	ReasonDormancyFee:            validatorChain(validatorAmountIsNegative, validatorTransactionIsNotExist),
 }
 
 var (
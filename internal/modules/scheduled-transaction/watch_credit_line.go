@@ -1,6 +1,7 @@
 package scheduled_transaction
 
 import (
+	"context"
 	"time"
 
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
@@ -8,11 +9,26 @@ import (
 	"github.com/Confialink/wallet-pkg-utils"
 	"github.com/inconshreveable/log15"
 	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+
+	accountTypeModel "github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+	accountTypeService "github.com/Confialink/wallet-accounts/internal/modules/account-type/service"
 )
 
 const watchCreditLineMaxErrors = 3
 
-func WatchCreditLine(scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) {
+// WatchCreditLine accrues daily debit interest on negative balance of accounts with arranged overdraft
+// using the credit rate of the account type valid today,
+// accruals of the period are summed up into the scheduled credit line fee
+func WatchCreditLine(
+	ctx context.Context,
+	scheduler *Service,
+	db *gorm.DB,
+	logger log15.Logger,
+	timeSource utils.Time,
+	dayCount calculation.DayCountConvention,
+	interestRates *accountTypeService.InterestRateService,
+) JobResult {
 	logger = logger.New("Task", "WatchCreditLine")
 
 	getPaymentDay := func(account *accountModel.Account) int {
@@ -28,18 +44,39 @@ func WatchCreditLine(scheduler *Service, db *gorm.DB, logger log15.Logger, timeS
 	errorsCount := 0
 	successfullyScheduledTransfersCount := 0
 	now := timeSource.Now()
+	year, month, day := timeSource.BeginningOfDay().Date()
+	rateDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 	for _, account := range findAccountsForDailyCreditFee(db, timeSource) {
+		if isAborted(ctx, logger) {
+			break
+		}
 		var period Period
-		method, err := calculation.MethodFromString(account.Type.CreditPayoutMethod.Method)
+		_, err := calculation.MethodFromString(account.Type.CreditPayoutMethod.Method)
 		if err == nil {
 			period, err = PeriodFromString(account.Type.CreditChargePeriod.Name)
 		}
 
+		var feePercent decimal.Decimal
+		if err == nil {
+			feePercent, err = interestRates.AnnualRate(account.Type, accountTypeModel.InterestRateKindCredit, account.Balance, rateDate)
+			if err == nil && !feePercent.IsPositive() {
+				continue
+			}
+		}
+
 		if err == nil {
-			feePercent := *account.Type.CreditAnnualInterestRate
+			accrual := &InterestAccrual{
+				AccountId: &account.ID,
+				Date:      &rateDate,
+				Balance:   account.Balance,
+				Rate:      feePercent,
+				DayCount:  dayCount.String(),
+				Amount:    dayCount.DailyInterest(account.Balance.Abs(), feePercent, rateDate).Neg(),
+				CreatedAt: &now,
+			}
 
 			params := &ScheduleParams{
-				Amount:     method.AnnualInterest(account.Balance.Abs(), feePercent).Neg(),
+				Amount:     accrual.Amount,
 				PaymentDay: getPaymentDay(account),
 				Period:     period,
 				Reason:     ReasonCreditLineFee,
@@ -52,7 +89,13 @@ func WatchCreditLine(scheduler *Service, db *gorm.DB, logger log15.Logger, timeS
 				params.Month = &m
 			}
 
-			err = scheduler.ScheduleTransfer(params, db)
+			tx := db.Begin()
+			err = scheduler.AccrueInterest(params, accrual, tx)
+			if err != nil {
+				tx.Rollback()
+			} else {
+				err = tx.Commit().Error
+			}
 		}
 
 		if err != nil {
@@ -75,6 +118,11 @@ func WatchCreditLine(scheduler *Service, db *gorm.DB, logger log15.Logger, timeS
 			ReasonCreditLineFee,
 		)
 	}
+
+	return JobResult{
+		Processed: uint64(successfullyScheduledTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
 }
 
 func findAccountsForDailyCreditFee(db *gorm.DB, timeSource utils.Time) []*accountModel.Account {
@@ -89,16 +137,19 @@ func findAccountsForDailyCreditFee(db *gorm.DB, timeSource utils.Time) []*accoun
 		Select("distinct accounts.*").
 		Joins("inner join account_types act on act.id = accounts.type_id").
 		Joins("inner join payout_methods pm on pm.id = act.credit_payout_method_id").
-		Where(`act.credit_limit_amount > 0 
-					 and act.credit_annual_interest_rate > 0 
+		Where(`COALESCE(accounts.overdraft_limit, act.credit_limit_amount, 0) > 0
+					 and (act.credit_annual_interest_rate > 0
+						or act.id in (select r.account_type_id from account_type_interest_rates r where r.kind = ?))
 				     and accounts.balance < 0
 					 and (accounts.maturity_date > ? OR accounts.maturity_date IS NULL)
+					 and accounts.closed_at is null
 					 and pm.method = ?
 					 and accounts.id not in (
 						select st.account_id from scheduled_transactions st
 						inner join scheduled_transaction_logs logs on logs.scheduled_transaction_id = st.id
 						where st.reason = ?
 						and logs.created_at between ? and ?)`,
+			accountTypeModel.InterestRateKindCredit,
 			today,
 			calculation.InterestCalculationMethodDaily,
 			ReasonCreditLineFee,
@@ -0,0 +1,143 @@
+package scheduled_transaction
+
+import (
+	"context"
+	"time"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	"github.com/Confialink/wallet-pkg-utils"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+)
+
+const watchDormancyMaxErrors = 3
+
+// WatchDormancy flags accounts which have had no customer initiated transactions for the given number of days
+// as dormant and schedules monthly dormancy fees for dormant accounts, nothing is done if the period is not positive
+func WatchDormancy(
+	ctx context.Context,
+	scheduler *Service,
+	db *gorm.DB,
+	logger log15.Logger,
+	timeSource utils.Time,
+	periodDays int64,
+) JobResult {
+	logger = logger.New("Task", "WatchDormancy")
+	if periodDays <= 0 {
+		return JobResult{}
+	}
+
+	now := timeSource.Now()
+	inactiveSince := now.AddDate(0, 0, -int(periodDays))
+	flagged, err := flagDormantAccounts(db, inactiveSince, now)
+	if err != nil {
+		logger.Error("failed to flag dormant accounts", "error", err)
+		return JobResult{Failed: 1}
+	}
+	if flagged > 0 {
+		logger.Info("accounts have been flagged as dormant", "count", flagged)
+	}
+
+	errorsCount := 0
+	successfullyScheduledTransfersCount := 0
+	for _, account := range findDormantAccountsForFee(db) {
+		if isAborted(ctx, logger) {
+			break
+		}
+		err := scheduler.ScheduleTransfer(
+			&ScheduleParams{
+				Amount:     account.Type.DormancyFee.Neg(),
+				PaymentDay: 1,
+				Period:     PeriodMonthly,
+				Reason:     ReasonDormancyFee,
+				Account:    account,
+				Now:        now,
+			},
+			db,
+		)
+
+		if err != nil {
+			errorsCount++
+			logger.Error("failed to schedule transfer", "error", err)
+			if errorsCount >= watchDormancyMaxErrors {
+				break
+			}
+			continue
+		}
+		successfullyScheduledTransfersCount++
+	}
+
+	if successfullyScheduledTransfersCount > 0 {
+		logger.Info(
+			"successfully scheduled transfers",
+			"count",
+			successfullyScheduledTransfersCount,
+			"reason",
+			ReasonDormancyFee,
+		)
+	}
+
+	return JobResult{
+		Processed: uint64(flagged) + uint64(successfullyScheduledTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
+}
+
+// flagDormantAccounts flags active accounts which have not had customer initiated transactions
+// since the given time, term deposits are not flagged since they are not supposed to be used until maturity
+func flagDormantAccounts(db *gorm.DB, inactiveSince, now time.Time) (int64, error) {
+	table := (&accountModel.Account{}).TableName()
+	result := db.Exec(
+		"UPDATE "+table+` SET dormant_at = ?
+			WHERE dormant_at IS NULL
+			AND is_active = 1
+			AND maturity_date IS NULL
+			AND created_at < ?
+			AND (reactivated_at IS NULL OR reactivated_at < ?)
+			AND NOT EXISTS (
+				SELECT 1 FROM transactions t
+				INNER JOIN requests r ON r.id = t.request_id
+				WHERE t.account_id = `+table+`.id
+				AND t.created_at >= ?
+				AND COALESCE(r.is_initiated_by_admin, 0) = 0
+				AND COALESCE(r.is_initiated_by_system, 0) = 0)`,
+		now,
+		inactiveSince,
+		inactiveSince,
+		inactiveSince,
+	)
+	return result.RowsAffected, result.Error
+}
+
+// findDormantAccountsForFee searches for dormant accounts
+// which have to be scheduled for dormancy fee
+func findDormantAccountsForFee(db *gorm.DB) []*accountModel.Account {
+	var accounts []*accountModel.Account
+
+	db.
+		Table("accounts").
+		Preload("Type").
+		Select("distinct accounts.*").
+		Joins("inner join account_types act on act.id = accounts.type_id").
+		Where(`act.dormancy_fee > 0
+					 and accounts.dormant_at is not null
+					 and accounts.closed_at is null
+					 and accounts.id not in
+						(select account_id from scheduled_transactions where reason = ? and status = ?)`,
+			ReasonDormancyFee,
+			StatusPending,
+		).
+		Find(&accounts)
+
+	return accounts
+}
+
+// dormancyPeriodDays returns configured dormancy period, dormancy detection is disabled if it is not set
+func dormancyPeriodDays(settingsService *settings.Service) int64 {
+	days, err := settingsService.Int64(SettingAccountDormancyPeriodDaysInt64)
+	if err != nil {
+		return 0
+	}
+	return days
+}
@@ -1,6 +1,7 @@
 package scheduled_transaction
 
 import (
+	"context"
 	"time"
 
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
@@ -8,11 +9,29 @@ import (
 	"github.com/Confialink/wallet-pkg-utils"
 	"github.com/inconshreveable/log15"
 	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	accountTypeModel "github.com/Confialink/wallet-accounts/internal/modules/account-type/model"
+	accountTypeService "github.com/Confialink/wallet-accounts/internal/modules/account-type/service"
+	txModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
 )
 
 const watchInterestGenerationMaxErrors = 3
 
-func WatchInterestGeneration(scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) {
+// WatchInterestGeneration accrues daily interest on the balance of deposit accounts at the scheduled time
+// using the rate of the account type valid on the accrual date, so that days missed during downtime
+// are accrued by catch-up runs on the balances of those days. Accruals of the period are summed up
+// into the scheduled interest payout.
+func WatchInterestGeneration(
+	ctx context.Context,
+	scheduler *Service,
+	db *gorm.DB,
+	logger log15.Logger,
+	timeSource utils.Time,
+	dayCount calculation.DayCountConvention,
+	interestRates *accountTypeService.InterestRateService,
+) JobResult {
 	logger = logger.New("Task", "WatchInterestGeneration")
 
 	getPaymentDay := func(account *accountModel.Account) int {
@@ -32,18 +51,47 @@ func WatchInterestGeneration(scheduler *Service, db *gorm.DB, logger log15.Logge
 	errorsCount := 0
 	successfullyScheduledTransfersCount := 0
 	now := timeSource.Now()
-	for _, account := range findAccountsForDailyInterestGeneration(db, timeSource) {
+	year, month, day := timeSource.BeginningOfDay().Date()
+	accrualDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for _, account := range findAccountsForDailyInterestGeneration(db, timeSource, accrualDate) {
+		if isAborted(ctx, logger) {
+			break
+		}
 		var period Period
-		method, err := calculation.MethodFromString(account.Type.DepositPayoutMethod.Method)
+		_, err := calculation.MethodFromString(account.Type.DepositPayoutMethod.Method)
 		if err == nil {
 			period, err = PeriodFromString(account.Type.DepositPayoutPeriod.Name)
 		}
 
+		var balance decimal.Decimal
+		if err == nil {
+			balance, err = balanceAt(db, account, now)
+			if err == nil && !balance.IsPositive() {
+				continue
+			}
+		}
+
+		var feePercent decimal.Decimal
 		if err == nil {
-			feePercent := *account.Type.DepositAnnualInterestRate
+			feePercent, err = interestRates.AnnualRate(account.Type, accountTypeModel.InterestRateKindDeposit, balance, accrualDate)
+			if err == nil && !feePercent.IsPositive() {
+				continue
+			}
+		}
+
+		if err == nil {
+			accrual := &InterestAccrual{
+				AccountId: &account.ID,
+				Date:      &accrualDate,
+				Balance:   balance,
+				Rate:      feePercent,
+				DayCount:  dayCount.String(),
+				Amount:    dayCount.DailyInterest(balance, feePercent, accrualDate),
+				CreatedAt: &now,
+			}
 
 			params := &ScheduleParams{
-				Amount:     method.AnnualInterest(account.Balance, feePercent),
+				Amount:     accrual.Amount,
 				PaymentDay: getPaymentDay(account),
 				Period:     period,
 				Reason:     ReasonInterestGeneration,
@@ -54,7 +102,13 @@ func WatchInterestGeneration(scheduler *Service, db *gorm.DB, logger log15.Logge
 				m := time.Month(*account.Type.DepositPayoutMonth)
 				params.Month = &m
 			}
-			err = scheduler.ScheduleTransfer(params, db)
+			tx := db.Begin()
+			err = scheduler.AccrueInterest(params, accrual, tx)
+			if err != nil {
+				tx.Rollback()
+			} else {
+				err = tx.Commit().Error
+			}
 		}
 
 		if err != nil {
@@ -77,9 +131,53 @@ func WatchInterestGeneration(scheduler *Service, db *gorm.DB, logger log15.Logge
 			ReasonInterestGeneration,
 		)
 	}
+
+	return JobResult{
+		Processed: uint64(successfullyScheduledTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
+}
+
+// balanceAt returns the current balance of the account at the given time. The balance of the account is returned
+// unless it has been changed since then, otherwise it is taken from the snapshot of the last transaction
+// executed before the time.
+func balanceAt(db *gorm.DB, account *accountModel.Account, at time.Time) (decimal.Decimal, error) {
+	var changed uint64
+	err := db.
+		Model(&txModel.Transaction{}).
+		Where("account_id = ? AND status = ? AND created_at > ?", account.ID, txModel.StatusExecuted, at).
+		Count(&changed).
+		Error
+	if err != nil {
+		return decimal.Zero, errors.Wrapf(err, "failed to check transactions of account #%d", account.ID)
+	}
+	if changed == 0 {
+		return account.Balance, nil
+	}
+
+	last := &txModel.Transaction{}
+	err = db.
+		Where("account_id = ? AND status = ? AND created_at <= ?", account.ID, txModel.StatusExecuted, at).
+		Order("created_at desc, id desc").
+		First(last).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, errors.Wrapf(err, "failed to retrieve last transaction of account #%d", account.ID)
+	}
+	if last.CurrentBalanceSnapshot == nil {
+		return decimal.Zero, errors.Errorf("balance snapshot of transaction #%d is missing", *last.Id)
+	}
+	return *last.CurrentBalanceSnapshot, nil
 }
 
-func findAccountsForDailyInterestGeneration(db *gorm.DB, timeSource utils.Time) []*accountModel.Account {
+func findAccountsForDailyInterestGeneration(
+	db *gorm.DB,
+	timeSource utils.Time,
+	accrualDate time.Time,
+) []*accountModel.Account {
 	var accounts []*accountModel.Account
 
 	today := timeSource.Now()
@@ -91,20 +189,24 @@ func findAccountsForDailyInterestGeneration(db *gorm.DB, timeSource utils.Time)
 		Select("distinct accounts.*").
 		Joins("inner join account_types act on act.id = accounts.type_id").
 		Joins("inner join payout_methods pm on pm.id = act.deposit_payout_method_id").
-		Where(`act.deposit_annual_interest_rate > 0 
+		Where(`(act.deposit_annual_interest_rate > 0
+						or act.id in (select r.account_type_id from account_type_interest_rates r where r.kind = ?))
 					 and act.deposit_payout_method_id is not null
 					 and act.deposit_payout_period_id is not null
 					 and act.deposit_payout_day is not null
-				     and accounts.balance > 0
 					 and (accounts.maturity_date > ? OR accounts.maturity_date IS NULL)
+					 and accounts.closed_at is null
 					 and pm.method = ?
+					 and accounts.id not in (select ia.account_id from interest_accruals ia where ia.date = ?)
 					 and accounts.id not in (
 						select st.account_id from scheduled_transactions st
 						inner join scheduled_transaction_logs logs on logs.scheduled_transaction_id = st.id
 						where st.reason = ?
 						and logs.created_at between ? and ?)`,
+			accountTypeModel.InterestRateKindDeposit,
 			today,
 			calculation.InterestCalculationMethodDaily,
+			accrualDate,
 			ReasonInterestGeneration,
 			timeSource.BeginningOfDay(),
 			timeSource.EndOfDay()).
@@ -0,0 +1,78 @@
+package scheduled_transaction_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+	txModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
+)
+
+var _ = Describe("Balance for interest accrual", func() {
+	var (
+		gdb  *gorm.DB
+		mock sqlmock.Sqlmock
+	)
+	at := time.Date(2020, 3, 10, 0, 5, 0, 0, time.UTC)
+	account := &model.Account{}
+	account.ID = 1
+	account.Balance = decimal.NewFromInt(500)
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectChangedSince := func(count int) {
+		mock.ExpectQuery("SELECT count\\(\\*\\) FROM `transactions` WHERE \\(account_id = \\? AND status = \\? AND created_at > \\?\\)").
+			WithArgs(1, txModel.StatusExecuted, at).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+	expectLastTransaction := func(rows *sqlmock.Rows) {
+		mock.ExpectQuery("SELECT \\* FROM `transactions` WHERE \\(account_id = \\? AND status = \\? AND created_at <= \\?\\) ORDER BY created_at desc, id desc").
+			WithArgs(1, txModel.StatusExecuted, at).
+			WillReturnRows(rows)
+	}
+
+	It("should use the balance of the account when it has not changed since", func() {
+		expectChangedSince(0)
+
+		balance, err := BalanceAt(gdb, account, at)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(balance.String()).To(Equal("500"))
+	})
+
+	It("should use the balance snapshot of the last transaction before the time", func() {
+		expectChangedSince(2)
+		expectLastTransaction(sqlmock.NewRows([]string{"id", "current_balance_snapshot"}).AddRow(7, "320.5"))
+
+		balance, err := BalanceAt(gdb, account, at)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(balance.String()).To(Equal("320.5"))
+	})
+
+	It("should use zero balance when there were no transactions before the time", func() {
+		expectChangedSince(1)
+		expectLastTransaction(sqlmock.NewRows([]string{"id", "current_balance_snapshot"}))
+
+		balance, err := BalanceAt(gdb, account, at)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(balance.IsZero()).To(BeTrue())
+	})
+
+	It("should fail when the balance snapshot is missing", func() {
+		expectChangedSince(1)
+		expectLastTransaction(sqlmock.NewRows([]string{"id", "current_balance_snapshot"}).AddRow(7, nil))
+
+		_, err := BalanceAt(gdb, account, at)
+		Expect(err).To(MatchError(ContainSubstring("balance snapshot of transaction #7 is missing")))
+	})
+})
@@ -1,6 +1,8 @@
 package scheduled_transaction
 
 import (
+	"context"
+
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
 	"github.com/Confialink/wallet-pkg-utils"
 	"github.com/inconshreveable/log15"
@@ -9,7 +11,7 @@ import (
 
 const watchLimitBalanceMaxErrors = 3
 
-func WatchLimitBalance(scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) {
+func WatchLimitBalance(ctx context.Context, scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) JobResult {
 	logger = logger.New("Task", "WatchLimitBalance")
 	getPaymentDay := func(account *accountModel.Account) int {
 		day := 1
@@ -25,6 +27,9 @@ func WatchLimitBalance(scheduler *Service, db *gorm.DB, logger log15.Logger, tim
 	successfullyScheduledTransfersCount := 0
 	now := timeSource.Now()
 	for _, account := range findAccountsHavingBalanceLessThanLimitBalance(db, timeSource) {
+		if isAborted(ctx, logger) {
+			break
+		}
 		err := scheduler.ScheduleTransfer(
 			&ScheduleParams{
 				Amount:     account.Type.BalanceFeeAmount.Neg(),
@@ -57,6 +62,11 @@ func WatchLimitBalance(scheduler *Service, db *gorm.DB, logger log15.Logger, tim
 			ReasonLimitBalanceFee,
 		)
 	}
+
+	return JobResult{
+		Processed: uint64(successfullyScheduledTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
 }
 
 // findAccountsHavingBalanceLessThanLimitBalance searches for accounts
@@ -75,6 +85,7 @@ func findAccountsHavingBalanceLessThanLimitBalance(db *gorm.DB, timeSource utils
 					 and act.balance_fee_amount > 0 
 					 and accounts.balance < act.balance_limit_amount
 					 and (accounts.maturity_date > ? OR accounts.maturity_date IS NULL)
+					 and accounts.closed_at is null
 					 and (st.reason is null or st.reason = ?)
 					 and (st.status is null or st.status != ?)
 					 and accounts.id not in 
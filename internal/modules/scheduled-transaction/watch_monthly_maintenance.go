@@ -1,6 +1,8 @@
 package scheduled_transaction
 
 import (
+	"context"
+
 	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
 	"github.com/Confialink/wallet-pkg-utils"
 	"github.com/inconshreveable/log15"
@@ -9,13 +11,16 @@ import (
 
 const watchLimitMaintenanceMaxErrors = 3
 
-func WatchMaintenanceFee(scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) {
+func WatchMaintenanceFee(ctx context.Context, scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) JobResult {
 	logger = logger.New("Task", "WatchMonthlyMaintenance")
 
 	errorsCount := 0
 	successfullyScheduledTransfersCount := 0
 	now := timeSource.Now()
 	for _, account := range findAccountsForMonthlyMaintenance(db, timeSource) {
+		if isAborted(ctx, logger) {
+			break
+		}
 		err := scheduler.ScheduleTransfer(
 			&ScheduleParams{
 				Amount:     account.Type.MonthlyMaintenanceFee.Neg(),
@@ -48,6 +53,11 @@ func WatchMaintenanceFee(scheduler *Service, db *gorm.DB, logger log15.Logger, t
 			ReasonMaintenanceFee,
 		)
 	}
+
+	return JobResult{
+		Processed: uint64(successfullyScheduledTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
 }
 
 // findAccountsForMonthlyMaintenance searches for accounts
@@ -64,6 +74,7 @@ func findAccountsForMonthlyMaintenance(db *gorm.DB, timeSource utils.Time) []*ac
 		Joins("left join scheduled_transactions st ON st.account_id = accounts.id").
 		Where(`act.monthly_maintenance_fee > 0
 					 and (accounts.maturity_date > ? OR accounts.maturity_date IS NULL)
+					 and accounts.closed_at is null
 					 and (st.reason is null or st.reason = ?)
 					 and (st.status is null or st.status != ?)					 
 					 and accounts.id not in 
@@ -0,0 +1,89 @@
+package scheduled_transaction
+
+import (
+	"context"
+
+	accountModel "github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	"github.com/Confialink/wallet-pkg-utils"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+)
+
+const watchUnarrangedOverdraftMaxErrors = 3
+
+// WatchUnarrangedOverdraft schedules the fee for the current day on accounts
+// which balance is below the arranged overdraft limit, the fee is charged once the day is over
+func WatchUnarrangedOverdraft(ctx context.Context, scheduler *Service, db *gorm.DB, logger log15.Logger, timeSource utils.Time) JobResult {
+	logger = logger.New("Task", "WatchUnarrangedOverdraft")
+
+	errorsCount := 0
+	successfullyScheduledTransfersCount := 0
+	now := timeSource.Now()
+	endOfDay := timeSource.EndOfDay()
+	for _, account := range findAccountsInUnarrangedOverdraft(db, timeSource) {
+		if isAborted(ctx, logger) {
+			break
+		}
+		err := scheduler.ScheduleTransfer(
+			&ScheduleParams{
+				Amount:  account.Type.UnarrangedOverdraftFee.Neg(),
+				Reason:  ReasonUnarrangedOverdraftFee,
+				Account: account,
+				Date:    &endOfDay,
+				Now:     now,
+			},
+			db,
+		)
+
+		if err != nil {
+			errorsCount++
+			logger.Error("failed to schedule transfer", "error", err)
+			if errorsCount >= watchUnarrangedOverdraftMaxErrors {
+				break
+			}
+			continue
+		}
+		successfullyScheduledTransfersCount++
+	}
+
+	if successfullyScheduledTransfersCount > 0 {
+		logger.Info(
+			"successfully scheduled transfers",
+			"count",
+			successfullyScheduledTransfersCount,
+			"reason",
+			ReasonUnarrangedOverdraftFee,
+		)
+	}
+
+	return JobResult{
+		Processed: uint64(successfullyScheduledTransfersCount),
+		Failed:    uint64(errorsCount),
+	}
+}
+
+// findAccountsInUnarrangedOverdraft searches for accounts having balance below the arranged overdraft limit
+// which are not scheduled for the fee of the current day yet
+func findAccountsInUnarrangedOverdraft(db *gorm.DB, timeSource utils.Time) []*accountModel.Account {
+	var accounts []*accountModel.Account
+
+	db.
+		Table("accounts").
+		Preload("Type").
+		Select("distinct accounts.*").
+		Joins("inner join account_types act on act.id = accounts.type_id").
+		Where(`act.unarranged_overdraft_fee > 0
+					 and accounts.balance + COALESCE(accounts.overdraft_limit, act.credit_limit_amount, 0) < 0
+					 and accounts.closed_at is null
+					 and accounts.id not in (
+						select st.account_id from scheduled_transactions st
+						where st.reason = ?
+						and st.status = ?
+						and st.scheduled_date > ?)`,
+			ReasonUnarrangedOverdraftFee,
+			StatusPending,
+			timeSource.Now()).
+		Find(&accounts)
+
+	return accounts
+}
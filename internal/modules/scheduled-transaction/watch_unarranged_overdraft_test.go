@@ -0,0 +1,110 @@
+package scheduled_transaction_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/account/model"
+	. "github.com/Confialink/wallet-accounts/internal/modules/scheduled-transaction"
+)
+
+var _ = Describe("Unarranged overdraft fee", func() {
+	var (
+		gdb       *gorm.DB
+		mock      sqlmock.Sqlmock
+		scheduler *Service
+	)
+	any := sqlmock.AnyArg()
+	now := time.Date(2020, 3, 10, 23, 55, 0, 0, time.UTC)
+	endOfDay := time.Date(2020, 3, 10, 23, 59, 59, 999999999, time.UTC)
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		scheduler = NewService(
+			NewRepository(gdb),
+			NewScheduledTransactionLogRepository(gdb),
+			NewInterestAccrualRepository(gdb),
+			gdb,
+			log15.New(),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectNoPendingFee := func() {
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions` WHERE \\(account_id = \\? AND reason = \\? AND status = \\? AND scheduled_date > \\?\\)").
+			WithArgs(1, ReasonUnarrangedOverdraftFee, StatusPending, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+	expectFeeScheduled := func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `scheduled_transactions`").
+			WithArgs(ReasonUnarrangedOverdraftFee, 1, any, StatusPending, nil, endOfDay, nil, any, any).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `scheduled_transactions` SET").
+			WithArgs("-5", 9, any, 9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `scheduled_transaction_logs`").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+
+	It("should schedule the fee of the day for accounts which have not been scheduled for it yet", func() {
+		mock.ExpectQuery("SELECT distinct accounts.\\* FROM `accounts` .* accounts.id not in \\(\\s*select st.account_id from scheduled_transactions st").
+			WithArgs(ReasonUnarrangedOverdraftFee, StatusPending, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type_id", "balance"}).AddRow(1, 3, "-150"))
+		mock.ExpectQuery("SELECT \\* FROM `account_types`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "credit_limit_amount", "unarranged_overdraft_fee"}).AddRow(3, "100", "5"))
+		// validation and lookup of the pending fee
+		expectNoPendingFee()
+		expectNoPendingFee()
+		expectFeeScheduled()
+
+		result := WatchUnarrangedOverdraft(context.Background(), scheduler, gdb, log15.New(), fixedTime(now))
+		Expect(result).To(Equal(JobResult{Processed: 1}))
+	})
+
+	It("should not schedule another fee for the same day", func() {
+		mock.ExpectQuery("SELECT \\* FROM `scheduled_transactions`").
+			WithArgs(1, ReasonUnarrangedOverdraftFee, StatusPending, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "scheduled_date"}).AddRow(9, endOfDay))
+
+		account := &model.Account{}
+		account.ID = 1
+		err := scheduler.ScheduleTransfer(&ScheduleParams{
+			Account: account,
+			Reason:  ReasonUnarrangedOverdraftFee,
+			Amount:  decimal.New(-5, 0),
+			Date:    &endOfDay,
+			Now:     now,
+		}, gdb)
+		Expect(err).To(Equal(ErrorAlreadyScheduled))
+	})
+})
+
+// fixedTime is the time source which always returns the given time
+type fixedTime time.Time
+
+func (t fixedTime) Now() time.Time {
+	return time.Time(t)
+}
+
+func (t fixedTime) BeginningOfDay() time.Time {
+	year, month, day := time.Time(t).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Time(t).Location())
+}
+
+func (t fixedTime) EndOfDay() time.Time {
+	return t.BeginningOfDay().AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
@@ -43,6 +43,10 @@ func (s *SettingsController) UpdateSetting(c *gin.Context) {
 	}
 
 	settingName := c.Param("setting")
+	if err := settings.Validate(settings.Name(settingName), settingForm.Value); err != nil {
+		errors.AddErrors(c, errcodes.CreatePublicError(errcodes.CodeInvalidSettingValue, err.Error()))
+		return
+	}
 
 	setting, err := s.repository.Update(settingName, settingForm.Value)
 	if nil != err {
@@ -63,6 +67,13 @@ func (s *SettingsController) MassUpdateSetting(c *gin.Context) {
 		return
 	}
 
+	for _, pair := range settingsForm.KeyValuePairs {
+		if err := settings.Validate(settings.Name(pair.Key), pair.Value); err != nil {
+			errors.AddErrors(c, errcodes.CreatePublicError(errcodes.CodeInvalidSettingValue, pair.Key+": "+err.Error()))
+			return
+		}
+	}
+
 	err := s.repository.MassUpdate(settingsForm.KeyValuePairs)
 	if nil != err {
 		errors.AddErrors(c, &errors.PrivateError{Message: err.Error()})
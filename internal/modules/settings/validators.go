@@ -0,0 +1,19 @@
+package settings
+
+// Validator checks the value of the setting before it is updated
+type Validator func(value string) error
+
+var validators = map[Name]Validator{}
+
+// RegisterValidator registers validator of the setting, modules register validators of their settings on init
+func RegisterValidator(name Name, validator Validator) {
+	validators[name] = validator
+}
+
+// Validate checks the value of the setting, settings without validators accept any value
+func Validate(name Name, value string) error {
+	if validator, ok := validators[name]; ok {
+		return validator(value)
+	}
+	return nil
+}
@@ -66,7 +66,44 @@ func (a *AccountLogCreator) LogModifyAccount(
 	old *model.Account, new *model.Account, userId string,
 ) {
 	defer a.recoverer()
+	a.logAccountChange(SubjectModifyAccount, old, new, userId)
+}
 
+// LogReactivateAccount records reactivation of the dormant account
+func (a *AccountLogCreator) LogReactivateAccount(
+	old *model.Account, new *model.Account, userId string,
+) {
+	defer a.recoverer()
+	a.logAccountChange(SubjectReactivateAccount, old, new, userId)
+}
+
+// LogCloseAccount records closure of the account along with its closing statement
+func (a *AccountLogCreator) LogCloseAccount(
+	account *model.Account, statement interface{}, userId string,
+) {
+	defer a.recoverer()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"account":   account,
+		"statement": statement,
+	})
+	if err != nil {
+		a.logger.Error("Can't marshall json", err)
+		return
+	}
+
+	a.logsServiceWrap.createLog(
+		SubjectCloseAccount,
+		userId,
+		time.Now().Format(time.RFC3339),
+		DataTitleAccountDetails+": "+account.Number,
+		data,
+	)
+}
+
+func (a *AccountLogCreator) logAccountChange(
+	subject string, old *model.Account, new *model.Account, userId string,
+) {
 	data, err := json.Marshal(map[string]interface{}{
 		"old": old,
 		"new": new,
@@ -77,7 +114,7 @@ func (a *AccountLogCreator) LogModifyAccount(
 	}
 
 	a.logsServiceWrap.createLog(
-		SubjectModifyAccount,
+		subject,
 		userId,
 		time.Now().Format(time.RFC3339),
 		DataTitleAccountDetails+": "+old.Number,
@@ -8,8 +8,10 @@ const (
 	SubjectDeleteIwtBankAccounts = "Delete IWT Bank Accounts"
 	SubjectModifyIwtBankAccounts = "Modify IWT Bank Accounts"
 
-	SubjectCreateAccount = "New Account"
-	SubjectModifyAccount = "Modify Account"
+	SubjectCreateAccount     = "New Account"
+	SubjectModifyAccount     = "Modify Account"
+	SubjectReactivateAccount = "Reactivate Account"
+	SubjectCloseAccount      = "Close Account"
 
 	SubjectCreateAccountTypes = "New Account Type"
 	SubjectModifyAccountTypes = "Modify Account Type"
@@ -19,6 +21,10 @@ const (
 
 	SubjectCreateCardType = "New Card Type"
 	SubjectModifyCardType = "Modify Card Type"
+
+	SubjectWaiveScheduledTransaction   = "Waive Scheduled Transaction"
+	SubjectModifyScheduledTransaction  = "Modify Scheduled Transaction"
+	SubjectExecuteScheduledTransaction = "Execute Scheduled Transaction"
 )
 
 const (
@@ -29,6 +35,7 @@ const (
 	DataTitleAccountTypeDetails          = "Account Type Details"
 	DataTitleCardDetails                 = "Card Details"
 	DataTitleCardTypeDetails             = "Card Type Details"
+	DataTitleScheduledTransactionDetails = "Scheduled Transaction Details"
 )
 
 const (
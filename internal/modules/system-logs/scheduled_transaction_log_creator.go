@@ -0,0 +1,53 @@
+package system_logs
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/Confialink/wallet-accounts/internal/recovery"
+)
+
+// ScheduledTransactionLogCreator logs changes of scheduled transactions made by administrators.
+// Transactions are passed as is since the scheduled transaction module depends on this one.
+type ScheduledTransactionLogCreator struct {
+	logsServiceWrap *logsServiceWrap
+	logger          log15.Logger
+	recoverer       func()
+}
+
+func NewScheduledTransactionLogCreator(
+	logger log15.Logger,
+) *ScheduledTransactionLogCreator {
+	logger = logger.New("service", "SystemLogsService")
+	return &ScheduledTransactionLogCreator{
+		logsServiceWrap: newLogsServiceWrap(logger.New("serviceWrap", "logsServiceWrap")),
+		logger:          logger,
+		recoverer:       recovery.DefaultRecoverer(),
+	}
+}
+
+func (l *ScheduledTransactionLogCreator) LogModifyScheduledTransaction(
+	subject string, id uint64, old interface{}, new interface{}, userId string,
+) {
+	defer l.recoverer()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"old": old,
+		"new": new,
+	})
+	if err != nil {
+		l.logger.Error("Can't marshall json", err)
+		return
+	}
+
+	l.logsServiceWrap.createLog(
+		subject,
+		userId,
+		time.Now().Format(time.RFC3339),
+		DataTitleScheduledTransactionDetails+": "+strconv.FormatUint(id, 10),
+		data,
+	)
+}
@@ -16,6 +16,7 @@ type SystemLogsService struct {
 	accountTypeLogCreator    *AccountTypeLogCreator
 	cardLogCreator           *CardLogCreator
 	cardTypeLogCreator       *CardTypeLogCreator
+	scheduledTxLogCreator    *ScheduledTransactionLogCreator
 }
 
 func NewSystemLogsService(
@@ -25,6 +26,7 @@ func NewSystemLogsService(
 	accountTypeLogCreator *AccountTypeLogCreator,
 	cardLogCreator *CardLogCreator,
 	cardTypeLogCreator *CardTypeLogCreator,
+	scheduledTxLogCreator *ScheduledTransactionLogCreator,
 ) *SystemLogsService {
 	return &SystemLogsService{
 		transactionLogCreator:    transactionLogCreator,
@@ -33,6 +35,7 @@ func NewSystemLogsService(
 		accountTypeLogCreator:    accountTypeLogCreator,
 		cardLogCreator:           cardLogCreator,
 		cardTypeLogCreator:       cardTypeLogCreator,
+		scheduledTxLogCreator:    scheduledTxLogCreator,
 	}
 }
 
@@ -81,6 +84,24 @@ func (s *SystemLogsService) LogModifyAccountAsync(
 	go s.accountLogCreator.LogModifyAccount(old, new, userId)
 }
 
+func (s *SystemLogsService) LogReactivateAccountAsync(
+	old *accountModel.Account,
+	new *accountModel.Account,
+	userId string,
+) {
+	go s.accountLogCreator.LogReactivateAccount(old, new, userId)
+}
+
+// LogCloseAccountAsync records closure of the account, statement is passed as is
+// since its type belongs to the module which depends on system logs
+func (s *SystemLogsService) LogCloseAccountAsync(
+	account *accountModel.Account,
+	statement interface{},
+	userId string,
+) {
+	go s.accountLogCreator.LogCloseAccount(account, statement, userId)
+}
+
 func (s *SystemLogsService) LogCreateAccountTypeAsync(
 	accountType *accountTypeModel.AccountType,
 	userId string,
@@ -125,3 +146,15 @@ func (s *SystemLogsService) LogModifyCardTypeAsync(
 ) {
 	go s.cardTypeLogCreator.LogModifyCardType(oldRec, newRec, userId)
 }
+
+// LogModifyScheduledTransactionAsync logs the change of the scheduled transaction with the given subject,
+// old and new are states of the transaction before and after the change
+func (s *SystemLogsService) LogModifyScheduledTransactionAsync(
+	subject string,
+	id uint64,
+	old interface{},
+	new interface{},
+	userId string,
+) {
+	go s.scheduledTxLogCreator.LogModifyScheduledTransaction(subject, id, old, new, userId)
+}
@@ -12,5 +12,6 @@ func Providers() []interface{} {
 		system_logs.NewAccountTypeLogCreator,
 		system_logs.NewCardLogCreator,
 		system_logs.NewCardTypeLogCreator,
+		system_logs.NewScheduledTransactionLogCreator,
 	}
 }
@@ -0,0 +1,137 @@
+package tan
+
+import (
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
+)
+
+const (
+	approvalTokenSize         = 16
+	defaultApprovalTtlSeconds = 300
+)
+
+const (
+	ErrApprovalNotFound = Error(errcodes.CodeTanApprovalNotFound)
+	ErrApprovalSelf     = Error(errcodes.CodeTanApprovalSelf)
+)
+
+// Approval implements out-of-band second factor:
+// a challenge is requested by a client, another user (e.g. an administrator) approves or rejects it
+// and then the client passes challenge token as the code. Self-approval is checked by the user id
+// since the session and the device of a client could not tell the user apart from the approver.
+type Approval struct {
+	repository *FactorRepository
+	settings   *settings.Service
+	now        func() time.Time
+}
+
+func NewApproval(repository *FactorRepository, settings *settings.Service) *Approval {
+	return &Approval{repository: repository, settings: settings, now: time.Now}
+}
+
+func (a *Approval) Method() Method {
+	return MethodApproval
+}
+
+// Request creates new pending challenge for the user, the challenge could not be approved by the user
+func (a *Approval) Request(userId string) (*model.ApprovalChallenge, error) {
+	random, err := generateRandomBytes(approvalTokenSize)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := a.settings.Int64(SettingTanApprovalTtlSecondsInt64)
+	if err != nil || ttl <= 0 {
+		ttl = defaultApprovalTtlSeconds
+	}
+	now := a.now()
+	challenge := &model.ApprovalChallenge{
+		UID:       userId,
+		Token:     hex.EncodeToString(random),
+		Status:    model.ApprovalStatusPending,
+		ExpiresAt: now.Add(time.Duration(ttl) * time.Second),
+		CreatedAt: now,
+	}
+	if err := a.repository.CreateApproval(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// Pending returns not expired pending challenges of the user
+func (a *Approval) Pending(userId string) ([]*model.ApprovalChallenge, error) {
+	return a.repository.FindPendingApprovals(userId, a.now())
+}
+
+// Approve approves pending challenge of another user,
+// ErrApprovalSelf is returned if the challenge has been requested by the approver
+func (a *Approval) Approve(approverId string, id int64) error {
+	challenge, err := a.repository.FindApprovalById(id, a.now())
+	if err != nil {
+		return err
+	}
+	if challenge == nil {
+		return ErrApprovalNotFound
+	}
+	if challenge.UID == approverId {
+		return ErrApprovalSelf
+	}
+	return a.resolve(challenge, model.ApprovalStatusApproved)
+}
+
+// Reject rejects pending challenge of any user
+func (a *Approval) Reject(id int64) error {
+	challenge, err := a.repository.FindApprovalById(id, a.now())
+	if err != nil {
+		return err
+	}
+	if challenge == nil {
+		return ErrApprovalNotFound
+	}
+	return a.resolve(challenge, model.ApprovalStatusRejected)
+}
+
+// Cancel rejects pending challenge requested by the user
+func (a *Approval) Cancel(userId string, id int64) error {
+	challenge, err := a.repository.FindApproval(id, userId, a.now())
+	if err != nil {
+		return err
+	}
+	if challenge == nil {
+		return ErrApprovalNotFound
+	}
+	return a.resolve(challenge, model.ApprovalStatusRejected)
+}
+
+// Use checks that challenge with the given token is approved and marks it as used
+func (a *Approval) Use(userId, token string) bool {
+	challenge, err := a.repository.FindApprovalByToken(token, userId, a.now())
+	if err != nil {
+		log.Println("approval failed to retrieve challenge: ", err)
+		return false
+	}
+	if challenge == nil {
+		return false
+	}
+	ok, err := a.repository.UpdateApprovalStatus(challenge, model.ApprovalStatusApproved, model.ApprovalStatusUsed)
+	if err != nil {
+		log.Println("approval failed to use challenge: ", err)
+		return false
+	}
+	return ok
+}
+
+func (a *Approval) resolve(challenge *model.ApprovalChallenge, status string) error {
+	ok, err := a.repository.UpdateApprovalStatus(challenge, model.ApprovalStatusPending, status)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrApprovalNotFound
+	}
+	return nil
+}
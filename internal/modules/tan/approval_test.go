@@ -0,0 +1,110 @@
+package tan_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/tan"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
+)
+
+var _ = Describe("Approval", func() {
+	var (
+		gdb      *gorm.DB
+		mock     sqlmock.Sqlmock
+		approval *Approval
+	)
+	any := sqlmock.AnyArg()
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		approval = NewApproval(NewFactorRepository(gdb), settings.NewService(settingsRepository.NewSettings(gdb)))
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectPendingChallenge := func() {
+		mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges` WHERE \\(id = \\? AND expires_at > \\?\\)").
+			WithArgs(7, any).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "uid", "token", "status", "expires_at"}).
+				AddRow(7, userId, "token", model.ApprovalStatusPending, time.Now().Add(time.Minute)))
+	}
+
+	It("should create pending challenge", func() {
+		expectNoSetting(mock)
+		expectExec(mock, "INSERT INTO `tan_approval_challenges`", userId, any, model.ApprovalStatusPending, any, any)
+
+		challenge, err := approval.Request(userId)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(challenge.Token).To(HaveLen(32))
+	})
+
+	It("should not be approved by the requesting user", func() {
+		expectPendingChallenge()
+
+		err := approval.Approve(userId, 7)
+		Expect(err).To(Equal(ErrApprovalSelf))
+	})
+
+	It("should be approved by another user", func() {
+		expectPendingChallenge()
+		expectExec(mock, "UPDATE `tan_approval_challenges` SET `status`", model.ApprovalStatusApproved, 7, model.ApprovalStatusPending)
+
+		Expect(approval.Approve("admin-uid", 7)).To(Succeed())
+	})
+
+	It("should be rejected by another user", func() {
+		expectPendingChallenge()
+		expectExec(mock, "UPDATE `tan_approval_challenges` SET `status`", model.ApprovalStatusRejected, 7, model.ApprovalStatusPending)
+
+		Expect(approval.Reject(7)).To(Succeed())
+	})
+
+	It("should be cancelled by the requesting user", func() {
+		mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges` WHERE \\(id = \\? AND uid = \\? AND expires_at > \\?\\)").
+			WithArgs(7, userId, any).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "uid", "status"}).AddRow(7, userId, model.ApprovalStatusPending))
+		expectExec(mock, "UPDATE `tan_approval_challenges` SET `status`", model.ApprovalStatusRejected, 7, model.ApprovalStatusPending)
+
+		Expect(approval.Cancel(userId, 7)).To(Succeed())
+	})
+
+	It("should not cancel challenge of another user", func() {
+		mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges`").
+			WithArgs(7, "another-uid", any).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		Expect(approval.Cancel("another-uid", 7)).To(Equal(ErrApprovalNotFound))
+	})
+
+	It("should not approve unknown or expired challenges", func() {
+		mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		err := approval.Approve("admin-uid", 7)
+		Expect(err).To(Equal(ErrApprovalNotFound))
+	})
+
+	It("should use approved challenge only once", func() {
+		mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges`").
+			WithArgs("token", userId, any).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, model.ApprovalStatusApproved))
+		expectExec(mock, "UPDATE `tan_approval_challenges` SET `status`", model.ApprovalStatusUsed, 7, model.ApprovalStatusApproved)
+		Expect(approval.Use(userId, "token")).To(BeTrue())
+
+		mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges`").
+			WithArgs("token", userId, any).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, model.ApprovalStatusUsed))
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `tan_approval_challenges` SET `status`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		Expect(approval.Use(userId, "token")).To(BeFalse())
+	})
+})
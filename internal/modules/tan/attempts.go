@@ -0,0 +1,117 @@
+package tan
+
+import (
+	"time"
+
+	"github.com/olebedev/emitter"
+	"github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/event"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
+)
+
+const (
+	defaultMaxFailedAttempts         = 5
+	defaultFailedAttemptDelaySeconds = 2
+	// maxFailedAttemptDelay limits progressive delay between attempts
+	maxFailedAttemptDelay = 15 * time.Minute
+)
+
+const (
+	ErrLocked          = Error(errcodes.CodeTanLocked)
+	ErrTooManyAttempts = Error(errcodes.CodeTanTooManyAttempts)
+)
+
+// AttemptGuard protects second factor codes against brute-force:
+// every failed attempt doubles the delay before the next one is accepted
+// and the user is locked out after the configured number of consecutive failures.
+type AttemptGuard struct {
+	repository *AttemptsRepository
+	settings   *settings.Service
+	emitter    *emitter.Emitter
+	now        func() time.Time
+}
+
+func NewAttemptGuard(repository *AttemptsRepository, settings *settings.Service, emitter *emitter.Emitter) *AttemptGuard {
+	return &AttemptGuard{repository: repository, settings: settings, emitter: emitter, now: time.Now}
+}
+
+// Check returns ErrLocked if the user is locked out or ErrTooManyAttempts if the next attempt is delayed.
+// Otherwise the attempt is registered as failed one in the same locked update until Succeed is called,
+// so that concurrent attempts are delayed as if the previous ones have already failed.
+func (g *AttemptGuard) Check(userId string) error {
+	now := g.now()
+	err := g.repository.Reserve(userId, now, func(attempts *model.FailedAttempts) error {
+		if attempts.LockedAt != nil {
+			return ErrLocked
+		}
+		if attempts.Count > 0 && now.Before(attempts.LastFailedAt.Add(g.delay(attempts.Count))) {
+			return ErrTooManyAttempts
+		}
+		return nil
+	})
+	switch err {
+	case nil, ErrLocked, ErrTooManyAttempts:
+		return err
+	}
+	return errors.Wrapf(err, "failed to register attempt of user %s", userId)
+}
+
+// Fail reports failed attempt registered by Check and locks the user out if the limit is reached
+func (g *AttemptGuard) Fail(userId string) error {
+	attempts, locked, err := g.repository.LockIfExceeded(userId, g.now(), g.maxAttempts())
+	if err != nil {
+		return errors.Wrapf(err, "failed to save failed attempts of user %s", userId)
+	}
+
+	<-g.emitter.Emit(event.FailedAttempt, &event.ContextFailedAttempt{
+		UserID:         userId,
+		FailedAttempts: attempts.Count,
+		Locked:         locked,
+	})
+	return nil
+}
+
+// Succeed resets failed attempts of the user
+func (g *AttemptGuard) Succeed(userId string) error {
+	return g.repository.DeleteByUID(userId)
+}
+
+// Status returns failed attempts of the user, nil is returned if there are no failed attempts
+func (g *AttemptGuard) Status(userId string) (*model.FailedAttempts, error) {
+	return g.repository.FindByUID(userId)
+}
+
+// Unlock removes lockout and resets failed attempts of the user
+func (g *AttemptGuard) Unlock(userId string) error {
+	return g.repository.DeleteByUID(userId)
+}
+
+func (g *AttemptGuard) maxAttempts() int64 {
+	max, err := g.settings.Int64(SettingTanMaxFailedAttemptsInt64)
+	if err != nil {
+		return defaultMaxFailedAttempts
+	}
+	return max
+}
+
+// delay returns time which must pass after the last failed attempt: base delay * 2^(failed attempts - 1)
+func (g *AttemptGuard) delay(failed int64) time.Duration {
+	base, err := g.settings.Int64(SettingTanFailedAttemptDelaySecondsInt64)
+	if err != nil {
+		base = defaultFailedAttemptDelaySeconds
+	}
+	if base <= 0 || failed <= 0 {
+		return 0
+	}
+	delay := time.Duration(base) * time.Second
+	for i := int64(1); i < failed; i++ {
+		delay *= 2
+		if delay >= maxFailedAttemptDelay {
+			return maxFailedAttemptDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,101 @@
+package tan
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
+)
+
+type AttemptsRepository struct {
+	db *gorm.DB
+}
+
+func NewAttemptsRepository(db *gorm.DB) *AttemptsRepository {
+	return &AttemptsRepository{db}
+}
+
+// FindByUID retrieves failed attempts of the given user, nil is returned if there are no failed attempts
+func (r *AttemptsRepository) FindByUID(uid string) (*model.FailedAttempts, error) {
+	attempts := &model.FailedAttempts{}
+	err := r.db.Where("uid = ?", uid).First(attempts).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return attempts, err
+}
+
+// Reserve registers the attempt of the given user as failed one in advance, so that concurrent attempts
+// could not pass the check before any of them fails. The row of the user is locked while allowed checks
+// the attempts registered so far, the attempt is not registered if allowed returns an error.
+func (r *AttemptsRepository) Reserve(uid string, now time.Time, allowed func(attempts *model.FailedAttempts) error) error {
+	tx := r.db.Begin()
+	err := tx.Exec(
+		"INSERT IGNORE INTO `tan_failed_attempts` (`uid`, `count`, `last_failed_at`) VALUES (?, 0, ?)",
+		uid,
+		now,
+	).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	attempts := &model.FailedAttempts{}
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("uid = ?", uid).First(attempts).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := allowed(attempts); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Exec(
+		"UPDATE `tan_failed_attempts` SET `count` = `count` + 1, `last_failed_at` = ? WHERE `uid` = ?",
+		now,
+		uid,
+	).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// LockIfExceeded locks the given user out once failed attempts registered by Reserve reach maxAttempts
+// (0 disables lockout). It returns attempts of the user and whether the user has been locked out by this call.
+func (r *AttemptsRepository) LockIfExceeded(uid string, now time.Time, maxAttempts int64) (*model.FailedAttempts, bool, error) {
+	tx := r.db.Begin()
+	locked := false
+	if maxAttempts > 0 {
+		result := tx.Exec(
+			"UPDATE `tan_failed_attempts` SET `locked_at` = ? WHERE `uid` = ? AND `locked_at` IS NULL AND `count` >= ?",
+			now,
+			uid,
+			maxAttempts,
+		)
+		if result.Error != nil {
+			tx.Rollback()
+			return nil, false, result.Error
+		}
+		locked = result.RowsAffected > 0
+	}
+
+	attempts := &model.FailedAttempts{}
+	err := tx.Where("uid = ?", uid).First(attempts).Error
+	if gorm.IsRecordNotFoundError(err) {
+		// attempts have been reset by a concurrent successful attempt
+		tx.Rollback()
+		return &model.FailedAttempts{UID: uid}, false, nil
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, false, err
+	}
+	return attempts, locked, tx.Commit().Error
+}
+
+func (r *AttemptsRepository) DeleteByUID(uid string) error {
+	return r.db.Delete(&model.FailedAttempts{}, "uid = ?", uid).Error
+}
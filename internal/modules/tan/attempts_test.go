@@ -0,0 +1,155 @@
+package tan_test
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/olebedev/emitter"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/tan"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/event"
+)
+
+// expectFailedAttempt expects the attempt registered by the check to be reported as failed one,
+// locked defines whether the lockout condition matches
+func expectFailedAttempt(mock sqlmock.Sqlmock, count int64, locked bool) {
+	mock.ExpectBegin()
+	lockedRows := int64(0)
+	if locked {
+		lockedRows = 1
+	}
+	mock.ExpectExec("UPDATE `tan_failed_attempts` SET `locked_at` = \\? WHERE `uid` = \\? AND `locked_at` IS NULL AND `count` >= \\?").
+		WithArgs(sqlmock.AnyArg(), userId, 5).
+		WillReturnResult(sqlmock.NewResult(0, lockedRows))
+	mock.ExpectQuery("SELECT \\* FROM `tan_failed_attempts`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uid", "count"}).AddRow(1, userId, count))
+	mock.ExpectCommit()
+}
+
+// expectAttemptCheck expects the attempt to be checked and registered in advance under the row lock,
+// delayed defines whether the delay setting is read, the attempt is not registered unless reserved
+func expectAttemptCheck(mock sqlmock.Sqlmock, attempts *sqlmock.Rows, delayed, reserved bool) {
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT IGNORE INTO `tan_failed_attempts`").
+		WithArgs(userId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT \\* FROM `tan_failed_attempts` WHERE \\(uid = \\?\\) .* FOR UPDATE").
+		WithArgs(userId).
+		WillReturnRows(attempts)
+	if delayed {
+		expectNoSetting(mock)
+	}
+	if !reserved {
+		mock.ExpectRollback()
+		return
+	}
+	mock.ExpectExec("UPDATE `tan_failed_attempts` SET `count` = `count` \\+ 1, `last_failed_at` = \\? WHERE `uid` = \\?").
+		WithArgs(sqlmock.AnyArg(), userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+var _ = Describe("AttemptGuard", func() {
+	var (
+		mock   sqlmock.Sqlmock
+		events <-chan emitter.Event
+		guard  *AttemptGuard
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		gdb, m := newMockDB()
+		mock = m
+		now = time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+		em := emitter.New(10)
+		events = em.On(event.FailedAttempt)
+		guard = NewAttemptGuard(NewAttemptsRepository(gdb), settings.NewService(settingsRepository.NewSettings(gdb)), em)
+		SetAttemptGuardClock(guard, func() time.Time { return now })
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	attemptRows := func(count int64, lastFailedAt time.Time, lockedAt *time.Time) *sqlmock.Rows {
+		return sqlmock.
+			NewRows([]string{"id", "uid", "count", "last_failed_at", "locked_at"}).
+			AddRow(1, userId, count, lastFailedAt, lockedAt)
+	}
+
+	nextEvent := func() *event.ContextFailedAttempt {
+		var e emitter.Event
+		Eventually(events).Should(Receive(&e))
+		return e.Args[0].(*event.ContextFailedAttempt)
+	}
+
+	It("should count failed attempts atomically", func() {
+		expectNoSetting(mock)
+		expectFailedAttempt(mock, 3, false)
+
+		Expect(guard.Fail(userId)).To(Succeed())
+		failed := nextEvent()
+		Expect(failed.FailedAttempts).To(Equal(int64(3)))
+		Expect(failed.Locked).To(BeFalse())
+	})
+
+	It("should lock the user out once the limit is reached", func() {
+		expectNoSetting(mock)
+		expectFailedAttempt(mock, 5, true)
+
+		Expect(guard.Fail(userId)).To(Succeed())
+		failed := nextEvent()
+		Expect(failed.FailedAttempts).To(Equal(int64(5)))
+		Expect(failed.Locked).To(BeTrue())
+
+		expectAttemptCheck(mock, attemptRows(5, now, &now), false, false)
+		Expect(guard.Check(userId)).To(Equal(ErrLocked))
+	})
+
+	It("should report lockout only once", func() {
+		// the user has been already locked out by a concurrent attempt
+		expectNoSetting(mock)
+		expectFailedAttempt(mock, 6, false)
+
+		Expect(guard.Fail(userId)).To(Succeed())
+		Expect(nextEvent().Locked).To(BeFalse())
+	})
+
+	It("should delay the next attempt progressively", func() {
+		// 2s * 2^(3-1)
+		expectAttemptCheck(mock, attemptRows(3, now.Add(-7*time.Second), nil), true, false)
+		Expect(guard.Check(userId)).To(Equal(ErrTooManyAttempts))
+
+		expectAttemptCheck(mock, attemptRows(3, now.Add(-8*time.Second), nil), true, true)
+		Expect(guard.Check(userId)).To(Succeed())
+	})
+
+	It("should register the attempt before it is verified", func() {
+		expectAttemptCheck(mock, attemptRows(0, now, nil), false, true)
+		Expect(guard.Check(userId)).To(Succeed())
+
+		// concurrent attempt sees the one which is being verified
+		expectAttemptCheck(mock, attemptRows(1, now, nil), true, false)
+		Expect(guard.Check(userId)).To(Equal(ErrTooManyAttempts))
+	})
+
+	It("should allow attempts if there are no failures", func() {
+		expectNoFailedAttempts(mock)
+
+		Expect(guard.Check(userId)).To(Succeed())
+	})
+
+	It("should reset failed attempts on success and unlock", func() {
+		expectExec(mock, "DELETE FROM `tan_failed_attempts`", userId)
+		Expect(guard.Succeed(userId)).To(Succeed())
+
+		expectExec(mock, "DELETE FROM `tan_failed_attempts`", userId)
+		Expect(guard.Unlock(userId)).To(Succeed())
+
+		expectNoFailedAttempts(mock)
+		Expect(guard.Check(userId)).To(Succeed())
+	})
+})
@@ -0,0 +1,13 @@
+package event
+
+const (
+	FailedAttempt = "tan:failed-attempt"
+)
+
+type ContextFailedAttempt struct {
+	UserID string
+	// FailedAttempts is number of consecutive failed attempts including the current one
+	FailedAttempts int64
+	// Locked is true if the user has been locked out by the current attempt
+	Locked bool
+}
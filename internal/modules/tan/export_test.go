@@ -0,0 +1,23 @@
+package tan
+
+import "time"
+
+// Digest returns hash of the transaction contents
+func Digest(tx *Transaction) string {
+	return tx.digest()
+}
+
+// SigningCode derives code from the challenge nonce and the transaction digest
+func SigningCode(nonce, digest string) string {
+	return signingCode(nonce, digest)
+}
+
+// SetSignerClock replaces clock of the signer
+func SetSignerClock(signer *Signer, now func() time.Time) {
+	signer.now = now
+}
+
+// SetAttemptGuardClock replaces clock of the attempt guard
+func SetAttemptGuardClock(guard *AttemptGuard, now func() time.Time) {
+	guard.now = now
+}
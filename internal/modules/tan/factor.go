@@ -0,0 +1,172 @@
+package tan
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+// Method is a second factor method name
+type Method string
+
+const (
+	// MethodTan is pre-generated list of TANs
+	MethodTan = Method("tan")
+	// MethodTotp is time-based one-time password (RFC 6238)
+	MethodTotp = Method("totp")
+	// MethodApproval is out-of-band approval of a challenge by another user
+	MethodApproval = Method("approval")
+)
+
+// Error defines string error
+type Error string
+
+// Error returns error message
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	ErrInvalid          = Error(errcodes.CodeTanInvalid)
+	ErrMethodNotAllowed = Error(errcodes.CodeTanMethodNotAllowed)
+	ErrUnknownMethod    = Error(errcodes.CodeTanUnknownMethod)
+)
+
+// SecondFactor verifies that an operation is confirmed by a user
+type SecondFactor interface {
+	// Method returns method name
+	Method() Method
+	// Use verifies the given code and makes it unusable for further operations
+	Use(userId, code string) bool
+}
+
+// tanFactor adapts TAN service to SecondFactor interface
+type tanFactor struct {
+	service *Service
+}
+
+func (t *tanFactor) Method() Method {
+	return MethodTan
+}
+
+func (t *tanFactor) Use(userId, code string) bool {
+	return t.service.Use(userId, code)
+}
+
+// Authenticator selects second factor method of a user and verifies passed code with it
+type Authenticator struct {
+	repository *FactorRepository
+	settings   *settings.Service
+	guard      *AttemptGuard
+	factors    map[Method]SecondFactor
+}
+
+func NewAuthenticator(
+	repository *FactorRepository,
+	settings *settings.Service,
+	guard *AttemptGuard,
+	tanService *Service,
+	totp *Totp,
+	approval *Approval,
+) *Authenticator {
+	a := &Authenticator{
+		repository: repository,
+		settings:   settings,
+		guard:      guard,
+		factors:    make(map[Method]SecondFactor),
+	}
+	a.Register(&tanFactor{service: tanService})
+	a.Register(totp)
+	a.Register(approval)
+	return a
+}
+
+// Register adds second factor method, existing method with the same name is replaced
+func (a *Authenticator) Register(factor SecondFactor) {
+	a.factors[factor.Method()] = factor
+}
+
+// IsKnown checks whether the given method is registered
+func (a *Authenticator) IsKnown(method Method) bool {
+	_, ok := a.factors[method]
+	return ok
+}
+
+// UserMethod returns method chosen by the user, TAN is used by default
+func (a *Authenticator) UserMethod(userId string) (Method, error) {
+	userMethod, err := a.repository.FindUserMethod(userId)
+	if err != nil {
+		return "", err
+	}
+	if userMethod == nil {
+		return MethodTan, nil
+	}
+	return Method(userMethod.Method), nil
+}
+
+// SetUserMethod changes method of the user
+func (a *Authenticator) SetUserMethod(userId string, method Method) error {
+	if !a.IsKnown(method) {
+		return ErrUnknownMethod
+	}
+	return a.repository.SaveUserMethod(userId, string(method))
+}
+
+// Use verifies the code using method chosen by the user.
+// requiredSetting is a setting which defines whether second factor is required e.g. "owt_tan_required",
+// it is used in order to find out the methods allowed for the transfer subject (see AllowedMethodsSettingName).
+// Failed attempts are counted by AttemptGuard, ErrLocked or ErrTooManyAttempts is returned while they are exceeded.
+func (a *Authenticator) Use(userId, code string, requiredSetting settings.Name) error {
+	method, err := a.UserMethod(userId)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve second factor method of user %s", userId)
+	}
+	if !a.isAllowed(method, requiredSetting) {
+		return errors.Wrapf(ErrMethodNotAllowed, "method %s is not allowed by %s", method, requiredSetting)
+	}
+	return a.use(userId, code, method)
+}
+
+// UseCurrent verifies the code using method chosen by the user regardless of the methods allowed for transfers,
+// it protects changes of the second factor itself so that a session could not replace it without the current one.
+func (a *Authenticator) UseCurrent(userId, code string) error {
+	method, err := a.UserMethod(userId)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve second factor method of user %s", userId)
+	}
+	return a.use(userId, code, method)
+}
+
+func (a *Authenticator) use(userId, code string, method Method) error {
+	factor, ok := a.factors[method]
+	if !ok {
+		return errors.Wrapf(ErrUnknownMethod, "method %s is not registered", method)
+	}
+	if err := a.guard.Check(userId); err != nil {
+		return err
+	}
+	if !factor.Use(userId, code) {
+		if err := a.guard.Fail(userId); err != nil {
+			return err
+		}
+		return ErrInvalid
+	}
+	return a.guard.Succeed(userId)
+}
+
+// isAllowed checks allowed methods setting, if the setting is not set or empty then any method is allowed
+func (a *Authenticator) isAllowed(method Method, requiredSetting settings.Name) bool {
+	allowed, err := a.settings.String(AllowedMethodsSettingName(requiredSetting))
+	if err != nil || strings.TrimSpace(allowed) == "" {
+		return true
+	}
+	for _, m := range strings.Split(allowed, ",") {
+		if Method(strings.TrimSpace(m)) == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package tan
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
+)
+
+// FactorRepository stores data of the second factor methods
+type FactorRepository struct {
+	db *gorm.DB
+}
+
+func NewFactorRepository(db *gorm.DB) *FactorRepository {
+	return &FactorRepository{db}
+}
+
+// FindUserMethod retrieves method chosen by the given user, nil is returned if the user has not chosen any
+func (r *FactorRepository) FindUserMethod(uid string) (*model.UserMethod, error) {
+	userMethod := &model.UserMethod{}
+	err := r.db.Where("uid = ?", uid).First(userMethod).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return userMethod, err
+}
+
+// SaveUserMethod creates or updates method of the given user
+func (r *FactorRepository) SaveUserMethod(uid, method string) error {
+	userMethod, err := r.FindUserMethod(uid)
+	if err != nil {
+		return err
+	}
+	if userMethod == nil {
+		userMethod = &model.UserMethod{UID: uid}
+	}
+	userMethod.Method = method
+	return r.db.Save(userMethod).Error
+}
+
+// FindTotpSecret retrieves TOTP secret of the given user, nil is returned if the user has no secret
+func (r *FactorRepository) FindTotpSecret(uid string) (*model.TotpSecret, error) {
+	secret := &model.TotpSecret{}
+	err := r.db.Where("uid = ?", uid).First(secret).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return secret, err
+}
+
+// SavePendingTotpSecret stores enrolled secret of the given user, the current secret is kept until it is confirmed
+func (r *FactorRepository) SavePendingTotpSecret(uid, pending string) error {
+	secret, err := r.FindTotpSecret(uid)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		secret = &model.TotpSecret{UID: uid}
+	}
+	secret.PendingSecret = pending
+	return r.db.Save(secret).Error
+}
+
+// ConfirmTotpSecret replaces the current secret with the pending one,
+// step is the time step of the confirmation code which could not be used again
+func (r *FactorRepository) ConfirmTotpSecret(secret *model.TotpSecret, step int64) error {
+	return r.db.
+		Model(&model.TotpSecret{}).
+		Where("id = ? AND pending_secret = ?", secret.ID, secret.PendingSecret).
+		Updates(map[string]interface{}{
+			"secret":         secret.PendingSecret,
+			"pending_secret": "",
+			"confirmed":      true,
+			"last_used_step": step,
+		}).Error
+}
+
+// UseTotpStep marks the given time step as used, it returns false if the same or later step has been already used
+func (r *FactorRepository) UseTotpStep(secret *model.TotpSecret, step int64) (bool, error) {
+	result := r.db.
+		Model(&model.TotpSecret{}).
+		Where("id = ? AND last_used_step < ?", secret.ID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *FactorRepository) DeleteTotpSecret(uid string) error {
+	return r.db.Delete(&model.TotpSecret{}, "uid = ?", uid).Error
+}
+
+func (r *FactorRepository) CreateApproval(challenge *model.ApprovalChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// FindApproval retrieves not expired approval challenge by its id and user id
+func (r *FactorRepository) FindApproval(id int64, uid string, now time.Time) (*model.ApprovalChallenge, error) {
+	challenge := &model.ApprovalChallenge{}
+	err := r.db.Where("id = ? AND uid = ? AND expires_at > ?", id, uid, now).First(challenge).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return challenge, err
+}
+
+// FindApprovalById retrieves not expired approval challenge of any user by its id
+func (r *FactorRepository) FindApprovalById(id int64, now time.Time) (*model.ApprovalChallenge, error) {
+	challenge := &model.ApprovalChallenge{}
+	err := r.db.Where("id = ? AND expires_at > ?", id, now).First(challenge).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return challenge, err
+}
+
+// FindPendingApprovals retrieves not expired pending approval challenges of the user starting from the oldest one
+func (r *FactorRepository) FindPendingApprovals(uid string, now time.Time) ([]*model.ApprovalChallenge, error) {
+	challenges := make([]*model.ApprovalChallenge, 0)
+	err := r.db.
+		Where("uid = ? AND status = ? AND expires_at > ?", uid, model.ApprovalStatusPending, now).
+		Order("id").
+		Find(&challenges).Error
+	return challenges, err
+}
+
+// FindApprovalByToken retrieves not expired approval challenge by its token and user id
+func (r *FactorRepository) FindApprovalByToken(token, uid string, now time.Time) (*model.ApprovalChallenge, error) {
+	challenge := &model.ApprovalChallenge{}
+	err := r.db.Where("token = ? AND uid = ? AND expires_at > ?", token, uid, now).First(challenge).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return challenge, err
+}
+
+// UpdateApprovalStatus changes challenge status only if it is in the expected status,
+// it returns false if the status has been already changed by someone else
+func (r *FactorRepository) UpdateApprovalStatus(challenge *model.ApprovalChallenge, from, to string) (bool, error) {
+	result := r.db.
+		Model(&model.ApprovalChallenge{}).
+		Where("id = ? AND status = ?", challenge.ID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	challenge.Status = to
+	return true, nil
+}
+
+func (r *FactorRepository) CreateSigningChallenge(challenge *model.SigningChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// FindSigningChallenge retrieves not used and not expired signing challenge by its id and user id
+func (r *FactorRepository) FindSigningChallenge(id int64, uid string, now time.Time) (*model.SigningChallenge, error) {
+	challenge := &model.SigningChallenge{}
+	err := r.db.
+		Where("id = ? AND uid = ? AND used_at IS NULL AND expires_at > ?", id, uid, now).
+		First(challenge).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	return challenge, err
+}
+
+// UseSigningChallenge marks the challenge as used, it returns false if the challenge has been already used
+func (r *FactorRepository) UseSigningChallenge(challenge *model.SigningChallenge, now time.Time) (bool, error) {
+	result := r.db.
+		Model(&model.SigningChallenge{}).
+		Where("id = ? AND used_at IS NULL", challenge.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	challenge.UsedAt = &now
+	return true, nil
+}
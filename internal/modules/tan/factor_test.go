@@ -0,0 +1,210 @@
+package tan_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base32"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/Confialink/wallet-users/rpc/proto/users"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"github.com/olebedev/emitter"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/tan"
+)
+
+const userId = "user-1"
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// currentTotpCode generates code of the current time step for the given secret
+func currentTotpCode(secret string) string {
+	key, err := totpEncoding.DecodeString(secret)
+	Expect(err).ShouldNot(HaveOccurred())
+	return TotpCode(key, time.Now().Unix()/30)
+}
+
+func newMockDB() (*gorm.DB, sqlmock.Sqlmock) {
+	var db *sql.DB
+	db, mock, err := sqlmock.New()
+	Expect(err).ShouldNot(HaveOccurred())
+	gdb, err := gorm.Open("mysql", db)
+	Expect(err).ShouldNot(HaveOccurred())
+	return gdb, mock
+}
+
+func expectNoSetting(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT \\* FROM `settings`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func expectNoFailedAttempts(mock sqlmock.Sqlmock) {
+	expectAttemptCheck(mock, sqlmock.NewRows([]string{"id", "uid", "count"}).AddRow(1, userId, 0), false, true)
+}
+
+func expectUserMethod(mock sqlmock.Sqlmock, method Method) {
+	mock.ExpectQuery("SELECT \\* FROM `tan_user_methods`").
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uid", "method"}).AddRow(1, userId, string(method)))
+}
+
+func expectTotpSecret(mock sqlmock.Sqlmock, secret string, confirmed bool, pending string) {
+	mock.ExpectQuery("SELECT \\* FROM `tan_totp_secrets`").
+		WithArgs(userId).
+		WillReturnRows(sqlmock.
+			NewRows([]string{"id", "uid", "secret", "confirmed", "pending_secret", "last_used_step"}).
+			AddRow(1, userId, secret, confirmed, pending, 0))
+}
+
+func expectExec(mock sqlmock.Sqlmock, query string, args ...driver.Value) {
+	mock.ExpectBegin()
+	exec := mock.ExpectExec(query)
+	if len(args) > 0 {
+		exec.WithArgs(args...)
+	}
+	exec.WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+}
+
+var _ = Describe("Second factor", func() {
+	var (
+		gdb           *gorm.DB
+		mock          sqlmock.Sqlmock
+		repository    *FactorRepository
+		totp          *Totp
+		authenticator *Authenticator
+	)
+	any := sqlmock.AnyArg()
+	currentSecret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	pendingSecret := totpEncoding.EncodeToString([]byte("09876543210987654321"))
+
+	BeforeEach(func() {
+		gdb, mock = newMockDB()
+		settingsService := settings.NewService(settingsRepository.NewSettings(gdb))
+		repository = NewFactorRepository(gdb)
+		totp = NewTotp(repository, settingsService)
+		guard := NewAttemptGuard(NewAttemptsRepository(gdb), settingsService, emitter.New(10))
+		authenticator = NewAuthenticator(
+			repository,
+			settingsService,
+			guard,
+			NewService(NewRepository(gdb), NewBcryptHasherVerifier(), NewSubscriberRepository(gdb)),
+			totp,
+			NewApproval(repository, settingsService),
+		)
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	Context("changes of the second factor", func() {
+		var router *gin.Engine
+		var changed bool
+
+		BeforeEach(func() {
+			gin.SetMode(gin.TestMode)
+			changed = false
+			router = gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("_user", &users.User{UID: userId})
+			})
+			router.PUT("/method", MiddlewareUseCurrent(authenticator, service.NewContext()), func(c *gin.Context) {
+				changed = true
+				c.Status(http.StatusNoContent)
+			})
+		})
+
+		changeMethod := func(code string) {
+			req := httptest.NewRequest(http.MethodPut, "/method", nil)
+			if code != "" {
+				req.Header.Set("X-TAN", code)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		It("should require a code", func() {
+			changeMethod("")
+			Expect(changed).To(BeFalse())
+		})
+
+		It("should accept a code of the current method", func() {
+			expectUserMethod(mock, MethodTotp)
+			expectNoFailedAttempts(mock)
+			expectTotpSecret(mock, currentSecret, true, "")
+			expectExec(mock, "UPDATE `tan_totp_secrets` SET `last_used_step`")
+			expectExec(mock, "DELETE FROM `tan_failed_attempts`")
+
+			changeMethod(currentTotpCode(currentSecret))
+			Expect(changed).To(BeTrue())
+		})
+
+		It("should not accept a code of a not confirmed secret", func() {
+			expectUserMethod(mock, MethodTotp)
+			expectNoFailedAttempts(mock)
+			expectTotpSecret(mock, currentSecret, true, pendingSecret)
+			// failed attempt is registered
+			expectNoSetting(mock)
+			expectFailedAttempt(mock, 1, false)
+
+			changeMethod(currentTotpCode(pendingSecret))
+			Expect(changed).To(BeFalse())
+		})
+
+		It("should not accept a code of other methods", func() {
+			expectUserMethod(mock, MethodApproval)
+			expectNoFailedAttempts(mock)
+			mock.ExpectQuery("SELECT \\* FROM `tan_approval_challenges`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			expectNoSetting(mock)
+			expectFailedAttempt(mock, 1, false)
+
+			changeMethod(currentTotpCode(currentSecret))
+			Expect(changed).To(BeFalse())
+		})
+	})
+
+	Context("TOTP enrolment", func() {
+		It("should keep the current secret until the new one is confirmed", func() {
+			expectTotpSecret(mock, currentSecret, true, "")
+			expectExec(mock, "UPDATE `tan_totp_secrets`", userId, currentSecret, true, any, any, 1)
+			expectNoSetting(mock)
+
+			enrolment, err := totp.Enrol(userId, "john")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(enrolment.Secret).NotTo(Equal(currentSecret))
+			Expect(enrolment.URI).To(HavePrefix("otpauth://totp/Wallet:john?"))
+
+			// the current secret is still accepted
+			expectTotpSecret(mock, currentSecret, true, enrolment.Secret)
+			expectExec(mock, "UPDATE `tan_totp_secrets` SET `last_used_step`")
+			Expect(totp.Use(userId, currentTotpCode(currentSecret))).To(BeTrue())
+		})
+
+		It("should confirm the pending secret with its code", func() {
+			expectTotpSecret(mock, currentSecret, true, pendingSecret)
+			expectExec(mock, "UPDATE `tan_totp_secrets`", true, any, "", pendingSecret, 1, pendingSecret)
+
+			Expect(totp.Confirm(userId, currentTotpCode(pendingSecret))).To(BeTrue())
+		})
+
+		It("should not confirm the pending secret with a code of the current one", func() {
+			expectTotpSecret(mock, currentSecret, true, pendingSecret)
+
+			Expect(totp.Confirm(userId, currentTotpCode(currentSecret))).To(BeFalse())
+		})
+
+		It("should not confirm if nothing is enrolled", func() {
+			expectTotpSecret(mock, currentSecret, true, "")
+
+			Expect(totp.Confirm(userId, currentTotpCode(currentSecret))).To(BeFalse())
+		})
+	})
+})
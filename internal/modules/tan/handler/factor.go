@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Confialink/wallet-pkg-errors"
+	"github.com/gin-gonic/gin"
+	"github.com/inconshreveable/log15"
+	pkgErrors "github.com/pkg/errors"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
+	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
+	"github.com/Confialink/wallet-accounts/internal/modules/notifications"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan"
+)
+
+type methodForm struct {
+	Method string `json:"method" binding:"required"`
+}
+
+type codeForm struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type methodResponse struct {
+	Method       tan.Method `json:"method"`
+	TotpEnrolled bool       `json:"totpEnrolled"`
+}
+
+type approvalResponse struct {
+	Id        int64     `json:"id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type pendingApprovalResponse struct {
+	Id        int64     `json:"id"`
+	UserId    string    `json:"userId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// FactorController manages second factor methods of the current user and approval challenges
+type FactorController struct {
+	authenticator       *tan.Authenticator
+	totp                *tan.Totp
+	approval            *tan.Approval
+	contextService      appHttpService.ContextInterface
+	notificationService *notifications.Service
+	logger              log15.Logger
+}
+
+func NewFactorController(
+	authenticator *tan.Authenticator,
+	totp *tan.Totp,
+	approval *tan.Approval,
+	contextService appHttpService.ContextInterface,
+	notificationService *notifications.Service,
+	logger log15.Logger,
+) *FactorController {
+	return &FactorController{
+		authenticator:       authenticator,
+		totp:                totp,
+		approval:            approval,
+		contextService:      contextService,
+		notificationService: notificationService,
+		logger:              logger.New("Handler", "FactorController"),
+	}
+}
+
+// GetOwnMethod returns second factor method of the current user
+func (f *FactorController) GetOwnMethod(ctx *gin.Context) {
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	method, err := f.authenticator.UserMethod(user.UID)
+	if err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+	enrolled, err := f.totp.IsEnrolled(user.UID)
+	if err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.New().SetData(&methodResponse{Method: method, TotpEnrolled: enrolled}))
+}
+
+// UpdateOwnMethod changes second factor method of the current user
+func (f *FactorController) UpdateOwnMethod(ctx *gin.Context) {
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	form := &methodForm{}
+	if err := ctx.ShouldBindJSON(form); err != nil {
+		errors.AddShouldBindError(ctx, err)
+		return
+	}
+
+	method := tan.Method(form.Method)
+	if method == tan.MethodTotp {
+		enrolled, err := f.totp.IsEnrolled(user.UID)
+		if err != nil {
+			errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+			return
+		}
+		if !enrolled {
+			errcodes.AddError(ctx, errcodes.CodeTotpNotEnrolled)
+			return
+		}
+	}
+
+	if err := f.authenticator.SetUserMethod(user.UID, method); err != nil {
+		errors.AddErrors(ctx, errcodes.ConvertToTyped(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.New().SetData(&methodResponse{Method: method, TotpEnrolled: method == tan.MethodTotp}))
+}
+
+// EnrolTotp generates new TOTP secret for the current user
+func (f *FactorController) EnrolTotp(ctx *gin.Context) {
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	enrolment, err := f.totp.Enrol(user.UID, user.Username)
+	if err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.New().SetData(enrolment))
+}
+
+// ConfirmTotp activates TOTP secret of the current user
+func (f *FactorController) ConfirmTotp(ctx *gin.Context) {
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	form := &codeForm{}
+	if err := ctx.ShouldBindJSON(form); err != nil {
+		errors.AddShouldBindError(ctx, err)
+		return
+	}
+
+	if !f.totp.Confirm(user.UID, form.Code) {
+		errcodes.AddError(ctx, errcodes.CodeTanInvalid)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DisableTotp removes TOTP secret of the current user, TAN method is used afterwards
+func (f *FactorController) DisableTotp(ctx *gin.Context) {
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	if err := f.totp.Disable(user.UID); err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+	method, err := f.authenticator.UserMethod(user.UID)
+	if err == nil && method == tan.MethodTotp {
+		err = f.authenticator.SetUserMethod(user.UID, tan.MethodTan)
+	}
+	if err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RequestApproval creates approval challenge and notifies the current user
+func (f *FactorController) RequestApproval(ctx *gin.Context) {
+	logger := f.logger.New("method", "RequestApproval")
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	challenge, err := f.approval.Request(user.UID)
+	if err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	if err := f.notificationService.TriggerTanApprovalRequested(user.UID, challenge.ID); err != nil {
+		logger.Error("failed to send approval notification", "error", err)
+	}
+
+	ctx.JSON(http.StatusCreated, response.New().SetData(&approvalResponse{
+		Id:        challenge.ID,
+		Token:     challenge.Token,
+		ExpiresAt: challenge.ExpiresAt,
+	}))
+}
+
+// CancelApproval rejects challenge requested by the current user
+func (f *FactorController) CancelApproval(ctx *gin.Context) {
+	f.resolveApproval(ctx, f.approval.Cancel)
+}
+
+// ListApprovals returns pending challenges of the given user, tokens of challenges are not exposed
+func (f *FactorController) ListApprovals(ctx *gin.Context) {
+	challenges, err := f.approval.Pending(ctx.Param("userId"))
+	if err != nil {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	items := make([]*pendingApprovalResponse, 0, len(challenges))
+	for _, challenge := range challenges {
+		items = append(items, &pendingApprovalResponse{
+			Id:        challenge.ID,
+			UserId:    challenge.UID,
+			ExpiresAt: challenge.ExpiresAt,
+			CreatedAt: challenge.CreatedAt,
+		})
+	}
+	ctx.JSON(http.StatusOK, response.New().SetData(items))
+}
+
+// Approve approves challenge of another user on behalf of the current user
+func (f *FactorController) Approve(ctx *gin.Context) {
+	f.resolveApproval(ctx, f.approval.Approve)
+}
+
+// Reject rejects challenge of another user on behalf of the current user
+func (f *FactorController) Reject(ctx *gin.Context) {
+	f.resolveApproval(ctx, func(_ string, id int64) error {
+		return f.approval.Reject(id)
+	})
+}
+
+func (f *FactorController) resolveApproval(ctx *gin.Context, resolve func(userId string, id int64) error) {
+	user := f.contextService.MustGetCurrentUser(ctx)
+
+	id, typedErr := f.contextService.GetIdParam(ctx)
+	if typedErr != nil {
+		errors.AddErrors(ctx, typedErr)
+		return
+	}
+
+	if err := resolve(user.UID, int64(id)); err != nil {
+		switch cause := pkgErrors.Cause(err); cause {
+		case tan.ErrApprovalNotFound, tan.ErrApprovalSelf:
+			errcodes.AddError(ctx, cause.Error())
+			return
+		}
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -15,11 +15,13 @@ import (
 	"github.com/Confialink/wallet-accounts/internal/modules/app/http/response"
 	appHttpService "github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
 	"github.com/Confialink/wallet-accounts/internal/modules/tan"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
 )
 
 type Controller struct {
 	service             *tan.Service
 	watcher             *tan.Watcher
+	guard               *tan.AttemptGuard
 	contextService      appHttpService.ContextInterface
 	notificationService *notifications.Service
 	logger              log15.Logger
@@ -28,6 +30,7 @@ type Controller struct {
 func NewController(
 	service *tan.Service,
 	watcher *tan.Watcher,
+	guard *tan.AttemptGuard,
 	contextService appHttpService.ContextInterface,
 	notificationService *notifications.Service,
 	logger log15.Logger,
@@ -35,6 +38,7 @@ func NewController(
 	return &Controller{
 		service:             service,
 		watcher:             watcher,
+		guard:               guard,
 		contextService:      contextService,
 		notificationService: notificationService,
 		logger:              logger,
@@ -89,6 +93,32 @@ func (c *Controller) Create(ctx *gin.Context) {
 	ctx.Status(http.StatusCreated)
 }
 
+// GetAttempts returns failed confirmation attempts of the user
+func (c *Controller) GetAttempts(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	attempts, err := c.guard.Status(userId)
+	if nil != err {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+	if attempts == nil {
+		attempts = &model.FailedAttempts{UID: userId}
+	}
+
+	ctx.JSON(http.StatusOK, response.New().SetData(attempts))
+}
+
+// Unlock removes lockout caused by failed confirmation attempts of the user
+func (c *Controller) Unlock(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	if err := c.guard.Unlock(userId); nil != err {
+		errors.AddErrors(ctx, &errors.PrivateError{Message: err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // UserRequestOne is used in order to provide user with ability to request TAN on demand ("Request TAN" button)
 func (c *Controller) UserRequestOne(ctx *gin.Context) {
 	user := c.contextService.MustGetCurrentUser(ctx)
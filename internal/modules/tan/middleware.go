@@ -1,15 +1,18 @@
 package tan
 
 import (
-	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
 	"github.com/Confialink/wallet-accounts/internal/modules/app/http/service"
 	"github.com/Confialink/wallet-accounts/internal/modules/settings"
-	"github.com/gin-gonic/gin"
 )
 
+// MiddlewareUseIfRequired verifies second factor code passed in X-TAN header if the given setting is enabled.
+// The code is verified with the method chosen by the user (TAN, TOTP or approval).
 func MiddlewareUseIfRequired(
-	tanService *Service,
+	authenticator *Authenticator,
 	contextService service.ContextInterface,
 	service *settings.Service,
 	settingName settings.Name,
@@ -29,8 +32,8 @@ func MiddlewareUseIfRequired(
 			}
 
 			user := contextService.MustGetCurrentUser(c)
-			if !tanService.Use(user.UID, tan) {
-				errcodes.AddError(c, errcodes.CodeTanInvalid)
+			if err := authenticator.Use(user.UID, tan, settingName); err != nil {
+				addUseError(c, err)
 				c.Abort()
 				return
 			}
@@ -38,6 +41,36 @@ func MiddlewareUseIfRequired(
 	}
 }
 
+// MiddlewareUseCurrent always requires second factor code passed in X-TAN header,
+// the code is verified with the method currently chosen by the user.
+// It protects changes of the second factor method and of its secrets.
+func MiddlewareUseCurrent(authenticator *Authenticator, contextService service.ContextInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tan := retrieveTanHeader(c)
+		if tan == "" {
+			errcodes.AddError(c, errcodes.CodeTanEmpty)
+			c.Abort()
+			return
+		}
+
+		user := contextService.MustGetCurrentUser(c)
+		if err := authenticator.UseCurrent(user.UID, tan); err != nil {
+			addUseError(c, err)
+			c.Abort()
+			return
+		}
+	}
+}
+
+func addUseError(c *gin.Context, err error) {
+	code := errcodes.CodeTanInvalid
+	switch cause := errors.Cause(err); cause {
+	case ErrMethodNotAllowed, ErrUnknownMethod, ErrLocked, ErrTooManyAttempts:
+		code = cause.Error()
+	}
+	errcodes.AddError(c, code)
+}
+
 func retrieveTanHeader(c *gin.Context) string {
 	return c.Request.Header.Get("X-TAN")
 }
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// FailedAttempts keeps consecutive failed second factor attempts of a user (uid is unique),
+// the record is removed on successful attempt or when the user is unlocked by an administrator
+type FailedAttempts struct {
+	ID           int64      `json:"-"`
+	UID          string     `json:"userId"`
+	Count        int64      `json:"count"`
+	LastFailedAt time.Time  `json:"lastFailedAt"`
+	LockedAt     *time.Time `json:"lockedAt"`
+}
+
+func (*FailedAttempts) TableName() string {
+	return "tan_failed_attempts"
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// UserMethod keeps second factor method chosen by a user
+type UserMethod struct {
+	ID     int64
+	UID    string
+	Method string
+}
+
+// TotpSecret is a shared secret used in order to generate time-based one-time passwords (RFC 6238)
+type TotpSecret struct {
+	ID        int64
+	UID       string
+	Secret    string
+	Confirmed bool
+	// PendingSecret is an enrolled secret which replaces Secret once it is confirmed with a valid code
+	PendingSecret string
+	// LastUsedStep is the time step of the last accepted code, it is used in order to prevent code reuse
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusUsed     = "used"
+)
+
+// ApprovalChallenge is an out-of-band confirmation request of the user which must be approved by another user
+type ApprovalChallenge struct {
+	ID        int64
+	UID       string
+	Token     string
+	Status    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (*UserMethod) TableName() string {
+	return "tan_user_methods"
+}
+
+func (*TotpSecret) TableName() string {
+	return "tan_totp_secrets"
+}
+
+func (*ApprovalChallenge) TableName() string {
+	return "tan_approval_challenges"
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// SigningChallenge binds a confirmation code to the contents of a transfer (dynamic linking).
+// Digest is a hash of transfer subject, amount, currency and destination, Nonce is a per-challenge
+// secret which is never returned to a client, the code is derived from both of them.
+type SigningChallenge struct {
+	ID        int64
+	UID       string
+	Subject   string
+	Digest    string
+	Nonce     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (*SigningChallenge) TableName() string {
+	return "tan_signing_challenges"
+}
@@ -1,10 +1,29 @@
 package tan
 
-import "github.com/Confialink/wallet-accounts/internal/modules/settings"
+import (
+	"strings"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
 
 const (
 	SettingTanGenerateQtyInt64        = settings.Name("tan_generate_qty")
 	SettingTanGenerateTriggerQtyInt64 = settings.Name("tan_generate_trigger_qty")
 	SettingTanMessageSubjectString    = settings.Name("tan_message_subject")
 	SettingTanMessageContentString    = settings.Name("tan_message_content")
+	SettingTanTotpIssuerString        = settings.Name("tan_totp_issuer")
+	SettingTanApprovalTtlSecondsInt64 = settings.Name("tan_approval_ttl_seconds")
+	// SettingTanMaxFailedAttemptsInt64 is number of consecutive failures after which the user is locked out, 0 disables lockout
+	SettingTanMaxFailedAttemptsInt64 = settings.Name("tan_max_failed_attempts")
+	// SettingTanFailedAttemptDelaySecondsInt64 is initial delay after a failed attempt, it doubles with every failure
+	SettingTanFailedAttemptDelaySecondsInt64 = settings.Name("tan_failed_attempt_delay_seconds")
+
+	SettingTransferSigningRequiredBool    = settings.Name("transfer_signing_required")
+	SettingTransferSigningTtlSecondsInt64 = settings.Name("transfer_signing_ttl_seconds")
 )
+
+// AllowedMethodsSettingName returns name of the setting that contains comma separated list
+// of second factor methods allowed for a transfer subject, e.g. "owt_tan_required" => "owt_tan_methods"
+func AllowedMethodsSettingName(requiredSetting settings.Name) settings.Name {
+	return settings.Name(strings.TrimSuffix(requiredSetting.String(), "_required") + "_methods")
+}
@@ -0,0 +1,163 @@
+package tan
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/errcodes"
+	"github.com/Confialink/wallet-accounts/internal/modules/notifications"
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	"github.com/Confialink/wallet-accounts/internal/modules/tan/model"
+)
+
+const (
+	signingNonceSize         = 32
+	signingCodeDigits        = 8
+	defaultSigningTtlSeconds = 300
+)
+
+const (
+	ErrSignatureRequired = Error(errcodes.CodeTransferSignatureRequired)
+	ErrSignatureInvalid  = Error(errcodes.CodeTransferSignatureInvalid)
+)
+
+// Transaction describes what is being authorised by a user
+type Transaction struct {
+	// Subject is a transfer type e.g. "owt"
+	Subject string
+	Amount  decimal.Decimal
+	// Currency is a currency code of the amount
+	Currency string
+	// Destination identifies the beneficiary e.g. account number, card id or IBAN
+	Destination string
+}
+
+// digest returns hash of the transaction contents, amounts which differ only in trailing zeros are equal
+func (t *Transaction) digest() string {
+	contents := strings.Join([]string{
+		t.Subject,
+		t.Amount.String(),
+		strings.ToUpper(t.Currency),
+		t.Destination,
+	}, "|")
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
+
+// Signer implements dynamic linking of a confirmation code to a transfer:
+// a challenge is issued on preview and the code derived from it is sent to the user together
+// with the transfer destination, then the code is accepted only for the same amount, currency and destination.
+type Signer struct {
+	repository          *FactorRepository
+	settings            *settings.Service
+	notificationService *notifications.Service
+	logger              log15.Logger
+	now                 func() time.Time
+}
+
+func NewSigner(
+	repository *FactorRepository,
+	settings *settings.Service,
+	notificationService *notifications.Service,
+	logger log15.Logger,
+) *Signer {
+	return &Signer{
+		repository:          repository,
+		settings:            settings,
+		notificationService: notificationService,
+		logger:              logger.New("Service", "Signer"),
+		now:                 time.Now,
+	}
+}
+
+// IsRequired checks whether transfers must be signed
+func (s *Signer) IsRequired() (bool, error) {
+	return s.settings.Bool(SettingTransferSigningRequiredBool)
+}
+
+// Challenge issues new challenge bound to the transaction and sends derived code to the user
+func (s *Signer) Challenge(userId string, tx *Transaction) (*model.SigningChallenge, error) {
+	logger := s.logger.New("method", "Challenge")
+
+	random, err := generateRandomBytes(signingNonceSize)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := s.settings.Int64(SettingTransferSigningTtlSecondsInt64)
+	if err != nil || ttl <= 0 {
+		ttl = defaultSigningTtlSeconds
+	}
+	now := s.now()
+	challenge := &model.SigningChallenge{
+		UID:       userId,
+		Subject:   tx.Subject,
+		Digest:    tx.digest(),
+		Nonce:     hex.EncodeToString(random),
+		ExpiresAt: now.Add(time.Duration(ttl) * time.Second),
+		CreatedAt: now,
+	}
+	if err := s.repository.CreateSigningChallenge(challenge); err != nil {
+		return nil, errors.Wrap(err, "failed to create signing challenge")
+	}
+
+	code := signingCode(challenge.Nonce, challenge.Digest)
+	err = s.notificationService.TriggerTransferSigningChallenge(userId, challenge.ID, code, tx.Destination)
+	if err != nil {
+		logger.Error("failed to send signing code", "error", err, "challengeId", challenge.ID)
+	}
+
+	return challenge, nil
+}
+
+// Verify checks that the code has been derived from the challenge issued for the same transaction.
+// The challenge is used on the first attempt regardless of result so the code can not be guessed.
+func (s *Signer) Verify(userId string, challengeId int64, code string, tx *Transaction) error {
+	now := s.now()
+	challenge, err := s.repository.FindSigningChallenge(challengeId, userId, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve signing challenge")
+	}
+	if challenge == nil || challenge.Subject != tx.Subject || !now.Before(challenge.ExpiresAt) {
+		return ErrSignatureInvalid
+	}
+
+	ok, err := s.repository.UseSigningChallenge(challenge, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to use signing challenge")
+	}
+	if !ok {
+		return ErrSignatureInvalid
+	}
+
+	expected := signingCode(challenge.Nonce, tx.digest())
+	if !hmac.Equal([]byte(expected), []byte(code)) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// signingCode derives numeric code from the challenge nonce and the transaction digest
+func signingCode(nonce, digest string) string {
+	mac := hmac.New(sha256.New, []byte(nonce))
+	mac.Write([]byte(digest))
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, see RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < signingCodeDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", signingCodeDigits, value%mod)
+}
@@ -0,0 +1,155 @@
+package tan_test
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inconshreveable/log15"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/shopspring/decimal"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+	settingsRepository "github.com/Confialink/wallet-accounts/internal/modules/settings/repository"
+	. "github.com/Confialink/wallet-accounts/internal/modules/tan"
+)
+
+// timeArg matches time argument of a query
+type timeArg time.Time
+
+func (t timeArg) Match(v driver.Value) bool {
+	actual, ok := v.(time.Time)
+	return ok && actual.Equal(time.Time(t))
+}
+
+var _ = Describe("Signer", func() {
+	var (
+		mock   sqlmock.Sqlmock
+		signer *Signer
+		now    time.Time
+	)
+	const nonce = "0123456789abcdef"
+
+	transaction := func() *Transaction {
+		return &Transaction{
+			Subject:     "owt",
+			Amount:      decimal.RequireFromString("100.50"),
+			Currency:    "EUR",
+			Destination: "DE89370400440532013000",
+		}
+	}
+
+	BeforeEach(func() {
+		gdb, m := newMockDB()
+		mock = m
+		now = time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+		signer = NewSigner(
+			NewFactorRepository(gdb),
+			settings.NewService(settingsRepository.NewSettings(gdb)),
+			nil,
+			log15.New(),
+		)
+		SetSignerClock(signer, func() time.Time { return now })
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	expectChallenge := func(digest string) {
+		mock.ExpectQuery("SELECT \\* FROM `tan_signing_challenges`").
+			WithArgs(7, userId, timeArg(now)).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "uid", "subject", "digest", "nonce", "expires_at"}).
+				AddRow(7, userId, "owt", digest, nonce, now.Add(time.Minute)))
+		expectExec(mock, "UPDATE `tan_signing_challenges` SET `used_at`", timeArg(now), 7)
+	}
+
+	Context("code", func() {
+		It("should not depend on trailing zeros of the amount and currency case", func() {
+			same := transaction()
+			same.Amount = decimal.RequireFromString("100.500")
+			same.Currency = "eur"
+			Expect(Digest(same)).To(Equal(Digest(transaction())))
+			Expect(SigningCode(nonce, Digest(same))).To(Equal(SigningCode(nonce, Digest(transaction()))))
+		})
+
+		It("should be different for different transactions", func() {
+			code := SigningCode(nonce, Digest(transaction()))
+			Expect(code).To(HaveLen(8))
+
+			changes := []func(tx *Transaction){
+				func(tx *Transaction) { tx.Amount = decimal.RequireFromString("100.51") },
+				func(tx *Transaction) { tx.Currency = "USD" },
+				func(tx *Transaction) { tx.Destination = "DE89370400440532013001" },
+				func(tx *Transaction) { tx.Subject = "tba" },
+			}
+			for _, change := range changes {
+				changed := transaction()
+				change(changed)
+				Expect(SigningCode(nonce, Digest(changed))).NotTo(Equal(code))
+			}
+			Expect(SigningCode("another nonce", Digest(transaction()))).NotTo(Equal(code))
+		})
+	})
+
+	Context("verification", func() {
+		It("should accept the code for the same amount, currency and destination", func() {
+			digest := Digest(transaction())
+			expectChallenge(digest)
+
+			Expect(signer.Verify(userId, 7, SigningCode(nonce, digest), transaction())).To(Succeed())
+		})
+
+		It("should reject the code if the amount is changed", func() {
+			digest := Digest(transaction())
+			expectChallenge(digest)
+
+			changed := transaction()
+			changed.Amount = decimal.RequireFromString("1000.50")
+			Expect(signer.Verify(userId, 7, SigningCode(nonce, digest), changed)).To(Equal(ErrSignatureInvalid))
+		})
+
+		It("should reject the code if the currency is changed", func() {
+			digest := Digest(transaction())
+			expectChallenge(digest)
+
+			changed := transaction()
+			changed.Currency = "USD"
+			Expect(signer.Verify(userId, 7, SigningCode(nonce, digest), changed)).To(Equal(ErrSignatureInvalid))
+		})
+
+		It("should reject the code if the destination is changed", func() {
+			digest := Digest(transaction())
+			expectChallenge(digest)
+
+			changed := transaction()
+			changed.Destination = "GB29NWBK60161331926819"
+			Expect(signer.Verify(userId, 7, SigningCode(nonce, digest), changed)).To(Equal(ErrSignatureInvalid))
+		})
+
+		It("should reject the code of expired challenge", func() {
+			digest := Digest(transaction())
+			mock.ExpectQuery("SELECT \\* FROM `tan_signing_challenges`").
+				WithArgs(7, userId, timeArg(now)).
+				WillReturnRows(sqlmock.
+					NewRows([]string{"id", "uid", "subject", "digest", "nonce", "expires_at"}).
+					AddRow(7, userId, "owt", digest, nonce, now))
+
+			Expect(signer.Verify(userId, 7, SigningCode(nonce, digest), transaction())).To(Equal(ErrSignatureInvalid))
+		})
+
+		It("should reject the code of already used challenge", func() {
+			digest := Digest(transaction())
+			mock.ExpectQuery("SELECT \\* FROM `tan_signing_challenges`").
+				WillReturnRows(sqlmock.
+					NewRows([]string{"id", "uid", "subject", "digest", "nonce", "expires_at"}).
+					AddRow(7, userId, "owt", digest, nonce, now.Add(time.Minute)))
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE `tan_signing_challenges` SET `used_at`").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectCommit()
+
+			Expect(signer.Verify(userId, 7, SigningCode(nonce, digest), transaction())).To(Equal(ErrSignatureInvalid))
+		})
+	})
+})
@@ -12,7 +12,15 @@ func Providers() []interface{} {
 		tan.NewService,
 		tan.NewWatcher,
 		tan.NewBcryptHasherVerifier,
+		tan.NewFactorRepository,
+		tan.NewTotp,
+		tan.NewApproval,
+		tan.NewAttemptsRepository,
+		tan.NewAttemptGuard,
+		tan.NewAuthenticator,
+		tan.NewSigner,
 
 		handler.NewController,
+		handler.NewFactorController,
 	}
 }
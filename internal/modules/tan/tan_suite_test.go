@@ -0,0 +1,13 @@
+package tan_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTan(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tan Suite")
+}
@@ -0,0 +1,173 @@
+package tan
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/Confialink/wallet-accounts/internal/modules/settings"
+)
+
+const (
+	totpSecretSize = 20 // 160 bits as recommended by RFC 4226
+	totpStep       = 30 * time.Second
+	totpDigits     = 6
+	// totpSkew is number of steps before and after the current one which codes are accepted
+	// in order to tolerate clock drift between server and user device
+	totpSkew = 1
+
+	defaultTotpIssuer = "Wallet"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TotpEnrolment contains data needed in order to set up an authenticator application
+type TotpEnrolment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Totp implements time-based one-time password second factor (RFC 6238)
+type Totp struct {
+	repository *FactorRepository
+	settings   *settings.Service
+	now        func() time.Time
+}
+
+func NewTotp(repository *FactorRepository, settings *settings.Service) *Totp {
+	return &Totp{repository: repository, settings: settings, now: time.Now}
+}
+
+func (t *Totp) Method() Method {
+	return MethodTotp
+}
+
+// Enrol generates new secret for the user, the secret must be confirmed with a valid code before use.
+// The current secret stays in use until the new one is confirmed.
+func (t *Totp) Enrol(userId, accountName string) (*TotpEnrolment, error) {
+	random, err := generateRandomBytes(totpSecretSize)
+	if err != nil {
+		return nil, err
+	}
+	secret := totpEncoding.EncodeToString(random)
+	if err := t.repository.SavePendingTotpSecret(userId, secret); err != nil {
+		return nil, err
+	}
+
+	issuer, err := t.settings.String(SettingTanTotpIssuerString)
+	if err != nil || issuer == "" {
+		issuer = defaultTotpIssuer
+	}
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpStep.Seconds())))
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}
+
+	return &TotpEnrolment{Secret: secret, URI: uri.String()}, nil
+}
+
+// Confirm activates enrolled secret if the given code is valid
+func (t *Totp) Confirm(userId, code string) bool {
+	secret, err := t.repository.FindTotpSecret(userId)
+	if err != nil {
+		log.Println("totp failed to retrieve secret: ", err)
+		return false
+	}
+	if secret == nil || secret.PendingSecret == "" {
+		return false
+	}
+	step, ok := t.match(secret.PendingSecret, code)
+	if !ok {
+		return false
+	}
+	if err := t.repository.ConfirmTotpSecret(secret, step); err != nil {
+		log.Println("totp failed to confirm secret: ", err)
+		return false
+	}
+	return true
+}
+
+// Disable removes secret of the user
+func (t *Totp) Disable(userId string) error {
+	return t.repository.DeleteTotpSecret(userId)
+}
+
+// IsEnrolled checks whether the user has confirmed secret
+func (t *Totp) IsEnrolled(userId string) (bool, error) {
+	secret, err := t.repository.FindTotpSecret(userId)
+	if err != nil {
+		return false, err
+	}
+	return secret != nil && secret.Confirmed, nil
+}
+
+// Use verifies the code using confirmed secret of the user
+func (t *Totp) Use(userId, code string) bool {
+	secret, err := t.repository.FindTotpSecret(userId)
+	if err != nil {
+		log.Println("totp failed to retrieve secret: ", err)
+		return false
+	}
+	if secret == nil || !secret.Confirmed {
+		return false
+	}
+	step, ok := t.match(secret.Secret, code)
+	if !ok {
+		return false
+	}
+	// each code could be used only once
+	ok, err = t.repository.UseTotpStep(secret, step)
+	if err != nil {
+		log.Println("totp failed to save used step: ", err)
+		return false
+	}
+	return ok
+}
+
+// match returns the time step which the code is generated for with the given secret
+func (t *Totp) match(secret, code string) (int64, bool) {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		log.Println("totp failed to decode secret: ", err)
+		return 0, false
+	}
+	current := t.now().Unix() / int64(totpStep.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if hmac.Equal([]byte(TotpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// TotpCode generates code for the given time step (RFC 6238, HMAC-SHA1)
+func TotpCode(key []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, see RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
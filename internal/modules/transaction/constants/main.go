@@ -16,19 +16,29 @@ const (
 	PurposeDebitAccount  = Purpose("debit_account")
 	PurposeCreditRevenue = Purpose("credit_revenue")
 
+	PurposeConvertOutgoing = Purpose("convert_outgoing")
+	PurposeConvertIncoming = Purpose("convert_incoming")
+
+	PurposeCardRenewalOutgoing = Purpose("card_renewal_outgoing")
+	PurposeCardRenewalIncoming = Purpose("card_renewal_incoming")
+
 	PurposeFeeExchangeMargin = Purpose("fee_exchange_margin")
 	PurposeFeeTransfer       = Purpose("fee_default_transfer")
 	PurposeFeeIWT            = Purpose("fee_iwt")
+	PurposeFeeOwtOurCharge   = Purpose("fee_owt_our_charge")
 
 	PurposeRevenueExchangeMargin = Purpose("revenue_exchange_margin")
 	PurposeRevenueIwt            = Purpose("revenue_iwt_transfer_fee")
+	PurposeRevenueOwtOurCharge   = Purpose("revenue_owt_our_charge")
 )
 
 // MainTransactions is a slice of Purposes that are main in context of request (All transactions excepts fee, revenue, etc.)
 var MainTransactions = []Purpose{PurposeTBAOutgoing, PurposeTBAIncoming,
 	PurposeTBUOutgoing, PurposeTBUIncoming, PurposeOWTOutgoing,
 	PurposeCFTOutgoing, PurposeCFTIncoming, PurposeCreditAccount,
-	PurposeDebitRevenue, PurposeDebitAccount, PurposeCreditRevenue}
+	PurposeDebitRevenue, PurposeDebitAccount, PurposeCreditRevenue,
+	PurposeConvertOutgoing, PurposeConvertIncoming,
+	PurposeCardRenewalOutgoing, PurposeCardRenewalIncoming}
 
 func (p Purpose) String() string {
 	return string(p)
@@ -24,8 +24,8 @@ const (
 )
 
 var (
-	incomingExpr = regexp.MustCompile(`^\w{2,3}_incoming$`)
-	outgoingExpr = regexp.MustCompile(`^\w{2,3}_outgoing$`)
+	incomingExpr = regexp.MustCompile(`^(\w{2,3}|convert)_incoming$`)
+	outgoingExpr = regexp.MustCompile(`^(\w{2,3}|convert)_outgoing$`)
 )
 
 type Transaction struct {
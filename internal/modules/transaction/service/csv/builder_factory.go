@@ -23,7 +23,7 @@ func (c *BuilderCreator) CreateBuilder(action string, file *csv.File) Builder {
 		builder = builders.NewSendBuilder(file)
 	case "tbu_incoming":
 		builder = builders.NewReceiveBuilder(file)
-	case "convert_outgoing":
+	case "convert_outgoing", "convert_incoming":
 		builder = builders.NewConvertBuilder(file)
 	default:
 		builder = builders.NewSellBuilder(file)
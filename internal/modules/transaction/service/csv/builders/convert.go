@@ -1,6 +1,7 @@
 package builders
 
 import (
+	requestModel "github.com/Confialink/wallet-accounts/internal/modules/request/model"
 	transactionModel "github.com/Confialink/wallet-accounts/internal/modules/transaction/model"
 
 	"github.com/Confialink/wallet-accounts/internal/modules/syssettings"
@@ -30,6 +31,7 @@ func (b *ConvertBuilder) MakeHeader() {
 		"Type",
 		"Date Created",
 		"Date Processed",
+		"Rate",
 	}
 	b.file.WriteRow(header)
 }
@@ -53,15 +55,22 @@ func (b *ConvertBuilder) MakeBody(
 		fees := b.helper.getFees(transactions)
 		totalFee := b.helper.calculateTotalFees(fees)
 
+		rate := ""
+		if request, ok := requestData["request"].(*requestModel.Request); ok && request.Rate != nil {
+			rate = request.Rate.String()
+		}
+
 		record := []string{
 			*v.Status,
 			source.Amount.String(),
 			target.Amount.String(),
 			"",
 			totalFee.String(),
-			"",
+			source.Amount.Abs().Add(totalFee).String(),
+			*v.Type,
 			formattedCreatedDate,
 			formattedUpdatedDate,
+			rate,
 		}
 
 		b.file.WriteRow(record)
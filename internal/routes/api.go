@@ -28,6 +28,7 @@ import (
 	cardRepo "github.com/Confialink/wallet-accounts/internal/modules/card/repository"
 	commonHandlers "github.com/Confialink/wallet-accounts/internal/modules/common/http/handlers"
 	countryHandler "github.com/Confialink/wallet-accounts/internal/modules/country/http/handler"
+	currencyHandler "github.com/Confialink/wallet-accounts/internal/modules/currency/http/handler"
 	feeHandler "github.com/Confialink/wallet-accounts/internal/modules/fee/http/handler"
 	moneyRequestHdlr "github.com/Confialink/wallet-accounts/internal/modules/moneyrequest/http/handler"
 	paymentMethodHandler "github.com/Confialink/wallet-accounts/internal/modules/payment-method/http/handler"
@@ -50,14 +51,16 @@ import (
 func NewAPIRouter(
 	logger log15.Logger,
 	config *config.Config,
-	tanService *tan.Service,
+	tanAuthenticator *tan.Authenticator,
 	contextService service.ContextInterface,
 	settingsService *settings.Service,
 	accountsHandler *accountHandler.AccountHandler,
 	accountsCsvHandler *accountHandler.CsvHandler,
 	accountsTypeHandler *accountTypeHandler.AccountTypeHandler,
+	interestRateHandler *accountTypeHandler.InterestRateHandler,
 	cardHandler *cardHandlers.CardHandler,
 	cardListHandler *cardHandlers.CardListHandler,
+	cardControlsHandler *cardHandlers.ControlsHandler,
 	cardTypeHandler *cardTypeHandler.CardTypeHandler,
 	cardTypeCategoryHandler *cardTypeCategoryHandler.CardTypeCategoryHandler,
 	cardTypeFormatHandler *cardTypeFormatHandler.CardTypeFormatHandler,
@@ -65,12 +68,14 @@ func NewAPIRouter(
 	paymentPeriodHandler *paymentPeriodHandler.PaymentPeriodHandler,
 	settingsHandler *settingsController.SettingsController,
 	tanHandler *tanHandler.Controller,
+	tanFactorHandler *tanHandler.FactorController,
 	bankDetailsHandler *bankDetailsHandler.IwtBankAccountHandler,
 	revenueAccountHandler *accountHandler.RevenueAccountHandler,
 	requestListHandler *requestHandler.ListHandler,
 	requestHandler *requestHandler.RequestHandler,
 	tbaHandler *requestHandler.TbaHandler,
 	tbuHandler *requestHandler.TbuHandler,
+	convertHandler *requestHandler.ConvertHandler,
 	owtHandler *requestHandler.OwtHandler,
 	cftHandler *requestHandler.CftHandler,
 	caHandler *requestHandler.CaHandler,
@@ -82,11 +87,20 @@ func NewAPIRouter(
 	transactionsHistoryHandler *transactionHandler.HistoryHandler,
 	transactionsCsvHandler *transactionHandler.CsvHandler,
 	countryHandler *countryHandler.CountryHandler,
+	currencyRatesHandler *currencyHandler.RatesHandler,
 	transferFeeHandler *feeHandler.TransferFee,
+	exchangeMarginHandler *feeHandler.ExchangeMargin,
+	feeSimulationHandler *requestHandler.FeeSimulationHandler,
 	templateHandler *requestHandler.TemplateHandler,
 	requestCsvHandler *requestHandler.CsvHandler,
+	wireHandler *requestHandler.WireHandler,
+	incomingPaymentHandler *requestHandler.IncomingPaymentHandler,
 	cardsCsvHandler *cardHandlers.CsvHandler,
 	scheduledTxHandler *scheduledTransactionsHandler.TransactionsHandler,
+	interestHandler *scheduledTransactionsHandler.InterestHandler,
+	scheduledTxManagementHandler *scheduledTransactionsHandler.ManagementHandler,
+	scheduledJobsHandler *scheduledTransactionsHandler.JobsHandler,
+	accountClosureHandler *scheduledTransactionsHandler.AccountClosureHandler,
 	authService authS.AuthServiceInterface,
 	accountRepo *accountRepo.AccountRepository,
 	cardRepo cardRepo.CardRepositoryInterface,
@@ -156,10 +170,13 @@ func NewAPIRouter(
 			{
 				mwRequestedAccount := accountMw.RequestedAccount(contextService, accountRepo)
 				accountsGroup.GET("/:id", mwRequestedAccount, mwPerm.CanDynamicWithAccount(authS.ActionRead, authS.AccountsResource), accountsHandler.GetHandler)
+				accountsGroup.GET("/:id/accrued-interest", mwRequestedAccount, mwPerm.CanDynamicWithAccount(authS.ActionRead, authS.AccountsResource), interestHandler.AccruedInterest)
 				accountsGroup.GET("", mwClient, accountsHandler.ListHandler)
 				accountsGroup.POST("", mwAdminRoot, mwPermCreateAccount, accountsHandler.CreateHandler)
 				update(accountsGroup, "/:id", mwAdminRoot, mwRequestedAccount, mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ModifyAccounts), accountsHandler.UpdateHandler)
 				accountsGroup.DELETE("/:id", mwAdminRoot, accountsHandler.DeleteHandler)
+				accountsGroup.POST("/:id/reactivate", mwAdminRoot, mwRequestedAccount, mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ModifyAccounts), accountsHandler.ReactivateHandler)
+				accountsGroup.POST("/:id/close", mwAdminRoot, mwRequestedAccount, mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ModifyAccounts), accountClosureHandler.CloseHandler)
 			}
 
 			accountTypesGroup := v1Group.Group("/account-types")
@@ -170,6 +187,9 @@ func NewAPIRouter(
 				accountTypesGroup.POST("", mwAdminRoot, mwPermCreateModifyAccountTypes, accountsTypeHandler.CreateHandler)
 				update(accountTypesGroup, "/:id", mwAdminRoot, mwPermCreateModifyAccountTypes, accountsTypeHandler.UpdateHandler)
 				accountTypesGroup.DELETE("/:id", mwAdminRoot, mwPermCreateModifyAccountTypes, accountsTypeHandler.DeleteHandler)
+				accountTypesGroup.GET("/:id/interest-rates", mwAdminRoot, interestRateHandler.ListHandler)
+				accountTypesGroup.POST("/:id/interest-rates", mwAdminRoot, mwPermCreateModifyAccountTypes, interestRateHandler.CreateHandler)
+				accountTypesGroup.DELETE("/:id/interest-rates/:rateId", mwAdminRoot, mwPermCreateModifyAccountTypes, interestRateHandler.DeleteHandler)
 			}
 
 			cardTypesGroup := v1Group.Group("/card-types")
@@ -204,25 +224,46 @@ func NewAPIRouter(
 				settingsGroup.GET("", mwAdminRoot, mwPermViewSettings, settingsHandler.ListSettings)
 			}
 
+			adminGroup.GET("/exchange-rates/cache-stats", mwPermViewSettings, currencyRatesHandler.CacheStats)
+
 			userTanGroup := userGroup.Group("/tan")
 			{
 				userTanGroup.GET("/count", tanHandler.GetOwnCount)
 				userTanGroup.GET("/request/availability", tanHandler.UserCanRequestTan)
 				userTanGroup.POST("", tanHandler.UserRequestOne)
+
+				mwUseCurrentTan := tan.MiddlewareUseCurrent(tanAuthenticator, contextService)
+				userTanGroup.GET("/method", tanFactorHandler.GetOwnMethod)
+				update(userTanGroup, "/method", mwUseCurrentTan, tanFactorHandler.UpdateOwnMethod)
+				userTanGroup.POST("/totp", mwUseCurrentTan, tanFactorHandler.EnrolTotp)
+				userTanGroup.POST("/totp/confirm", tanFactorHandler.ConfirmTotp)
+				userTanGroup.DELETE("/totp", mwUseCurrentTan, tanFactorHandler.DisableTotp)
+				userTanGroup.POST("/approvals", tanFactorHandler.RequestApproval)
+				userTanGroup.POST("/approvals/:id/reject", tanFactorHandler.CancelApproval)
 			}
 
 			adminTanGroup := adminGroup.Group("/tan")
 			{
 				adminTanGroup.GET("/count/:userId", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ViewUserProfiles), tanHandler.GetCount)
 				adminTanGroup.POST("/:userId", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.GenerateSendNewTans), tanHandler.Create)
+				adminTanGroup.GET("/attempts/:userId", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ViewUserProfiles), tanHandler.GetAttempts)
+				adminTanGroup.POST("/unlock/:userId", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.GenerateSendNewTans), tanHandler.Unlock)
+				adminTanGroup.GET("/approvals/:userId", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ViewUserProfiles), tanFactorHandler.ListApprovals)
+				adminTanGroup.POST("/approvals/:id/approve", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.GenerateSendNewTans), tanFactorHandler.Approve)
+				adminTanGroup.POST("/approvals/:id/reject", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.GenerateSendNewTans), tanFactorHandler.Reject)
 			}
 
 			v1Group.GET("/own-cards", mwClient, cardListHandler.IndexOwnCardsHandler)
+			ownCardsGroup := v1Group.Group("/own-cards/:id", mwClient, cardMw.RequestedCard(contextService, cardRepo))
+			{
+				ownCardsGroup.GET("/controls", cardControlsHandler.ShowOwnHandler)
+				update(ownCardsGroup, "/controls", cardControlsHandler.UpdateOwnHandler)
+			}
 
 			tbaRequestsGroup := v1Group.Group("/tba-requests", mwClient)
 			{
 				mwUseTan := tan.MiddlewareUseIfRequired(
-					tanService,
+					tanAuthenticator,
 					contextService,
 					settingsService,
 					"tba_tan_required",
@@ -231,6 +272,18 @@ func NewAPIRouter(
 				tbaRequestsGroup.POST("", mwUseTan, tbaHandler.CreateRequestUser)
 			}
 
+			convertRequestsGroup := v1Group.Group("/convert-requests", mwClient)
+			{
+				mwUseTan := tan.MiddlewareUseIfRequired(
+					tanAuthenticator,
+					contextService,
+					settingsService,
+					"convert_tan_required",
+				)
+				convertRequestsGroup.POST("/preview", convertHandler.CreatePreviewUser)
+				convertRequestsGroup.POST("", mwUseTan, convertHandler.CreateRequestUser)
+			}
+
 			mwInitiateExecuteUserTransfers := mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.InitiateExecuteUserTransfers)
 			mwExecuteCancelPendingTransferRequests := mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ExecuteCancelPendingTransferRequests)
 
@@ -244,7 +297,7 @@ func NewAPIRouter(
 			tbuRequestsGroup := v1Group.Group("/tbu-requests", mwClient)
 			{
 				mwUseTan := tan.MiddlewareUseIfRequired(
-					tanService,
+					tanAuthenticator,
 					contextService,
 					settingsService,
 					"tbu_tan_required",
@@ -263,7 +316,7 @@ func NewAPIRouter(
 			tbuMoneyRequestsGroup := v1Group.Group("tbu-money-requests", mwClient)
 			{
 				mwUseTan := tan.MiddlewareUseIfRequired(
-					tanService,
+					tanAuthenticator,
 					contextService,
 					settingsService,
 					"tbu_tan_required",
@@ -281,7 +334,7 @@ func NewAPIRouter(
 			owtRequestsGroup := v1Group.Group("/owt-requests", mwClient)
 			{
 				mwUseTan := tan.MiddlewareUseIfRequired(
-					tanService,
+					tanAuthenticator,
 					contextService,
 					settingsService,
 					"owt_tan_required",
@@ -299,7 +352,7 @@ func NewAPIRouter(
 			cftRequestsGroup := v1Group.Group("/cft-requests", mwClient)
 			{
 				mwUseTan := tan.MiddlewareUseIfRequired(
-					tanService,
+					tanAuthenticator,
 					contextService,
 					settingsService,
 					"cft_tan_required",
@@ -337,6 +390,12 @@ func NewAPIRouter(
 			{
 				requestsAdminGroup.POST("/csv/update", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ImportTransferRequests), requestCsvHandler.UpdateFromCsv)
 				requestsAdminGroup.POST("/csv/import", mwPermManualDebitCredit, requestCsvHandler.ImportFromCsv)
+				requestsAdminGroup.POST("/owt/export", mwExecuteCancelPendingTransferRequests, wireHandler.Export)
+				requestsAdminGroup.POST("/owt/statuses", mwExecuteCancelPendingTransferRequests, wireHandler.ImportStatuses)
+				requestsAdminGroup.POST("/camt/import", mwPermManualDebitCredit, incomingPaymentHandler.Import)
+				requestsAdminGroup.GET("/incoming-payments/exceptions", mwPermManualDebitCredit, incomingPaymentHandler.Exceptions)
+				requestsAdminGroup.POST("/incoming-payments/:id/resolve", mwPermManualDebitCredit, incomingPaymentHandler.Resolve)
+				requestsAdminGroup.POST("/incoming-payments/:id/dismiss", mwPermManualDebitCredit, incomingPaymentHandler.Dismiss)
 				requestsAdminGroup.POST("/cancel/:requestId", mwRequestedRequest, mwExecuteCancelPendingTransferRequests, requestHandler.CancelRequest)
 				requestsAdminGroup.POST("/execute/:requestId", mwRequestedRequest, mwExecuteCancelPendingTransferRequests, requestHandler.ExecuteRequest)
 				requestsAdminGroup.PATCH("/:requestId", mwRequestedRequest, requestHandler.ModifyRequest)
@@ -385,6 +444,13 @@ func NewAPIRouter(
 				adminFeeGroup.DELETE("/transfer/id/:id", mwPermRemoveSettings, transferFeeHandler.DeleteFee)
 				adminFeeGroup.GET("/transfer/subject/:requestSubject", mwPermViewSettings, transferFeeHandler.ListFees)
 				adminFeeGroup.GET("/transfer/parameters/:id", mwPermViewSettings, transferFeeHandler.ListFeeParameters)
+				adminFeeGroup.POST("/transfer/simulate", mwPerm.CanDynamic(authS.ActionHas, authS.ResourcePermission, permission.ViewUserReports), feeSimulationHandler.Simulate)
+				adminFeeGroup.GET("/exchange-margins", mwPermViewSettings, exchangeMarginHandler.List)
+				adminFeeGroup.GET("/exchange-margins/preview", mwPermViewSettings, exchangeMarginHandler.Preview)
+				adminFeeGroup.POST("/exchange-margins", mwPermCreateSettings, exchangeMarginHandler.Create)
+				adminFeeGroup.GET("/exchange-margins/id/:id", mwPermViewSettings, exchangeMarginHandler.Get)
+				adminFeeGroup.POST("/exchange-margins/id/:id", mwPermModifySettings, exchangeMarginHandler.Update)
+				adminFeeGroup.DELETE("/exchange-margins/id/:id", mwPermRemoveSettings, exchangeMarginHandler.Delete)
 			}
 
 			userFeeGroup := userGroup.Group("/fee")
@@ -441,6 +507,14 @@ func NewAPIRouter(
 			{
 				scheduledTransactionsGroup.GET("", mwPerm.CanDynamic(authS.ActionReadList, authS.ResourceScheduledTransactions, nil), scheduledTxHandler.ListHandler)
 				scheduledTransactionsGroup.GET("/:id", mwPerm.CanDynamic(authS.ActionReadList, authS.ResourceScheduledTransactions, nil), scheduledTxHandler.GetById)
+				scheduledTransactionsGroup.PATCH("/:id", mwPerm.CanDynamic(authS.ActionUpdate, authS.ResourceScheduledTransactions, nil), scheduledTxManagementHandler.UpdateHandler)
+				scheduledTransactionsGroup.POST("/:id/waive", mwPerm.CanDynamic(authS.ActionUpdate, authS.ResourceScheduledTransactions, nil), scheduledTxManagementHandler.WaiveHandler)
+				scheduledTransactionsGroup.POST("/:id/execute", mwPerm.CanDynamic(authS.ActionUpdate, authS.ResourceScheduledTransactions, nil), scheduledTxManagementHandler.ExecuteHandler)
+			}
+
+			scheduledJobsGroup := v1Group.Group("scheduled-jobs")
+			{
+				scheduledJobsGroup.GET("", mwPerm.CanDynamic(authS.ActionReadList, authS.ResourceScheduledTransactions, nil), scheduledJobsHandler.PlannedRunsHandler)
 			}
 
 			formsGroup := v1Group.Group("/form")
@@ -206,3 +206,24 @@ func (a *AmountNeg) Currency() Currency {
 func (a *AmountNeg) Amount() decimal.Decimal {
 	return a.topAmount.Amount().Neg()
 }
+
+// AmountDifference is used in order to subtract one amount from another
+type AmountDifference struct {
+	topAmount  CurrencyAmount
+	subtrahend CurrencyAmount
+}
+
+// NewAmountDifference is AmountDifference constructor
+func NewAmountDifference(topAmount, subtrahend CurrencyAmount) *AmountDifference {
+	return &AmountDifference{topAmount: topAmount, subtrahend: subtrahend}
+}
+
+// Currency returns top amount currency
+func (a *AmountDifference) Currency() Currency {
+	return a.topAmount.Currency()
+}
+
+// Amount returns top amount reduced by the subtrahend
+func (a *AmountDifference) Amount() decimal.Decimal {
+	return a.topAmount.Amount().Sub(a.subtrahend.Amount())
+}
@@ -126,6 +126,73 @@ var _ = Describe("Fee", func() {
 				Expect(transferFee.Amount()).To(decEqual(str2Dec("0.3")))
 			})
 		})
+
+		Context("tiers", func() {
+			params := TransferFeeParams{
+				Percent: str2Dec("5"),
+				Tiers: []TransferFeeTier{
+					{From: str2Dec("50"), Percent: str2Dec("1")},
+					{From: str2Dec("5"), Percent: str2Dec("2")},
+				},
+			}
+
+			It("should charge percent of the band the amount falls into", func() {
+				debit, _ := transfer.NewDebitAction(euroWallet, transfer.NewAmount(euroCurrency, decimal10))
+				transferFee, _ := NewDebitTransferFeeAction(euroWallet, debit, params)
+				err := transfer.NewPerformerGroup(debit, transferFee).Perform()
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transferFee.Amount()).To(decEqual(str2Dec("0.2")))
+			})
+
+			It("should charge percent of the highest reached band", func() {
+				debit, _ := transfer.NewDebitAction(euroWallet, transfer.NewAmount(euroCurrency, str2Dec("60")))
+				transferFee, _ := NewDebitTransferFeeAction(euroWallet, debit, params)
+				err := transfer.NewPerformerGroup(debit, transferFee).Perform()
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transferFee.Amount()).To(decEqual(str2Dec("0.6")))
+			})
+
+			It("should charge default percent below the lowest band", func() {
+				debit, _ := transfer.NewDebitAction(euroWallet, transfer.NewAmount(euroCurrency, str2Dec("4")))
+				transferFee, _ := NewDebitTransferFeeAction(euroWallet, debit, params)
+				err := transfer.NewPerformerGroup(debit, transferFee).Perform()
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(transferFee.Amount()).To(decEqual(str2Dec("0.2")))
+			})
+		})
+
+		It("should apply discount to the whole fee", func() {
+			params := TransferFeeParams{
+				Percent:         decimal10,
+				Base:            str2Dec("1"),
+				DiscountPercent: str2Dec("50"),
+			}
+			debit, _ := transfer.NewDebitAction(euroWallet, transfer.NewAmount(euroCurrency, decimal10))
+			transferFee, _ := NewDebitTransferFeeAction(euroWallet, debit, params)
+			err := transfer.NewPerformerGroup(debit, transferFee).Perform()
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(euroWallet.Amount()).To(decEqual(str2Dec("89")))
+			Expect(transferFee.Amount()).To(decEqual(str2Dec("1")))
+		})
+
+		It("should not charge free transfer", func() {
+			params := TransferFeeParams{
+				Percent: decimal10,
+				Base:    str2Dec("0.3"),
+				Free:    true,
+			}
+			debit, _ := transfer.NewDebitAction(euroWallet, transfer.NewAmount(euroCurrency, decimal10))
+			transferFee, _ := NewDebitTransferFeeAction(euroWallet, debit, params)
+			err := transfer.NewPerformerGroup(debit, transferFee).Perform()
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(euroWallet.Amount()).To(decEqual(str2Dec("90")))
+			Expect(transferFee.Amount()).To(decEqual(str2Dec("0")))
+		})
 	})
 })
 
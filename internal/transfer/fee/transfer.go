@@ -15,6 +15,35 @@ type TransferFeeParams struct {
 	Min decimal.Decimal `json:"min"`
 	// Max is only used when Percent is not zero. This value limits the maximum transfer fee amount.
 	Max decimal.Decimal `json:"max"`
+	// Tiers replace Percent by the percent of the amount band the given amount falls into
+	Tiers []TransferFeeTier `json:"tiers,omitempty"`
+	// DiscountPercent reduces the calculated fee e.g. by monthly volume discount
+	DiscountPercent decimal.Decimal `json:"discountPercent"`
+	// Free waives the fee e.g. if free transfers quota is not exhausted
+	Free bool `json:"free"`
+}
+
+// TransferFeeTier is a band of amounts charged with its own percent
+type TransferFeeTier struct {
+	// From is the lower bound of the band (inclusive)
+	From decimal.Decimal `json:"from"`
+	// Percent is percentage of a given amount
+	Percent decimal.Decimal `json:"percent"`
+}
+
+// PercentFor returns percentage charged from the given amount, it is percent of the highest tier
+// which lower bound does not exceed the amount or Percent if there are no such tiers
+func (p TransferFeeParams) PercentFor(amount decimal.Decimal) decimal.Decimal {
+	percent := p.Percent
+	var from *decimal.Decimal
+	for i, tier := range p.Tiers {
+		if tier.From.GreaterThan(amount) || (from != nil && tier.From.LessThan(*from)) {
+			continue
+		}
+		from = &p.Tiers[i].From
+		percent = tier.Percent
+	}
+	return percent
 }
 
 // NewDebitTransferFeeAction is TransferFee constructor
@@ -41,9 +70,13 @@ func (t *TransferFeeAmount) Currency() transfer.Currency {
 // Amount calculates transfer fee
 func (t *TransferFeeAmount) Amount() decimal.Decimal {
 	zero := decimal.New(0, 1)
+	if t.params.Free {
+		return zero
+	}
 	totalFee := t.params.Base
-	if t.params.Percent.GreaterThan(zero) {
-		percentFee := t.fromAmount.Amount().Mul(t.params.Percent.Div(decimal.New(100, 0)))
+	percent := t.params.PercentFor(t.fromAmount.Amount())
+	if percent.GreaterThan(zero) {
+		percentFee := t.fromAmount.Amount().Mul(percent.Div(decimal.New(100, 0)))
 		if t.params.Min.GreaterThan(zero) && percentFee.LessThan(t.params.Min) {
 			percentFee = t.params.Min
 		}
@@ -52,5 +85,11 @@ func (t *TransferFeeAmount) Amount() decimal.Decimal {
 		}
 		totalFee = totalFee.Add(percentFee)
 	}
+	if t.params.DiscountPercent.GreaterThan(zero) {
+		totalFee = totalFee.Sub(totalFee.Mul(t.params.DiscountPercent.Div(decimal.New(100, 0))))
+		if totalFee.LessThan(zero) {
+			totalFee = zero
+		}
+	}
 	return totalFee
 }
@@ -159,6 +159,14 @@ var _ = Describe("Transfer", func() {
 			absAmount = NewAmountAbs(NewAmount(euroCurrency, decimal100))
 			Expect(absAmount.Amount()).To(decEqual(decimal100))
 		})
+		It("should return difference of amounts", func() {
+			difference := NewAmountDifference(
+				NewAmount(euroCurrency, decimal100),
+				NewAmount(euroCurrency, decimal10),
+			)
+			Expect(difference.Currency()).To(Equal(euroCurrency))
+			Expect(difference.Amount()).To(decEqual(str2Dec("90")))
+		})
 	})
 
 	Context("Wallet", func() {